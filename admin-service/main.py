@@ -1,12 +1,24 @@
-from fastapi import FastAPI, HTTPException, status, Depends
+from fastapi import FastAPI, status, Depends, Query
+from fastapi.responses import PlainTextResponse
+from pydantic import BaseModel, EmailStr
+from typing import Dict, Optional
 from sqlalchemy.orm import Session
 from sqlalchemy import func
-from datetime import datetime, timedelta
+from datetime import datetime, timedelta, timezone
 import logging
 
 from shared.config import settings
-from shared.database import get_db, check_db_connection
-from shared.models import Tenant, Booking, User, TenantStatus
+from shared.database import (
+    get_db, get_read_db, check_db_connection, engine, get_pool_stats, render_prometheus_pool_metrics,
+    run_in_transaction
+)
+from shared.api import APIError, ErrorCode, register_exception_handlers, request_id_middleware
+from shared.i18n import verify_translation_coverage
+from shared.models import Tenant, Booking, User, TenantStatus, BookingStatus, UserRole, AdminAction
+from shared.auth import verify_password, create_token_pair, create_impersonation_token, ADMIN_SCOPE
+from shared.jobs import JOB_STATUSES, get_job_stats, list_jobs
+from shared.events import EventType, publish_event
+from shared.billing import Feature, get_tenant_features
 
 # Configure logging
 logging.basicConfig(
@@ -22,11 +34,59 @@ app = FastAPI(
     version="2.0.0"
 )
 
+# Request ID and standard error envelope
+app.middleware("http")(request_id_middleware)
+register_exception_handlers(app)
+
+
+# Sortable columns of the pending tenants list; sort_by is never used
+# in the query directly
+TENANT_SORT_COLUMNS = {
+    "created_at": Tenant.created_at,
+    "business_name": Tenant.business_name,
+    "subdomain": Tenant.subdomain
+}
+SORT_ORDERS = ("asc", "desc")
+
+# Status filter value listing tenants of every status
+ALL_STATUSES = "ALL"
+TENANTS_PAGE_SIZE = 50
+TENANTS_MAX_PAGE_SIZE = 200
+
+
+# Request models
+class AdminLoginRequest(BaseModel):
+    email: EmailStr
+    password: str
+
+
+class ImpersonateTenantRequest(BaseModel):
+    admin_id: int
+    reason: Optional[str] = None
+
+
+class TenantFeaturesRequest(BaseModel):
+    admin_id: int
+    features: Dict[Feature, bool]
+
+
+class ResendDecisionEmailRequest(BaseModel):
+    admin_id: int
+
+
+class ImpersonatedActionRequest(BaseModel):
+    admin_id: int
+    tenant_id: int
+    method: str
+    path: str
+    status_code: int
+
 
 @app.on_event("startup")
 async def startup_event():
     """Initialize on startup."""
     logger.info("Starting Admin Service...")
+    verify_translation_coverage()
     if check_db_connection():
         logger.info("Database connection successful")
 
@@ -34,20 +94,116 @@ async def startup_event():
 @app.get("/health")
 async def health_check():
     """Health check endpoint."""
+    db_healthy = check_db_connection()
     return {
-        "status": "healthy",
-        "service": "admin-service"
+        "status": "healthy" if db_healthy else "unhealthy",
+        "service": "admin-service",
+        "database": "connected" if db_healthy else "disconnected"
     }
 
 
-@app.get("/tenants/pending")
-async def get_pending_tenants(db: Session = Depends(get_db)):
+@app.get("/metrics", response_class=PlainTextResponse)
+async def metrics():
     """
-    Get all pending tenant applications.
+    Database pool metrics in Prometheus text format.
     """
-    tenants = db.query(Tenant).filter(
-        Tenant.status == TenantStatus.PENDING
-    ).order_by(Tenant.created_at.desc()).all()
+    return render_prometheus_pool_metrics(get_pool_stats(engine), "admin-service")
+
+
+@app.post("/login")
+async def admin_login(data: AdminLoginRequest, db: Session = Depends(get_db)):
+    """
+    Authenticate platform administrator.
+
+    Tokens are only issued to active SUPER_ADMIN users and carry
+    the admin scope required by admin endpoints.
+    """
+    user = db.query(User).filter(User.email == data.email).first()
+
+    if not user or not verify_password(data.password, user.password_hash):
+        raise APIError(
+            status_code=status.HTTP_401_UNAUTHORIZED,
+            code=ErrorCode.INVALID_CREDENTIALS
+        )
+
+    if user.role != UserRole.SUPER_ADMIN:
+        logger.warning(f"Admin login rejected for non-admin user: {user.email}")
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    if not user.is_active:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.ACCOUNT_INACTIVE
+        )
+
+    tokens = create_token_pair(
+        user.id,
+        user.email,
+        user.role.value,
+        scope=ADMIN_SCOPE,
+        expires_delta=timedelta(minutes=settings.ADMIN_ACCESS_TOKEN_EXPIRE_MINUTES)
+    )
+
+    logger.info(f"Admin logged in: {user.email}")
+
+    return {
+        "message": "Login successful",
+        "user": {
+            "id": user.id,
+            "email": user.email,
+            "full_name": user.full_name,
+            "role": user.role.value
+        },
+        **tokens
+    }
+
+
+@app.get("/tenants")
+async def list_tenants(
+    status_filter: str = Query(TenantStatus.PENDING.value, alias="status"),
+    search: Optional[str] = Query(None, max_length=200),
+    sort_by: str = "created_at",
+    sort_order: str = "desc",
+    page: int = Query(1, ge=1),
+    page_size: int = Query(TENANTS_PAGE_SIZE, ge=1, le=TENANTS_MAX_PAGE_SIZE),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Get tenants of a status (pending applications by default, ALL for
+    every status), optionally searched by business name.
+
+    Sorted by one of TENANT_SORT_COLUMNS, newest first by default. Other
+    status, sort_by or sort_order values are rejected with 400.
+    status_counts has the number of tenants matching the search by status.
+    """
+    column = TENANT_SORT_COLUMNS.get(sort_by)
+    statuses = [s.value for s in TenantStatus] + [ALL_STATUSES]
+    if column is None or sort_order not in SORT_ORDERS or status_filter not in statuses:
+        raise APIError(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            code=ErrorCode.BAD_REQUEST,
+            details={"status": statuses, "sort_by": list(TENANT_SORT_COLUMNS), "sort_order": list(SORT_ORDERS)}
+        )
+
+    filters = []
+    if search and search.strip():
+        pattern = search.strip().replace("\\", "\\\\").replace("%", "\\%").replace("_", "\\_")
+        filters.append(Tenant.business_name.ilike(f"%{pattern}%", escape="\\"))
+
+    status_counts = dict(
+        db.query(Tenant.status, func.count(Tenant.id)).filter(*filters).group_by(Tenant.status).all()
+    )
+
+    query = db.query(Tenant).filter(*filters)
+    if status_filter != ALL_STATUSES:
+        query = query.filter(Tenant.status == TenantStatus(status_filter))
+
+    total = query.count()
+    ordering = column.asc() if sort_order == "asc" else column.desc()
+    tenants = query.order_by(ordering, Tenant.id).offset((page - 1) * page_size).limit(page_size).all()
 
     return {
         "tenants": [
@@ -61,26 +217,49 @@ async def get_pending_tenants(db: Session = Depends(get_db)):
                 "status": t.status.value
             }
             for t in tenants
-        ]
+        ],
+        "total": total,
+        "page": page,
+        "page_size": page_size,
+        "pages": (total + page_size - 1) // page_size,
+        "status_counts": {s.value: status_counts.get(s, 0) for s in TenantStatus}
     }
 
 
+@app.get("/tenants/pending")
+async def get_pending_tenants(
+    sort_by: str = "created_at",
+    sort_order: str = "desc",
+    page: int = Query(1, ge=1),
+    page_size: int = Query(TENANTS_PAGE_SIZE, ge=1, le=TENANTS_MAX_PAGE_SIZE),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Get pending tenant applications, see list_tenants.
+    """
+    return await list_tenants(TenantStatus.PENDING.value, None, sort_by, sort_order, page, page_size, db)
+
+
 @app.put("/tenant/{tenant_id}/approve")
 async def approve_tenant(tenant_id: int, db: Session = Depends(get_db)):
     """
     Approve tenant application.
     """
-    tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+    def approve(db: Session) -> Tenant:
+        tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
 
-    if not tenant:
-        raise HTTPException(
-            status_code=status.HTTP_404_NOT_FOUND,
-            detail="Tenant not found"
-        )
+        if not tenant:
+            raise APIError(
+                status_code=status.HTTP_404_NOT_FOUND,
+                code=ErrorCode.TENANT_NOT_FOUND
+            )
 
-    tenant.status = TenantStatus.ACTIVE
-    db.commit()
+        tenant.status = TenantStatus.ACTIVE
+        return tenant
+
+    tenant = run_in_transaction(db, approve)
 
+    publish_event(EventType.TENANT_APPROVED, {"tenant_id": tenant.id})
     logger.info(f"Tenant approved: {tenant.subdomain}")
 
     return {
@@ -95,28 +274,206 @@ async def reject_tenant(tenant_id: int, db: Session = Depends(get_db)):
     """
     Reject tenant application.
     """
+    def reject(db: Session) -> Tenant:
+        tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+
+        if not tenant:
+            raise APIError(
+                status_code=status.HTTP_404_NOT_FOUND,
+                code=ErrorCode.TENANT_NOT_FOUND
+            )
+
+        tenant.status = TenantStatus.REJECTED
+        return tenant
+
+    tenant = run_in_transaction(db, reject)
+
+    publish_event(EventType.TENANT_REJECTED, {"tenant_id": tenant.id})
+    logger.info(f"Tenant rejected: {tenant.subdomain}")
+
+    return {
+        "message": "Tenant rejected",
+        "tenant_id": tenant.id,
+        "status": tenant.status.value
+    }
+
+
+# Decision email sent for a tenant in the status
+DECISION_EMAILS = {
+    TenantStatus.ACTIVE: "approved",
+    TenantStatus.REJECTED: "rejected",
+}
+
+
+@app.post("/tenant/{tenant_id}/resend-decision-email")
+async def resend_tenant_decision_email(
+    tenant_id: int,
+    data: ResendDecisionEmailRequest,
+    db: Session = Depends(get_db)
+):
+    """
+    Resend the approval or rejection email of the tenant.
+
+    The email follows the current status: approval for active tenants,
+    rejection for rejected ones; other statuses are rejected with 409.
+    Recorded in admin actions.
+    """
+    tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+
+    if not tenant:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.TENANT_NOT_FOUND
+        )
+
+    decision = DECISION_EMAILS.get(tenant.status)
+    if not decision:
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.INVALID_TENANT_STATUS,
+            details={"status": tenant.status.value}
+        )
+
+    db.add(AdminAction(
+        admin_id=data.admin_id,
+        action_type="TENANT_DECISION_EMAIL_RESENT",
+        target_type="tenant",
+        target_id=tenant.id,
+        details=decision
+    ))
+    db.commit()
+
+    publish_event(EventType.TENANT_DECISION_EMAIL_REQUESTED, {"tenant_id": tenant.id, "decision": decision})
+    logger.info(f"Tenant {decision} email resent: {tenant.subdomain}")
+
+    return {
+        "message": "Decision email resent",
+        "tenant_id": tenant.id,
+        "decision": decision
+    }
+
+
+@app.get("/tenant/{tenant_id}/features")
+async def get_tenant_feature_flags(tenant_id: int, db: Session = Depends(get_read_db)):
+    """
+    Get features of the tenant with whether each is enabled.
+    """
+    tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+
+    if not tenant:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.TENANT_NOT_FOUND
+        )
+
+    return {"tenant_id": tenant.id, "features": get_tenant_features(tenant)}
+
+
+@app.put("/tenant/{tenant_id}/features")
+async def update_tenant_feature_flags(tenant_id: int, data: TenantFeaturesRequest, db: Session = Depends(get_db)):
+    """
+    Enable or disable features of the tenant, e.g. on a plan change.
+
+    Features not in the request keep their current setting. The change is
+    recorded in admin actions.
+    """
     tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
 
     if not tenant:
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_404_NOT_FOUND,
-            detail="Tenant not found"
+            code=ErrorCode.TENANT_NOT_FOUND
         )
 
-    tenant.status = TenantStatus.REJECTED
+    changes = {feature.value: enabled for feature, enabled in data.features.items()}
+    # Reassigned, not mutated, so the JSON column is marked changed
+    tenant.features = {**(tenant.features or {}), **changes}
+
+    db.add(AdminAction(
+        admin_id=data.admin_id,
+        action_type="TENANT_FEATURES_UPDATED",
+        target_type="tenant",
+        target_id=tenant.id,
+        details=", ".join(f"{name}={'on' if enabled else 'off'}" for name, enabled in sorted(changes.items()))
+    ))
     db.commit()
 
-    logger.info(f"Tenant rejected: {tenant.subdomain}")
+    logger.info(f"Tenant features updated: {tenant.subdomain} {changes}")
+
+    return {"tenant_id": tenant.id, "features": get_tenant_features(tenant)}
+
+
+@app.post("/tenant/{tenant_id}/impersonate")
+async def impersonate_tenant(tenant_id: int, data: ImpersonateTenantRequest, db: Session = Depends(get_db)):
+    """
+    Issue a short-lived token acting as the tenant's owner, for support.
+
+    The token carries the impersonated_by claim; starting the session and
+    every request made with the token are recorded in admin actions.
+    """
+    tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+
+    if not tenant:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.TENANT_NOT_FOUND
+        )
+
+    owner = db.query(User).filter(
+        User.tenant_id == tenant.id,
+        User.role == UserRole.OWNER,
+        User.is_active == True
+    ).order_by(User.id).first()
+
+    if not owner:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.USER_NOT_FOUND
+        )
+
+    access_token = create_impersonation_token(
+        data.admin_id, owner.id, owner.email, owner.role.value, tenant.id
+    )
+
+    db.add(AdminAction(
+        admin_id=data.admin_id,
+        action_type="IMPERSONATION_STARTED",
+        target_type="tenant",
+        target_id=tenant.id,
+        details=f"As user {owner.id}" + (f": {data.reason}" if data.reason else "")
+    ))
+    db.commit()
+
+    logger.warning(f"Admin {data.admin_id} impersonates tenant {tenant.subdomain} as user {owner.id}")
 
     return {
-        "message": "Tenant rejected",
+        "access_token": access_token,
+        "token_type": "bearer",
+        "expires_in": settings.IMPERSONATION_TOKEN_EXPIRE_MINUTES * 60,
         "tenant_id": tenant.id,
-        "status": tenant.status.value
+        "user_id": owner.id
     }
 
 
+@app.post("/actions/impersonated", status_code=status.HTTP_201_CREATED)
+async def record_impersonated_action(data: ImpersonatedActionRequest, db: Session = Depends(get_db)):
+    """
+    Record a request made with an impersonation token.
+    """
+    db.add(AdminAction(
+        admin_id=data.admin_id,
+        action_type="IMPERSONATED_REQUEST",
+        target_type="tenant",
+        target_id=data.tenant_id,
+        details=f"{data.method} {data.path} -> {data.status_code}"
+    ))
+    db.commit()
+
+    return {"message": "Action recorded"}
+
+
 @app.get("/statistics")
-async def get_statistics(db: Session = Depends(get_db)):
+async def get_statistics(db: Session = Depends(get_read_db)):
     """
     Get platform statistics.
     """
@@ -134,6 +491,9 @@ async def get_statistics(db: Session = Depends(get_db)):
 
     # Count bookings
     total_bookings = db.query(func.count(Booking.id)).scalar()
+    no_show_bookings = db.query(func.count(Booking.id)).filter(
+        Booking.status == BookingStatus.NO_SHOW
+    ).scalar()
 
     # Count users
     total_users = db.query(func.count(User.id)).scalar()
@@ -153,6 +513,7 @@ async def get_statistics(db: Session = Depends(get_db)):
         },
         "bookings": {
             "total": total_bookings,
+            "no_show": no_show_bookings,
             "last_30_days": recent_bookings
         },
         "users": {
@@ -161,6 +522,66 @@ async def get_statistics(db: Session = Depends(get_db)):
     }
 
 
+@app.get("/jobs/stats")
+async def get_jobs_statistics():
+    """
+    Get background job counters and queue depth.
+    """
+    try:
+        return get_job_stats()
+    except Exception as e:
+        logger.error(f"Failed to read job stats: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@app.get("/jobs")
+async def get_jobs(
+    type: Optional[str] = Query(None),
+    status_filter: Optional[str] = Query(None, alias="status"),
+    since: Optional[datetime] = Query(None),
+    until: Optional[datetime] = Query(None),
+    limit: int = Query(100, ge=1, le=500)
+):
+    """
+    Get recent background jobs, newest first.
+
+    Filters by task type, status and queued time (UTC).
+    """
+    if status_filter and status_filter not in JOB_STATUSES:
+        raise APIError(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            code=ErrorCode.BAD_REQUEST,
+            details={"status": list(JOB_STATUSES)}
+        )
+
+    try:
+        jobs = list_jobs(
+            task_name=type,
+            status=status_filter,
+            since=utc_timestamp(since) if since else None,
+            until=utc_timestamp(until) if until else None,
+            limit=limit
+        )
+    except Exception as e:
+        logger.error(f"Failed to read job history: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+    return {"jobs": jobs}
+
+
+def utc_timestamp(value: datetime) -> float:
+    """Epoch seconds of a datetime, naive values are UTC."""
+    if value.tzinfo is None:
+        value = value.replace(tzinfo=timezone.utc)
+    return value.timestamp()
+
+
 if __name__ == "__main__":
     import uvicorn
 
@@ -0,0 +1,47 @@
+import pytest
+
+from shared.auth import decode_token, get_password_hash
+from shared.models import UserRole
+
+PASSWORD = "correct-horse-battery"
+
+
+@pytest.fixture
+def admin(factory):
+    return factory.user(role=UserRole.SUPER_ADMIN, password_hash=get_password_hash(PASSWORD))
+
+
+def test_super_admin_gets_admin_scoped_token(client, admin):
+    response = client.post("/login", json={"email": admin.email, "password": PASSWORD})
+
+    assert response.status_code == 200
+    payload = decode_token(response.json()["access_token"])
+    assert payload["scope"] == "admin"
+    assert payload["role"] == UserRole.SUPER_ADMIN.value
+
+
+def test_non_admin_is_forbidden_without_token(client, factory):
+    tenant = factory.tenant()
+    owner = factory.user(tenant, password_hash=get_password_hash(PASSWORD))
+
+    response = client.post("/login", json={"email": owner.email, "password": PASSWORD})
+
+    assert response.status_code == 403
+    assert response.json()["error"] == "FORBIDDEN"
+    assert "access_token" not in response.text
+
+
+def test_wrong_password_is_unauthorized(client, admin):
+    response = client.post("/login", json={"email": admin.email, "password": "wrong"})
+
+    assert response.status_code == 401
+    assert response.json()["error"] == "INVALID_CREDENTIALS"
+
+
+def test_inactive_admin_is_forbidden(client, factory):
+    admin = factory.user(role=UserRole.SUPER_ADMIN, password_hash=get_password_hash(PASSWORD), is_active=False)
+
+    response = client.post("/login", json={"email": admin.email, "password": PASSWORD})
+
+    assert response.status_code == 403
+    assert response.json()["error"] == "ACCOUNT_INACTIVE"
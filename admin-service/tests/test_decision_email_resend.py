@@ -0,0 +1,55 @@
+import pytest
+
+from shared.events import EventType
+from shared.models import AdminAction, TenantStatus, UserRole
+
+
+@pytest.fixture
+def events(service, monkeypatch):
+    """Events published by the admin service."""
+    events = []
+    monkeypatch.setattr(service, "publish_event", lambda event_type, data: events.append((event_type, data)))
+    return events
+
+
+@pytest.fixture
+def admin(factory):
+    return factory.user(role=UserRole.SUPER_ADMIN)
+
+
+def resend(client, admin, tenant_id):
+    return client.post(f"/tenant/{tenant_id}/resend-decision-email", json={"admin_id": admin.id})
+
+
+@pytest.mark.parametrize("tenant_status, decision", [
+    (TenantStatus.ACTIVE, "approved"),
+    (TenantStatus.REJECTED, "rejected")
+])
+def test_email_follows_current_status(client, db, factory, admin, events, tenant_status, decision):
+    tenant = factory.tenant(status=tenant_status)
+
+    response = resend(client, admin, tenant.id)
+
+    assert response.status_code == 200
+    assert response.json()["decision"] == decision
+    assert events == [(EventType.TENANT_DECISION_EMAIL_REQUESTED, {"tenant_id": tenant.id, "decision": decision})]
+    action = db.query(AdminAction).filter(AdminAction.target_id == tenant.id).one()
+    assert (action.admin_id, action.action_type, action.details) == (admin.id, "TENANT_DECISION_EMAIL_RESENT", decision)
+
+
+def test_undecided_tenant_is_a_conflict(client, factory, admin, events):
+    tenant = factory.tenant(status=TenantStatus.PENDING)
+
+    response = resend(client, admin, tenant.id)
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "INVALID_TENANT_STATUS"
+    assert response.json()["details"] == {"status": "PENDING"}
+    assert events == []
+
+
+def test_unknown_tenant_is_not_found(client, admin, events):
+    response = resend(client, admin, 0)
+
+    assert response.status_code == 404
+    assert events == []
@@ -0,0 +1,56 @@
+import pytest
+
+from shared.config import settings
+from shared.models import AdminAction, UserRole
+
+
+@pytest.fixture(autouse=True)
+def defaults(monkeypatch):
+    monkeypatch.setattr(settings, "DEFAULT_TENANT_FEATURES", "reviews,deposits")
+
+
+def test_features_of_tenant(client, factory):
+    tenant = factory.tenant(features={"reviews": False})
+
+    response = client.get(f"/tenant/{tenant.id}/features")
+
+    assert response.status_code == 200
+    assert response.json() == {
+        "tenant_id": tenant.id,
+        "features": {"reviews": False, "deposits": True, "multi_location": False}
+    }
+
+
+def test_update_merges_and_is_recorded(client, db, factory):
+    admin = factory.user(role=UserRole.SUPER_ADMIN)
+    tenant = factory.tenant(features={"reviews": False})
+
+    response = client.put(f"/tenant/{tenant.id}/features", json={
+        "admin_id": admin.id, "features": {"deposits": False, "multi_location": True}
+    })
+
+    assert response.status_code == 200
+    assert response.json()["features"] == {"reviews": False, "deposits": False, "multi_location": True}
+    db.expire_all()
+    assert tenant.features == {"reviews": False, "deposits": False, "multi_location": True}
+    action = db.query(AdminAction).filter(AdminAction.target_id == tenant.id).one()
+    assert action.action_type == "TENANT_FEATURES_UPDATED"
+    assert action.details == "deposits=off, multi_location=on"
+
+
+def test_unknown_feature_is_rejected(client, factory):
+    admin = factory.user(role=UserRole.SUPER_ADMIN)
+    tenant = factory.tenant()
+
+    response = client.put(f"/tenant/{tenant.id}/features", json={"admin_id": admin.id, "features": {"gift_cards": True}})
+
+    assert response.status_code == 422
+
+
+def test_unknown_tenant_is_not_found(client, factory):
+    admin = factory.user(role=UserRole.SUPER_ADMIN)
+
+    response = client.put("/tenant/0/features", json={"admin_id": admin.id, "features": {"reviews": True}})
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "TENANT_NOT_FOUND"
@@ -0,0 +1,67 @@
+from shared.auth import IMPERSONATION_CLAIM, decode_token
+from shared.models import AdminAction, UserRole
+
+
+def actions(db, tenant, action_type):
+    return db.query(AdminAction).filter(
+        AdminAction.target_id == tenant.id, AdminAction.action_type == action_type
+    ).all()
+
+
+def test_token_carries_impersonation_claim(client, db, factory):
+    admin = factory.user(role=UserRole.SUPER_ADMIN)
+    tenant = factory.tenant()
+    factory.user(tenant, role=UserRole.MANAGER)
+    owner = factory.user(tenant)
+
+    response = client.post(f"/tenant/{tenant.id}/impersonate", json={"admin_id": admin.id, "reason": "Ticket 42"})
+
+    assert response.status_code == 200
+    payload = decode_token(response.json()["access_token"])
+    assert payload[IMPERSONATION_CLAIM] == str(admin.id)
+    assert payload["sub"] == str(owner.id)
+    assert payload["role"] == UserRole.OWNER.value
+    assert payload["tenant_id"] == tenant.id
+    assert "refresh_token" not in response.json()
+
+
+def test_impersonation_start_is_audited(client, db, factory):
+    admin = factory.user(role=UserRole.SUPER_ADMIN)
+    tenant = factory.tenant()
+    owner = factory.user(tenant)
+
+    client.post(f"/tenant/{tenant.id}/impersonate", json={"admin_id": admin.id, "reason": "Ticket 42"})
+
+    action, = actions(db, tenant, "IMPERSONATION_STARTED")
+    assert action.admin_id == admin.id
+    assert action.details == f"As user {owner.id}: Ticket 42"
+
+
+def test_impersonated_request_is_recorded(client, db, factory):
+    admin = factory.user(role=UserRole.SUPER_ADMIN)
+    tenant = factory.tenant()
+
+    response = client.post("/actions/impersonated", json={
+        "admin_id": admin.id, "tenant_id": tenant.id, "method": "DELETE", "path": "/api/v1/masters/5", "status_code": 403
+    })
+
+    assert response.status_code == 201
+    action, = actions(db, tenant, "IMPERSONATED_REQUEST")
+    assert action.details == "DELETE /api/v1/masters/5 -> 403"
+
+
+def test_tenant_without_owner_cannot_be_impersonated(client, factory):
+    tenant = factory.tenant()
+    factory.user(tenant, role=UserRole.MANAGER)
+
+    response = client.post(f"/tenant/{tenant.id}/impersonate", json={"admin_id": 1})
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "USER_NOT_FOUND"
+
+
+def test_unknown_tenant_is_not_found(client):
+    response = client.post("/tenant/0/impersonate", json={"admin_id": 1})
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "TENANT_NOT_FOUND"
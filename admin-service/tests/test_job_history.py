@@ -0,0 +1,35 @@
+import pytest
+
+from shared.jobs.history import JobHistory
+
+
+@pytest.fixture
+def history(service, broker_url, monkeypatch):
+    history = JobHistory(broker_url)
+    monkeypatch.setattr(service, "list_jobs", history.list_jobs)
+    history.record_queued("job-1", "notifications.send_reminder", queued_at=1_700_000_000)
+    history.record_queued("job-2", "notifications.cleanup", queued_at=1_700_003_600)
+    history.record_status("job-2", "failed", error="ValueError: bad payload")
+    return history
+
+
+def test_jobs_filtered_by_status(client, history):
+    response = client.get("/jobs", params={"status": "failed"})
+
+    assert response.status_code == 200
+    job, = response.json()["jobs"]
+    assert job["id"] == "job-2"
+    assert job["error"] == "ValueError: bad payload"
+
+
+def test_jobs_filtered_by_queued_time(client, history):
+    response = client.get("/jobs", params={"until": "2023-11-14T22:30:00"})
+
+    assert [job["id"] for job in response.json()["jobs"]] == ["job-1"]
+
+
+def test_unknown_status_is_rejected(client, history):
+    response = client.get("/jobs", params={"status": "lost"})
+
+    assert response.status_code == 400
+    assert response.json()["details"]["status"] == ["queued", "started", "retrying", "succeeded", "failed"]
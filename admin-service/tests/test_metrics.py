@@ -0,0 +1,6 @@
+def test_pool_metrics_are_exported(client):
+    response = client.get("/metrics")
+
+    assert response.status_code == 200
+    assert 'db_pool_in_use{service="admin-service"}' in response.text
+    assert 'db_pool_wait_count{service="admin-service"}' in response.text
@@ -0,0 +1,59 @@
+import pytest
+from sqlalchemy.exc import OperationalError
+
+from shared.database import run_in_transaction
+from shared.models import TenantStatus
+
+
+class SerializationFailure(Exception):
+    pgcode = "40001"
+
+
+@pytest.mark.parametrize("action, expected", [
+    ("approve", TenantStatus.ACTIVE),
+    ("reject", TenantStatus.REJECTED)
+])
+def test_decision_sets_status(client, db, factory, action, expected):
+    tenant = factory.tenant(status=TenantStatus.PENDING)
+
+    response = client.put(f"/tenant/{tenant.id}/{action}")
+
+    assert response.status_code == 200
+    assert response.json()["status"] == expected.value
+    db.expire_all()
+    assert tenant.status == expected
+
+
+@pytest.mark.parametrize("action", ["approve", "reject"])
+def test_decision_of_unknown_tenant_is_not_found(client, action):
+    response = client.put(f"/tenant/0/{action}")
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "TENANT_NOT_FOUND"
+
+
+@pytest.mark.parametrize("action, expected", [
+    ("approve", TenantStatus.ACTIVE),
+    ("reject", TenantStatus.REJECTED)
+])
+def test_decision_is_retried_after_serialization_failure(client, db, factory, service, monkeypatch, action, expected):
+    tenant = factory.tenant(status=TenantStatus.PENDING)
+    attempts = []
+
+    def flaky(session, work, attempts_limit=None):
+        def once(session):
+            attempts.append(1)
+            if len(attempts) == 1:
+                raise OperationalError("UPDATE tenants", {}, SerializationFailure())
+            return work(session)
+        return run_in_transaction(session, once, attempts_limit)
+
+    monkeypatch.setattr(service, "run_in_transaction", flaky)
+    monkeypatch.setattr(service.settings, "DB_TX_RETRY_DELAY_MS", 0)
+
+    response = client.put(f"/tenant/{tenant.id}/{action}")
+
+    assert response.status_code == 200
+    assert len(attempts) == 2
+    db.expire_all()
+    assert tenant.status == expected
@@ -0,0 +1,69 @@
+import pytest
+
+from shared.models import TenantStatus
+
+
+@pytest.fixture
+def tenants(factory):
+    return {
+        name: factory.tenant(status=tenant_status, business_name=name)
+        for name, tenant_status in [
+            ("Zebra Nails Almaty", TenantStatus.ACTIVE),
+            ("Zebra Nails Astana", TenantStatus.ACTIVE),
+            ("Zebra Barbers", TenantStatus.SUSPENDED),
+            ("Zebra Spa", TenantStatus.PENDING),
+            ("Lotus Spa", TenantStatus.ACTIVE),
+        ]
+    }
+
+
+def list_tenants(client, **params):
+    response = client.get("/tenants", params={"sort_by": "business_name", "sort_order": "asc", **params})
+    assert response.status_code == 200
+    return response.json()
+
+
+def names(result):
+    return [t["business_name"] for t in result["tenants"]]
+
+
+def test_active_tenants_by_name_search(client, tenants):
+    result = list_tenants(client, status="ACTIVE", search="zebra")
+
+    assert names(result) == ["Zebra Nails Almaty", "Zebra Nails Astana"]
+    assert result["total"] == 2
+    # Counts cover every status of the searched tenants
+    assert result["status_counts"]["ACTIVE"] == 2
+    assert result["status_counts"]["SUSPENDED"] == 1
+    assert result["status_counts"]["PENDING"] == 1
+
+
+def test_all_statuses(client, tenants):
+    result = list_tenants(client, status="ALL", search="Zebra")
+
+    assert names(result) == ["Zebra Barbers", "Zebra Nails Almaty", "Zebra Nails Astana", "Zebra Spa"]
+
+
+def test_pending_by_default(client, tenants):
+    assert names(list_tenants(client, search="Zebra")) == ["Zebra Spa"]
+
+
+def test_search_is_paginated(client, tenants):
+    result = list_tenants(client, status="ALL", search="Zebra", page=2, page_size=3)
+
+    assert names(result) == ["Zebra Spa"]
+    assert (result["total"], result["pages"]) == (4, 2)
+
+
+def test_search_wildcards_are_literal(client, factory, tenants):
+    factory.tenant(status=TenantStatus.ACTIVE, business_name="Zebra 100% Nails")
+
+    assert names(list_tenants(client, status="ACTIVE", search="Zebra 100%")) == ["Zebra 100% Nails"]
+    assert names(list_tenants(client, status="ACTIVE", search="Zebra_Nails")) == []
+
+
+def test_unknown_status_is_rejected(client):
+    response = client.get("/tenants", params={"status": "DELETED"})
+
+    assert response.status_code == 400
+    assert response.json()["error"] == "BAD_REQUEST"
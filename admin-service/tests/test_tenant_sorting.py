@@ -0,0 +1,51 @@
+import pytest
+
+from shared.models import Tenant, TenantStatus
+
+
+@pytest.fixture
+def pending(factory):
+    return [
+        factory.tenant(status=TenantStatus.PENDING, business_name=name)
+        for name in ("Beta Salon", "Alpha Salon", "Gamma Salon")
+    ]
+
+
+def names(response, tenants):
+    ids = {t.id for t in tenants}
+    return [t["business_name"] for t in response.json()["tenants"] if t["id"] in ids]
+
+
+def test_sorted_by_whitelisted_column(client, pending):
+    response = client.get("/tenants/pending", params={"sort_by": "business_name", "sort_order": "asc"})
+
+    assert response.status_code == 200
+    assert names(response, pending) == ["Alpha Salon", "Beta Salon", "Gamma Salon"]
+
+
+def test_descending_order(client, pending):
+    response = client.get("/tenants/pending", params={"sort_by": "business_name", "sort_order": "desc"})
+
+    assert names(response, pending) == ["Gamma Salon", "Beta Salon", "Alpha Salon"]
+
+
+@pytest.mark.parametrize("params", [
+    {"sort_by": "created_at; DROP TABLE tenants; --"},
+    {"sort_by": "(SELECT password_hash FROM users LIMIT 1)"},
+    {"sort_by": "id"},
+    {"sort_order": "desc, (SELECT 1)"},
+])
+def test_malicious_sort_is_rejected(client, db, pending, params):
+    response = client.get("/tenants/pending", params=params)
+
+    assert response.status_code == 400
+    assert response.json()["error"] == "BAD_REQUEST"
+    assert response.json()["details"]["sort_by"] == ["created_at", "business_name", "subdomain"]
+    # Nothing was executed: the tenants are still there
+    assert db.query(Tenant).filter(Tenant.id.in_([t.id for t in pending])).count() == 3
+
+
+def test_tenants_list_rejects_malicious_sort(client, pending):
+    response = client.get("/tenants", params={"status": "ALL", "sort_by": "1; DELETE FROM tenants"})
+
+    assert response.status_code == 400
@@ -1,15 +1,22 @@
-from fastapi import FastAPI, Depends, HTTPException, status, Request
-from fastapi.middleware.cors import CORSMiddleware
+from fastapi import FastAPI
 from fastapi.responses import JSONResponse
+from fastapi.middleware.cors import CORSMiddleware
+import asyncio
 import httpx
 import logging
-from typing import Optional
+from typing import List, Optional
 
 from shared.config import settings
 from shared.auth import decode_token
+from shared.api import register_exception_handlers, request_id_middleware
+from shared.i18n import get_supported_languages, get_missing_translation_counts, verify_translation_coverage
+from shared.calendar import supported_timezones
 from middleware.auth import get_current_user
 from middleware.rate_limit import rate_limit_middleware
-from routes import auth, booking, admin
+from middleware.limits import BodySizeLimitMiddleware, request_timeout_middleware
+from middleware.impersonation import impersonation_audit_middleware
+from routes import auth, booking, admin, client, payment, events
+from utils import CallKind, upstream_timeout
 
 # Configure logging
 logging.basicConfig(
@@ -27,31 +34,141 @@ app = FastAPI(
     redoc_url="/api/redoc"
 )
 
+
+def check_cors_settings(origins: List[str], allow_credentials: bool) -> None:
+    """
+    Refuse to start with credentialed requests allowed from any origin.
+
+    Any site could then make requests with the user's cookies and read
+    the responses, so origins must be listed explicitly.
+    """
+    if allow_credentials and "*" in origins:
+        raise RuntimeError(
+            "CORS_ORIGINS=* cannot be used with CORS_ALLOW_CREDENTIALS=true, list the allowed origins"
+        )
+
+
 # CORS middleware
+check_cors_settings(settings.cors_origins_list, settings.CORS_ALLOW_CREDENTIALS)
 app.add_middleware(
     CORSMiddleware,
     allow_origins=settings.cors_origins_list,
-    allow_credentials=True,
+    allow_credentials=settings.CORS_ALLOW_CREDENTIALS,
     allow_methods=["*"],
     allow_headers=["*"],
+    max_age=settings.CORS_MAX_AGE_SECONDS,
 )
 
+# Request body size limit
+app.add_middleware(BodySizeLimitMiddleware, max_body_size=settings.MAX_REQUEST_BODY_BYTES)
+
+# Audit of requests made by admins impersonating a tenant
+app.middleware("http")(impersonation_audit_middleware)
+
+# Request timeout middleware
+app.middleware("http")(request_timeout_middleware)
+
 # Rate limiting middleware
 app.middleware("http")(rate_limit_middleware)
 
+# Request ID middleware (outermost, so every response carries the ID)
+app.middleware("http")(request_id_middleware)
+
+# Standard error envelope
+register_exception_handlers(app)
+
 # Include routers
 app.include_router(auth.router, prefix="/api/v1", tags=["Authentication"])
 app.include_router(booking.router, prefix="/api/v1", tags=["Booking"])
+app.include_router(client.router, prefix="/api/v1", tags=["Client"])
+app.include_router(payment.router, prefix="/api/v1", tags=["Payment"])
+app.include_router(events.router, prefix="/api/v1", tags=["Events"])
 app.include_router(admin.router, prefix="/api/v1/admin", tags=["Admin"])
 
 
+@app.on_event("startup")
+async def startup_event():
+    """Initialize on startup."""
+    logger.info("Starting API Gateway...")
+    verify_translation_coverage()
+
+
 @app.get("/health")
 async def health_check():
     """Health check endpoint."""
     return {
         "status": "healthy",
         "service": "api-gateway",
-        "version": "2.0.0"
+        "version": "2.0.0",
+        "missing_translations": get_missing_translation_counts()
+    }
+
+
+# Backends the gateway needs to serve requests
+BACKEND_HEALTH_URLS = {
+    "user-service": f"{auth.USER_SERVICE_URL}/health",
+    "booking-service": f"{booking.BOOKING_SERVICE_URL}/health",
+    "notification-service": f"{settings.NOTIFICATION_SERVICE_URL}/health",
+    "payment-service": f"{payment.PAYMENT_SERVICE_URL}/health",
+    "admin-service": f"{admin.ADMIN_SERVICE_URL}/health",
+}
+
+
+async def check_backend(client: httpx.AsyncClient, url: str) -> str:
+    """SERVING if the backend reports itself healthy, NOT_SERVING otherwise."""
+    try:
+        response = await client.get(url, timeout=upstream_timeout(CallKind.FAST))
+        if response.status_code == 200 and response.json().get("status") == "healthy":
+            return "SERVING"
+    except (httpx.RequestError, ValueError) as e:
+        logger.warning(f"Health check of {url} failed: {e}")
+    return "NOT_SERVING"
+
+
+@app.get("/readyz")
+async def readiness_check():
+    """
+    Readiness check endpoint.
+
+    Checks the health of every backend concurrently; returns 503 if any of
+    them is not serving. Probes use a plain client without retries or
+    replica balancing, so a failing backend is reported, not retried around.
+    """
+    async with httpx.AsyncClient() as client:
+        statuses = await asyncio.gather(*(check_backend(client, url) for url in BACKEND_HEALTH_URLS.values()))
+
+    services = dict(zip(BACKEND_HEALTH_URLS, statuses))
+    ready = all(s == "SERVING" for s in statuses)
+    return JSONResponse(
+        status_code=200 if ready else 503,
+        content={"status": "ready" if ready else "not_ready", "services": services}
+    )
+
+
+@app.get("/api/v1/languages")
+async def get_languages():
+    """
+    Get supported languages.
+
+    Language can be chosen with ?lang=, the X-Language header or
+    Accept-Language, in this order.
+    """
+    return {
+        "default": settings.DEFAULT_LANGUAGE,
+        "languages": get_supported_languages()
+    }
+
+
+@app.get("/api/v1/public/timezones")
+async def get_timezones():
+    """
+    Get IANA timezones accepted for a business, e.g. "Asia/Almaty".
+
+    Other values are rejected on registration and settings update.
+    """
+    return {
+        "default": settings.DEFAULT_TIMEZONE,
+        "timezones": supported_timezones()
     }
 
 
@@ -65,31 +182,6 @@ async def root():
     }
 
 
-@app.exception_handler(HTTPException)
-async def http_exception_handler(request: Request, exc: HTTPException):
-    """Custom HTTP exception handler."""
-    return JSONResponse(
-        status_code=exc.status_code,
-        content={
-            "error": exc.detail,
-            "status_code": exc.status_code
-        }
-    )
-
-
-@app.exception_handler(Exception)
-async def general_exception_handler(request: Request, exc: Exception):
-    """General exception handler."""
-    logger.error(f"Unhandled exception: {exc}", exc_info=True)
-    return JSONResponse(
-        status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-        content={
-            "error": "Internal server error",
-            "status_code": 500
-        }
-    )
-
-
 if __name__ == "__main__":
     import uvicorn
 
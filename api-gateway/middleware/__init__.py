@@ -1,10 +1,23 @@
-from .auth import get_current_user, get_current_active_user, require_role, get_optional_user
+from .auth import (
+    get_current_user, get_current_active_user, require_role, require_admin, get_optional_user,
+    is_impersonated, deny_impersonation
+)
 from .rate_limit import rate_limit_middleware
+from .impersonation import impersonation_audit_middleware
+from .captcha import require_captcha
+from .limits import BodySizeLimitMiddleware, request_timeout_middleware
 
 __all__ = [
     "get_current_user",
     "get_current_active_user",
     "require_role",
+    "require_admin",
     "get_optional_user",
-    "rate_limit_middleware"
+    "is_impersonated",
+    "deny_impersonation",
+    "rate_limit_middleware",
+    "impersonation_audit_middleware",
+    "require_captcha",
+    "BodySizeLimitMiddleware",
+    "request_timeout_middleware"
 ]
@@ -1,10 +1,12 @@
-from fastapi import Depends, HTTPException, status
+from fastapi import Depends, Query, status
 from fastapi.security import HTTPBearer, HTTPAuthorizationCredentials
 from typing import Optional, Dict
 import logging
 
 from shared.auth import decode_token
 from shared.models import UserRole
+from shared.auth import ADMIN_SCOPE, IMPERSONATION_CLAIM
+from shared.api import APIError, ErrorCode
 
 logger = logging.getLogger(__name__)
 
@@ -18,23 +20,46 @@ async def get_current_user(
     Dependency to get current authenticated user from JWT token.
 
     Returns user data from token payload.
-    Raises HTTPException if token is invalid.
+    Raises APIError if token is invalid.
     """
     token = credentials.credentials
 
     payload = decode_token(token)
     if not payload:
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_401_UNAUTHORIZED,
-            detail="Invalid authentication credentials",
+            code=ErrorCode.INVALID_TOKEN,
             headers={"WWW-Authenticate": "Bearer"},
         )
 
     # Check token type
     if payload.get("type") != "access":
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_401_UNAUTHORIZED,
-            detail="Invalid token type",
+            code=ErrorCode.INVALID_TOKEN,
+            headers={"WWW-Authenticate": "Bearer"},
+        )
+
+    return payload
+
+
+async def get_stream_user(
+    credentials: Optional[HTTPAuthorizationCredentials] = Depends(HTTPBearer(auto_error=False)),
+    access_token: Optional[str] = Query(None)
+) -> Dict:
+    """
+    Dependency to authenticate streaming endpoints.
+
+    Accepts the bearer header or, for EventSource clients that can't set
+    headers, the access_token query parameter.
+    """
+    token = credentials.credentials if credentials else access_token
+
+    payload = decode_token(token) if token else None
+    if not payload or payload.get("type") != "access":
+        raise APIError(
+            status_code=status.HTTP_401_UNAUTHORIZED,
+            code=ErrorCode.INVALID_TOKEN,
             headers={"WWW-Authenticate": "Bearer"},
         )
 
@@ -63,9 +88,9 @@ def require_role(*allowed_roles: UserRole):
         user_role = current_user.get("role")
 
         if user_role not in [role.value for role in allowed_roles]:
-            raise HTTPException(
+            raise APIError(
                 status_code=status.HTTP_403_FORBIDDEN,
-                detail="Insufficient permissions"
+                code=ErrorCode.FORBIDDEN
             )
 
         return current_user
@@ -73,6 +98,52 @@ def require_role(*allowed_roles: UserRole):
     return role_checker
 
 
+async def require_admin(current_user: Dict = Depends(get_current_user)) -> Dict:
+    """
+    Dependency to require a SUPER_ADMIN token issued by admin login.
+
+    Regular login tokens of SUPER_ADMIN users lack the admin scope
+    and are rejected.
+    """
+    if current_user.get("role") != UserRole.SUPER_ADMIN.value:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    if current_user.get("scope") != ADMIN_SCOPE:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.ADMIN_SCOPE_REQUIRED
+        )
+
+    return current_user
+
+
+def is_impersonated(current_user: Dict) -> bool:
+    """Check if the token was issued to a super admin impersonating a tenant."""
+    return bool(current_user.get(IMPERSONATION_CLAIM))
+
+
+async def deny_impersonation(current_user: Dict = Depends(get_current_user)) -> Dict:
+    """
+    Dependency to reject impersonation tokens.
+
+    Used on destructive or account-level operations support staff must
+    not perform on behalf of a tenant.
+    """
+    if is_impersonated(current_user):
+        logger.warning(
+            f"Admin {current_user.get(IMPERSONATION_CLAIM)} denied action as user {current_user.get('sub')}"
+        )
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.IMPERSONATION_FORBIDDEN
+        )
+
+    return current_user
+
+
 async def get_optional_user(
     credentials: Optional[HTTPAuthorizationCredentials] = Depends(HTTPBearer(auto_error=False))
 ) -> Optional[Dict]:
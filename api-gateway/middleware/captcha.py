@@ -0,0 +1,88 @@
+from fastapi import Header, Request, status
+from typing import Optional
+import httpx
+import logging
+
+from shared.config import settings
+from shared.api import APIError, ErrorCode
+
+logger = logging.getLogger(__name__)
+
+# Header carrying the CAPTCHA response token from the widget
+CAPTCHA_HEADER = "X-Captcha-Token"
+
+# Server-side verification endpoints by CAPTCHA_PROVIDER
+CAPTCHA_VERIFY_URLS = {
+    "hcaptcha": "https://api.hcaptcha.com/siteverify",
+    "recaptcha": "https://www.google.com/recaptcha/api/siteverify",
+    "turnstile": "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+
+async def verify_captcha(token: str, remote_ip: Optional[str] = None) -> bool:
+    """
+    Verify CAPTCHA response token with the provider.
+
+    All supported providers share the siteverify protocol: form-encoded
+    secret, response and remoteip, answered with {"success": bool}.
+    """
+    form = {"secret": settings.CAPTCHA_SECRET_KEY, "response": token}
+    if remote_ip:
+        form["remoteip"] = remote_ip
+
+    async with httpx.AsyncClient() as client:
+        response = await client.post(
+            CAPTCHA_VERIFY_URLS[settings.CAPTCHA_PROVIDER],
+            data=form,
+            timeout=settings.CAPTCHA_VERIFY_TIMEOUT_SECONDS
+        )
+        response.raise_for_status()
+        result = response.json()
+
+    if not result.get("success"):
+        logger.info(f"CAPTCHA rejected: {result.get('error-codes')}")
+        return False
+
+    return True
+
+
+async def require_captcha(
+    request: Request,
+    captcha_token: Optional[str] = Header(None, alias=CAPTCHA_HEADER)
+):
+    """
+    Dependency to require a valid CAPTCHA token on public endpoints open to bots.
+
+    Does nothing unless CAPTCHA_PROVIDER is set. Verification failures of
+    the provider itself reject the request (fail closed).
+    """
+    if not settings.CAPTCHA_PROVIDER:
+        return
+
+    if settings.CAPTCHA_PROVIDER not in CAPTCHA_VERIFY_URLS:
+        logger.error(f"Unknown CAPTCHA provider: {settings.CAPTCHA_PROVIDER}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+    if not captcha_token:
+        raise APIError(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            code=ErrorCode.CAPTCHA_REQUIRED
+        )
+
+    try:
+        valid = await verify_captcha(captcha_token, request.client.host if request.client else None)
+    except (httpx.HTTPError, ValueError) as e:
+        logger.error(f"CAPTCHA verification failed: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+    if not valid:
+        raise APIError(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            code=ErrorCode.CAPTCHA_FAILED
+        )
@@ -0,0 +1,64 @@
+from fastapi import Request
+import httpx
+import logging
+
+from shared.auth import decode_token, IMPERSONATION_CLAIM
+from shared.config import settings
+from shared.api import request_id_headers
+from utils import upstream_client, upstream_timeout, CallKind
+
+logger = logging.getLogger(__name__)
+
+# Admin service URL
+ADMIN_SERVICE_URL = f"http://admin-service:{settings.ADMIN_SERVICE_PORT if hasattr(settings, 'ADMIN_SERVICE_PORT') else 8005}"
+
+
+def get_impersonation(request: Request):
+    """Get payload of the request's bearer token if it's an impersonation token."""
+    authorization = request.headers.get("authorization", "")
+    scheme, _, token = authorization.partition(" ")
+
+    if scheme.lower() != "bearer" or not token:
+        return None
+
+    payload = decode_token(token)
+    if not payload or payload.get("type") != "access" or not payload.get(IMPERSONATION_CLAIM):
+        return None
+
+    return payload
+
+
+async def impersonation_audit_middleware(request: Request, call_next):
+    """
+    Record every request made with an impersonation token in admin actions.
+
+    The admin ID is exposed as request.state.impersonated_by. Failing to
+    record the action is logged and doesn't fail the request.
+    """
+    payload = get_impersonation(request)
+    request.state.impersonated_by = payload.get(IMPERSONATION_CLAIM) if payload else None
+
+    response = await call_next(request)
+
+    if payload:
+        try:
+            async with upstream_client(headers=request_id_headers()) as client:
+                audit = await client.post(
+                    f"{ADMIN_SERVICE_URL}/actions/impersonated",
+                    json={
+                        "admin_id": payload[IMPERSONATION_CLAIM],
+                        "tenant_id": payload.get("tenant_id"),
+                        "method": request.method,
+                        "path": request.url.path,
+                        "status_code": response.status_code
+                    },
+                    timeout=upstream_timeout(CallKind.FAST)
+                )
+                audit.raise_for_status()
+        except httpx.HTTPError as e:
+            logger.error(
+                f"Failed to record impersonated request {request.method} {request.url.path} "
+                f"of admin {payload[IMPERSONATION_CLAIM]}: {e}"
+            )
+
+    return response
@@ -0,0 +1,98 @@
+from fastapi import Request, status
+from starlette.types import ASGIApp, Message, Receive, Scope, Send
+import asyncio
+import logging
+
+from shared.config import settings
+from shared.api import APIError, ErrorCode, error_response, get_request_id
+from utils import request_timeout
+
+logger = logging.getLogger(__name__)
+
+
+class BodySizeLimitMiddleware:
+    """
+    Request body size limit middleware.
+
+    Rejects requests whose Content-Length exceeds the limit upfront and
+    stops reading chunked bodies as soon as the limit is crossed.
+    """
+
+    def __init__(self, app: ASGIApp, max_body_size: int):
+        self.app = app
+        self.max_body_size = max_body_size
+
+    async def __call__(self, scope: Scope, receive: Receive, send: Send):
+        if scope["type"] != "http":
+            return await self.app(scope, receive, send)
+
+        headers = dict(scope.get("headers", []))
+        content_length = headers.get(b"content-length")
+
+        if content_length and content_length.isdigit() and int(content_length) > self.max_body_size:
+            return await self._reject(scope, receive, send)
+
+        received = 0
+        response_started = False
+
+        async def limited_receive() -> Message:
+            nonlocal received
+            message = await receive()
+
+            if message["type"] == "http.request":
+                received += len(message.get("body", b""))
+                if received > self.max_body_size:
+                    # HTTPException subclass, so FastAPI re-raises it from body parsing
+                    raise APIError(
+                        status_code=status.HTTP_413_REQUEST_ENTITY_TOO_LARGE,
+                        code=ErrorCode.PAYLOAD_TOO_LARGE,
+                        details={"max_body_bytes": self.max_body_size}
+                    )
+
+            return message
+
+        async def tracked_send(message: Message):
+            nonlocal response_started
+            if message["type"] == "http.response.start":
+                response_started = True
+            await send(message)
+
+        try:
+            await self.app(scope, limited_receive, tracked_send)
+        except APIError as e:
+            if response_started or e.code != ErrorCode.PAYLOAD_TOO_LARGE:
+                raise
+            await self._reject(scope, receive, send)
+
+    async def _reject(self, scope: Scope, receive: Receive, send: Send):
+        response = error_response(
+            Request(scope),
+            status.HTTP_413_REQUEST_ENTITY_TOO_LARGE,
+            ErrorCode.PAYLOAD_TOO_LARGE,
+            details={"max_body_bytes": self.max_body_size}
+        )
+        await response(scope, receive, send)
+
+
+async def request_timeout_middleware(request: Request, call_next):
+    """
+    Request timeout middleware.
+
+    Cancels request handling (including pending downstream calls)
+    when it takes longer than REQUEST_TIMEOUT_SECONDS, or the bulk call
+    timeout for routes making bulk calls.
+    """
+    timeout = request_timeout(request.url.path)
+    try:
+        return await asyncio.wait_for(call_next(request), timeout=timeout)
+    except asyncio.TimeoutError:
+        logger.warning(
+            f"Request timed out after {timeout}s: "
+            f"{request.method} {request.url.path} (request_id={get_request_id(request)})"
+        )
+        return error_response(
+            request,
+            status.HTTP_408_REQUEST_TIMEOUT,
+            ErrorCode.REQUEST_TIMEOUT,
+            details={"timeout_seconds": timeout}
+        )
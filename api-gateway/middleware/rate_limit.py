@@ -1,22 +1,72 @@
-from fastapi import Request, HTTPException, status
-from fastapi.responses import JSONResponse
+from fastapi import Request, status
+from typing import List, Optional, Tuple
 import time
 import logging
 
-from shared.cache import redis_client
+from shared.auth import decode_token
+from shared.cache import redis_client, CacheError
 from shared.config import settings
+from shared.api import ErrorCode, error_response
 
 logger = logging.getLogger(__name__)
 
+# Length of the fixed rate limit window
+WINDOW_SECONDS = 60
+
+
+def user_limit(request: Request) -> Optional[Tuple[str, int]]:
+    """
+    Get user ID and per-minute limit of the authenticated user.
+
+    None for anonymous requests or if per-user limits are off for the role.
+    """
+    authorization = request.headers.get("authorization", "")
+    scheme, _, token = authorization.partition(" ")
+    if scheme.lower() != "bearer" or not token:
+        return None
+
+    payload = decode_token(token)
+    if not payload or payload.get("type") != "access":
+        return None
+
+    limit = settings.rate_limit_roles_map.get(payload.get("role"), settings.RATE_LIMIT_PER_USER_PER_MINUTE)
+    if limit <= 0:
+        return None
+
+    return payload.get("sub"), limit
+
+
+def request_limits(request: Request, client_ip: str) -> List[Tuple[str, str, int]]:
+    """
+    Get limits applying to the request as (scope, key, limit per minute).
+
+    The per-IP limit always applies; stricter route limits and per-user
+    limits are layered on top of it.
+    """
+    limits = [("ip", f"rate_limit:{client_ip}", settings.RATE_LIMIT_PER_MINUTE)]
+
+    route_limit = settings.rate_limit_routes_map.get(request.url.path)
+    if route_limit:
+        limits.append(("route", f"rate_limit:route:{request.url.path}:{client_ip}", route_limit))
+
+    user = user_limit(request)
+    if user:
+        user_id, limit = user
+        limits.append(("user", f"rate_limit:user:{user_id}", limit))
+
+    return limits
+
 
 async def rate_limit_middleware(request: Request, call_next):
     """
     Rate limiting middleware using Redis.
 
-    Implements sliding window rate limiting per IP address.
+    Counts requests per fixed one-minute window for each limit applying to
+    the request. Rejected requests get 429 with Retry-After set to the end
+    of the window.
     """
     # Skip rate limiting for health checks
-    if request.url.path in ["/health", "/api/docs", "/api/redoc", "/openapi.json"]:
+    if request.url.path in ["/health", "/readyz", "/api/docs", "/api/redoc", "/openapi.json"]:
         return await call_next(request)
 
     # Get client IP
@@ -24,31 +74,32 @@ async def rate_limit_middleware(request: Request, call_next):
     if not client_ip:
         return await call_next(request)
 
-    # Build rate limit key
-    key = f"rate_limit:{client_ip}"
-    current_minute = int(time.time() / 60)
-    rate_limit_key = f"{key}:{current_minute}"
+    now = time.time()
+    current_window = int(now / WINDOW_SECONDS)
 
     try:
-        # Get current request count
-        current_count = redis_client.get(rate_limit_key)
-
-        if current_count is None:
-            # First request in this minute
-            redis_client.set(rate_limit_key, 1, expire=60)
-        else:
-            # Increment counter
-            if int(current_count) >= settings.RATE_LIMIT_PER_MINUTE:
-                return JSONResponse(
-                    status_code=status.HTTP_429_TOO_MANY_REQUESTS,
-                    content={
-                        "error": "Rate limit exceeded",
-                        "detail": f"Maximum {settings.RATE_LIMIT_PER_MINUTE} requests per minute"
-                    }
-                )
+        for scope, key, limit in request_limits(request, client_ip):
+            window_key = f"{key}:{current_window}"
+            count = redis_client.increment(window_key)
 
-            redis_client.incr(rate_limit_key)
+            if count == 1:
+                redis_client.expire(window_key, WINDOW_SECONDS)
+
+            if count > limit:
+                retry_after = max(int((current_window + 1) * WINDOW_SECONDS - now), 1)
+                logger.warning(f"Rate limit exceeded: scope={scope}, ip={client_ip}, path={request.url.path}")
+                return error_response(
+                    request,
+                    status.HTTP_429_TOO_MANY_REQUESTS,
+                    ErrorCode.RATE_LIMITED,
+                    details={"limit_per_minute": limit, "scope": scope},
+                    headers={"Retry-After": str(retry_after)}
+                )
 
+    except CacheError:
+        # Redis is down: allow the request, the failure is counted in
+        # cache_errors_total by the cache client
+        logger.warning(f"Rate limit not checked, Redis unavailable: ip={client_ip}, path={request.url.path}")
     except Exception as e:
         logger.error(f"Rate limit check failed: {e}")
         # If Redis fails, allow request to proceed
@@ -1,12 +1,15 @@
-from fastapi import APIRouter, HTTPException, status, Depends
-from pydantic import BaseModel
-from typing import Optional
+from fastapi import APIRouter, status, Depends, Query
+from pydantic import BaseModel, EmailStr, Field
+from typing import Dict, Optional
+from datetime import datetime
 import httpx
 import logging
 
 from shared.config import settings
-from shared.models import UserRole
-from middleware.auth import require_role
+from shared.api import APIError, ErrorCode, request_id_headers
+from shared.billing import Feature
+from utils import raise_for_upstream, upstream_client, upstream_timeout, CallKind
+from middleware.auth import require_admin
 
 logger = logging.getLogger(__name__)
 
@@ -15,142 +18,436 @@ router = APIRouter()
 # Admin service URL
 ADMIN_SERVICE_URL = f"http://admin-service:{settings.ADMIN_SERVICE_PORT if hasattr(settings, 'ADMIN_SERVICE_PORT') else 8005}"
 
+# Payment service URL
+PAYMENT_SERVICE_URL = f"http://payment-service:{settings.PAYMENT_SERVICE_PORT if hasattr(settings, 'PAYMENT_SERVICE_PORT') else 8004}"
+
+
+# Request models
+class AdminLoginRequest(BaseModel):
+    email: EmailStr
+    password: str
+
+
+class RecordSubscriptionRequest(BaseModel):
+    period_days: int = Field(..., gt=0)
+    reference: Optional[str] = None
+
+
+class ImpersonateTenantRequest(BaseModel):
+    reason: Optional[str] = None
+
+
+class TenantFeaturesRequest(BaseModel):
+    features: Dict[Feature, bool]
+
+
+@router.post("/login")
+async def admin_login(data: AdminLoginRequest):
+    """
+    Login platform administrator.
+
+    Tokens are issued only to SUPER_ADMIN users and are required
+    for all other admin endpoints.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.post(
+                f"{ADMIN_SERVICE_URL}/login",
+                json=data.dict(),
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to admin service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
 
 @router.get("/tenants")
-async def get_pending_tenants(
-    current_user: dict = Depends(require_role(UserRole.SUPER_ADMIN))
+async def list_tenants(
+    status_filter: str = Query("PENDING", alias="status"),
+    search: Optional[str] = Query(None, max_length=200),
+    sort_by: str = "created_at",
+    sort_order: str = "desc",
+    page: int = Query(1, ge=1),
+    page_size: int = Query(50, ge=1, le=200),
+    current_user: dict = Depends(require_admin)
 ):
     """
-    Get all pending tenant applications.
+    Get tenants of a status, pending applications by default (ALL for
+    every status), optionally searched by business name. Paginated, with
+    counts by status.
 
-    Only accessible by SUPER_ADMIN.
+    Only accessible with an admin login token.
     """
+    params = {
+        "status": status_filter,
+        "sort_by": sort_by,
+        "sort_order": sort_order,
+        "page": page,
+        "page_size": page_size
+    }
+    if search:
+        params["search"] = search
+
     try:
-        async with httpx.AsyncClient() as client:
+        async with upstream_client(headers=request_id_headers()) as client:
             response = await client.get(
-                f"{ADMIN_SERVICE_URL}/tenants/pending",
-                timeout=10.0
+                f"{ADMIN_SERVICE_URL}/tenants",
+                params=params,
+                timeout=upstream_timeout(CallKind.READ)
             )
 
-            if response.status_code == 200:
-                return response.json()
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="Admin service error"
-                )
+            raise_for_upstream(response)
+            return response.json()
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to admin service: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="Admin service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
 
 
 @router.put("/tenant/{tenant_id}/approve")
 async def approve_tenant(
     tenant_id: int,
-    current_user: dict = Depends(require_role(UserRole.SUPER_ADMIN))
+    current_user: dict = Depends(require_admin)
 ):
     """
     Approve tenant application.
 
-    Only accessible by SUPER_ADMIN.
+    Only accessible with an admin login token.
     """
     try:
-        async with httpx.AsyncClient() as client:
+        async with upstream_client(headers=request_id_headers()) as client:
             response = await client.put(
                 f"{ADMIN_SERVICE_URL}/tenant/{tenant_id}/approve",
-                timeout=10.0
+                timeout=upstream_timeout(CallKind.WRITE)
             )
 
-            if response.status_code == 200:
-                return response.json()
-            elif response.status_code == 404:
-                raise HTTPException(
-                    status_code=status.HTTP_404_NOT_FOUND,
-                    detail="Tenant not found"
-                )
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="Admin service error"
-                )
+            raise_for_upstream(response, not_found=ErrorCode.TENANT_NOT_FOUND)
+            return response.json()
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to admin service: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="Admin service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
 
 
 @router.put("/tenant/{tenant_id}/reject")
 async def reject_tenant(
     tenant_id: int,
-    current_user: dict = Depends(require_role(UserRole.SUPER_ADMIN))
+    current_user: dict = Depends(require_admin)
 ):
     """
     Reject tenant application.
 
-    Only accessible by SUPER_ADMIN.
+    Only accessible with an admin login token.
     """
     try:
-        async with httpx.AsyncClient() as client:
+        async with upstream_client(headers=request_id_headers()) as client:
             response = await client.put(
                 f"{ADMIN_SERVICE_URL}/tenant/{tenant_id}/reject",
-                timeout=10.0
+                timeout=upstream_timeout(CallKind.WRITE)
             )
 
-            if response.status_code == 200:
-                return response.json()
-            elif response.status_code == 404:
-                raise HTTPException(
-                    status_code=status.HTTP_404_NOT_FOUND,
-                    detail="Tenant not found"
-                )
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="Admin service error"
-                )
+            raise_for_upstream(response, not_found=ErrorCode.TENANT_NOT_FOUND)
+            return response.json()
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to admin service: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="Admin service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/tenant/{tenant_id}/features")
+async def get_tenant_features(
+    tenant_id: int,
+    current_user: dict = Depends(require_admin)
+):
+    """
+    Get features of a tenant with whether each is enabled.
+
+    Only accessible with an admin login token.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{ADMIN_SERVICE_URL}/tenant/{tenant_id}/features",
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TENANT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to admin service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.put("/tenant/{tenant_id}/features")
+async def update_tenant_features(
+    tenant_id: int,
+    data: TenantFeaturesRequest,
+    current_user: dict = Depends(require_admin)
+):
+    """
+    Enable or disable features of a tenant: reviews, deposits, multi_location.
+
+    Only accessible with an admin login token.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.put(
+                f"{ADMIN_SERVICE_URL}/tenant/{tenant_id}/features",
+                json={
+                    "admin_id": current_user.get("sub"),
+                    "features": {feature.value: enabled for feature, enabled in data.features.items()}
+                },
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TENANT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to admin service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.post("/tenant/{tenant_id}/impersonate")
+async def impersonate_tenant(
+    tenant_id: int,
+    data: ImpersonateTenantRequest,
+    current_user: dict = Depends(require_admin)
+):
+    """
+    Get a short-lived token to act as the tenant's owner for support.
+
+    Only accessible with an admin login token. Every request made with
+    the token is recorded in admin actions; payments, password change and
+    booking cancellation are not allowed with it.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.post(
+                f"{ADMIN_SERVICE_URL}/tenant/{tenant_id}/impersonate",
+                json={"admin_id": current_user.get("sub"), "reason": data.reason},
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TENANT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to admin service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.post("/tenant/{tenant_id}/resend-decision-email")
+async def resend_tenant_decision_email(
+    tenant_id: int,
+    current_user: dict = Depends(require_admin)
+):
+    """
+    Resend the approval or rejection email of the tenant, by its current
+    status.
+
+    Only accessible with an admin login token. Recorded in admin actions.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.post(
+                f"{ADMIN_SERVICE_URL}/tenant/{tenant_id}/resend-decision-email",
+                json={"admin_id": current_user.get("sub")},
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TENANT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to admin service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
 
 
 @router.get("/statistics")
 async def get_statistics(
-    current_user: dict = Depends(require_role(UserRole.SUPER_ADMIN))
+    current_user: dict = Depends(require_admin)
 ):
     """
     Get platform statistics.
 
-    Only accessible by SUPER_ADMIN.
+    Only accessible with an admin login token.
     """
     try:
-        async with httpx.AsyncClient() as client:
+        async with upstream_client(headers=request_id_headers()) as client:
             response = await client.get(
                 f"{ADMIN_SERVICE_URL}/statistics",
-                timeout=10.0
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to admin service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/jobs/stats")
+async def get_job_stats(
+    current_user: dict = Depends(require_admin)
+):
+    """
+    Get background job counters and queue depth.
+
+    Only accessible with an admin login token.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{ADMIN_SERVICE_URL}/jobs/stats",
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to admin service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/jobs")
+async def get_jobs(
+    type: Optional[str] = Query(None),
+    status_filter: Optional[str] = Query(None, alias="status"),
+    since: Optional[datetime] = Query(None),
+    until: Optional[datetime] = Query(None),
+    limit: int = Query(100, ge=1, le=500),
+    current_user: dict = Depends(require_admin)
+):
+    """
+    Get recent background jobs with their statuses, newest first.
+
+    Only accessible with an admin login token.
+    """
+    params = {"limit": limit}
+    if type:
+        params["type"] = type
+    if status_filter:
+        params["status"] = status_filter
+    if since:
+        params["since"] = since.isoformat()
+    if until:
+        params["until"] = until.isoformat()
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{ADMIN_SERVICE_URL}/jobs",
+                params=params,
+                timeout=upstream_timeout(CallKind.READ)
             )
 
-            if response.status_code == 200:
-                return response.json()
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="Admin service error"
-                )
+            raise_for_upstream(response)
+            return response.json()
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to admin service: {e}")
-        raise HTTPException(
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.post("/tenant/{tenant_id}/subscription", status_code=status.HTTP_201_CREATED)
+async def record_subscription(
+    tenant_id: int,
+    data: RecordSubscriptionRequest,
+    current_user: dict = Depends(require_admin)
+):
+    """
+    Record paid subscription period for a tenant.
+
+    Only accessible with an admin login token.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.post(
+                f"{PAYMENT_SERVICE_URL}/subscriptions",
+                json={
+                    "tenant_id": tenant_id,
+                    "period_days": data.period_days,
+                    "admin_id": current_user.get("sub"),
+                    "reference": data.reference
+                },
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TENANT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to payment service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/tenant/{tenant_id}/subscription")
+async def get_tenant_subscription(
+    tenant_id: int,
+    current_user: dict = Depends(require_admin)
+):
+    """
+    Get subscription status of a tenant.
+
+    Only accessible with an admin login token.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{PAYMENT_SERVICE_URL}/subscription/{tenant_id}",
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TENANT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to payment service: {e}")
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="Admin service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
@@ -1,11 +1,16 @@
-from fastapi import APIRouter, HTTPException, status, Depends
+from fastapi import APIRouter, status, Depends
 from pydantic import BaseModel, EmailStr, validator
 from typing import Optional
 import httpx
 import logging
 
 from shared.config import settings
-from middleware.auth import get_current_user
+from shared.auth import IMPERSONATION_CLAIM
+from shared.api import APIError, ErrorCode, request_id_headers, normalize_subdomain, subdomain_error
+from shared.calendar import is_valid_timezone
+from utils import raise_for_upstream, upstream_client, upstream_timeout, CallKind
+from middleware.auth import get_current_user, deny_impersonation
+from middleware.captcha import require_captcha
 
 logger = logging.getLogger(__name__)
 
@@ -23,6 +28,7 @@ class RegisterRequest(BaseModel):
     phone: str
     business_name: str
     subdomain: str
+    timezone: Optional[str] = None
 
     @validator('password')
     def password_strength(cls, v):
@@ -32,9 +38,17 @@ class RegisterRequest(BaseModel):
 
     @validator('subdomain')
     def subdomain_valid(cls, v):
-        if not v.isalnum():
-            raise ValueError('Subdomain must contain only letters and numbers')
-        return v.lower()
+        v = normalize_subdomain(v)
+        error = subdomain_error(v)
+        if error:
+            raise ValueError(error)
+        return v
+
+    @validator('timezone')
+    def timezone_valid(cls, v):
+        if v is not None and not is_valid_timezone(v):
+            raise ValueError('Unknown timezone, see /api/v1/public/timezones')
+        return v
 
 
 class LoginRequest(BaseModel):
@@ -57,39 +71,57 @@ class ChangePasswordRequest(BaseModel):
         return v
 
 
-@router.post("/register", status_code=status.HTTP_201_CREATED)
+@router.post("/register", status_code=status.HTTP_201_CREATED, dependencies=[Depends(require_captcha)])
 async def register(data: RegisterRequest):
     """
     Register new business owner account.
 
-    Creates new tenant and owner user.
+    Creates new tenant and owner user. Requires X-Captcha-Token if
+    CAPTCHA is enabled.
     """
     try:
-        async with httpx.AsyncClient() as client:
+        async with upstream_client(headers=request_id_headers()) as client:
             response = await client.post(
                 f"{USER_SERVICE_URL}/register",
                 json=data.dict(),
-                timeout=10.0
+                timeout=upstream_timeout(CallKind.WRITE)
             )
 
-            if response.status_code == 201:
-                return response.json()
-            elif response.status_code == 400:
-                raise HTTPException(
-                    status_code=status.HTTP_400_BAD_REQUEST,
-                    detail=response.json().get("detail", "Registration failed")
-                )
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="User service error"
-                )
+            raise_for_upstream(response)
+            return response.json()
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to user service: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="User service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/public/subdomain-available")
+async def check_subdomain_available(name: str):
+    """
+    Check whether a subdomain is free, for live validation during registration.
+
+    Returns available and, when it isn't, reason: "invalid", "reserved"
+    or "taken". Rate-limited per IP.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{USER_SERVICE_URL}/subdomain-available",
+                params={"name": name},
+                timeout=upstream_timeout(CallKind.FAST)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to user service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
 
 
@@ -101,31 +133,21 @@ async def login(data: LoginRequest):
     Returns access token and refresh token.
     """
     try:
-        async with httpx.AsyncClient() as client:
+        async with upstream_client(headers=request_id_headers()) as client:
             response = await client.post(
                 f"{USER_SERVICE_URL}/login",
                 json=data.dict(),
-                timeout=10.0
+                timeout=upstream_timeout(CallKind.WRITE)
             )
 
-            if response.status_code == 200:
-                return response.json()
-            elif response.status_code == 401:
-                raise HTTPException(
-                    status_code=status.HTTP_401_UNAUTHORIZED,
-                    detail="Invalid email or password"
-                )
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="User service error"
-                )
+            raise_for_upstream(response)
+            return response.json()
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to user service: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="User service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
 
 
@@ -137,31 +159,21 @@ async def refresh_token(data: RefreshTokenRequest):
     Returns new access token.
     """
     try:
-        async with httpx.AsyncClient() as client:
+        async with upstream_client(headers=request_id_headers()) as client:
             response = await client.post(
                 f"{USER_SERVICE_URL}/refresh-token",
                 json=data.dict(),
-                timeout=10.0
+                timeout=upstream_timeout(CallKind.WRITE)
             )
 
-            if response.status_code == 200:
-                return response.json()
-            elif response.status_code == 401:
-                raise HTTPException(
-                    status_code=status.HTTP_401_UNAUTHORIZED,
-                    detail="Invalid refresh token"
-                )
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="User service error"
-                )
+            raise_for_upstream(response)
+            return response.json()
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to user service: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="User service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
 
 
@@ -187,20 +199,23 @@ async def get_current_user_info(current_user: dict = Depends(get_current_user)):
         "user_id": current_user.get("sub"),
         "email": current_user.get("email"),
         "role": current_user.get("role"),
-        "tenant_id": current_user.get("tenant_id")
+        "tenant_id": current_user.get("tenant_id"),
+        "impersonated_by": current_user.get(IMPERSONATION_CLAIM)
     }
 
 
 @router.post("/change-password")
 async def change_password(
     data: ChangePasswordRequest,
-    current_user: dict = Depends(get_current_user)
+    current_user: dict = Depends(deny_impersonation)
 ):
     """
     Change password for current user.
+
+    Not allowed to admins impersonating the user.
     """
     try:
-        async with httpx.AsyncClient() as client:
+        async with upstream_client(headers=request_id_headers()) as client:
             response = await client.post(
                 f"{USER_SERVICE_URL}/change-password",
                 json={
@@ -208,25 +223,15 @@ async def change_password(
                     "old_password": data.old_password,
                     "new_password": data.new_password
                 },
-                timeout=10.0
+                timeout=upstream_timeout(CallKind.WRITE)
             )
 
-            if response.status_code == 200:
-                return {"message": "Password changed successfully"}
-            elif response.status_code == 400:
-                raise HTTPException(
-                    status_code=status.HTTP_400_BAD_REQUEST,
-                    detail="Invalid old password"
-                )
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="User service error"
-                )
+            raise_for_upstream(response)
+            return {"message": "Password changed successfully"}
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to user service: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="User service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
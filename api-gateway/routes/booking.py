@@ -1,12 +1,20 @@
-from fastapi import APIRouter, HTTPException, status, Depends, Query
+from fastapi import APIRouter, Request, status, Depends, Query
+from fastapi.encoders import jsonable_encoder
+from fastapi.responses import Response, StreamingResponse
 from pydantic import BaseModel
-from typing import Optional, List
-from datetime import datetime, date
+from typing import Optional, List, Dict
+from datetime import datetime, date, time
 import httpx
 import logging
 
 from shared.config import settings
-from middleware.auth import get_current_user, get_optional_user
+from shared.models import UserRole, ReviewStatus
+from shared.api import APIError, ErrorCode, request_id_headers
+from shared.i18n import get_request_language
+from shared.tenants import TenantSettingsUpdate, ServiceCancellationPolicyUpdate
+from utils import raise_for_upstream, upstream_client, upstream_timeout, CallKind
+from middleware.auth import get_current_user, get_optional_user, require_role, deny_impersonation
+from middleware.captcha import require_captcha
 
 logger = logging.getLogger(__name__)
 
@@ -21,10 +29,18 @@ class CreateBookingRequest(BaseModel):
     subdomain: str
     client_phone: str
     client_name: str
-    master_id: int
-    service_id: int
+    client_email: Optional[str] = None
+    master_id: Optional[int] = None
+    service_id: Optional[int] = None
+    service_ids: Optional[List[int]] = None
     booking_date: datetime
     notes: Optional[str] = None
+    reminder_channels: Optional[List[str]] = None  # WHATSAPP, EMAIL; empty: no reminders
+
+
+class ResendConfirmationRequest(BaseModel):
+    code: str
+    email: str
 
 
 class UpdateBookingRequest(BaseModel):
@@ -33,6 +49,58 @@ class UpdateBookingRequest(BaseModel):
     notes: Optional[str] = None
 
 
+class CompleteBookingRequest(BaseModel):
+    force: bool = False
+
+
+class TemplateOverrideRequest(BaseModel):
+    language: str
+    body: str
+
+
+class TemplatePreviewRequest(BaseModel):
+    language: str
+    body: Optional[str] = None
+    sample_data: Optional[Dict[str, str]] = None
+
+
+class ReviewModerationRequest(BaseModel):
+    status: Optional[ReviewStatus] = None
+    response: Optional[str] = None
+
+
+class ReviewSettingsRequest(BaseModel):
+    moderate_reviews: bool
+
+
+class WeeklyHoursRequest(BaseModel):
+    day_of_week: int
+    start_time: time
+    end_time: time
+    is_working: bool = True
+
+
+class MasterScheduleRequest(BaseModel):
+    weekly: List[WeeklyHoursRequest]
+
+
+class LocationHoursRequest(MasterScheduleRequest):
+    """Weekly opening hours of a location, is_working false or days left out are closed."""
+
+
+class TimeOffRequest(BaseModel):
+    start_date: date
+    end_date: date
+    reason: Optional[str] = None
+
+
+class ScheduleOverrideRequest(BaseModel):
+    is_working: bool = False
+    start_time: Optional[time] = None
+    end_time: Optional[time] = None
+    reason: Optional[str] = None
+
+
 @router.get("/public/business/{subdomain}")
 async def get_business_info(subdomain: str):
     """
@@ -41,190 +109,389 @@ async def get_business_info(subdomain: str):
     Available to everyone without authentication.
     """
     try:
-        async with httpx.AsyncClient() as client:
+        async with upstream_client(headers=request_id_headers()) as client:
             response = await client.get(
                 f"{BOOKING_SERVICE_URL}/public/business/{subdomain}",
-                timeout=10.0
+                timeout=upstream_timeout(CallKind.READ)
             )
 
-            if response.status_code == 200:
-                return response.json()
-            elif response.status_code == 404:
-                raise HTTPException(
-                    status_code=status.HTTP_404_NOT_FOUND,
-                    detail="Business not found"
-                )
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="Booking service error"
-                )
+            raise_for_upstream(response, not_found=ErrorCode.BUSINESS_NOT_FOUND)
+            return response.json()
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to booking service: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="Booking service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
 
 
 @router.get("/public/business/{subdomain}/services")
-async def get_business_services(subdomain: str):
+async def get_business_services(subdomain: str, location_id: Optional[int] = Query(None)):
     """
-    Get all active services for a business.
+    Get active services for a business with the masters offering them.
 
+    Services without masters (at the location, if given) are hidden.
     Public endpoint - no authentication required.
     """
     try:
-        async with httpx.AsyncClient() as client:
+        params = {}
+        if location_id:
+            params["location_id"] = location_id
+
+        async with upstream_client(headers=request_id_headers()) as client:
             response = await client.get(
                 f"{BOOKING_SERVICE_URL}/public/business/{subdomain}/services",
-                timeout=10.0
+                params=params,
+                timeout=upstream_timeout(CallKind.READ)
             )
 
-            if response.status_code == 200:
-                return response.json()
-            elif response.status_code == 404:
-                raise HTTPException(
-                    status_code=status.HTTP_404_NOT_FOUND,
-                    detail="Business not found"
-                )
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="Booking service error"
-                )
+            raise_for_upstream(response, not_found=ErrorCode.BUSINESS_NOT_FOUND)
+            return response.json()
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to booking service: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="Booking service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
 
 
 @router.get("/public/business/{subdomain}/masters")
 async def get_business_masters(
     subdomain: str,
-    service_id: Optional[int] = Query(None)
+    service_id: Optional[int] = Query(None),
+    sort: Optional[str] = Query(None)
 ):
     """
     Get all active masters for a business.
 
-    Optionally filter by service_id.
+    Optionally filter by service_id. sort: rating, experience or soonest
+    (earliest free slot).
     Public endpoint - no authentication required.
     """
     try:
         params = {}
         if service_id:
             params["service_id"] = service_id
+        if sort:
+            params["sort"] = sort
 
-        async with httpx.AsyncClient() as client:
+        async with upstream_client(headers=request_id_headers()) as client:
             response = await client.get(
                 f"{BOOKING_SERVICE_URL}/public/business/{subdomain}/masters",
                 params=params,
-                timeout=10.0
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.BUSINESS_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/public/business/{subdomain}/locations")
+async def get_business_locations(subdomain: str):
+    """
+    Get locations of a business, the main one first.
+
+    Public endpoint - no authentication required.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/public/business/{subdomain}/locations",
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.BUSINESS_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/public/business/{subdomain}/services/{service_id}")
+async def get_business_service(subdomain: str, service_id: int):
+    """
+    Get active service of a business with its masters.
+
+    Public endpoint - no authentication required.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/public/business/{subdomain}/services/{service_id}",
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.SERVICE_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/public/business/{subdomain}/masters/{master_id}")
+async def get_business_master(subdomain: str, master_id: int):
+    """
+    Get public profile of an active master of a business.
+
+    Public endpoint - no authentication required.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/public/business/{subdomain}/masters/{master_id}",
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.MASTER_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/public/business/{subdomain}/reviews")
+async def get_business_reviews(
+    subdomain: str,
+    master_id: Optional[int] = Query(None),
+    page: int = Query(1, ge=1),
+    page_size: int = Query(20, ge=1, le=50)
+):
+    """
+    Get approved reviews of a business or one of its masters, with the average rating.
+
+    Public endpoint - no authentication required.
+    """
+    params = {"page": page, "page_size": page_size}
+    if master_id is not None:
+        params["master_id"] = master_id
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/public/business/{subdomain}/reviews",
+                params=params,
+                timeout=upstream_timeout(CallKind.READ)
             )
 
-            if response.status_code == 200:
-                return response.json()
-            elif response.status_code == 404:
-                raise HTTPException(
-                    status_code=status.HTTP_404_NOT_FOUND,
-                    detail="Business not found"
-                )
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="Booking service error"
-                )
+            raise_for_upstream(response, not_found=ErrorCode.BUSINESS_NOT_FOUND)
+            return response.json()
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to booking service: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="Booking service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
 
 
 @router.get("/public/business/{subdomain}/availability")
 async def check_availability(
     subdomain: str,
-    master_id: int = Query(...),
-    date: date = Query(...)
+    master_id: Optional[int] = Query(None),
+    date: date = Query(...),
+    service_ids: Optional[List[int]] = Query(None)
 ):
     """
     Check master availability for a specific date.
 
-    Returns available time slots.
+    Returns available time slots, with service_ids only slots fitting
+    all the services back to back. Without master_id returns slots where
+    any master providing the services is free.
     """
     try:
-        async with httpx.AsyncClient() as client:
+        params = {"date": date.isoformat()}
+        if master_id:
+            params["master_id"] = master_id
+        if service_ids:
+            params["service_ids"] = service_ids
+
+        async with upstream_client(headers=request_id_headers()) as client:
             response = await client.get(
                 f"{BOOKING_SERVICE_URL}/public/business/{subdomain}/availability",
-                params={"master_id": master_id, "date": date.isoformat()},
-                timeout=10.0
+                params=params,
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/public/business/{subdomain}/available-dates")
+async def get_available_dates(
+    subdomain: str,
+    month: str = Query(..., pattern=r"^\d{4}-(0[1-9]|1[0-2])$"),
+    master_id: Optional[int] = Query(None),
+    service_ids: Optional[List[int]] = Query(None)
+):
+    """
+    Get dates of the month (YYYY-MM) with any available slot.
+
+    Lets the date picker grey out unavailable dates in one call. Takes
+    the same master_id and service_ids as availability.
+    """
+    try:
+        params = {"month": month}
+        if master_id:
+            params["master_id"] = master_id
+        if service_ids:
+            params["service_ids"] = service_ids
+
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/public/business/{subdomain}/available-dates",
+                params=params,
+                timeout=upstream_timeout(CallKind.READ)
             )
 
-            if response.status_code == 200:
-                return response.json()
-            elif response.status_code == 404:
-                raise HTTPException(
-                    status_code=status.HTTP_404_NOT_FOUND,
-                    detail="Business or master not found"
-                )
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="Booking service error"
-                )
+            raise_for_upstream(response)
+            return response.json()
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to booking service: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="Booking service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
 
 
-@router.post("/public/booking", status_code=status.HTTP_201_CREATED)
+@router.post("/public/booking", status_code=status.HTTP_201_CREATED, dependencies=[Depends(require_captcha)])
 async def create_public_booking(data: CreateBookingRequest):
     """
     Create a new booking (public endpoint for clients).
 
-    Sends WhatsApp confirmation to client.
+    Sends WhatsApp confirmation to client. Requires X-Captcha-Token if
+    CAPTCHA is enabled.
     """
     try:
-        async with httpx.AsyncClient() as client:
+        async with upstream_client(headers=request_id_headers()) as client:
             response = await client.post(
                 f"{BOOKING_SERVICE_URL}/public/booking",
+                json=jsonable_encoder(data),
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.post("/public/booking/resend-confirmation")
+async def resend_booking_confirmation(data: ResendConfirmationRequest):
+    """
+    Resend booking confirmation by WhatsApp and email.
+
+    Public endpoint - the code returned on booking creation together with
+    the client's email authorizes access. Rate-limited per IP and per
+    booking.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.post(
+                f"{BOOKING_SERVICE_URL}/public/booking/resend-confirmation",
                 json=data.dict(),
-                timeout=15.0
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.BOOKING_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/public/booking/{code}/calendar.ics")
+async def get_booking_calendar(code: str):
+    """
+    Download calendar file of a booking.
+
+    Public endpoint - the code returned on booking creation authorizes access.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/public/booking/{code}/calendar.ics",
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.BOOKING_NOT_FOUND)
+            return Response(
+                content=response.content,
+                media_type=response.headers.get("content-type"),
+                headers={"Content-Disposition": response.headers.get("content-disposition", "attachment")}
+            )
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/public/calendar/{token}.ics")
+async def get_master_calendar(token: str):
+    """
+    Calendar feed of a master's bookings to subscribe to in a calendar app.
+
+    Public endpoint - the token in the URL authorizes access.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/public/calendar/{token}.ics",
+                timeout=upstream_timeout(CallKind.READ)
             )
 
-            if response.status_code == 201:
-                return response.json()
-            elif response.status_code == 400:
-                raise HTTPException(
-                    status_code=status.HTTP_400_BAD_REQUEST,
-                    detail=response.json().get("detail", "Invalid booking data")
-                )
-            elif response.status_code == 409:
-                raise HTTPException(
-                    status_code=status.HTTP_409_CONFLICT,
-                    detail="Time slot not available"
-                )
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="Booking service error"
-                )
+            raise_for_upstream(response, not_found=ErrorCode.MASTER_NOT_FOUND)
+            return Response(
+                content=response.content,
+                media_type=response.headers.get("content-type"),
+                headers={"Cache-Control": "no-cache"}
+            )
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to booking service: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="Booking service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
 
 
@@ -232,7 +499,10 @@ async def create_public_booking(data: CreateBookingRequest):
 async def get_bookings(
     current_user: dict = Depends(get_current_user),
     date: Optional[date] = Query(None),
-    status: Optional[str] = Query(None)
+    booking_status: Optional[str] = Query(None, alias="status"),
+    limit: Optional[int] = Query(None, ge=1, le=200),
+    page: Optional[int] = Query(None, ge=1),
+    cursor: Optional[str] = Query(None)
 ):
     """
     Get bookings for current user.
@@ -241,6 +511,8 @@ async def get_bookings(
     - OWNER: all tenant bookings
     - MANAGER: location bookings
     - MASTER: own bookings
+
+    With limit the list is paginated by page or by cursor (next_cursor).
     """
     try:
         params = {
@@ -251,125 +523,1532 @@ async def get_bookings(
 
         if date:
             params["date"] = date.isoformat()
-        if status:
-            params["status"] = status
+        if booking_status:
+            params["status"] = booking_status
+        if limit:
+            params["limit"] = limit
+        if page:
+            params["page"] = page
+        if cursor:
+            params["cursor"] = cursor
 
-        async with httpx.AsyncClient() as client:
+        async with upstream_client(headers=request_id_headers()) as client:
             response = await client.get(
                 f"{BOOKING_SERVICE_URL}/bookings",
                 params=params,
-                timeout=10.0
+                timeout=upstream_timeout(CallKind.READ)
             )
 
-            if response.status_code == 200:
-                return response.json()
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="Booking service error"
-                )
+            raise_for_upstream(response)
+            return response.json()
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to booking service: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="Booking service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
 
 
-@router.put("/booking/{booking_id}")
-async def update_booking(
-    booking_id: int,
-    data: UpdateBookingRequest,
-    current_user: dict = Depends(get_current_user)
+@router.get("/services/{service_id}")
+async def get_service(
+    service_id: int,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
 ):
     """
-    Update booking.
+    Get service of the current user's business with all settings, for edit forms.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
 
-    Requires appropriate permissions.
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/services/{service_id}",
+                params={"tenant_id": tenant_id},
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.SERVICE_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.put("/services/{service_id}/cancellation-policy")
+async def set_service_cancellation_policy(
+    service_id: int,
+    data: ServiceCancellationPolicyUpdate,
+    current_user: dict = Depends(require_role(UserRole.OWNER))
+):
+    """
+    Set cancellation fee tiers of a service of the current user's
+    business; null falls back to the business policy.
     """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
     try:
-        request_data = data.dict(exclude_unset=True)
-        request_data.update({
-            "user_id": current_user.get("sub"),
-            "role": current_user.get("role")
-        })
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.put(
+                f"{BOOKING_SERVICE_URL}/services/{service_id}/cancellation-policy",
+                params={"tenant_id": tenant_id},
+                json=jsonable_encoder(data),
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.SERVICE_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/masters/{master_id}")
+async def get_master(
+    master_id: int,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Get master of the current user's business with the linked user account.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/masters/{master_id}",
+                params={"tenant_id": tenant_id},
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.MASTER_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/masters/{master_id}/schedule")
+async def get_master_schedule(
+    master_id: int,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Get working schedule of a master: weekly hours and upcoming one-off
+    overrides.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/masters/{master_id}/schedule",
+                params={"tenant_id": tenant_id},
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.MASTER_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.put("/masters/{master_id}/schedule")
+async def set_master_schedule(
+    master_id: int,
+    data: MasterScheduleRequest,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Replace weekly working hours of a master; days left out are days off.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
 
-        async with httpx.AsyncClient() as client:
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
             response = await client.put(
-                f"{BOOKING_SERVICE_URL}/booking/{booking_id}",
-                json=request_data,
-                timeout=10.0
+                f"{BOOKING_SERVICE_URL}/masters/{master_id}/schedule",
+                params={"tenant_id": tenant_id},
+                json=jsonable_encoder(data),
+                timeout=upstream_timeout(CallKind.WRITE)
             )
 
-            if response.status_code == 200:
-                return response.json()
-            elif response.status_code == 403:
-                raise HTTPException(
-                    status_code=status.HTTP_403_FORBIDDEN,
-                    detail="Insufficient permissions"
-                )
-            elif response.status_code == 404:
-                raise HTTPException(
-                    status_code=status.HTTP_404_NOT_FOUND,
-                    detail="Booking not found"
-                )
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="Booking service error"
-                )
+            raise_for_upstream(response, not_found=ErrorCode.MASTER_NOT_FOUND)
+            return response.json()
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to booking service: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="Booking service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
 
 
-@router.delete("/booking/{booking_id}")
-async def cancel_booking(
-    booking_id: int,
-    current_user: dict = Depends(get_current_user)
+@router.get("/locations/{location_id}/hours")
+async def get_location_hours(
+    location_id: int,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
 ):
     """
-    Cancel booking.
+    Get weekly opening hours of a location.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/locations/{location_id}/hours",
+                params={"tenant_id": tenant_id},
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.LOCATION_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
 
-    Sends WhatsApp notification to client.
+@router.put("/locations/{location_id}/hours")
+async def set_location_hours(
+    location_id: int,
+    data: LocationHoursRequest,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Replace weekly opening hours of a location; masters there are only
+    available while it's open.
     """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
     try:
-        async with httpx.AsyncClient() as client:
-            response = await client.delete(
-                f"{BOOKING_SERVICE_URL}/booking/{booking_id}",
-                params={
-                    "user_id": current_user.get("sub"),
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.put(
+                f"{BOOKING_SERVICE_URL}/locations/{location_id}/hours",
+                params={"tenant_id": tenant_id},
+                json=jsonable_encoder(data),
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.LOCATION_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.put("/masters/{master_id}/schedule/overrides/{day}")
+async def set_master_schedule_override(
+    master_id: int,
+    day: date,
+    data: ScheduleOverrideRequest,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Set one-off working hours of a master on a date, or a day off.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.put(
+                f"{BOOKING_SERVICE_URL}/masters/{master_id}/schedule/overrides/{day.isoformat()}",
+                params={"tenant_id": tenant_id},
+                json=jsonable_encoder(data),
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.MASTER_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.delete("/masters/{master_id}/schedule/overrides/{day}")
+async def delete_master_schedule_override(
+    master_id: int,
+    day: date,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Remove the one-off override of a date, the weekly hours apply again.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.delete(
+                f"{BOOKING_SERVICE_URL}/masters/{master_id}/schedule/overrides/{day.isoformat()}",
+                params={"tenant_id": tenant_id},
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.MASTER_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/masters/{master_id}/time-off")
+async def list_time_off(
+    master_id: int,
+    include_past: bool = Query(False),
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER, UserRole.MASTER))
+):
+    """
+    List time off of a master. Masters only see their own.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/masters/{master_id}/time-off",
+                params={
+                    "tenant_id": tenant_id,
+                    "user_id": current_user.get("sub"),
+                    "role": current_user.get("role"),
+                    "include_past": include_past
+                },
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TIME_OFF_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.post("/masters/{master_id}/time-off", status_code=status.HTTP_201_CREATED)
+async def create_time_off(
+    master_id: int,
+    data: TimeOffRequest,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER, UserRole.MASTER))
+):
+    """
+    Add time off (vacation) of a master over a date range.
+
+    Masters request their own time off, approved by an owner or manager
+    when TIME_OFF_REQUIRES_APPROVAL is set. Rejected with 409 while the
+    master has bookings within the dates.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.post(
+                f"{BOOKING_SERVICE_URL}/masters/{master_id}/time-off",
+                params={
+                    "tenant_id": tenant_id,
+                    "user_id": current_user.get("sub"),
+                    "role": current_user.get("role")
+                },
+                json=jsonable_encoder(data),
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TIME_OFF_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.put("/masters/{master_id}/time-off/{time_off_id}/approve")
+async def approve_time_off(
+    master_id: int,
+    time_off_id: int,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Approve time off requested by a master.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.put(
+                f"{BOOKING_SERVICE_URL}/masters/{master_id}/time-off/{time_off_id}/approve",
+                params={"tenant_id": tenant_id, "user_id": current_user.get("sub")},
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TIME_OFF_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.put("/masters/{master_id}/time-off/{time_off_id}/reject")
+async def reject_time_off(
+    master_id: int,
+    time_off_id: int,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Reject time off requested by a master.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.put(
+                f"{BOOKING_SERVICE_URL}/masters/{master_id}/time-off/{time_off_id}/reject",
+                params={"tenant_id": tenant_id, "user_id": current_user.get("sub")},
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TIME_OFF_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.delete("/masters/{master_id}/time-off/{time_off_id}")
+async def delete_time_off(
+    master_id: int,
+    time_off_id: int,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER, UserRole.MASTER))
+):
+    """
+    Delete time off of a master. Masters only delete their own.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.delete(
+                f"{BOOKING_SERVICE_URL}/masters/{master_id}/time-off/{time_off_id}",
+                params={
+                    "tenant_id": tenant_id,
+                    "user_id": current_user.get("sub"),
                     "role": current_user.get("role")
                 },
-                timeout=10.0
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TIME_OFF_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/my/bookings")
+async def get_my_bookings(
+    days: int = Query(3, ge=1, le=14),
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER, UserRole.MASTER))
+):
+    """
+    Get "my day" view: upcoming bookings of the current user as a master,
+    today and the next days grouped by day.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/my/bookings",
+                params={
+                    "user_id": current_user.get("sub"),
+                    "tenant_id": tenant_id,
+                    "days": days
+                },
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.MASTER_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.post("/my/calendar-token")
+async def rotate_my_calendar_token(
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER, UserRole.MASTER))
+):
+    """
+    Issue a new calendar feed URL of the current user as a master.
+
+    The previous feed URL stops working.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.post(
+                f"{BOOKING_SERVICE_URL}/my/calendar-token",
+                params={
+                    "user_id": current_user.get("sub"),
+                    "tenant_id": tenant_id
+                },
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.MASTER_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/booking/{booking_id}")
+async def get_booking(
+    booking_id: int,
+    current_user: dict = Depends(get_current_user)
+):
+    """
+    Get booking details with price and tax breakdown.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/booking/{booking_id}",
+                params={
+                    "user_id": current_user.get("sub"),
+                    "role": current_user.get("role"),
+                    "tenant_id": current_user.get("tenant_id")
+                },
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.BOOKING_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.put("/booking/{booking_id}")
+async def update_booking(
+    booking_id: int,
+    data: UpdateBookingRequest,
+    current_user: dict = Depends(get_current_user)
+):
+    """
+    Update booking.
+
+    Requires appropriate permissions.
+    """
+    try:
+        request_data = jsonable_encoder(data, exclude_unset=True)
+        request_data.update({
+            "user_id": current_user.get("sub"),
+            "role": current_user.get("role"),
+            "tenant_id": current_user.get("tenant_id")
+        })
+
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.put(
+                f"{BOOKING_SERVICE_URL}/booking/{booking_id}",
+                json=request_data,
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.BOOKING_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.put("/booking/{booking_id}/complete")
+async def complete_booking(
+    booking_id: int,
+    data: Optional[CompleteBookingRequest] = None,
+    current_user: dict = Depends(get_current_user)
+):
+    """
+    Mark booking as completed.
+
+    Only bookings of the current user's business can be completed, and only
+    after the appointment start time. OWNER can override this with force.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.put(
+                f"{BOOKING_SERVICE_URL}/booking/{booking_id}/complete",
+                json={
+                    "user_id": current_user.get("sub"),
+                    "role": current_user.get("role"),
+                    "tenant_id": current_user.get("tenant_id"),
+                    "force": data.force if data else False
+                },
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.BOOKING_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.put("/booking/{booking_id}/no-show")
+async def mark_no_show(
+    booking_id: int,
+    current_user: dict = Depends(get_current_user)
+):
+    """
+    Mark that the client did not show up.
+
+    Only allowed after the appointment start time.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.put(
+                f"{BOOKING_SERVICE_URL}/booking/{booking_id}/no-show",
+                json={
+                    "user_id": current_user.get("sub"),
+                    "role": current_user.get("role"),
+                    "tenant_id": current_user.get("tenant_id")
+                },
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.BOOKING_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/statistics")
+async def get_statistics(
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Get booking statistics of the current user's business.
+
+    Includes counts by status (with no-shows), the no-show rate,
+    revenue and the customer retention rate.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/statistics",
+                params={"tenant_id": tenant_id},
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/onboarding")
+async def get_onboarding_status(
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Get setup checklist of the current user's business.
+
+    Steps: location, master, service and working hours.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/onboarding",
+                params={"tenant_id": tenant_id},
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/clients/export")
+async def export_client_data(
+    email: str = Query(...),
+    current_user: dict = Depends(require_role(UserRole.OWNER))
+):
+    """
+    Export data of a client of the current user's business by email.
+
+    For data-portability requests, OWNER only.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/clients/export",
+                params={"tenant_id": tenant_id, "email": email},
+                timeout=upstream_timeout(CallKind.BULK)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.CLIENT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.delete("/clients/data", dependencies=[Depends(deny_impersonation)])
+async def delete_client_data(
+    email: str = Query(...),
+    current_user: dict = Depends(require_role(UserRole.OWNER))
+):
+    """
+    Delete personal data of a client of the current user's business by email.
+
+    For right-to-be-forgotten requests, OWNER only. Not allowed to admins
+    impersonating the tenant.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.delete(
+                f"{BOOKING_SERVICE_URL}/clients/data",
+                params={"tenant_id": tenant_id, "user_id": current_user.get("sub"), "email": email},
+                timeout=upstream_timeout(CallKind.BULK)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.CLIENT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/export")
+async def export_tenant_data(
+    current_user: dict = Depends(require_role(UserRole.OWNER))
+):
+    """
+    Export all data of the current user's business as JSON.
+
+    OWNER only.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/export",
+                params={"tenant_id": tenant_id},
+                timeout=upstream_timeout(CallKind.BULK)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TENANT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/bookings/export")
+async def export_bookings_csv(
+    request: Request,
+    date: Optional[date] = Query(None),
+    booking_status: Optional[str] = Query(None, alias="status"),
+    current_user: dict = Depends(require_role(UserRole.OWNER))
+):
+    """
+    Export bookings of the current user's business as CSV.
+
+    Takes the same filters as the booking list. Column headers follow the
+    request language (?lang, X-Language or Accept-Language). OWNER only.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    params = {"tenant_id": tenant_id, "language": get_request_language(request)}
+    if date:
+        params["date"] = date.isoformat()
+    if booking_status:
+        params["status"] = booking_status
+
+    # The client stays open while the file is relayed, closed by relay()
+    client = upstream_client(headers=request_id_headers())
+    try:
+        response = await client.send(
+            client.build_request(
+                "GET",
+                f"{BOOKING_SERVICE_URL}/bookings/export",
+                params=params,
+                timeout=upstream_timeout(CallKind.BULK)
+            ),
+            stream=True
+        )
+    except httpx.RequestError as e:
+        await client.aclose()
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+    if not response.is_success:
+        try:
+            await response.aread()
+            raise_for_upstream(response, not_found=ErrorCode.TENANT_NOT_FOUND)
+        finally:
+            await response.aclose()
+            await client.aclose()
+
+    async def relay():
+        try:
+            async for chunk in response.aiter_bytes():
+                yield chunk
+        finally:
+            await response.aclose()
+            await client.aclose()
+
+    return StreamingResponse(
+        relay(),
+        media_type=response.headers.get("content-type"),
+        headers={"Content-Disposition": response.headers.get("content-disposition", "attachment")}
+    )
+
+
+@router.get("/clients/history")
+async def get_client_history(
+    email: Optional[str] = Query(None),
+    phone: Optional[str] = Query(None),
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Get booking history of a client of the current user's business.
+
+    Includes total spend, favorite master and service and last visit.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/clients/history",
+                params={
+                    "tenant_id": tenant_id,
+                    "email": email,
+                    "phone": phone
+                },
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.CLIENT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/notifications/history")
+async def get_notification_history(
+    booking_id: Optional[int] = Query(None),
+    client_id: Optional[int] = Query(None),
+    limit: int = Query(100, ge=1, le=500),
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Get notifications sent for a booking or a client of the current
+    user's business, with their delivery status.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        params = {"tenant_id": tenant_id, "limit": limit}
+        if booking_id:
+            params["booking_id"] = booking_id
+        if client_id:
+            params["client_id"] = client_id
+
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/notifications/history",
+                params=params,
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/reviews")
+async def get_reviews(
+    review_status: Optional[ReviewStatus] = Query(None, alias="status"),
+    master_id: Optional[int] = Query(None),
+    page: int = Query(1, ge=1),
+    page_size: int = Query(20, ge=1, le=50),
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Get reviews of the current user's business of any status, for moderation.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    params = {"tenant_id": tenant_id, "page": page, "page_size": page_size}
+    if review_status:
+        params["status"] = review_status.value
+    if master_id is not None:
+        params["master_id"] = master_id
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/reviews",
+                params=params,
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.put("/reviews/settings")
+async def update_review_settings(
+    data: ReviewSettingsRequest,
+    current_user: dict = Depends(require_role(UserRole.OWNER))
+):
+    """
+    Turn approval of new reviews before publishing on or off.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.put(
+                f"{BOOKING_SERVICE_URL}/reviews/settings",
+                params={"tenant_id": tenant_id},
+                json=data.dict(),
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TENANT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/settings")
+async def get_settings(current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))):
+    """
+    Get settings of the current user's business: timezone, reminder
+    channels, cancellation policy and branding.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/settings",
+                params={"tenant_id": tenant_id},
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TENANT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.put("/settings")
+async def update_settings(
+    data: TenantSettingsUpdate,
+    current_user: dict = Depends(require_role(UserRole.OWNER))
+):
+    """
+    Update settings of the current user's business.
+
+    Sections in the request replace the stored ones, unknown keys and
+    invalid values are rejected with 422.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.put(
+                f"{BOOKING_SERVICE_URL}/settings",
+                params={"tenant_id": tenant_id},
+                json=jsonable_encoder(data, exclude_none=True),
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TENANT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.put("/reviews/{review_id}")
+async def moderate_review(
+    review_id: int,
+    data: ReviewModerationRequest,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Approve or reject a review of the current user's business and/or respond to it.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.put(
+                f"{BOOKING_SERVICE_URL}/reviews/{review_id}",
+                params={"tenant_id": tenant_id},
+                json=data.dict(),
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.REVIEW_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/templates")
+async def list_templates(
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Get customizable message templates of the current user's business
+    with defaults and overrides.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/templates",
+                params={"tenant_id": tenant_id},
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.put("/templates/{name}")
+async def set_template(
+    name: str,
+    data: TemplateOverrideRequest,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Override message template of the current user's business.
+
+    The placeholders of the default template must be kept.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.put(
+                f"{BOOKING_SERVICE_URL}/templates/{name}",
+                params={"tenant_id": tenant_id},
+                json=data.dict(),
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TEMPLATE_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.post("/templates/{name}/preview")
+async def preview_template(
+    name: str,
+    data: TemplatePreviewRequest,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Preview template rendered with sample data as text and HTML.
+
+    Previews the saved template, or the draft given in body.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.post(
+                f"{BOOKING_SERVICE_URL}/templates/{name}/preview",
+                params={"tenant_id": tenant_id},
+                json=data.dict(),
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TEMPLATE_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.post("/templates/{name}/test")
+async def send_test_template(
+    name: str,
+    data: TemplatePreviewRequest,
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Email template rendered with sample data to the current user's own
+    email address.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.post(
+                f"{BOOKING_SERVICE_URL}/templates/{name}/test",
+                params={"tenant_id": tenant_id, "user_id": current_user.get("sub")},
+                json=data.dict(),
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TEMPLATE_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.delete("/templates/{name}")
+async def reset_template(
+    name: str,
+    language: str = Query(...),
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Reset message template of the current user's business to the default.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.delete(
+                f"{BOOKING_SERVICE_URL}/templates/{name}",
+                params={"tenant_id": tenant_id, "language": language},
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TEMPLATE_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/reports/revenue")
+async def get_revenue_report(
+    start_date: date = Query(...),
+    end_date: date = Query(...),
+    group_by: str = Query("day"),
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Get revenue report of the current user's business.
+
+    group_by: day, week, month, master or service.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/reports/revenue",
+                params={
+                    "tenant_id": tenant_id,
+                    "start_date": start_date.isoformat(),
+                    "end_date": end_date.isoformat(),
+                    "group_by": group_by
+                },
+                timeout=upstream_timeout(CallKind.BULK)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.delete("/booking/{booking_id}")
+async def cancel_booking(
+    booking_id: int,
+    waive_fee: bool = Query(False),
+    current_user: dict = Depends(deny_impersonation)
+):
+    """
+    Cancel booking.
+
+    Sends WhatsApp notification to client. The cancellation policy fee is
+    kept from a paid deposit unless waive_fee is set. Not allowed to
+    admins impersonating the tenant.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.delete(
+                f"{BOOKING_SERVICE_URL}/booking/{booking_id}",
+                params={
+                    "user_id": current_user.get("sub"),
+                    "role": current_user.get("role"),
+                    "tenant_id": current_user.get("tenant_id"),
+                    "waive_fee": waive_fee
+                },
+                timeout=upstream_timeout(CallKind.WRITE)
             )
 
-            if response.status_code == 200:
-                return response.json()
-            elif response.status_code == 403:
-                raise HTTPException(
-                    status_code=status.HTTP_403_FORBIDDEN,
-                    detail="Insufficient permissions"
-                )
-            elif response.status_code == 404:
-                raise HTTPException(
-                    status_code=status.HTTP_404_NOT_FOUND,
-                    detail="Booking not found"
-                )
-            else:
-                raise HTTPException(
-                    status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="Booking service error"
-                )
+            raise_for_upstream(response, not_found=ErrorCode.BOOKING_NOT_FOUND)
+            return response.json()
 
     except httpx.RequestError as e:
         logger.error(f"Failed to connect to booking service: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="Booking service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
@@ -0,0 +1,307 @@
+from fastapi import APIRouter, Request, status, Header, Depends
+from pydantic import BaseModel, Field
+from typing import Optional
+import httpx
+import logging
+
+from shared.config import settings
+from shared.api import APIError, ErrorCode, request_id_headers
+from shared.i18n import get_request_language
+from utils import raise_for_upstream, upstream_client, upstream_timeout, CallKind
+from middleware.captcha import require_captcha
+
+logger = logging.getLogger(__name__)
+
+router = APIRouter()
+
+# Booking service URL
+BOOKING_SERVICE_URL = f"http://booking-service:{settings.BOOKING_SERVICE_PORT if hasattr(settings, 'BOOKING_SERVICE_PORT') else 8002}"
+
+# Header carrying the client session token
+CLIENT_SESSION_HEADER = "X-Client-Session"
+
+
+# Request models
+class ClientCodeRequest(BaseModel):
+    phone: str
+
+
+class ClientVerifyRequest(BaseModel):
+    phone: str
+    code: str
+
+
+class ReviewRequest(BaseModel):
+    rating: int = Field(..., ge=1, le=5)
+    comment: Optional[str] = Field(None, max_length=2000)
+
+
+def client_headers(session_token: str) -> dict:
+    """Headers for client requests to the booking service."""
+    return {**request_id_headers(), CLIENT_SESSION_HEADER: session_token}
+
+
+@router.post("/public/client/request-code")
+async def request_client_code(data: ClientCodeRequest):
+    """
+    Send verification code to the client's WhatsApp.
+
+    Public endpoint - no authentication required.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.post(
+                f"{BOOKING_SERVICE_URL}/public/client/request-code",
+                json=data.dict(),
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.post("/public/client/verify", dependencies=[Depends(require_captcha)])
+async def verify_client(data: ClientVerifyRequest):
+    """
+    Verify code and get client session token.
+
+    The token is passed in the X-Client-Session header of client endpoints.
+    Requires X-Captcha-Token if CAPTCHA is enabled.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.post(
+                f"{BOOKING_SERVICE_URL}/public/client/verify",
+                json=data.dict(),
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.post("/client/session/refresh")
+async def refresh_client_session(session_token: str = Header(..., alias=CLIENT_SESSION_HEADER)):
+    """
+    Get a new session token with extended expiry for a valid session.
+
+    The old token stops working. Expired sessions get 401 and have to be
+    verified again.
+    """
+    try:
+        async with upstream_client(headers=client_headers(session_token)) as client:
+            response = await client.post(
+                f"{BOOKING_SERVICE_URL}/client/session/refresh",
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/client/bookings")
+async def get_client_bookings(request: Request, session_token: str = Header(..., alias=CLIENT_SESSION_HEADER)):
+    """
+    Get bookings of the authenticated client.
+
+    Prices are formatted in the request language.
+    """
+    try:
+        async with upstream_client(headers=client_headers(session_token)) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/client/bookings",
+                params={"language": get_request_language(request)},
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/client/export")
+async def export_client_data(session_token: str = Header(..., alias=CLIENT_SESSION_HEADER)):
+    """
+    Export all data of the authenticated client as JSON.
+    """
+    try:
+        async with upstream_client(headers=client_headers(session_token)) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/client/export",
+                timeout=upstream_timeout(CallKind.BULK)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.delete("/client/data")
+async def delete_client_data(session_token: str = Header(..., alias=CLIENT_SESSION_HEADER)):
+    """
+    Delete personal data of the authenticated client.
+
+    Ends the session; bookings are kept anonymized.
+    """
+    try:
+        async with upstream_client(headers=client_headers(session_token)) as client:
+            response = await client.delete(
+                f"{BOOKING_SERVICE_URL}/client/data",
+                timeout=upstream_timeout(CallKind.BULK)
+            )
+
+            raise_for_upstream(response)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.get("/client/booking/{booking_id}")
+async def get_client_booking(
+    booking_id: int,
+    request: Request,
+    session_token: str = Header(..., alias=CLIENT_SESSION_HEADER)
+):
+    """
+    Get booking of the authenticated client.
+
+    Bookings of other clients are rejected with 403. Prices are formatted
+    in the request language.
+    """
+    try:
+        async with upstream_client(headers=client_headers(session_token)) as client:
+            response = await client.get(
+                f"{BOOKING_SERVICE_URL}/client/booking/{booking_id}",
+                params={"language": get_request_language(request)},
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.BOOKING_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.delete("/client/booking/{booking_id}")
+async def cancel_client_booking(
+    booking_id: int,
+    session_token: str = Header(..., alias=CLIENT_SESSION_HEADER)
+):
+    """
+    Cancel booking of the authenticated client.
+    """
+    try:
+        async with upstream_client(headers=client_headers(session_token)) as client:
+            response = await client.delete(
+                f"{BOOKING_SERVICE_URL}/client/booking/{booking_id}",
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.BOOKING_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.post("/client/booking/{booking_id}/resend-confirmation")
+async def resend_client_booking_confirmation(
+    booking_id: int,
+    session_token: str = Header(..., alias=CLIENT_SESSION_HEADER)
+):
+    """
+    Resend confirmation of a booking of the authenticated client.
+
+    Limited per booking, see CONFIRMATION_RESEND_LIMIT.
+    """
+    try:
+        async with upstream_client(headers=client_headers(session_token)) as client:
+            response = await client.post(
+                f"{BOOKING_SERVICE_URL}/client/booking/{booking_id}/resend-confirmation",
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.BOOKING_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.post("/client/booking/{booking_id}/review", status_code=status.HTTP_201_CREATED)
+async def create_client_review(
+    booking_id: int,
+    data: ReviewRequest,
+    session_token: str = Header(..., alias=CLIENT_SESSION_HEADER)
+):
+    """
+    Review a completed booking of the authenticated client.
+    """
+    try:
+        async with upstream_client(headers=client_headers(session_token)) as client:
+            response = await client.post(
+                f"{BOOKING_SERVICE_URL}/client/booking/{booking_id}/review",
+                json=data.dict(),
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.BOOKING_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to booking service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
@@ -0,0 +1,90 @@
+from fastapi import APIRouter, Request, Depends, status
+from fastapi.responses import StreamingResponse
+from typing import AsyncIterator
+import asyncio
+import logging
+
+import redis.asyncio as aioredis
+
+from shared.config import settings
+from shared.models import UserRole
+from shared.api import APIError, ErrorCode
+from shared.events import booking_events_channel
+from middleware.auth import get_stream_user
+
+logger = logging.getLogger(__name__)
+
+router = APIRouter()
+
+
+async def booking_event_stream(request: Request, tenant_id: int) -> AsyncIterator[str]:
+    """
+    Relay booking events of the tenant from Redis pub/sub as SSE messages.
+
+    Sends a comment line every SSE_KEEPALIVE_SECONDS so proxies keep the
+    connection open, and stops when the client disconnects.
+    """
+    redis_conn = aioredis.Redis(
+        host=settings.REDIS_HOST,
+        port=settings.REDIS_PORT,
+        db=settings.REDIS_DB,
+        decode_responses=True
+    )
+    pubsub = redis_conn.pubsub()
+
+    try:
+        await pubsub.subscribe(booking_events_channel(tenant_id))
+        yield "retry: 5000\n\n"
+
+        while not await request.is_disconnected():
+            message = await pubsub.get_message(
+                ignore_subscribe_messages=True,
+                timeout=settings.SSE_KEEPALIVE_SECONDS
+            )
+
+            if message is None:
+                yield ": keepalive\n\n"
+                continue
+
+            yield f"event: booking\ndata: {message['data']}\n\n"
+
+    except asyncio.CancelledError:
+        raise
+    except Exception as e:
+        logger.error(f"Booking event stream failed for tenant {tenant_id}: {e}")
+    finally:
+        await pubsub.unsubscribe()
+        await pubsub.close()
+        await redis_conn.close()
+
+
+@router.get("/events/bookings")
+async def stream_booking_events(
+    request: Request,
+    current_user: dict = Depends(get_stream_user)
+):
+    """
+    Stream booking events of the current user's business (Server-Sent Events).
+
+    Events: created, updated, confirmed, cancelled, completed, no_show.
+    Browsers' EventSource can't set headers, so the access token may be
+    passed in the access_token query parameter instead.
+    """
+    if current_user.get("role") not in (UserRole.OWNER.value, UserRole.MANAGER.value):
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    return StreamingResponse(
+        booking_event_stream(request, tenant_id),
+        media_type="text/event-stream",
+        headers={"Cache-Control": "no-cache", "X-Accel-Buffering": "no"}
+    )
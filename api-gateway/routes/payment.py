@@ -0,0 +1,121 @@
+from fastapi import APIRouter, Request, status, Depends, Header
+import httpx
+import logging
+
+from shared.config import settings
+from shared.models import UserRole
+from shared.api import APIError, ErrorCode, request_id_headers
+from utils import raise_for_upstream, upstream_client, upstream_timeout, CallKind
+from middleware.auth import require_role, deny_impersonation
+
+logger = logging.getLogger(__name__)
+
+router = APIRouter()
+
+# Payment service URL
+PAYMENT_SERVICE_URL = f"http://payment-service:{settings.PAYMENT_SERVICE_PORT if hasattr(settings, 'PAYMENT_SERVICE_PORT') else 8004}"
+
+
+@router.get("/subscription")
+async def get_subscription_status(
+    current_user: dict = Depends(require_role(UserRole.OWNER, UserRole.MANAGER))
+):
+    """
+    Get subscription status of the current user's business.
+
+    Returns trial or paid plan, days remaining and whether the
+    account is in good standing.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.get(
+                f"{PAYMENT_SERVICE_URL}/subscription/{tenant_id}",
+                timeout=upstream_timeout(CallKind.READ)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TENANT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to payment service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.post(
+    "/subscription/checkout",
+    status_code=status.HTTP_201_CREATED,
+    dependencies=[Depends(deny_impersonation)]
+)
+async def create_subscription_checkout(
+    current_user: dict = Depends(require_role(UserRole.OWNER))
+):
+    """
+    Start subscription payment for the current user's business.
+
+    Returns the payment provider checkout URL. Not allowed to admins
+    impersonating the owner.
+    """
+    tenant_id = current_user.get("tenant_id")
+    if not tenant_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.post(
+                f"{PAYMENT_SERVICE_URL}/subscription/checkout",
+                json={"tenant_id": tenant_id},
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.TENANT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to payment service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@router.post("/payments/webhook")
+async def payment_webhook(
+    request: Request,
+    signature: str = Header("", alias="X-Payment-Signature")
+):
+    """
+    Payment provider webhook.
+
+    The raw body is forwarded unchanged so the signature can be verified.
+    """
+    try:
+        async with upstream_client(headers=request_id_headers()) as client:
+            response = await client.post(
+                f"{PAYMENT_SERVICE_URL}/webhooks/payment",
+                content=await request.body(),
+                headers={"Content-Type": "application/json", "X-Payment-Signature": signature},
+                timeout=upstream_timeout(CallKind.WRITE)
+            )
+
+            raise_for_upstream(response, not_found=ErrorCode.PAYMENT_NOT_FOUND)
+            return response.json()
+
+    except httpx.RequestError as e:
+        logger.error(f"Failed to connect to payment service: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
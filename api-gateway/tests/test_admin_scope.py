@@ -0,0 +1,33 @@
+import asyncio
+import importlib
+
+import pytest
+
+from shared.api import APIError, ErrorCode
+from shared.auth import ADMIN_SCOPE
+
+
+@pytest.fixture
+def auth(service):
+    return importlib.import_module("middleware.auth")
+
+
+def test_admin_scoped_token_is_accepted(auth):
+    user = {"user_id": 1, "role": "SUPER_ADMIN", "scope": ADMIN_SCOPE}
+
+    assert asyncio.run(auth.require_admin(user)) == user
+
+
+def test_regular_super_admin_token_is_rejected(auth):
+    with pytest.raises(APIError) as exc:
+        asyncio.run(auth.require_admin({"user_id": 1, "role": "SUPER_ADMIN"}))
+
+    assert exc.value.status_code == 403
+    assert exc.value.code == ErrorCode.ADMIN_SCOPE_REQUIRED
+
+
+def test_admin_scope_of_other_role_is_rejected(auth):
+    with pytest.raises(APIError) as exc:
+        asyncio.run(auth.require_admin({"user_id": 2, "role": "OWNER", "scope": ADMIN_SCOPE}))
+
+    assert exc.value.code == ErrorCode.FORBIDDEN
@@ -0,0 +1,68 @@
+import asyncio
+import importlib
+import json
+
+import pytest
+
+from shared.auth import create_access_token
+from shared.events import BookingEvent, publish_booking_event
+
+
+class ConnectedRequest:
+    """Request of a client that stays connected."""
+
+    async def is_disconnected(self) -> bool:
+        return False
+
+
+@pytest.fixture
+def events(service):
+    return importlib.import_module("routes.events")
+
+
+def token(role, tenant_id=None) -> dict:
+    return {"Authorization": f"Bearer {create_access_token({'sub': '1', 'role': role, 'tenant_id': tenant_id})}"}
+
+
+def test_stream_relays_own_tenant_booking_events(events, db, cache, factory):
+    tenant, service, master = factory.salon()
+    booking = factory.booking(tenant, master, service, factory.client())
+    other_tenant = factory.tenant()
+    other = factory.booking(other_tenant, factory.master(other_tenant), factory.service(other_tenant), factory.client())
+
+    async def first_event():
+        stream = events.booking_event_stream(ConnectedRequest(), tenant.id)
+        try:
+            assert await stream.__anext__() == "retry: 5000\n\n"
+            publish_booking_event(other, BookingEvent.CREATED)
+            publish_booking_event(booking, BookingEvent.CREATED)
+            # The subscription confirmation comes out as a keepalive
+            message = ": keepalive\n\n"
+            while message == ": keepalive\n\n":
+                message = await asyncio.wait_for(stream.__anext__(), timeout=5)
+            return message
+        finally:
+            await stream.aclose()
+
+    message = asyncio.run(first_event())
+
+    assert message.startswith("event: booking\ndata: ")
+    payload = json.loads(message.split("data: ", 1)[1])
+    assert payload["event"] == "created"
+    assert payload["booking_id"] == booking.id
+    assert payload["tenant_id"] == tenant.id
+
+
+def test_stream_requires_token(client):
+    response = client.get("/api/v1/events/bookings")
+
+    assert response.status_code == 401
+    assert response.json()["error"] == "INVALID_TOKEN"
+
+
+@pytest.mark.parametrize("role, tenant_id", [("MASTER", 1), ("OWNER", None)])
+def test_stream_is_for_business_staff(client, role, tenant_id):
+    response = client.get("/api/v1/events/bookings", headers=token(role, tenant_id))
+
+    assert response.status_code == 403
+    assert response.json()["error"] == "FORBIDDEN"
@@ -0,0 +1,113 @@
+import asyncio
+import importlib
+from urllib.parse import parse_qs
+
+import httpx
+import pytest
+
+from shared.config import settings
+
+VERIFY_URL = "/api/v1/public/client/verify"
+VERIFY_BODY = {"phone": "+77011111111", "code": "123456"}
+
+
+@pytest.fixture
+def forwarded(service, monkeypatch):
+    """Requests the gateway sends to the booking service."""
+    requests = []
+    routes = importlib.import_module("routes.client")
+
+    def handler(request: httpx.Request) -> httpx.Response:
+        requests.append(request)
+        return httpx.Response(200, json={"session_token": "session", "client_id": 1})
+
+    monkeypatch.setattr(
+        routes, "upstream_client", lambda **kwargs: httpx.AsyncClient(transport=httpx.MockTransport(handler), **kwargs)
+    )
+    return requests
+
+
+@pytest.fixture
+def verifier(service, monkeypatch):
+    """CAPTCHA verifier accepting the token "valid"; tokens it was asked about."""
+    captcha = importlib.import_module("middleware.captcha")
+    tokens = []
+
+    async def verify_captcha(token, remote_ip=None):
+        tokens.append(token)
+        if token == "error":
+            raise httpx.ConnectError("provider down")
+        return token == "valid"
+
+    monkeypatch.setattr(settings, "CAPTCHA_PROVIDER", "turnstile")
+    monkeypatch.setattr(captcha, "verify_captcha", verify_captcha)
+    return tokens
+
+
+def test_disabled_captcha_is_not_checked(client, forwarded):
+    response = client.post(VERIFY_URL, json=VERIFY_BODY)
+
+    assert response.status_code == 200
+    assert len(forwarded) == 1
+
+
+def test_valid_token_passes(client, forwarded, verifier):
+    response = client.post(VERIFY_URL, json=VERIFY_BODY, headers={"X-Captcha-Token": "valid"})
+
+    assert response.status_code == 200
+    assert verifier == ["valid"]
+    assert len(forwarded) == 1
+
+
+def test_missing_token_is_rejected(client, forwarded, verifier):
+    response = client.post(VERIFY_URL, json=VERIFY_BODY)
+
+    assert response.status_code == 400
+    assert response.json()["error"] == "CAPTCHA_REQUIRED"
+    assert verifier == []
+    assert forwarded == []
+
+
+def test_invalid_token_is_rejected(client, forwarded, verifier):
+    response = client.post(VERIFY_URL, json=VERIFY_BODY, headers={"X-Captcha-Token": "bot"})
+
+    assert response.status_code == 400
+    assert response.json()["error"] == "CAPTCHA_FAILED"
+    assert forwarded == []
+
+
+def test_provider_failure_fails_closed(client, forwarded, verifier):
+    response = client.post(VERIFY_URL, json=VERIFY_BODY, headers={"X-Captcha-Token": "error"})
+
+    assert response.status_code == 503
+    assert forwarded == []
+
+
+@pytest.mark.parametrize("path", ["/api/v1/public/booking", "/api/v1/register"])
+def test_booking_and_registration_need_token(client, verifier, path):
+    response = client.post(path, json={})
+
+    assert response.status_code == 400
+    assert response.json()["error"] == "CAPTCHA_REQUIRED"
+
+
+def test_token_is_verified_with_provider(service, monkeypatch):
+    captcha = importlib.import_module("middleware.captcha")
+    requests = []
+    real_client = httpx.AsyncClient
+
+    def handler(request: httpx.Request) -> httpx.Response:
+        requests.append(request)
+        return httpx.Response(200, json={"success": False, "error-codes": ["invalid-input-response"]})
+
+    monkeypatch.setattr(settings, "CAPTCHA_PROVIDER", "hcaptcha")
+    monkeypatch.setattr(settings, "CAPTCHA_SECRET_KEY", "secret")
+    monkeypatch.setattr(
+        captcha.httpx, "AsyncClient", lambda **kwargs: real_client(transport=httpx.MockTransport(handler), **kwargs)
+    )
+
+    assert asyncio.run(captcha.verify_captcha("token", "10.0.0.1")) is False
+
+    request, = requests
+    assert str(request.url) == "https://api.hcaptcha.com/siteverify"
+    assert parse_qs(request.content.decode()) == {"secret": ["secret"], "response": ["token"], "remoteip": ["10.0.0.1"]}
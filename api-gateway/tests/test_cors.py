@@ -0,0 +1,36 @@
+import pytest
+
+from shared.config import settings
+
+
+def preflight(client, origin):
+    return client.options("/api/v1/login", headers={
+        "Origin": origin,
+        "Access-Control-Request-Method": "POST"
+    })
+
+
+def test_listed_origin_is_allowed_with_credentials(client):
+    origin = settings.cors_origins_list[0]
+
+    response = preflight(client, origin)
+
+    assert response.status_code == 200
+    assert response.headers["access-control-allow-origin"] == origin
+    assert response.headers["access-control-allow-credentials"] == "true"
+
+
+def test_unlisted_origin_is_refused(client):
+    response = preflight(client, "https://evil.example.com")
+
+    assert response.status_code == 400
+    assert "access-control-allow-origin" not in response.headers
+
+
+def test_any_origin_with_credentials_fails_startup(service):
+    with pytest.raises(RuntimeError):
+        service.check_cors_settings(["*"], allow_credentials=True)
+
+
+def test_any_origin_without_credentials_is_accepted(service):
+    service.check_cors_settings(["*"], allow_credentials=False)
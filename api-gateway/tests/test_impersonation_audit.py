@@ -0,0 +1,71 @@
+import asyncio
+import importlib
+import json
+
+import httpx
+import pytest
+
+from shared.api import APIError, ErrorCode
+from shared.auth import IMPERSONATION_CLAIM, create_access_token, create_impersonation_token
+
+
+@pytest.fixture
+def audited(service, monkeypatch):
+    """Impersonated actions the gateway records in the admin service."""
+    recorded = []
+    impersonation = importlib.import_module("middleware.impersonation")
+
+    def handler(request: httpx.Request) -> httpx.Response:
+        recorded.append(json.loads(request.content))
+        return httpx.Response(201, json={"message": "Action recorded"})
+
+    monkeypatch.setattr(
+        impersonation, "upstream_client",
+        lambda **kwargs: httpx.AsyncClient(transport=httpx.MockTransport(handler), **kwargs)
+    )
+    return recorded
+
+
+@pytest.fixture
+def auth(service):
+    return importlib.import_module("middleware.auth")
+
+
+def bearer(token: str) -> dict:
+    return {"Authorization": f"Bearer {token}"}
+
+
+def test_impersonated_request_is_audited(client, audited):
+    token = create_impersonation_token(9, 5, "owner@example.com", "OWNER", 3)
+
+    response = client.get("/api/v1/languages", headers=bearer(token))
+
+    assert response.status_code == 200
+    assert audited == [{
+        "admin_id": "9", "tenant_id": 3, "method": "GET", "path": "/api/v1/languages", "status_code": 200
+    }]
+
+
+def test_regular_request_is_not_audited(client, audited):
+    token = create_access_token({"sub": "5", "role": "OWNER", "tenant_id": 3})
+
+    client.get("/api/v1/languages", headers=bearer(token))
+
+    assert audited == []
+
+
+def test_destructive_action_is_denied_when_impersonating(auth):
+    user = {"sub": "5", "role": "OWNER", "tenant_id": 3, IMPERSONATION_CLAIM: "9"}
+
+    with pytest.raises(APIError) as exc:
+        asyncio.run(auth.deny_impersonation(user))
+
+    assert exc.value.status_code == 403
+    assert exc.value.code == ErrorCode.IMPERSONATION_FORBIDDEN
+
+
+def test_own_session_may_act(auth):
+    user = {"sub": "5", "role": "OWNER", "tenant_id": 3}
+
+    assert auth.is_impersonated(user) is False
+    assert asyncio.run(auth.deny_impersonation(user)) == user
@@ -0,0 +1,8 @@
+from shared.config import settings
+
+
+def test_languages_endpoint_lists_supported_languages(client):
+    response = client.get("/api/v1/languages")
+
+    assert response.status_code == 200
+    assert [language["code"] for language in response.json()["languages"]] == settings.supported_languages_list
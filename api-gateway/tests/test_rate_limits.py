@@ -0,0 +1,84 @@
+import importlib
+from types import SimpleNamespace
+
+import pytest
+import redis
+
+from shared.auth import create_access_token
+from shared.cache.redis_client import cache_errors
+from shared.config import settings
+
+
+@pytest.fixture
+def rate_limit(service, cache, monkeypatch):
+    module = importlib.import_module("middleware.rate_limit")
+    # Fixed clock 20 seconds before the end of a window
+    monkeypatch.setattr(module, "time", SimpleNamespace(time=lambda: 1_000_000.0))
+    monkeypatch.setattr(settings, "RATE_LIMIT_PER_MINUTE", 5)
+    monkeypatch.setattr(settings, "RATE_LIMIT_ROUTES", "/api/v1/login=2")
+    monkeypatch.setattr(settings, "RATE_LIMIT_PER_USER_PER_MINUTE", 0)
+    monkeypatch.setattr(settings, "RATE_LIMIT_ROLES", "")
+    return module
+
+
+def statuses(client, method, path, times, **kwargs):
+    return [client.request(method, path, **kwargs).status_code for _ in range(times)]
+
+
+def test_login_is_limited_before_general_routes(client, rate_limit):
+    login = statuses(client, "GET", "/api/v1/login", 3)
+
+    assert 429 not in login[:2]
+    assert login[2] == 429
+
+    assert statuses(client, "GET", "/api/v1/languages", 2) == [200, 200]
+
+
+def test_general_routes_share_ip_limit(client, rate_limit):
+    assert statuses(client, "GET", "/api/v1/languages", 6) == [200] * 5 + [429]
+
+
+def test_rejection_has_retry_after_and_scope(client, rate_limit):
+    statuses(client, "GET", "/api/v1/login", 2)
+
+    response = client.get("/api/v1/login")
+
+    assert response.status_code == 429
+    assert response.headers["Retry-After"] == "20"
+    assert response.json()["error"] == "RATE_LIMITED"
+    assert response.json()["details"] == {"limit_per_minute": 2, "scope": "route"}
+
+
+def test_per_role_user_limit(client, rate_limit, monkeypatch):
+    monkeypatch.setattr(settings, "RATE_LIMIT_ROLES", "MASTER=1")
+    headers = {"Authorization": f"Bearer {create_access_token({'sub': '7', 'role': 'MASTER'})}"}
+
+    response = client.get("/api/v1/languages", headers=headers)
+    limited = client.get("/api/v1/languages", headers=headers)
+
+    assert response.status_code == 200
+    assert limited.status_code == 429
+    assert limited.json()["details"]["scope"] == "user"
+
+
+def test_requests_are_allowed_while_redis_is_down(client, rate_limit, cache, monkeypatch, caplog):
+    def fail(*args, **kwargs):
+        raise redis.ConnectionError("Connection refused")
+
+    monkeypatch.setattr(cache.client, "incr", fail)
+    cache_errors.clear()
+
+    assert statuses(client, "GET", "/api/v1/languages", 6) == [200] * 6
+    assert cache_errors["incr"] == 6
+    assert "Rate limit not checked, Redis unavailable" in caplog.text
+    cache_errors.clear()
+
+
+def test_health_checks_are_not_limited(client, rate_limit):
+    statuses(client, "GET", "/api/v1/languages", 5)
+
+    assert client.get("/health").status_code == 200
+
+
+def test_subdomain_check_is_rate_limited():
+    assert settings.rate_limit_routes_map["/api/v1/public/subdomain-available"] < settings.RATE_LIMIT_PER_MINUTE
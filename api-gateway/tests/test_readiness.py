@@ -0,0 +1,44 @@
+import httpx
+import pytest
+
+from shared.config import settings
+
+
+@pytest.fixture
+def backends(monkeypatch):
+    """Answer backend health probes, unhealthy for hosts added to the returned set."""
+    down = set()
+    real_client = httpx.AsyncClient
+
+    def handler(request: httpx.Request) -> httpx.Response:
+        if request.url.host in down:
+            return httpx.Response(200, json={"status": "unhealthy"})
+        return httpx.Response(200, json={"status": "healthy"})
+
+    monkeypatch.setattr(
+        httpx, "AsyncClient", lambda **kwargs: real_client(transport=httpx.MockTransport(handler), **kwargs)
+    )
+    return down
+
+
+def test_ready_when_all_backends_serve(client, backends):
+    response = client.get("/readyz")
+
+    assert response.status_code == 200
+    assert response.json()["status"] == "ready"
+    assert set(response.json()["services"].values()) == {"SERVING"}
+
+
+def test_not_ready_when_a_backend_is_unhealthy(client, backends):
+    backends.add("payment-service")
+
+    response = client.get("/readyz")
+
+    assert response.status_code == 503
+    assert response.json()["status"] == "not_ready"
+    assert response.json()["services"]["payment-service"] == "NOT_SERVING"
+    assert response.json()["services"]["booking-service"] == "SERVING"
+
+
+def test_notification_service_url_from_settings(service):
+    assert service.BACKEND_HEALTH_URLS["notification-service"] == f"{settings.NOTIFICATION_SERVICE_URL}/health"
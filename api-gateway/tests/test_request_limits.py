@@ -0,0 +1,69 @@
+import asyncio
+import importlib
+
+from fastapi import FastAPI, Request
+from fastapi.testclient import TestClient
+import pytest
+
+from shared.api import register_exception_handlers, request_id_middleware
+
+
+@pytest.fixture
+def limits(service):
+    return importlib.import_module("middleware.limits")
+
+
+@pytest.fixture
+def app(limits, monkeypatch):
+    monkeypatch.setattr(limits, "request_timeout", lambda path: 0.1)
+
+    app = FastAPI()
+    register_exception_handlers(app)
+
+    @app.post("/echo")
+    async def echo(request: Request):
+        return {"size": len(await request.body())}
+
+    @app.get("/slow")
+    async def slow():
+        await asyncio.sleep(1)
+        return {}
+
+    app.add_middleware(limits.BodySizeLimitMiddleware, max_body_size=10)
+    app.middleware("http")(limits.request_timeout_middleware)
+    app.middleware("http")(request_id_middleware)
+    return app
+
+
+def test_body_within_limit_passes(app):
+    response = TestClient(app).post("/echo", content=b"0123456789")
+
+    assert response.status_code == 200
+    assert response.json() == {"size": 10}
+
+
+def test_content_length_over_limit_rejected(app):
+    response = TestClient(app).post("/echo", content=b"0" * 11)
+
+    assert response.status_code == 413
+    assert response.json()["error"] == "PAYLOAD_TOO_LARGE"
+    assert response.json()["details"] == {"max_body_bytes": 10}
+
+
+def test_chunked_body_over_limit_rejected(app):
+    def chunks():
+        yield b"012345"
+        yield b"678901"
+
+    response = TestClient(app).post("/echo", content=chunks())
+
+    assert response.status_code == 413
+    assert response.json()["error"] == "PAYLOAD_TOO_LARGE"
+
+
+def test_slow_request_times_out(app):
+    response = TestClient(app).get("/slow")
+
+    assert response.status_code == 408
+    assert response.json()["error"] == "REQUEST_TIMEOUT"
+    assert response.json()["details"] == {"timeout_seconds": 0.1}
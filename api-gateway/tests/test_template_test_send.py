@@ -0,0 +1,53 @@
+import importlib
+import json
+
+import httpx
+import pytest
+
+from shared.auth import create_access_token
+
+TEST_SEND_URL = "/api/v1/templates/booking_confirmation_subject/test"
+
+
+@pytest.fixture
+def forwarded(service, monkeypatch):
+    """Requests the gateway sends to the booking service."""
+    requests = []
+    routes = importlib.import_module("routes.booking")
+
+    def handler(request: httpx.Request) -> httpx.Response:
+        requests.append(request)
+        return httpx.Response(200, json={"message": "Test email sent", "sent": True, "to": "owner@example.com"})
+
+    monkeypatch.setattr(
+        routes, "upstream_client", lambda **kwargs: httpx.AsyncClient(transport=httpx.MockTransport(handler), **kwargs)
+    )
+    return requests
+
+
+def token(sub, role="OWNER", tenant_id=3) -> dict:
+    return {"Authorization": f"Bearer {create_access_token({'sub': sub, 'role': role, 'tenant_id': tenant_id})}"}
+
+
+def test_test_email_is_sent_to_token_user(client, forwarded):
+    response = client.post(TEST_SEND_URL, headers=token("7"), json={"language": "en", "to": "someone@example.com"})
+
+    assert response.status_code == 200
+    request, = forwarded
+    assert request.url.params["user_id"] == "7"
+    assert request.url.params["tenant_id"] == "3"
+    assert "to" not in json.loads(request.content)
+
+
+def test_test_email_needs_owner_or_manager(client, forwarded):
+    response = client.post(TEST_SEND_URL, headers=token("7", role="MASTER"), json={"language": "en"})
+
+    assert response.status_code == 403
+    assert forwarded == []
+
+
+def test_test_email_needs_tenant(client, forwarded):
+    response = client.post(TEST_SEND_URL, headers=token("7", tenant_id=None), json={"language": "en"})
+
+    assert response.status_code == 403
+    assert forwarded == []
@@ -0,0 +1,10 @@
+from shared.calendar import supported_timezones
+from shared.config import settings
+
+
+def test_timezones_endpoint_lists_supported_zones(client):
+    response = client.get("/api/v1/public/timezones")
+
+    assert response.status_code == 200
+    assert response.json() == {"default": settings.DEFAULT_TIMEZONE, "timezones": supported_timezones()}
+    assert settings.DEFAULT_TIMEZONE in response.json()["timezones"]
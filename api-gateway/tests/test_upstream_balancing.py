@@ -0,0 +1,95 @@
+import asyncio
+import importlib
+
+import httpx
+import pytest
+
+REPLICAS = ["10.0.0.1", "10.0.0.2", "10.0.0.3"]
+
+
+@pytest.fixture
+def balancing(service):
+    return importlib.import_module("utils.balancer")
+
+
+@pytest.fixture
+def balancer(balancing):
+    class FixedBalancer(balancing.UpstreamBalancer):
+        """Balancer over a fixed set of replicas instead of DNS."""
+
+        async def resolve(self, host, port):
+            return REPLICAS
+
+    return FixedBalancer()
+
+
+class FakeTransport(httpx.AsyncBaseTransport):
+    """Replicas answering with their address; down ones refuse connections."""
+
+    def __init__(self):
+        self.hosts = []
+        self.down = set()
+
+    async def handle_async_request(self, request):
+        self.hosts.append(request.url.host)
+        if request.url.host in self.down:
+            raise httpx.ConnectError("connection refused", request=request)
+        return httpx.Response(200, text=request.url.host)
+
+
+@pytest.fixture
+def transport(balancing, balancer):
+    transport = balancing.BalancedTransport(balancer)
+    transport.transport = FakeTransport()
+    return transport
+
+
+def get(transport, times):
+    async def send():
+        async with httpx.AsyncClient(transport=transport) as client:
+            return [(await client.get("http://booking-service:8002/health")).text for _ in range(times)]
+
+    return asyncio.run(send())
+
+
+def test_requests_are_spread_across_replicas(transport):
+    assert get(transport, 6) == REPLICAS * 2
+
+
+def test_unreachable_replica_is_skipped(transport):
+    transport.transport.down.add("10.0.0.2")
+
+    answered = get(transport, 6)
+
+    assert "10.0.0.2" not in answered
+    # Tried once, then skipped while marked unhealthy
+    assert transport.transport.hosts.count("10.0.0.2") == 1
+
+
+def test_all_replicas_down_raises(transport):
+    transport.transport.down.update(REPLICAS)
+
+    with pytest.raises(httpx.ConnectError):
+        get(transport, 1)
+
+    assert sorted(transport.transport.hosts) == REPLICAS
+
+
+def test_unhealthy_replicas_are_tried_last(balancer):
+    balancer.mark_unhealthy("10.0.0.1")
+
+    assert asyncio.run(balancer.candidates("booking-service", 8002))[-1] == "10.0.0.1"
+
+
+def test_host_header_keeps_service_name(transport):
+    requests = []
+
+    class Recording(FakeTransport):
+        async def handle_async_request(self, request):
+            requests.append(request)
+            return await super().handle_async_request(request)
+
+    transport.transport = Recording()
+    get(transport, 1)
+
+    assert requests[0].headers["host"] == "booking-service:8002"
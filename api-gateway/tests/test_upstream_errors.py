@@ -0,0 +1,60 @@
+import importlib
+
+import httpx
+import pytest
+
+from shared.api import APIError, ErrorCode
+
+
+@pytest.fixture
+def upstream(service):
+    return importlib.import_module("utils.upstream")
+
+
+def downstream(status_code, **kwargs) -> httpx.Response:
+    return httpx.Response(
+        status_code, request=httpx.Request("GET", "http://booking-service:8002/bookings"), **kwargs
+    )
+
+
+def test_success_passes(upstream):
+    upstream.raise_for_upstream(downstream(200, json={"id": 1}))
+
+
+def test_envelope_code_and_details_are_kept(upstream):
+    details = [{"field": "body.master_id", "message": "field required"}]
+    response = downstream(409, json={"error": "SLOT_UNAVAILABLE", "details": details})
+
+    with pytest.raises(APIError) as exc:
+        upstream.raise_for_upstream(response)
+
+    assert exc.value.status_code == 409
+    assert exc.value.code == ErrorCode.SLOT_UNAVAILABLE
+    assert exc.value.details == details
+
+
+def test_not_found_fallback_without_envelope(upstream):
+    with pytest.raises(APIError) as exc:
+        upstream.raise_for_upstream(downstream(404, text="Not Found"), not_found=ErrorCode.BOOKING_NOT_FOUND)
+
+    assert exc.value.status_code == 404
+    assert exc.value.code == ErrorCode.BOOKING_NOT_FOUND
+
+
+def test_gateway_timeout_is_service_unavailable(upstream):
+    with pytest.raises(APIError) as exc:
+        upstream.raise_for_upstream(downstream(504))
+
+    assert exc.value.status_code == 503
+    assert exc.value.code == ErrorCode.SERVICE_UNAVAILABLE
+
+
+def test_unknown_status_hides_downstream_error(upstream):
+    response = downstream(500, json={"error": "DATABASE_ERROR", "details": "relation does not exist"})
+
+    with pytest.raises(APIError) as exc:
+        upstream.raise_for_upstream(response)
+
+    assert exc.value.status_code == 500
+    assert exc.value.code == ErrorCode.SERVICE_ERROR
+    assert exc.value.details is None
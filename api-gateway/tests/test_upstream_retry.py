@@ -0,0 +1,88 @@
+import asyncio
+import importlib
+
+import httpx
+import pytest
+
+from shared.config import settings
+
+
+@pytest.fixture
+def retrying(service, monkeypatch):
+    monkeypatch.setattr(settings, "UPSTREAM_RETRY_ATTEMPTS", 2)
+    monkeypatch.setattr(settings, "UPSTREAM_RETRY_BACKOFF_SECONDS", 0)
+    return importlib.import_module("utils.retry")
+
+
+def backend(*outcomes):
+    """Backend answering with the outcomes in turn: status codes, or exceptions raised."""
+    calls = []
+    queue = list(outcomes)
+
+    def handler(request: httpx.Request) -> httpx.Response:
+        calls.append(request.method)
+        outcome = queue.pop(0) if queue else 200
+        if isinstance(outcome, Exception):
+            raise outcome
+        return httpx.Response(outcome)
+
+    return httpx.MockTransport(handler), calls
+
+
+def send(retrying, transport, method):
+    async def request():
+        async with httpx.AsyncClient(transport=retrying.RetryTransport(transport)) as client:
+            return await client.request(method, "http://booking-service:8002/public/business/salon")
+
+    return asyncio.run(request())
+
+
+def test_read_is_retried_on_unavailable(retrying):
+    transport, calls = backend(503, 502, 200)
+
+    assert send(retrying, transport, "GET").status_code == 200
+    assert calls == ["GET"] * 3
+
+
+def test_read_is_retried_on_connection_error(retrying):
+    transport, calls = backend(httpx.ConnectError("connection refused"), 200)
+
+    assert send(retrying, transport, "GET").status_code == 200
+    assert len(calls) == 2
+
+
+def test_read_gives_up_after_attempts(retrying):
+    transport, calls = backend(503, 503, 503, 200)
+
+    assert send(retrying, transport, "GET").status_code == 503
+    assert len(calls) == 3
+
+
+@pytest.mark.parametrize("method", ["POST", "PUT", "PATCH", "DELETE"])
+def test_write_is_not_retried(retrying, method):
+    transport, calls = backend(503, 200)
+
+    assert send(retrying, transport, method).status_code == 503
+    assert calls == [method]
+
+
+def test_write_connection_error_is_raised(retrying):
+    transport, calls = backend(httpx.ConnectError("connection refused"), 200)
+
+    with pytest.raises(httpx.ConnectError):
+        send(retrying, transport, "POST")
+    assert len(calls) == 1
+
+
+def test_other_errors_are_not_retried(retrying):
+    transport, calls = backend(500, 200)
+
+    assert send(retrying, transport, "GET").status_code == 500
+    assert len(calls) == 1
+
+
+def test_backoff_grows_with_attempts(retrying, monkeypatch):
+    monkeypatch.setattr(settings, "UPSTREAM_RETRY_BACKOFF_SECONDS", 0.2)
+    monkeypatch.setattr(retrying.random, "uniform", lambda low, high: high)
+
+    assert [retrying.upstream_retry_delay(attempt) for attempt in (1, 2, 3)] == [0.2, 0.4, 0.8]
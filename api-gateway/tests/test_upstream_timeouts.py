@@ -0,0 +1,65 @@
+import importlib
+
+import httpx
+import pytest
+
+from shared.config import settings
+
+
+@pytest.fixture
+def timeouts(service):
+    return importlib.import_module("utils.timeouts")
+
+
+def capture(monkeypatch, module_name):
+    """Read timeouts of the requests the gateway route module sends."""
+    read_timeouts = []
+    routes = importlib.import_module(module_name)
+
+    def handler(request: httpx.Request) -> httpx.Response:
+        read_timeouts.append(request.extensions["timeout"]["read"])
+        return httpx.Response(200, json={"available": True, "locations": []})
+
+    monkeypatch.setattr(
+        routes, "upstream_client", lambda **kwargs: httpx.AsyncClient(transport=httpx.MockTransport(handler), **kwargs)
+    )
+    return read_timeouts
+
+
+def test_fast_call_uses_shorter_timeout(client, monkeypatch):
+    monkeypatch.setattr(settings, "UPSTREAM_FAST_TIMEOUT_SECONDS", 1.5)
+    read_timeouts = capture(monkeypatch, "routes.auth")
+
+    response = client.get("/api/v1/public/subdomain-available", params={"name": "salon"})
+
+    assert response.status_code == 200
+    assert read_timeouts == [1.5]
+    assert read_timeouts[0] < settings.UPSTREAM_READ_TIMEOUT_SECONDS
+
+
+def test_read_call_uses_read_timeout(client, monkeypatch):
+    monkeypatch.setattr(settings, "UPSTREAM_READ_TIMEOUT_SECONDS", 7.0)
+    read_timeouts = capture(monkeypatch, "routes.booking")
+
+    client.get("/api/v1/public/business/salon/locations")
+
+    assert read_timeouts == [7.0]
+
+
+def test_timeouts_by_call_kind(timeouts):
+    assert timeouts.upstream_timeout(timeouts.CallKind.FAST) == settings.UPSTREAM_FAST_TIMEOUT_SECONDS
+    assert timeouts.upstream_timeout(timeouts.CallKind.WRITE) == settings.UPSTREAM_WRITE_TIMEOUT_SECONDS
+    assert timeouts.upstream_timeout(timeouts.CallKind.BULK) == settings.UPSTREAM_BULK_TIMEOUT_SECONDS
+    assert (
+        settings.UPSTREAM_FAST_TIMEOUT_SECONDS
+        < settings.UPSTREAM_READ_TIMEOUT_SECONDS
+        < settings.UPSTREAM_BULK_TIMEOUT_SECONDS
+    )
+
+
+def test_bulk_routes_may_run_longer(timeouts, monkeypatch):
+    monkeypatch.setattr(settings, "REQUEST_TIMEOUT_SECONDS", 30.0)
+    monkeypatch.setattr(settings, "UPSTREAM_BULK_TIMEOUT_SECONDS", 60.0)
+
+    assert timeouts.request_timeout("/api/v1/bookings/export") == 60.0
+    assert timeouts.request_timeout("/api/v1/bookings") == 30.0
@@ -0,0 +1,19 @@
+from .upstream import UPSTREAM_STATUS_MAP, raise_for_upstream
+from .retry import RETRY_METHODS, RetryTransport
+from .timeouts import BULK_ROUTES, CallKind, upstream_timeout, request_timeout
+from .balancer import UpstreamBalancer, BalancedTransport, upstream_balancer, upstream_client
+
+__all__ = [
+    "UPSTREAM_STATUS_MAP",
+    "raise_for_upstream",
+    "BULK_ROUTES",
+    "CallKind",
+    "upstream_timeout",
+    "request_timeout",
+    "RETRY_METHODS",
+    "RetryTransport",
+    "UpstreamBalancer",
+    "BalancedTransport",
+    "upstream_balancer",
+    "upstream_client"
+]
@@ -0,0 +1,123 @@
+import asyncio
+import itertools
+import socket
+import time
+import httpx
+import logging
+from typing import Dict, List, Tuple
+
+from shared.config import settings
+from .retry import RetryTransport
+
+logger = logging.getLogger(__name__)
+
+
+class UpstreamBalancer:
+    """
+    Round-robin over all addresses a service hostname resolves to.
+
+    A service scaled to several replicas resolves to one address per
+    replica. Addresses refusing connections are skipped for
+    UPSTREAM_UNHEALTHY_SECONDS; resolved addresses are cached for
+    UPSTREAM_DNS_TTL_SECONDS, so added or removed replicas are picked up.
+    """
+
+    def __init__(self):
+        self.addresses: Dict[Tuple[str, int], Tuple[float, List[str]]] = {}
+        self.unhealthy: Dict[str, float] = {}
+        self.counters: Dict[Tuple[str, int], itertools.count] = {}
+
+    async def resolve(self, host: str, port: int) -> List[str]:
+        """Get addresses of the host, cached."""
+        key = (host, port)
+        now = time.monotonic()
+
+        cached = self.addresses.get(key)
+        if cached and cached[0] > now:
+            return cached[1]
+
+        infos = await asyncio.get_running_loop().getaddrinfo(host, port, type=socket.SOCK_STREAM)
+        addresses = sorted({info[4][0] for info in infos})
+        self.addresses[key] = (now + settings.UPSTREAM_DNS_TTL_SECONDS, addresses)
+        return addresses
+
+    async def candidates(self, host: str, port: int) -> List[str]:
+        """
+        Get addresses to try for a request, healthy ones first.
+
+        Each request starts at the next address, so requests are spread
+        evenly. Unhealthy addresses are tried last rather than never, in
+        case all replicas were marked down.
+        """
+        addresses = await self.resolve(host, port)
+        if not addresses:
+            return []
+
+        counter = self.counters.setdefault((host, port), itertools.count())
+        start = next(counter) % len(addresses)
+        ordered = addresses[start:] + addresses[:start]
+
+        now = time.monotonic()
+        healthy = [a for a in ordered if self.unhealthy.get(a, 0) <= now]
+        return healthy + [a for a in ordered if a not in healthy]
+
+    def mark_unhealthy(self, address: str) -> None:
+        self.unhealthy[address] = time.monotonic() + settings.UPSTREAM_UNHEALTHY_SECONDS
+
+
+# Shared by all gateway requests
+upstream_balancer = UpstreamBalancer()
+
+
+class BalancedTransport(httpx.AsyncBaseTransport):
+    """
+    Transport sending each request to one replica of the service.
+
+    The Host header keeps the service name. A request whose connection
+    fails is retried on the next replica; requests that reached a replica
+    are never retried.
+    """
+
+    def __init__(self, balancer: UpstreamBalancer = upstream_balancer):
+        self.balancer = balancer
+        self.transport = httpx.AsyncHTTPTransport()
+
+    async def handle_async_request(self, request: httpx.Request) -> httpx.Response:
+        # Backend services are plain HTTP; TLS needs the hostname to verify
+        if request.url.scheme != "http":
+            return await self.transport.handle_async_request(request)
+
+        host, port = request.url.host, request.url.port or 80
+
+        try:
+            addresses = await self.balancer.candidates(host, port)
+        except socket.gaierror:
+            # Let the default transport report the resolution error
+            return await self.transport.handle_async_request(request)
+
+        original_url = request.url
+        for attempt, address in enumerate(addresses):
+            request.url = original_url.copy_with(host=address)
+            try:
+                return await self.transport.handle_async_request(request)
+            except (httpx.ConnectError, httpx.ConnectTimeout):
+                self.balancer.mark_unhealthy(address)
+                logger.warning(f"Upstream {host} replica {address} unreachable, marked unhealthy")
+                if attempt == len(addresses) - 1:
+                    raise
+            finally:
+                request.url = original_url
+
+        return await self.transport.handle_async_request(request)
+
+    async def aclose(self) -> None:
+        await self.transport.aclose()
+
+
+def upstream_client(**kwargs) -> httpx.AsyncClient:
+    """
+    HTTP client for requests to backend services, balanced across replicas.
+
+    Reads are retried on transient failures, see RetryTransport.
+    """
+    return httpx.AsyncClient(transport=RetryTransport(BalancedTransport()), **kwargs)
@@ -0,0 +1,60 @@
+import asyncio
+import random
+import httpx
+import logging
+
+from fastapi import status
+
+from shared.config import settings
+
+logger = logging.getLogger(__name__)
+
+# Only reads are retried; writes like creating a booking must not run twice
+RETRY_METHODS = ("GET", "HEAD", "OPTIONS")
+
+# Responses of a backend that is restarting or overloaded
+RETRY_STATUSES = (status.HTTP_502_BAD_GATEWAY, status.HTTP_503_SERVICE_UNAVAILABLE)
+
+
+def upstream_retry_delay(attempt: int) -> float:
+    """Delay before retry attempt (1-based), exponential with full jitter."""
+    return random.uniform(0, settings.UPSTREAM_RETRY_BACKOFF_SECONDS * (2 ** (attempt - 1)))
+
+
+class RetryTransport(httpx.AsyncBaseTransport):
+    """
+    Transport retrying reads on transient backend failures.
+
+    GET, HEAD and OPTIONS requests are retried up to UPSTREAM_RETRY_ATTEMPTS
+    times on connection errors, timeouts and 502/503 responses, with
+    backoff. Other methods are sent once.
+    """
+
+    def __init__(self, transport: httpx.AsyncBaseTransport):
+        self.transport = transport
+
+    async def handle_async_request(self, request: httpx.Request) -> httpx.Response:
+        if request.method not in RETRY_METHODS:
+            return await self.transport.handle_async_request(request)
+
+        attempt = 0
+        while True:
+            try:
+                response = await self.transport.handle_async_request(request)
+            except httpx.TransportError as e:
+                if attempt >= settings.UPSTREAM_RETRY_ATTEMPTS:
+                    raise
+                reason = type(e).__name__
+            else:
+                if response.status_code not in RETRY_STATUSES or attempt >= settings.UPSTREAM_RETRY_ATTEMPTS:
+                    return response
+                await response.aclose()
+                reason = f"status {response.status_code}"
+
+            attempt += 1
+            delay = upstream_retry_delay(attempt)
+            logger.warning(f"Retrying {request.method} {request.url} ({reason}), attempt {attempt} in {delay:.2f}s")
+            await asyncio.sleep(delay)
+
+    async def aclose(self) -> None:
+        await self.transport.aclose()
@@ -0,0 +1,40 @@
+from enum import Enum
+
+from shared.config import settings
+
+
+# Gateway routes making bulk calls, allowed to run past REQUEST_TIMEOUT_SECONDS
+BULK_ROUTES = (
+    "/api/v1/export",
+    "/api/v1/bookings/export",
+    "/api/v1/clients/export",
+    "/api/v1/clients/data",
+    "/api/v1/client/export",
+    "/api/v1/client/data",
+    "/api/v1/reports/revenue"
+)
+
+
+class CallKind(str, Enum):
+    """Category of a backend call, each with its own timeout."""
+    FAST = "fast"  # small bookkeeping calls, e.g. audit records
+    READ = "read"
+    WRITE = "write"
+    BULK = "bulk"  # exports, reports and other heavy requests
+
+
+def upstream_timeout(kind: CallKind) -> float:
+    """Timeout in seconds of a backend call of the kind."""
+    return {
+        CallKind.FAST: settings.UPSTREAM_FAST_TIMEOUT_SECONDS,
+        CallKind.READ: settings.UPSTREAM_READ_TIMEOUT_SECONDS,
+        CallKind.WRITE: settings.UPSTREAM_WRITE_TIMEOUT_SECONDS,
+        CallKind.BULK: settings.UPSTREAM_BULK_TIMEOUT_SECONDS
+    }[kind]
+
+
+def request_timeout(path: str) -> float:
+    """Time a gateway request may take, longer for routes making bulk calls."""
+    if path in BULK_ROUTES:
+        return max(settings.REQUEST_TIMEOUT_SECONDS, settings.UPSTREAM_BULK_TIMEOUT_SECONDS)
+    return settings.REQUEST_TIMEOUT_SECONDS
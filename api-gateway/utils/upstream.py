@@ -0,0 +1,65 @@
+from fastapi import status
+from typing import Dict, Optional, Tuple
+import httpx
+import logging
+
+from shared.api import APIError, ErrorCode, REQUEST_ID_HEADER, error_code_from_response
+
+logger = logging.getLogger(__name__)
+
+
+# Downstream status -> (gateway status, fallback error code)
+UPSTREAM_STATUS_MAP: Dict[int, Tuple[int, ErrorCode]] = {
+    status.HTTP_400_BAD_REQUEST: (status.HTTP_400_BAD_REQUEST, ErrorCode.BAD_REQUEST),
+    status.HTTP_401_UNAUTHORIZED: (status.HTTP_401_UNAUTHORIZED, ErrorCode.UNAUTHORIZED),
+    status.HTTP_403_FORBIDDEN: (status.HTTP_403_FORBIDDEN, ErrorCode.FORBIDDEN),
+    status.HTTP_404_NOT_FOUND: (status.HTTP_404_NOT_FOUND, ErrorCode.NOT_FOUND),
+    status.HTTP_409_CONFLICT: (status.HTTP_409_CONFLICT, ErrorCode.CONFLICT),
+    status.HTTP_422_UNPROCESSABLE_ENTITY: (status.HTTP_422_UNPROCESSABLE_ENTITY, ErrorCode.VALIDATION_ERROR),
+    status.HTTP_429_TOO_MANY_REQUESTS: (status.HTTP_429_TOO_MANY_REQUESTS, ErrorCode.RATE_LIMITED),
+    status.HTTP_503_SERVICE_UNAVAILABLE: (status.HTTP_503_SERVICE_UNAVAILABLE, ErrorCode.SERVICE_UNAVAILABLE),
+    status.HTTP_504_GATEWAY_TIMEOUT: (status.HTTP_503_SERVICE_UNAVAILABLE, ErrorCode.SERVICE_UNAVAILABLE),
+}
+
+
+def raise_for_upstream(response: httpx.Response, not_found: Optional[ErrorCode] = None) -> None:
+    """
+    Raise APIError for a failed downstream service response.
+
+    Keeps the downstream error code and details when the response carries
+    the standard error envelope, otherwise falls back to a generic code
+    for the status. Unknown statuses are reported as SERVICE_ERROR.
+
+    Args:
+        response: Downstream service response
+        not_found: Fallback error code for 404 responses
+    """
+    if response.is_success:
+        return
+
+    gateway_status, fallback = UPSTREAM_STATUS_MAP.get(
+        response.status_code,
+        (status.HTTP_500_INTERNAL_SERVER_ERROR, ErrorCode.SERVICE_ERROR)
+    )
+
+    if response.status_code == status.HTTP_404_NOT_FOUND and not_found:
+        fallback = not_found
+
+    if gateway_status == status.HTTP_500_INTERNAL_SERVER_ERROR:
+        logger.error(
+            f"Downstream error {response.status_code} from {response.request.url} "
+            f"(request_id={response.headers.get(REQUEST_ID_HEADER)}): {response.text}"
+        )
+        raise APIError(status_code=gateway_status, code=fallback)
+
+    details = None
+    try:
+        details = response.json().get("details")
+    except (ValueError, AttributeError):
+        pass
+
+    raise APIError(
+        status_code=gateway_status,
+        code=error_code_from_response(response, fallback),
+        details=details
+    )
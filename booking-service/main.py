@@ -1,18 +1,61 @@
-from fastapi import FastAPI, HTTPException, status, Depends, Query
-from pydantic import BaseModel
+from fastapi import FastAPI, status, Depends, Query, Header
+from fastapi.concurrency import run_in_threadpool
+from fastapi.responses import PlainTextResponse, Response, StreamingResponse
+from pydantic import BaseModel, EmailStr, Field, validator
 from sqlalchemy.orm import Session
+from sqlalchemy import func, or_, and_
 from datetime import datetime, date, time, timedelta
-from typing import Optional, List
+from typing import Optional, List, Dict
+from enum import Enum
+import calendar
 import httpx
 import logging
+import secrets
 
 from shared.config import settings
-from shared.database import get_db, check_db_connection
+from shared.database import (
+    SessionLocal, get_db, get_read_db, check_db_connection, engine, get_pool_stats, render_prometheus_pool_metrics,
+    run_in_transaction
+)
+from shared.api import (
+    APIError, ErrorCode, register_exception_handlers, request_id_middleware, request_id_headers,
+    encode_cursor, decode_cursor
+)
+from shared.i18n import (
+    CUSTOMIZABLE_TEMPLATES, get_default_template, get_template_override, validate_template,
+    render_preview, translate, verify_translation_coverage, format_datetime, format_money
+)
+from shared.email import EmailError, email_client, get_email_branding, text_to_html
 from shared.models import (
     Tenant, Service, Master, Booking, Client, MasterSchedule,
-    MasterService, BookingStatus, TenantStatus
+    MasterService, BookingStatus, UserRole, ClientSession,
+    Payment, PaymentStatus, NotificationLog, NotificationChannel, NotificationStatus, MessageTemplate,
+    User, Review, ReviewStatus, Location, LocationHours, MasterScheduleOverride, MasterTimeOff, TimeOffStatus
+)
+from shared.cache import (
+    redis_client, CacheError, cache_availability, get_availability_version, get_cached_availability,
+    invalidate_availability, invalidate_master_availability, render_prometheus_cache_metrics
 )
-from services.booking_service import BookingService
+from shared.events import BookingEvent, EventType, publish_booking_event, publish_event
+from shared.notifications import record_notification
+from shared.sms import SmsTemplate, render_sms
+from shared.calendar import ICS_CONTENT_TYPE, booking_ics, build_calendar
+from shared.billing import (
+    to_major, tenant_currency, compute_tax, get_tax_config, is_publicly_visible,
+    Feature, enabled_features, has_feature, require_feature,
+    compute_cancellation_fee, get_cancellation_policy, get_cancellation_tier
+)
+from shared.analytics import recompute_master_ratings
+from shared.tenants import (
+    TenantSettingsUpdate, ServiceCancellationPolicyUpdate, get_tenant_settings, apply_tenant_settings
+)
+from services.booking_service import (
+    BookingService, tenant_local_now, price_booking_items, group_capacity, bookable_master_filter,
+    get_slot_interval, get_min_lead_minutes, earliest_booking_start
+)
+from services.client_service import ClientService
+from services.report_service import ReportService, RevenueGroupBy
+from services.export_service import ExportService
 
 # Configure logging
 logging.basicConfig(
@@ -28,25 +71,471 @@ app = FastAPI(
     version="2.0.0"
 )
 
+# Request ID and standard error envelope
+app.middleware("http")(request_id_middleware)
+register_exception_handlers(app)
+
 # WhatsApp service URL
 WHATSAPP_SERVICE_URL = settings.WHATSAPP_SERVICE_URL
 
+# Payment service URL, sends refunds to the payment provider
+PAYMENT_SERVICE_URL = f"http://payment-service:{settings.PAYMENT_SERVICE_PORT}"
+
+
+class MasterSort(str, Enum):
+    """Orderings of the public masters listing."""
+    RATING = "rating"
+    EXPERIENCE = "experience"  # completed bookings
+    SOONEST = "soonest"  # earliest free slot
+
 
 # Request/Response models
 class CreateBookingRequest(BaseModel):
     subdomain: str
     client_phone: str
     client_name: str
-    master_id: int
-    service_id: int
+    client_email: Optional[str] = None
+    master_id: Optional[int] = None  # empty: any available master
+    service_id: Optional[int] = None
+    service_ids: Optional[List[int]] = None  # combo, performed in the given order
     booking_date: datetime
     notes: Optional[str] = None
+    reminder_channels: Optional[List[NotificationChannel]] = None  # empty: no reminders
+
+
+class UpdateBookingRequest(BaseModel):
+    user_id: int
+    role: str
+    tenant_id: Optional[int] = None
+    booking_date: Optional[datetime] = None
+    status: Optional[str] = None
+    notes: Optional[str] = None
+
+
+class CompleteBookingRequest(BaseModel):
+    user_id: int
+    role: str
+    tenant_id: Optional[int] = None
+    force: bool = False
+
+
+class NoShowBookingRequest(BaseModel):
+    user_id: int
+    role: str
+    tenant_id: Optional[int] = None
+
+
+class ClientCodeRequest(BaseModel):
+    phone: str
+
+
+class ClientVerifyRequest(BaseModel):
+    phone: str
+    code: str
+
+
+class ResendConfirmationRequest(BaseModel):
+    code: str  # public code returned on booking creation
+    email: EmailStr
+
+
+class WeeklyHoursRequest(BaseModel):
+    day_of_week: int = Field(..., ge=0, le=6)  # 0=Monday, 6=Sunday
+    start_time: time
+    end_time: time
+    is_working: bool = True
+
+    @validator('end_time')
+    def validate_time_range(cls, v, values):
+        if 'start_time' in values and v <= values['start_time']:
+            raise ValueError('end_time must be after start_time')
+        return v
+
+
+class MasterScheduleRequest(BaseModel):
+    weekly: List[WeeklyHoursRequest]
+
+    @validator('weekly')
+    def validate_days(cls, v):
+        days = [hours.day_of_week for hours in v]
+        if len(days) != len(set(days)):
+            raise ValueError('Each day of week may be given once')
+        return v
+
+
+class LocationHoursRequest(MasterScheduleRequest):
+    """Weekly opening hours of a location, is_working false or days left out are closed."""
+
+
+class ScheduleOverrideRequest(BaseModel):
+    is_working: bool = False
+    start_time: Optional[time] = None
+    end_time: Optional[time] = None
+    reason: Optional[str] = Field(None, max_length=200)
+
+    @validator('end_time', always=True)
+    def validate_working_hours(cls, v, values):
+        if values.get('is_working'):
+            if values.get('start_time') is None or v is None:
+                raise ValueError('start_time and end_time are required on a working day')
+            if v <= values['start_time']:
+                raise ValueError('end_time must be after start_time')
+        return v
+
+
+class TimeOffRequest(BaseModel):
+    start_date: date
+    end_date: date
+    reason: Optional[str] = Field(None, max_length=200)
+
+
+class ReviewRequest(BaseModel):
+    rating: int = Field(..., ge=1, le=5)
+    comment: Optional[str] = Field(None, max_length=2000)
+
+
+class ReviewModerationRequest(BaseModel):
+    status: Optional[ReviewStatus] = None
+    response: Optional[str] = Field(None, max_length=2000)  # empty string removes the response
+
+
+class ReviewSettingsRequest(BaseModel):
+    moderate_reviews: bool
+
+
+class TemplateOverrideRequest(BaseModel):
+    language: str
+    body: str
+
+
+class TemplatePreviewRequest(BaseModel):
+    language: str
+    body: Optional[str] = None  # draft to preview instead of the saved template
+    sample_data: Optional[Dict[str, str]] = None
+
+
+async def send_whatsapp_message(
+    phone: str,
+    message: str,
+    template: str,
+    booking: Optional[Booking] = None
+) -> bool:
+    """
+    Send WhatsApp message and record it in the notification log.
+
+    Failures are logged and not raised.
+    """
+    if not settings.WHATSAPP_ENABLED:
+        return False
+
+    log = {
+        "tenant_id": booking.tenant_id if booking else None,
+        "booking_id": booking.id if booking else None,
+        "client_id": booking.client_id if booking else None
+    }
+
+    try:
+        async with httpx.AsyncClient(headers=request_id_headers()) as client:
+            response = await client.post(
+                f"{WHATSAPP_SERVICE_URL}/send-message",
+                json={"phone": phone, "message": message},
+                timeout=5.0
+            )
+    except Exception as e:
+        logger.error(f"Failed to send WhatsApp message: {e}")
+        record_notification(
+            NotificationChannel.WHATSAPP, phone, template, NotificationStatus.FAILED, error=str(e), **log
+        )
+        return False
+
+    if response.status_code != 200:
+        record_notification(
+            NotificationChannel.WHATSAPP, phone, template, NotificationStatus.FAILED, error=response.text, **log
+        )
+        return False
+
+    record_notification(
+        NotificationChannel.WHATSAPP, phone, template, NotificationStatus.SENT,
+        provider_message_id=response.json().get("message_id"), **log
+    )
+    return True
+
+
+def booking_confirmed_message(booking: Booking) -> str:
+    """WhatsApp text confirming the booking to the client, in the tenant's language."""
+    language = booking.tenant.language
+    return render_sms(
+        SmsTemplate.BOOKING_CONFIRMED,
+        language,
+        business_name=booking.tenant.business_name,
+        service_name=booking.service_names,
+        booking_date=format_datetime(booking.booking_date, language),
+        price=format_money(booking.price, booking.currency, language)
+    )
+
+
+async def resend_booking_confirmation(booking: Booking) -> dict:
+    """
+    Send the confirmation of a confirmed booking again, by WhatsApp and email.
+
+    Limited to CONFIRMATION_RESEND_LIMIT resends per booking within
+    CONFIRMATION_RESEND_WINDOW_MINUTES; not limited while Redis is down.
+    """
+    if booking.status != BookingStatus.CONFIRMED:
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.INVALID_BOOKING_STATUS,
+            details={"status": booking.status.value}
+        )
+
+    key = f"confirmation_resend:{booking.id}"
+    window_seconds = settings.CONFIRMATION_RESEND_WINDOW_MINUTES * 60
+    resends = redis_client.incr(key)
+    if resends == 1:
+        redis_client.expire(key, window_seconds)
+    if resends is not None and resends > settings.CONFIRMATION_RESEND_LIMIT:
+        ttl = redis_client.ttl(key)
+        raise APIError(
+            status_code=status.HTTP_429_TOO_MANY_REQUESTS,
+            code=ErrorCode.RATE_LIMITED,
+            details={"retry_after_seconds": ttl if ttl > 0 else window_seconds}
+        )
+
+    sent = await send_whatsapp_message(
+        booking.client.phone, booking_confirmed_message(booking), SmsTemplate.BOOKING_CONFIRMED.value, booking
+    )
+    publish_event(EventType.BOOKING_CONFIRMATION_REQUESTED, {
+        "booking_id": booking.id,
+        "tenant_id": booking.tenant_id,
+        "status": booking.status.value
+    })
+
+    logger.info(f"Booking confirmation resent: ID={booking.id}")
+
+    return {
+        "message": "Confirmation resent",
+        "whatsapp_sent": sent,
+        "email_queued": bool(booking.client.email)
+    }
+
+
+def get_client_session(db: Session, session_token: str) -> ClientSession:
+    """Load verified client session or raise 401."""
+    session = ClientService(db).get_session(session_token)
+
+    if not session:
+        raise APIError(
+            status_code=status.HTTP_401_UNAUTHORIZED,
+            code=ErrorCode.INVALID_CLIENT_SESSION
+        )
+
+    return session
+
+
+def get_client_booking(db: Session, booking_id: int, client_id: int) -> Booking:
+    """
+    Load booking owned by the client.
+
+    Bookings of other clients are rejected with 403.
+    """
+    return get_scoped_booking(db, booking_id, client_id, CLIENT_ROLE, None)
+
+
+def price_breakdown(booking: Booking) -> dict:
+    """Booking price breakdown with tax, amounts in major and minor units."""
+    net = booking.price - booking.tax_amount
+    return {
+        "currency": booking.currency,
+        "net": to_major(net),
+        "net_minor": net,
+        "tax": to_major(booking.tax_amount),
+        "tax_minor": booking.tax_amount,
+        "tax_rate_percent": booking.tax_rate / 100,
+        "tax_inclusive": booking.tax_inclusive,
+        "total": to_major(booking.price),
+        "total_minor": booking.price
+    }
+
+
+def booking_services(booking: Booking) -> List[dict]:
+    """Services of the booking, a single one for bookings made before combos."""
+    if not booking.items:
+        return [{
+            "service_id": booking.service_id,
+            "name": booking.service.name if booking.service else None,
+            "duration_minutes": booking.duration_minutes,
+            "price": to_major(booking.price),
+            "price_minor": booking.price
+        }]
+
+    return [
+        {
+            "service_id": item.service_id,
+            "name": item.service.name if item.service else None,
+            "duration_minutes": item.duration_minutes,
+            "price": to_major(item.price),
+            "price_minor": item.price
+        }
+        for item in booking.items
+    ]
+
+
+def client_booking_response(booking: Booking, language: Optional[str] = None) -> dict:
+    """
+    Booking representation for the client.
+
+    The formatted price follows the language, the tenant's one by default.
+    """
+    return {
+        "id": booking.id,
+        "booking_date": booking.booking_date.isoformat(),
+        "duration_minutes": booking.duration_minutes,
+        "status": booking.status.value,
+        "business_name": booking.tenant.business_name if booking.tenant else None,
+        "service_name": booking.service_names or None,
+        "services": booking_services(booking),
+        "master_name": booking.master.full_name if booking.master else None,
+        "price": to_major(booking.price),
+        "price_minor": booking.price,
+        "currency": booking.currency,
+        "price_formatted": format_money(
+            booking.price, booking.currency, language or (booking.tenant.language if booking.tenant else None)
+        ),
+        "price_breakdown": price_breakdown(booking),
+        "notes": booking.client_notes
+    }
+
+
+def single_service(services: List[Service]) -> Optional[Service]:
+    """Service of a single-service booking, None for combos (never group bookings)."""
+    return services[0] if len(services) == 1 else None
+
+
+def get_booking_services(
+    db: Session,
+    tenant_id: int,
+    master_id: Optional[int],
+    service_ids: List[Optional[int]]
+) -> List[Service]:
+    """
+    Load services of a booking in the given order.
+
+    Raises 400 for an empty or repeated list, 404 for unknown services and
+    400 if the master (when given) doesn't provide one of them.
+    """
+    if not service_ids or None in service_ids or len(set(service_ids)) != len(service_ids):
+        raise APIError(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            code=ErrorCode.BAD_REQUEST
+        )
+
+    services = {
+        s.id: s for s in db.query(Service).filter(Service.id.in_(service_ids), Service.tenant_id == tenant_id)
+    }
+
+    if len(services) != len(service_ids):
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.SERVICE_NOT_FOUND
+        )
+
+    if master_id is None:
+        return [services[service_id] for service_id in service_ids]
+
+    # Check if master provides all services
+    provided = db.query(MasterService.service_id).filter(
+        MasterService.master_id == master_id,
+        MasterService.service_id.in_(service_ids)
+    ).count()
+
+    if provided != len(service_ids):
+        raise APIError(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            code=ErrorCode.MASTER_SERVICE_MISMATCH
+        )
+
+    return [services[service_id] for service_id in service_ids]
+
+
+def invalidate_booking_availability(booking: Booking, *dates: datetime) -> None:
+    """Drop cached availability of the booking's master on the given dates."""
+    for booking_date in set(d.date() for d in dates):
+        invalidate_availability(booking.tenant_id, booking.master_id, booking_date.isoformat())
+
+
+def invalidate_bookable_availability(master: Master) -> None:
+    """Drop cached availability of the master on all bookable dates, after its weekly hours change."""
+    today = tenant_local_now(master.tenant).date()
+    invalidate_master_availability(
+        master.tenant_id, master.id, today, today + timedelta(days=settings.BOOKING_ADVANCE_LIMIT_DAYS)
+    )
+
+
+# Role of requests made by clients with a session, user_id is then the client ID
+CLIENT_ROLE = "CLIENT"
+
+# Statuses set only by their own endpoints, which check the booking and
+# count no-shows or refund deposits: complete, no-show and cancel
+DEDICATED_STATUSES = {BookingStatus.COMPLETED, BookingStatus.NO_SHOW, BookingStatus.CANCELLED}
+
+
+def get_scoped_booking(
+    db: Session,
+    booking_id: int,
+    user_id: int,
+    role: str,
+    tenant_id: Optional[int]
+) -> Booking:
+    """
+    Load booking the caller is allowed to access.
+
+    Staff can only access bookings of their own tenant, masters only
+    their own bookings. Bookings of other tenants are reported as not
+    found so their existence is not revealed. Clients (CLIENT_ROLE) can
+    access their own bookings with any tenant, others are rejected
+    with 403.
+    """
+    query = db.query(Booking).filter(Booking.id == booking_id)
+
+    if role == CLIENT_ROLE:
+        pass
+    elif role != UserRole.SUPER_ADMIN.value:
+        if not tenant_id:
+            raise APIError(
+                status_code=status.HTTP_403_FORBIDDEN,
+                code=ErrorCode.FORBIDDEN
+            )
+        query = query.filter(Booking.tenant_id == tenant_id)
+
+    booking = query.first()
+
+    if not booking:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.BOOKING_NOT_FOUND
+        )
+
+    if role == CLIENT_ROLE and booking.client_id != user_id:
+        logger.warning(f"Client {user_id} tried to access booking {booking_id}")
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    if role == UserRole.MASTER.value and (not booking.master or booking.master.user_id != user_id):
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    return booking
 
 
 @app.on_event("startup")
 async def startup_event():
     """Initialize on startup."""
     logger.info("Starting Booking Service...")
+    verify_translation_coverage()
     if check_db_connection():
         logger.info("Database connection successful")
     else:
@@ -64,21 +553,23 @@ async def health_check():
     }
 
 
+@app.get("/metrics", response_class=PlainTextResponse)
+async def metrics():
+    """
+    Database pool and cache metrics in Prometheus text format.
+    """
+    return (
+        render_prometheus_pool_metrics(get_pool_stats(engine), "booking-service")
+        + render_prometheus_cache_metrics("booking-service")
+    )
+
+
 @app.get("/public/business/{subdomain}")
-async def get_business_info(subdomain: str, db: Session = Depends(get_db)):
+async def get_business_info(subdomain: str, db: Session = Depends(get_read_db)):
     """
     Get public business information.
     """
-    tenant = db.query(Tenant).filter(
-        Tenant.subdomain == subdomain,
-        Tenant.status.in_([TenantStatus.ACTIVE, TenantStatus.TRIAL])
-    ).first()
-
-    if not tenant:
-        raise HTTPException(
-            status_code=status.HTTP_404_NOT_FOUND,
-            detail="Business not found"
-        )
+    tenant = get_public_tenant(db, subdomain)
 
     return {
         "id": tenant.id,
@@ -87,28 +578,41 @@ async def get_business_info(subdomain: str, db: Session = Depends(get_db)):
         "phone": tenant.phone,
         "email": tenant.email,
         "description": tenant.description,
-        "status": tenant.status.value
+        "status": tenant.status.value,
+        "features": enabled_features(tenant)
     }
 
 
 @app.get("/public/business/{subdomain}/services")
-async def get_business_services(subdomain: str, db: Session = Depends(get_db)):
-    """
-    Get all active services for a business.
+async def get_business_services(
+    subdomain: str,
+    location_id: Optional[int] = Query(None),
+    db: Session = Depends(get_read_db)
+):
     """
-    tenant = db.query(Tenant).filter(Tenant.subdomain == subdomain).first()
+    Get active services for a business with the masters offering them.
 
-    if not tenant:
-        raise HTTPException(
-            status_code=status.HTTP_404_NOT_FOUND,
-            detail="Business not found"
-        )
+    Services no active master offers (at the location, if given) are
+    hidden since they can't be booked.
+    """
+    tenant = get_public_tenant(db, subdomain)
 
     services = db.query(Service).filter(
         Service.tenant_id == tenant.id,
         Service.is_active == True
     ).all()
 
+    offered = db.query(MasterService.service_id, Master).join(Master).filter(
+        Master.tenant_id == tenant.id,
+        *bookable_master_filter()
+    )
+    if location_id:
+        offered = offered.filter(Master.location_id == location_id)
+
+    masters_by_service = {}
+    for service_id, master in offered:
+        masters_by_service.setdefault(service_id, []).append({"id": master.id, "full_name": master.full_name})
+
     return {
         "services": [
             {
@@ -116,301 +620,3051 @@ async def get_business_services(subdomain: str, db: Session = Depends(get_db)):
                 "name": s.name,
                 "description": s.description,
                 "duration_minutes": s.duration_minutes,
-                "price": float(s.price)
+                "price": to_major(s.price),
+                "price_minor": s.price,
+                "currency": tenant_currency(tenant),
+                "tax_inclusive": get_tax_config(tenant, s)["inclusive"],
+                "popularity_score": s.popularity_score,
+                "capacity": s.capacity,
+                "slot_interval_minutes": get_slot_interval(tenant, s),
+                "min_lead_minutes": get_min_lead_minutes(tenant, s),
+                "masters_count": len(masters_by_service[s.id]),
+                "masters": masters_by_service[s.id]
             }
             for s in services
+            if s.id in masters_by_service
         ]
     }
 
 
+def master_next_available(
+    db: Session,
+    tenant: Tenant,
+    master: Master,
+    service: Optional[Service] = None
+) -> Optional[datetime]:
+    """
+    Start of the master's earliest free slot within AVAILABILITY_WARM_DAYS.
+
+    Looks at cached availability of the service, or of the default slot,
+    from the earliest bookable time on. None if there's no free slot.
+    """
+    if not master.is_accepting_bookings:
+        return None
+
+    interval = get_slot_interval(tenant, service)
+    duration = service.duration_minutes if service else interval
+    not_before = earliest_booking_start(tenant, [service] if service else [])
+
+    for offset in range(settings.AVAILABILITY_WARM_DAYS):
+        day = not_before.date() + timedelta(days=offset)
+        slots = get_master_slots(db, tenant.id, master.id, day, duration, interval, service, not_before)
+        if slots["available_slots"]:
+            return datetime.combine(day, time.fromisoformat(slots["available_slots"][0]))
+
+    return None
+
+
 @app.get("/public/business/{subdomain}/masters")
 async def get_business_masters(
     subdomain: str,
     service_id: Optional[int] = Query(None),
-    db: Session = Depends(get_db)
+    sort: Optional[MasterSort] = Query(None),
+    db: Session = Depends(get_read_db)
 ):
     """
     Get all active masters for a business.
     Optionally filter by service.
-    """
-    tenant = db.query(Tenant).filter(Tenant.subdomain == subdomain).first()
 
-    if not tenant:
-        raise HTTPException(
-            status_code=status.HTTP_404_NOT_FOUND,
-            detail="Business not found"
-        )
+    sort orders them by rating (average of approved reviews), experience
+    (completed bookings) or soonest (earliest free slot for the service,
+    next_available_at in the response); masters without a rating or free
+    slot go last.
+    """
+    tenant = get_public_tenant(db, subdomain)
 
     query = db.query(Master).filter(
         Master.tenant_id == tenant.id,
-        Master.is_active == True
+        Master.is_active == True,
+        Master.is_visible == True
     )
 
     if service_id:
         # Filter masters who provide this service
         query = query.join(MasterService).filter(MasterService.service_id == service_id)
 
-    masters = query.all()
+    masters = query.order_by(Master.id).all()
+    master_ids = [m.id for m in masters]
 
-    return {
-        "masters": [
-            {
-                "id": m.id,
-                "full_name": m.full_name,
-                "description": m.description,
-                "phone": m.phone
-            }
-            for m in masters
-        ]
+    ratings = {
+        master_id: (round(float(average), 2), count)
+        for master_id, average, count in db.query(
+            Review.master_id, func.avg(Review.rating), func.count(Review.id)
+        ).filter(
+            Review.master_id.in_(master_ids),
+            Review.status == ReviewStatus.APPROVED
+        ).group_by(Review.master_id)
     }
+    completed = dict(
+        db.query(Booking.master_id, func.count(Booking.id)).filter(
+            Booking.master_id.in_(master_ids),
+            Booking.status == BookingStatus.COMPLETED
+        ).group_by(Booking.master_id).all()
+    )
 
+    next_available = {}
+    if sort == MasterSort.SOONEST:
+        service = db.query(Service).filter(
+            Service.id == service_id,
+            Service.tenant_id == tenant.id
+        ).first() if service_id else None
+        next_available = {m.id: master_next_available(db, tenant, m, service) for m in masters}
+        masters.sort(key=lambda m: (next_available[m.id] is None, next_available[m.id] or datetime.max))
+    elif sort == MasterSort.RATING:
+        # Higher average first, more reviews break ties
+        masters.sort(key=lambda m: (m.id not in ratings, [-v for v in ratings.get(m.id, (0, 0))]))
+    elif sort == MasterSort.EXPERIENCE:
+        masters.sort(key=lambda m: -completed.get(m.id, 0))
 
-@app.get("/public/business/{subdomain}/availability")
-async def check_availability(
-    subdomain: str,
-    master_id: int = Query(...),
-    date: date = Query(...),
-    db: Session = Depends(get_db)
-):
+    items = []
+    for m in masters:
+        item = {
+            "id": m.id,
+            "full_name": m.full_name,
+            "description": m.description,
+            "phone": m.phone,
+            "is_accepting_bookings": m.is_accepting_bookings,
+            "rating": ratings[m.id][0] if m.id in ratings else None,
+            "reviews_count": ratings[m.id][1] if m.id in ratings else 0,
+            "completed_bookings": completed.get(m.id, 0)
+        }
+        if sort == MasterSort.SOONEST:
+            available_at = next_available[m.id]
+            item["next_available_at"] = available_at.isoformat() if available_at else None
+        items.append(item)
+
+    return {"masters": items}
+
+
+def drop_slots_before(result: dict, day: date, not_before: Optional[datetime]) -> dict:
+    """Copy of a slots result without the slots starting before not_before."""
+    if not_before is None or day > not_before.date():
+        return result
+
+    slots = [
+        slot for slot in result["available_slots"]
+        if datetime.combine(day, time.fromisoformat(slot)) >= not_before
+    ]
+    result = dict(result, available_slots=slots)
+    if "spots" in result:
+        result["spots"] = {slot: result["spots"][slot] for slot in slots}
+    return result
+
+
+def availability_variant(duration: int, interval: int, service: Optional[Service] = None) -> str:
+    """Cache variant of slots queries; group services get their own, as spots differ."""
+    group = service if group_capacity(service) > 1 else None
+    return f"{interval}:{duration}:{group.id}" if group else f"{interval}:{duration}"
+
+
+def get_master_slots(
+    db: Session,
+    tenant_id: int,
+    master_id: int,
+    day: date,
+    duration: int,
+    interval: int,
+    service: Optional[Service] = None,
+    not_before: Optional[datetime] = None
+) -> dict:
     """
-    Check master availability for a specific date.
-    Returns available time slots.
+    Get free slots of the master for bookings of the duration, cached in Redis.
+
+    Slots start every interval minutes. For a group service the free spots
+    of each slot are included. Slots starting before not_before (tenant
+    local time, e.g. within the booking lead time) are left out; the cache
+    keeps all of them, since it outlives the cutoff.
     """
-    tenant = db.query(Tenant).filter(Tenant.subdomain == subdomain).first()
+    group = service if group_capacity(service) > 1 else None
+    variant = availability_variant(duration, interval, group)
 
-    if not tenant:
-        raise HTTPException(
-            status_code=status.HTTP_404_NOT_FOUND,
-            detail="Business not found"
-        )
+    try:
+        # Version is read before the slots so a booking committed meanwhile
+        # invalidates what is cached below
+        version = get_availability_version(tenant_id, master_id, day.isoformat())
+        cached = get_cached_availability(tenant_id, master_id, day.isoformat(), version, variant)
+    except CacheError:
+        # Redis outage: serve from the database, failure is counted in cache metrics
+        version = cached = None
 
-    master = db.query(Master).filter(
-        Master.id == master_id,
-        Master.tenant_id == tenant.id
-    ).first()
+    if cached is not None:
+        return drop_slots_before(cached, day, not_before)
 
-    if not master:
-        raise HTTPException(
-            status_code=status.HTTP_404_NOT_FOUND,
-            detail="Master not found"
+    # Computed on the primary: the slots are cached under the version read
+    # above, and a lagging replica could still miss a booking committed
+    # before that version, caching its slot as free until the next change
+    with SessionLocal() as primary:
+        spots = BookingService(primary).get_slot_spots(
+            master_id, day, interval, duration_minutes=duration, service=group
         )
 
-    booking_service = BookingService(db)
-    available_slots = booking_service.get_available_slots(master_id, date)
-
-    return {
-        "date": date.isoformat(),
+    result = {
+        "date": day.isoformat(),
         "master_id": master_id,
-        "available_slots": available_slots
+        "duration_minutes": duration,
+        "slot_interval_minutes": interval,
+        "available_slots": list(spots)
     }
+    if group:
+        result["capacity"] = group_capacity(group)
+        result["spots"] = spots
 
+    if version is not None:
+        cache_availability(tenant_id, master_id, day.isoformat(), version, variant, result)
 
-@app.post("/public/booking", status_code=status.HTTP_201_CREATED)
-async def create_public_booking(data: CreateBookingRequest, db: Session = Depends(get_db)):
+    return drop_slots_before(result, day, not_before)
+
+
+def get_master_available_dates(
+    db: Session,
+    tenant_id: int,
+    master_id: int,
+    month: date,
+    duration: int,
+    interval: int,
+    service: Optional[Service] = None,
+    not_before: Optional[datetime] = None
+) -> List[str]:
     """
-    Create a new booking (public endpoint).
-    Sends WhatsApp confirmation.
+    Get dates of the month with any free slot of the master, cached in Redis.
+
+    A date is available when get_master_slots has a slot on it, so working
+    hours, overrides, time off and bookings are all taken into account.
+    Dates before not_before are left out and its own date is checked
+    against the cutoff; the cache keeps them, since it outlives the cutoff.
     """
-    tenant = db.query(Tenant).filter(Tenant.subdomain == data.subdomain).first()
+    month_key = month.strftime("%Y-%m")
+    variant = "dates:" + availability_variant(duration, interval, service)
 
-    if not tenant or tenant.status not in [TenantStatus.ACTIVE, TenantStatus.TRIAL]:
-        raise HTTPException(
-            status_code=status.HTTP_404_NOT_FOUND,
-            detail="Business not found or inactive"
-        )
+    try:
+        version = get_availability_version(tenant_id, master_id, month_key)
+        dates = get_cached_availability(tenant_id, master_id, month_key, version, variant)
+    except CacheError:
+        version = dates = None
 
-    # Get or create client
-    client = db.query(Client).filter(Client.phone == data.client_phone).first()
-    if not client:
-        client = Client(
-            phone=data.client_phone,
-            full_name=data.client_name
-        )
-        db.add(client)
-        db.flush()
+    if dates is None:
+        first = max(month, not_before.date()) if not_before else month
+        last = month.replace(day=calendar.monthrange(month.year, month.month)[1])
 
-    # Get service
-    service = db.query(Service).filter(
-        Service.id == data.service_id,
-        Service.tenant_id == tenant.id
-    ).first()
+        dates = []
+        day = first
+        while day <= last:
+            if get_master_slots(db, tenant_id, master_id, day, duration, interval, service)["available_slots"]:
+                dates.append(day.isoformat())
+            day += timedelta(days=1)
 
-    if not service:
-        raise HTTPException(
-            status_code=status.HTTP_404_NOT_FOUND,
-            detail="Service not found"
-        )
+        if version is not None:
+            cache_availability(tenant_id, master_id, month_key, version, variant, dates)
 
-    # Check if master provides this service
-    master_service = db.query(MasterService).filter(
-        MasterService.master_id == data.master_id,
-        MasterService.service_id == data.service_id
-    ).first()
+    if not_before is None:
+        return dates
 
-    if not master_service:
-        raise HTTPException(
-            status_code=status.HTTP_400_BAD_REQUEST,
-            detail="Master does not provide this service"
-        )
+    cutoff = not_before.date().isoformat()
+    return [
+        d for d in dates
+        if d > cutoff or (d == cutoff and get_master_slots(
+            db, tenant_id, master_id, not_before.date(), duration, interval, service, not_before
+        )["available_slots"])
+    ]
 
-    # Check availability
-    booking_service = BookingService(db)
-    if not booking_service.is_slot_available(data.master_id, data.booking_date, service.duration_minutes):
-        raise HTTPException(
-            status_code=status.HTTP_409_CONFLICT,
-            detail="Time slot not available"
+
+def get_public_tenant(db: Session, subdomain: str) -> Tenant:
+    """
+    Load tenant of a public business page or raise 404.
+
+    Pending, suspended, rejected and expired businesses, and those whose
+    trial or subscription has ended, are not found.
+    """
+    tenant = db.query(Tenant).filter(Tenant.subdomain == subdomain).first()
+
+    if not tenant or not is_publicly_visible(tenant):
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.BUSINESS_NOT_FOUND
         )
 
-    try:
+    return tenant
+
+
+def service_detail(service: Service, tenant: Tenant) -> dict:
+    """Full service representation with pricing and booking settings."""
+    tax_config = get_tax_config(tenant, service)
+    return {
+        "id": service.id,
+        "name": service.name,
+        "description": service.description,
+        "duration_minutes": service.duration_minutes,
+        "price": to_major(service.price),
+        "price_minor": service.price,
+        "currency": tenant_currency(tenant),
+        "tax_rate_percent": tax_config["rate_bp"] / 100,
+        "tax_inclusive": tax_config["inclusive"],
+        "require_deposit": has_feature(tenant, Feature.DEPOSITS) and bool(
+            service.require_deposit or tenant.require_deposit
+        ),
+        "deposit_amount": to_major(service.deposit_amount) if service.deposit_amount is not None else None,
+        "capacity": service.capacity,
+        "slot_interval_minutes": get_slot_interval(tenant, service),
+        "min_lead_minutes": get_min_lead_minutes(tenant, service),
+        "popularity_score": service.popularity_score,
+        "is_active": service.is_active,
+        "masters": [
+            {"id": ms.master.id, "full_name": ms.master.full_name}
+            for ms in service.master_services if ms.master.is_active and ms.master.is_visible
+        ]
+    }
+
+
+def review_stats(db: Session, *filters) -> dict:
+    """Count and average rating of the approved reviews matching the filters."""
+    count, average = db.query(func.count(Review.id), func.avg(Review.rating)).filter(
+        Review.status == ReviewStatus.APPROVED,
+        *filters
+    ).one()
+
+    return {
+        "average_rating": round(float(average), 2) if average is not None else None,
+        "reviews_count": count
+    }
+
+
+def review_response(review: Review) -> dict:
+    """Review representation, client name shortened to the first name."""
+    client_name = review.client.full_name if review.client else None
+    return {
+        "id": review.id,
+        "rating": review.rating,
+        "comment": review.comment,
+        "client_name": client_name.split()[0] if client_name else None,
+        "master_id": review.master_id,
+        "master_name": review.master.full_name if review.master else None,
+        "service_name": (review.booking.service_names or None) if review.booking else None,
+        "owner_response": review.owner_response,
+        "responded_at": review.responded_at.isoformat() if review.responded_at else None,
+        "created_at": review.created_at.isoformat()
+    }
+
+
+def master_detail(db: Session, master: Master) -> dict:
+    """Full master representation with services, schedule and booking stats."""
+    completed = db.query(func.count(Booking.id)).filter(
+        Booking.master_id == master.id,
+        Booking.status == BookingStatus.COMPLETED
+    ).scalar()
+
+    return {
+        "id": master.id,
+        "full_name": master.full_name,
+        "description": master.description,
+        "phone": master.phone,
+        "location": {
+            "id": master.location.id,
+            "name": master.location.name,
+            "address": master.location.address
+        } if master.location else None,
+        "is_active": master.is_active,
+        "is_accepting_bookings": master.is_accepting_bookings,
+        "is_visible": master.is_visible,
+        "completed_bookings": completed,
+        "rating": master.rating,
+        "reviews_count": master.reviews_count or 0,
+        "services": [
+            {"id": ms.service.id, "name": ms.service.name}
+            for ms in master.master_services if ms.service.is_active
+        ],
+        "schedule": [
+            {
+                "day_of_week": s.day_of_week,
+                "start_time": s.start_time.strftime("%H:%M"),
+                "end_time": s.end_time.strftime("%H:%M")
+            }
+            for s in sorted(master.schedules, key=lambda s: s.day_of_week) if s.is_working
+        ]
+    }
+
+
+@app.get("/public/business/{subdomain}/locations")
+async def get_business_locations(subdomain: str, db: Session = Depends(get_read_db)):
+    """
+    Get locations of a business, the main one first.
+
+    An unknown business is 404, a business without locations gets an
+    empty list. Without the multi_location feature only the main location
+    is listed.
+    """
+    tenant = get_public_tenant(db, subdomain)
+
+    locations = db.query(Location).filter(
+        Location.tenant_id == tenant.id
+    ).order_by(Location.is_main.desc(), Location.id).all()
+
+    if not has_feature(tenant, Feature.MULTI_LOCATION):
+        locations = locations[:1]
+
+    return {
+        "locations": [
+            {
+                "id": l.id,
+                "name": l.name,
+                "address": l.address,
+                "phone": l.phone,
+                "is_main": bool(l.is_main),
+                "hours": location_hours_response(l)
+            }
+            for l in locations
+        ]
+    }
+
+
+@app.get("/public/business/{subdomain}/services/{service_id}")
+async def get_business_service(subdomain: str, service_id: int, db: Session = Depends(get_read_db)):
+    """
+    Get active service of a business with its masters.
+    """
+    tenant = get_public_tenant(db, subdomain)
+
+    service = db.query(Service).filter(
+        Service.id == service_id,
+        Service.tenant_id == tenant.id,
+        Service.is_active == True
+    ).first()
+
+    if not service:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.SERVICE_NOT_FOUND
+        )
+
+    return service_detail(service, tenant)
+
+
+@app.get("/public/business/{subdomain}/masters/{master_id}")
+async def get_business_master(subdomain: str, master_id: int, db: Session = Depends(get_read_db)):
+    """
+    Get public profile of an active master of a business.
+    """
+    tenant = get_public_tenant(db, subdomain)
+
+    master = db.query(Master).filter(
+        Master.id == master_id,
+        Master.tenant_id == tenant.id,
+        Master.is_active == True,
+        Master.is_visible == True
+    ).first()
+
+    if not master:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.MASTER_NOT_FOUND
+        )
+
+    return master_detail(db, master)
+
+
+# Reviews per page of the public reviews list by default and at most
+REVIEWS_PAGE_SIZE = 20
+REVIEWS_MAX_PAGE_SIZE = 50
+
+
+@app.get("/public/business/{subdomain}/reviews")
+async def get_business_reviews(
+    subdomain: str,
+    master_id: Optional[int] = Query(None),
+    page: int = Query(1, ge=1),
+    page_size: int = Query(REVIEWS_PAGE_SIZE, ge=1, le=REVIEWS_MAX_PAGE_SIZE),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Get approved reviews of a business, or of one of its masters, newest first.
+
+    The average rating covers all approved reviews, not only the page.
+    """
+    tenant = get_public_tenant(db, subdomain)
+
+    filters = [Review.tenant_id == tenant.id]
+
+    if master_id is not None:
+        master = db.query(Master).filter(
+            Master.id == master_id,
+            Master.tenant_id == tenant.id,
+            Master.is_active == True,
+            Master.is_visible == True
+        ).first()
+
+        if not master:
+            raise APIError(
+                status_code=status.HTTP_404_NOT_FOUND,
+                code=ErrorCode.MASTER_NOT_FOUND
+            )
+
+        filters.append(Review.master_id == master.id)
+
+    stats = review_stats(db, *filters)
+    total = stats["reviews_count"]
+
+    reviews = db.query(Review).filter(
+        Review.status == ReviewStatus.APPROVED,
+        *filters
+    ).order_by(
+        Review.created_at.desc(), Review.id.desc()
+    ).offset((page - 1) * page_size).limit(page_size).all()
+
+    return {
+        "reviews": [review_response(r) for r in reviews],
+        "average_rating": stats["average_rating"],
+        "total": total,
+        "page": page,
+        "page_size": page_size,
+        "pages": (total + page_size - 1) // page_size
+    }
+
+
+@app.get("/services/{service_id}")
+async def get_service(
+    service_id: int,
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Get service of the tenant with all settings, inactive ones included.
+    """
+    service = db.query(Service).filter(
+        Service.id == service_id,
+        Service.tenant_id == tenant_id
+    ).first()
+
+    if not service:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.SERVICE_NOT_FOUND
+        )
+
+    detail = service_detail(service, service.tenant)
+    detail.update({
+        "tax_rate_override": service.tax_rate,
+        "tax_inclusive_override": service.tax_inclusive,
+        "service_require_deposit": bool(service.require_deposit),
+        "cancellation_policy": get_cancellation_policy(service.tenant, service),
+        "cancellation_policy_override": service.cancellation_policy,
+        "created_at": service.created_at.isoformat() if service.created_at else None,
+        "updated_at": service.updated_at.isoformat() if service.updated_at else None
+    })
+    return detail
+
+
+@app.put("/services/{service_id}/cancellation-policy")
+async def set_service_cancellation_policy(
+    service_id: int,
+    data: ServiceCancellationPolicyUpdate,
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Set cancellation fee tiers of a service, overriding the tenant policy.
+
+    null removes the override. Applies to cancellations made from now on,
+    including of existing bookings.
+    """
+    service = db.query(Service).filter(
+        Service.id == service_id,
+        Service.tenant_id == tenant_id
+    ).first()
+
+    if not service:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.SERVICE_NOT_FOUND
+        )
+
+    service.cancellation_policy = (
+        [tier.dict() for tier in data.cancellation_policy] if data.cancellation_policy is not None else None
+    )
+    db.commit()
+
+    logger.info(f"Service cancellation policy updated: service={service.id}, override={service.cancellation_policy}")
+
+    return {
+        "service_id": service.id,
+        "cancellation_policy": get_cancellation_policy(service.tenant, service),
+        "cancellation_policy_override": service.cancellation_policy
+    }
+
+
+@app.get("/masters/{master_id}")
+async def get_master(
+    master_id: int,
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Get master of the tenant with the linked user account, inactive ones included.
+    """
+    master = db.query(Master).filter(
+        Master.id == master_id,
+        Master.tenant_id == tenant_id
+    ).first()
+
+    if not master:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.MASTER_NOT_FOUND
+        )
+
+    user = db.query(User).filter(User.id == master.user_id).first() if master.user_id else None
+
+    detail = master_detail(db, master)
+    detail.update({
+        "user": {
+            "id": user.id,
+            "email": user.email,
+            "full_name": user.full_name,
+            "role": user.role.value,
+            "is_active": user.is_active
+        } if user else None,
+        "created_at": master.created_at.isoformat() if master.created_at else None,
+        "updated_at": master.updated_at.isoformat() if master.updated_at else None
+    })
+    return detail
+
+
+def get_tenant_master(db: Session, master_id: int, tenant_id: int) -> Master:
+    """Load master of the tenant, inactive ones included, or raise 404."""
+    master = db.query(Master).filter(
+        Master.id == master_id,
+        Master.tenant_id == tenant_id
+    ).first()
+
+    if not master:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.MASTER_NOT_FOUND
+        )
+
+    return master
+
+
+def get_tenant_location(db: Session, location_id: int, tenant_id: int) -> Location:
+    """Load location of the tenant or raise 404."""
+    location = db.query(Location).filter(
+        Location.id == location_id,
+        Location.tenant_id == tenant_id
+    ).first()
+
+    if not location:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.LOCATION_NOT_FOUND
+        )
+
+    return location
+
+
+def location_hours_response(location: Location) -> List[dict]:
+    """Weekly opening hours of the location by day of week."""
+    return [
+        {
+            "day_of_week": h.day_of_week,
+            "start_time": h.start_time.strftime("%H:%M"),
+            "end_time": h.end_time.strftime("%H:%M"),
+            "is_open": h.is_open
+        }
+        for h in sorted(location.hours, key=lambda h: h.day_of_week)
+    ]
+
+
+def master_schedule_response(db: Session, master: Master) -> dict:
+    """Weekly schedule of the master with one-off overrides from today on."""
+    today = tenant_local_now(master.tenant).date()
+    overrides = db.query(MasterScheduleOverride).filter(
+        MasterScheduleOverride.master_id == master.id,
+        MasterScheduleOverride.date >= today
+    ).order_by(MasterScheduleOverride.date).all()
+
+    return {
+        "master_id": master.id,
+        "weekly": [
+            {
+                "day_of_week": s.day_of_week,
+                "start_time": s.start_time.strftime("%H:%M"),
+                "end_time": s.end_time.strftime("%H:%M"),
+                "is_working": s.is_working
+            }
+            for s in sorted(master.schedules, key=lambda s: s.day_of_week)
+        ],
+        "overrides": [
+            {
+                "date": o.date.isoformat(),
+                "is_working": o.is_working,
+                "start_time": o.start_time.strftime("%H:%M") if o.is_working else None,
+                "end_time": o.end_time.strftime("%H:%M") if o.is_working else None,
+                "reason": o.reason
+            }
+            for o in overrides
+        ]
+    }
+
+
+@app.get("/masters/{master_id}/schedule")
+async def get_master_schedule(
+    master_id: int,
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Get working schedule of a master: weekly hours and upcoming one-off
+    overrides (days off, changed hours).
+    """
+    master = get_tenant_master(db, master_id, tenant_id)
+    return master_schedule_response(db, master)
+
+
+@app.put("/masters/{master_id}/schedule")
+async def set_master_schedule(
+    master_id: int,
+    data: MasterScheduleRequest,
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Replace weekly working hours of a master.
+
+    Days left out are days off. Existing bookings are kept even if they
+    fall outside the new hours; overrides are not touched.
+    """
+    master = get_tenant_master(db, master_id, tenant_id)
+
+    master.schedules = [
+        MasterSchedule(
+            day_of_week=hours.day_of_week,
+            start_time=hours.start_time,
+            end_time=hours.end_time,
+            is_working=hours.is_working
+        )
+        for hours in data.weekly
+    ]
+    db.commit()
+    invalidate_bookable_availability(master)
+
+    logger.info(f"Master schedule updated: master={master.id}, days={len(data.weekly)}")
+
+    return master_schedule_response(db, master)
+
+
+@app.get("/locations/{location_id}/hours")
+async def get_location_hours(
+    location_id: int,
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Get weekly opening hours of a location. An empty list means the
+    location is open whenever its masters work.
+    """
+    location = get_tenant_location(db, location_id, tenant_id)
+    return {"location_id": location.id, "hours": location_hours_response(location)}
+
+
+@app.put("/locations/{location_id}/hours")
+async def set_location_hours(
+    location_id: int,
+    data: LocationHoursRequest,
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Replace weekly opening hours of a location.
+
+    Availability of its masters is limited to the intersection of their
+    working hours and the opening hours; days left out are closed. An
+    empty list removes the limit. Existing bookings are kept.
+    """
+    location = get_tenant_location(db, location_id, tenant_id)
+
+    location.hours = [
+        LocationHours(
+            day_of_week=hours.day_of_week,
+            start_time=hours.start_time,
+            end_time=hours.end_time,
+            is_open=hours.is_working
+        )
+        for hours in data.weekly
+    ]
+    db.commit()
+
+    for master in location.masters:
+        invalidate_bookable_availability(master)
+
+    logger.info(f"Location hours updated: location={location.id}, days={len(data.weekly)}")
+
+    return {"location_id": location.id, "hours": location_hours_response(location)}
+
+
+@app.put("/masters/{master_id}/schedule/overrides/{day}")
+async def set_master_schedule_override(
+    master_id: int,
+    day: date,
+    data: ScheduleOverrideRequest,
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Set one-off working hours of a master on a date, or a day off
+    (is_working false), replacing the weekly hours of that date.
+    """
+    master = get_tenant_master(db, master_id, tenant_id)
+
+    override = db.query(MasterScheduleOverride).filter(
+        MasterScheduleOverride.master_id == master.id,
+        MasterScheduleOverride.date == day
+    ).first()
+
+    if not override:
+        override = MasterScheduleOverride(master_id=master.id, date=day)
+        db.add(override)
+
+    override.is_working = data.is_working
+    override.start_time = data.start_time if data.is_working else None
+    override.end_time = data.end_time if data.is_working else None
+    override.reason = data.reason
+    db.commit()
+    invalidate_availability(tenant_id, master.id, day.isoformat())
+
+    logger.info(f"Master schedule override set: master={master.id}, date={day}, working={data.is_working}")
+
+    return master_schedule_response(db, master)
+
+
+@app.delete("/masters/{master_id}/schedule/overrides/{day}")
+async def delete_master_schedule_override(
+    master_id: int,
+    day: date,
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Remove the one-off override of a date, so the weekly hours apply again.
+    """
+    master = get_tenant_master(db, master_id, tenant_id)
+
+    deleted = db.query(MasterScheduleOverride).filter(
+        MasterScheduleOverride.master_id == master.id,
+        MasterScheduleOverride.date == day
+    ).delete(synchronize_session=False)
+    db.commit()
+
+    if deleted:
+        invalidate_availability(tenant_id, master.id, day.isoformat())
+
+    return master_schedule_response(db, master)
+
+
+def get_time_off_master(db: Session, master_id: int, tenant_id: int, user_id: int, role: str) -> Master:
+    """Load master whose time off the caller manages; masters only manage their own."""
+    master = get_tenant_master(db, master_id, tenant_id)
+
+    if role == UserRole.MASTER.value and master.user_id != user_id:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    return master
+
+
+def get_master_time_off(db: Session, master: Master, time_off_id: int) -> MasterTimeOff:
+    """Load time off of the master or raise 404."""
+    time_off = db.query(MasterTimeOff).filter(
+        MasterTimeOff.id == time_off_id,
+        MasterTimeOff.master_id == master.id
+    ).first()
+
+    if not time_off:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.TIME_OFF_NOT_FOUND
+        )
+
+    return time_off
+
+
+def check_time_off_conflicts(db: Session, master: Master, start_date: date, end_date: date) -> None:
+    """Raise 409 if the master has bookings holding a slot within the dates."""
+    conflicts = BookingService(db).get_time_off_conflicts(master.id, start_date, end_date)
+
+    if conflicts:
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.TIME_OFF_CONFLICT,
+            details={"booking_ids": [b.id for b in conflicts]}
+        )
+
+
+def time_off_response(time_off: MasterTimeOff) -> dict:
+    return {
+        "id": time_off.id,
+        "master_id": time_off.master_id,
+        "start_date": time_off.start_date.isoformat(),
+        "end_date": time_off.end_date.isoformat(),
+        "reason": time_off.reason,
+        "status": time_off.status.value,
+        "requested_by": time_off.requested_by,
+        "reviewed_by": time_off.reviewed_by,
+        "created_at": time_off.created_at.isoformat() if time_off.created_at else None
+    }
+
+
+@app.get("/masters/{master_id}/time-off")
+async def list_time_off(
+    master_id: int,
+    tenant_id: int = Query(...),
+    user_id: int = Query(...),
+    role: str = Query(...),
+    include_past: bool = Query(False),
+    db: Session = Depends(get_read_db)
+):
+    """
+    List time off of a master, ending today or later unless include_past.
+    """
+    master = get_time_off_master(db, master_id, tenant_id, user_id, role)
+
+    query = db.query(MasterTimeOff).filter(MasterTimeOff.master_id == master.id)
+    if not include_past:
+        query = query.filter(MasterTimeOff.end_date >= tenant_local_now(master.tenant).date())
+
+    return {"time_off": [time_off_response(t) for t in query.order_by(MasterTimeOff.start_date).all()]}
+
+
+@app.post("/masters/{master_id}/time-off", status_code=status.HTTP_201_CREATED)
+async def create_time_off(
+    master_id: int,
+    data: TimeOffRequest,
+    tenant_id: int = Query(...),
+    user_id: int = Query(...),
+    role: str = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Add time off of a master over a date range, inclusive.
+
+    Requested by the master it waits for approval if
+    TIME_OFF_REQUIRES_APPROVAL is set, otherwise it's approved at once.
+    Rejected while the master has bookings within the dates; approved
+    time off leaves no free slots on its dates.
+    """
+    master = get_time_off_master(db, master_id, tenant_id, user_id, role)
+
+    if data.end_date < data.start_date or (data.end_date - data.start_date).days + 1 > settings.TIME_OFF_MAX_DAYS:
+        raise APIError(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            code=ErrorCode.INVALID_DATE_RANGE,
+            details={"max_days": settings.TIME_OFF_MAX_DAYS}
+        )
+
+    # Bookings of the master are created under this lock, so none can
+    # slip into the dates between the check and the commit
+    BookingService(db).lock_master(master.id)
+    check_time_off_conflicts(db, master, data.start_date, data.end_date)
+
+    needs_approval = role == UserRole.MASTER.value and settings.TIME_OFF_REQUIRES_APPROVAL
+    time_off = MasterTimeOff(
+        master_id=master.id,
+        start_date=data.start_date,
+        end_date=data.end_date,
+        reason=data.reason,
+        status=TimeOffStatus.PENDING if needs_approval else TimeOffStatus.APPROVED,
+        requested_by=user_id,
+        reviewed_by=None if needs_approval else user_id
+    )
+    db.add(time_off)
+    db.commit()
+    db.refresh(time_off)
+
+    if time_off.status == TimeOffStatus.APPROVED:
+        invalidate_master_availability(tenant_id, master.id, time_off.start_date, time_off.end_date)
+
+    logger.info(
+        f"Time off added: master={master.id}, {data.start_date}..{data.end_date}, status={time_off.status.value}"
+    )
+
+    return time_off_response(time_off)
+
+
+@app.put("/masters/{master_id}/time-off/{time_off_id}/approve")
+async def approve_time_off(
+    master_id: int,
+    time_off_id: int,
+    tenant_id: int = Query(...),
+    user_id: int = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Approve time off requested by a master.
+
+    Rejected while the master has bookings within the dates, e.g. made
+    after the request.
+    """
+    master = get_tenant_master(db, master_id, tenant_id)
+    time_off = get_master_time_off(db, master, time_off_id)
+
+    if time_off.status != TimeOffStatus.PENDING:
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.CONFLICT,
+            details={"status": time_off.status.value}
+        )
+
+    BookingService(db).lock_master(master.id)
+    check_time_off_conflicts(db, master, time_off.start_date, time_off.end_date)
+
+    time_off.status = TimeOffStatus.APPROVED
+    time_off.reviewed_by = user_id
+    db.commit()
+    invalidate_master_availability(tenant_id, master.id, time_off.start_date, time_off.end_date)
+
+    logger.info(f"Time off approved: ID={time_off.id}, master={master.id}")
+
+    return time_off_response(time_off)
+
+
+@app.put("/masters/{master_id}/time-off/{time_off_id}/reject")
+async def reject_time_off(
+    master_id: int,
+    time_off_id: int,
+    tenant_id: int = Query(...),
+    user_id: int = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Reject time off requested by a master.
+    """
+    master = get_tenant_master(db, master_id, tenant_id)
+    time_off = get_master_time_off(db, master, time_off_id)
+
+    if time_off.status != TimeOffStatus.PENDING:
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.CONFLICT,
+            details={"status": time_off.status.value}
+        )
+
+    time_off.status = TimeOffStatus.REJECTED
+    time_off.reviewed_by = user_id
+    db.commit()
+
+    return time_off_response(time_off)
+
+
+@app.delete("/masters/{master_id}/time-off/{time_off_id}")
+async def delete_time_off(
+    master_id: int,
+    time_off_id: int,
+    tenant_id: int = Query(...),
+    user_id: int = Query(...),
+    role: str = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Delete time off, freeing its dates again if it was approved.
+    """
+    master = get_time_off_master(db, master_id, tenant_id, user_id, role)
+    time_off = get_master_time_off(db, master, time_off_id)
+    was_approved = time_off.status == TimeOffStatus.APPROVED
+    start_date, end_date = time_off.start_date, time_off.end_date
+
+    db.delete(time_off)
+    db.commit()
+
+    if was_approved:
+        invalidate_master_availability(tenant_id, master.id, start_date, end_date)
+
+    return {"message": "Time off deleted", "id": time_off_id}
+
+
+@app.get("/public/business/{subdomain}/availability")
+async def check_availability(
+    subdomain: str,
+    master_id: Optional[int] = Query(None),
+    date: date = Query(...),
+    service_ids: Optional[List[int]] = Query(None),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Check master availability for a specific date.
+    Returns available time slots.
+
+    With service_ids only slots fitting all the services back to back
+    are returned. Without master_id (service_ids required) returns slots
+    where any master providing the services is free, with the free
+    masters of each slot.
+
+    Slots within the minimum lead time from now (tenant timezone) are
+    not offered.
+    """
+    tenant = get_public_tenant(db, subdomain)
+
+    if master_id is None:
+        services = get_booking_services(db, tenant.id, None, service_ids)
+        duration = sum(s.duration_minutes for s in services)
+        # A combo starts on the first service's grid
+        interval = get_slot_interval(tenant, services[0])
+        not_before = earliest_booking_start(tenant, services)
+
+        masters_by_slot = {}
+        for master in BookingService(db).get_qualified_masters(tenant.id, service_ids):
+            slots = get_master_slots(
+                db, tenant.id, master.id, date, duration, interval, single_service(services), not_before
+            )
+            for slot in slots["available_slots"]:
+                masters_by_slot.setdefault(slot, []).append(master.id)
+
+        return {
+            "date": date.isoformat(),
+            "master_id": None,
+            "duration_minutes": duration,
+            "slot_interval_minutes": interval,
+            "available_slots": sorted(masters_by_slot),
+            "masters_by_slot": masters_by_slot
+        }
+
+    master = db.query(Master).filter(
+        Master.id == master_id,
+        Master.tenant_id == tenant.id,
+        Master.is_active == True,
+        Master.is_visible == True
+    ).first()
+
+    if not master:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.MASTER_NOT_FOUND
+        )
+
+    if not master.is_accepting_bookings:
+        return {
+            "date": date.isoformat(),
+            "master_id": master_id,
+            "accepting_bookings": False,
+            "available_slots": []
+        }
+
+    interval = duration = get_slot_interval(tenant)
+    service = None
+    services = []
+    if service_ids:
+        services = get_booking_services(db, tenant.id, master_id, service_ids)
+        duration = sum(s.duration_minutes for s in services)
+        interval = get_slot_interval(tenant, services[0])
+        service = single_service(services)
+
+    return get_master_slots(
+        db, tenant.id, master_id, date, duration, interval, service, earliest_booking_start(tenant, services)
+    )
+
+
+@app.get("/public/business/{subdomain}/available-dates")
+async def get_available_dates(
+    subdomain: str,
+    month: str = Query(..., pattern=r"^\d{4}-(0[1-9]|1[0-2])$"),
+    master_id: Optional[int] = Query(None),
+    service_ids: Optional[List[int]] = Query(None),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Dates of the month (YYYY-MM) with any available slot, for a date picker.
+
+    Takes the same master_id and service_ids as availability; without
+    master_id (service_ids required) a date is available when any master
+    providing the services is free on it. Past dates are not available.
+    """
+    tenant = get_public_tenant(db, subdomain)
+    first_day = datetime.strptime(month, "%Y-%m").date()
+
+    if master_id is None:
+        services = get_booking_services(db, tenant.id, None, service_ids)
+        masters = BookingService(db).get_qualified_masters(tenant.id, service_ids)
+    else:
+        master = db.query(Master).filter(
+            Master.id == master_id,
+            Master.tenant_id == tenant.id,
+            Master.is_active == True,
+            Master.is_visible == True
+        ).first()
+
+        if not master:
+            raise APIError(
+                status_code=status.HTTP_404_NOT_FOUND,
+                code=ErrorCode.MASTER_NOT_FOUND
+            )
+
+        services = get_booking_services(db, tenant.id, master_id, service_ids) if service_ids else []
+        masters = [master] if master.is_accepting_bookings else []
+
+    if services:
+        duration = sum(s.duration_minutes for s in services)
+        interval = get_slot_interval(tenant, services[0])
+    else:
+        interval = duration = get_slot_interval(tenant)
+    not_before = earliest_booking_start(tenant, services)
+
+    available = set()
+    for master in masters:
+        available.update(get_master_available_dates(
+            db, tenant.id, master.id, first_day, duration, interval, single_service(services), not_before
+        ))
+
+    return {
+        "month": month,
+        "master_id": master_id,
+        "duration_minutes": duration,
+        "available_dates": sorted(available)
+    }
+
+
+@app.post("/availability/warm")
+async def warm_availability(
+    tenant_id: int = Query(...),
+    days: int = Query(settings.AVAILABILITY_WARM_DAYS, ge=1, le=settings.BOOKING_ADVANCE_LIMIT_DAYS),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Pre-compute cached availability of the tenant's bookable masters.
+
+    Covers today and the following days the master works (weekly hours
+    and one-off overrides), for the default slot and for each service the
+    master provides, so public availability requests hit the cache.
+    Entries still cached are left as they are.
+    """
+    tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+
+    if not tenant:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.TENANT_NOT_FOUND
+        )
+
+    today = tenant_local_now(tenant).date()
+    masters = db.query(Master).filter(
+        Master.tenant_id == tenant.id,
+        *bookable_master_filter()
+    ).all()
+
+    booking_service = BookingService(db)
+    warmed = 0
+    for master in masters:
+
+        # Requests differing only in the service share cache entries
+        default_interval = get_slot_interval(tenant)
+        variants = {(default_interval, default_interval, None): None}
+        for ms in master.master_services:
+            if ms.service.is_active:
+                group = ms.service if group_capacity(ms.service) > 1 else None
+                key = (get_slot_interval(tenant, ms.service), ms.service.duration_minutes, group.id if group else None)
+                variants[key] = group
+
+        for offset in range(days):
+            day = today + timedelta(days=offset)
+            if not booking_service.get_working_hours(master.id, day):
+                continue
+
+            for (interval, duration, _), group in variants.items():
+                get_master_slots(db, tenant.id, master.id, day, duration, interval, group)
+                warmed += 1
+
+    logger.info(f"Availability warmed: tenant={tenant.id}, masters={len(masters)}, entries={warmed}")
+
+    return {"tenant_id": tenant.id, "masters": len(masters), "days": days, "entries": warmed}
+
+
+@app.post("/public/booking", status_code=status.HTTP_201_CREATED)
+async def create_public_booking(data: CreateBookingRequest, db: Session = Depends(get_db)):
+    """
+    Create a new booking (public endpoint).
+    Sends WhatsApp confirmation.
+
+    If the service requires a deposit, the booking stays PENDING until
+    the deposit is paid and is cancelled if not paid in time.
+    """
+    tenant = get_public_tenant(db, data.subdomain)
+
+    def book(db: Session):
+        # Get or create client
+        client = db.query(Client).filter(Client.phone == data.client_phone).first()
+        if not client:
+            client = Client(
+                phone=data.client_phone,
+                full_name=data.client_name,
+                email=data.client_email
+            )
+            db.add(client)
+            db.flush()
+        elif data.client_email and not client.email:
+            client.email = data.client_email
+
+        # Get services, a single service_id or a combo of service_ids
+        services = get_booking_services(db, tenant.id, data.master_id, data.service_ids or [data.service_id])
+        service = services[0]
+        pricing = price_booking_items(tenant, services)
+
+        not_before = earliest_booking_start(tenant, services)
+        if data.booking_date.replace(tzinfo=None) < not_before:
+            raise APIError(
+                status_code=status.HTTP_409_CONFLICT,
+                code=ErrorCode.BOOKING_TOO_SOON,
+                details={"min_lead_minutes": max(get_min_lead_minutes(tenant, s) for s in services)}
+            )
+
+        # Check availability for all services back to back. The master stays
+        # locked until commit so concurrent requests can't take the same slot.
+        booking_service = BookingService(db)
+        if data.master_id:
+            master = booking_service.lock_master(data.master_id)
+
+            if not master or master.tenant_id != tenant.id or not master.is_active or not master.is_visible:
+                raise APIError(
+                    status_code=status.HTTP_404_NOT_FOUND,
+                    code=ErrorCode.MASTER_NOT_FOUND
+                )
+
+            if not master.is_accepting_bookings:
+                raise APIError(
+                    status_code=status.HTTP_409_CONFLICT,
+                    code=ErrorCode.MASTER_NOT_ACCEPTING_BOOKINGS
+                )
+
+            if not booking_service.is_slot_available(
+                master.id, data.booking_date, pricing["duration_minutes"], service=single_service(services)
+            ):
+                raise APIError(
+                    status_code=status.HTTP_409_CONFLICT,
+                    code=ErrorCode.SLOT_UNAVAILABLE
+                )
+        else:
+            master = booking_service.find_available_master(
+                tenant.id, [s.id for s in services], data.booking_date, pricing["duration_minutes"],
+                service=single_service(services)
+            )
+
+            if not master:
+                raise APIError(
+                    status_code=status.HTTP_409_CONFLICT,
+                    code=ErrorCode.NO_MASTER_AVAILABLE
+                )
+
+        currency = tenant_currency(tenant)
+        tax_config = get_tax_config(tenant, service)
+        deposit_amount = pricing["deposit"]
+
         # Create booking
         booking = Booking(
             tenant_id=tenant.id,
             client_id=client.id,
-            master_id=data.master_id,
-            service_id=data.service_id,
+            master_id=master.id,
+            service_id=service.id,
             booking_date=data.booking_date,
-            duration_minutes=service.duration_minutes,
-            price=service.price,
+            duration_minutes=pricing["duration_minutes"],
+            price=pricing["total"],
+            currency=currency,
+            tax_amount=pricing["tax"],
+            tax_rate=tax_config["rate_bp"],  # of the first service, combo items keep their own
+            tax_inclusive=tax_config["inclusive"],
             status=BookingStatus.CONFIRMED,
-            client_notes=data.notes
+            client_notes=data.notes,
+            reminder_channels=(
+                [c.value for c in data.reminder_channels] if data.reminder_channels is not None else None
+            ),
+            items=pricing["items"]
+        )
+
+        if deposit_amount is not None:
+            booking.status = BookingStatus.PENDING
+            booking.payment_due_at = datetime.utcnow() + timedelta(
+                minutes=settings.DEPOSIT_PAYMENT_TIMEOUT_MINUTES
+            )
+
+        db.add(booking)
+        db.flush()
+
+        payment = None
+        if deposit_amount is not None:
+            payment = Payment(
+                tenant_id=tenant.id,
+                booking_id=booking.id,
+                amount=deposit_amount,
+                currency=currency,
+                status=PaymentStatus.PENDING
+            )
+            db.add(payment)
+
+        return booking, master, payment
+
+    try:
+        booking, master, payment = run_in_transaction(db, book)
+    except APIError:
+        raise
+    except Exception as e:
+        logger.error(f"Booking creation failed: {e}")
+        raise APIError(
+            status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
+            code=ErrorCode.BOOKING_FAILED
+        )
+
+    db.refresh(booking)
+    invalidate_booking_availability(booking, booking.booking_date)
+    publish_booking_event(booking, BookingEvent.CREATED)
+
+    logger.info(f"Booking created: ID={booking.id}, status={booking.status.value}")
+
+    if payment:
+        checkout_url = settings.PAYMENT_CHECKOUT_URL.format(payment_id=payment.id)
+
+        message = render_sms(
+            SmsTemplate.BOOKING_DEPOSIT_PENDING,
+            tenant.language,
+            business_name=tenant.business_name,
+            service_name=booking.service_names,
+            booking_date=format_datetime(data.booking_date, tenant.language),
+            deposit=format_money(payment.amount, payment.currency, tenant.language),
+            minutes=settings.DEPOSIT_PAYMENT_TIMEOUT_MINUTES,
+            checkout_url=checkout_url
+        )
+        await send_whatsapp_message(
+            data.client_phone, message, SmsTemplate.BOOKING_DEPOSIT_PENDING.value, booking
+        )
+
+        return {
+            "message": "Booking awaits deposit payment",
+            "booking_id": booking.id,
+            "public_code": booking.public_code,
+            "master_id": booking.master_id,
+            "master_name": master.full_name,
+            "booking_date": booking.booking_date.isoformat(),
+            "status": booking.status.value,
+            "deposit": {
+                "payment_id": payment.id,
+                "amount": to_major(payment.amount),
+                "amount_minor": payment.amount,
+                "currency": payment.currency,
+                "checkout_url": checkout_url,
+                "pay_before": booking.payment_due_at.isoformat()
+            }
+        }
+
+    # Send WhatsApp confirmation
+    await send_whatsapp_message(
+        data.client_phone, booking_confirmed_message(booking), SmsTemplate.BOOKING_CONFIRMED.value, booking
+    )
+
+    return {
+        "message": "Booking created successfully",
+        "booking_id": booking.id,
+        "public_code": booking.public_code,
+        "master_id": booking.master_id,
+        "master_name": master.full_name,
+        "booking_date": booking.booking_date.isoformat(),
+        "status": booking.status.value
+    }
+
+
+@app.post("/public/booking/resend-confirmation")
+async def resend_public_booking_confirmation(data: ResendConfirmationRequest, db: Session = Depends(get_db)):
+    """
+    Resend confirmation of a booking by its public code and the client's email.
+
+    A code and email not matching the same booking are reported as not
+    found, so bookings can't be probed.
+    """
+    booking = db.query(Booking).filter(Booking.public_code == data.code).first()
+
+    if not booking or not booking.client.email or booking.client.email.lower() != data.email.lower():
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.BOOKING_NOT_FOUND
+        )
+
+    return await resend_booking_confirmation(booking)
+
+
+@app.get("/public/booking/{code}/calendar.ics")
+async def get_booking_calendar(code: str, db: Session = Depends(get_db)):
+    """
+    Get calendar file (RFC 5545) of a booking by its public code.
+    """
+    booking = db.query(Booking).filter(Booking.public_code == code).first()
+
+    if not booking or booking.status not in (BookingStatus.PENDING, BookingStatus.CONFIRMED):
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.BOOKING_NOT_FOUND
+        )
+
+    return Response(
+        content=booking_ics(booking),
+        media_type=ICS_CONTENT_TYPE,
+        headers={"Content-Disposition": f'attachment; filename="booking-{booking.id}.ics"'}
+    )
+
+
+# Most bookings per page of the paginated bookings list
+BOOKINGS_MAX_PAGE_SIZE = 200
+
+
+def filter_bookings(query, day: Optional[date], booking_status: Optional[str]):
+    """Apply the booking list filters: booking day and status."""
+    if day:
+        query = query.filter(
+            Booking.booking_date >= datetime.combine(day, time.min),
+            Booking.booking_date <= datetime.combine(day, time.max)
+        )
+
+    if booking_status:
+        query = query.filter(Booking.status == booking_status)
+
+    return query
+
+
+@app.get("/bookings")
+async def get_bookings(
+    user_id: int = Query(...),
+    role: str = Query(...),
+    tenant_id: Optional[int] = Query(None),
+    date: Optional[date] = Query(None),
+    status: Optional[str] = Query(None),
+    limit: Optional[int] = Query(None, ge=1, le=BOOKINGS_MAX_PAGE_SIZE),
+    page: Optional[int] = Query(None, ge=1),
+    cursor: Optional[str] = Query(None),
+    db: Session = Depends(get_db)
+):
+    """
+    Get bookings filtered by user role.
+
+    Without limit all bookings are returned, latest booking date first.
+    With limit they're paginated newest created first, by page (offset,
+    with total) or by cursor (keyset on created_at, id): next_cursor of
+    a page fetches the following one.
+    """
+    query = db.query(Booking)
+
+    # Staff only see bookings of their own tenant
+    if role != UserRole.SUPER_ADMIN.value:
+        if not tenant_id:
+            return {"bookings": []}
+        query = query.filter(Booking.tenant_id == tenant_id)
+
+    # Filter based on role
+    if role == "MASTER":
+        # Get master record for this user
+        master = db.query(Master).filter(Master.user_id == user_id).first()
+        if master:
+            query = query.filter(Booking.master_id == master.id)
+        else:
+            return {"bookings": []}
+
+    query = filter_bookings(query, date, status)
+    pagination = {}
+
+    if limit is None:
+        bookings = query.order_by(Booking.booking_date.desc()).all()
+    else:
+        if cursor:
+            created_at, last_id = decode_cursor(cursor)
+            query = query.filter(
+                or_(
+                    Booking.created_at < created_at,
+                    and_(Booking.created_at == created_at, Booking.id < last_id)
+                )
+            )
+        elif page:
+            pagination = {"page": page, "total": query.count()}
+            query = query.offset((page - 1) * limit)
+
+        # One extra row tells if there is a next page
+        bookings = query.order_by(Booking.created_at.desc(), Booking.id.desc()).limit(limit + 1).all()
+        has_more = len(bookings) > limit
+        bookings = bookings[:limit]
+
+        pagination.update({
+            "limit": limit,
+            "next_cursor": encode_cursor(bookings[-1].created_at, bookings[-1].id) if has_more else None
+        })
+
+    return {
+        **pagination,
+        "bookings": [
+            {
+                "id": b.id,
+                "booking_date": b.booking_date.isoformat(),
+                "status": b.status.value,
+                "client_name": b.client.full_name if b.client else None,
+                "client_phone": b.client.phone if b.client else None,
+                "client_no_show_count": b.client.no_show_count if b.client else 0,
+                "master_name": b.master.full_name if b.master else None,
+                "price": to_major(b.price),
+                "price_minor": b.price,
+                "currency": b.currency,
+                "created_at": b.created_at.isoformat()
+            }
+            for b in bookings
+        ]
+    }
+
+
+# Days shown in the master's "my day" view by default and at most
+MY_BOOKINGS_DEFAULT_DAYS = 3
+MY_BOOKINGS_MAX_DAYS = 14
+
+
+@app.get("/my/bookings")
+async def get_my_bookings(
+    user_id: int = Query(...),
+    tenant_id: int = Query(...),
+    days: int = Query(MY_BOOKINGS_DEFAULT_DAYS, ge=1, le=MY_BOOKINGS_MAX_DAYS),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Get upcoming bookings of the master linked to the user.
+
+    Covers today and the following days (days in total) in the tenant's
+    local time, grouped by day. Cancelled bookings are left out.
+    """
+    master = db.query(Master).filter(
+        Master.user_id == user_id,
+        Master.tenant_id == tenant_id
+    ).first()
+
+    if not master:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.MASTER_NOT_FOUND
+        )
+
+    today = tenant_local_now(master.tenant).date()
+    last_day = today + timedelta(days=days - 1)
+
+    bookings = db.query(Booking).filter(
+        Booking.master_id == master.id,
+        Booking.status != BookingStatus.CANCELLED,
+        Booking.booking_date >= datetime.combine(today, time.min),
+        Booking.booking_date <= datetime.combine(last_day, time.max)
+    ).order_by(Booking.booking_date).all()
+
+    by_day = {today + timedelta(days=offset): [] for offset in range(days)}
+    for b in bookings:
+        by_day[b.booking_date.date()].append({
+            "id": b.id,
+            "booking_date": b.booking_date.isoformat(),
+            "duration_minutes": b.duration_minutes,
+            "status": b.status.value,
+            "client_name": b.client.full_name if b.client else None,
+            "client_phone": b.client.phone if b.client else None,
+            "service_name": b.service_names or None,
+            "client_notes": b.client_notes
+        })
+
+    return {
+        "master_id": master.id,
+        "master_name": master.full_name,
+        "days": [
+            {"date": day.isoformat(), "bookings": day_bookings}
+            for day, day_bookings in by_day.items()
+        ]
+    }
+
+
+@app.post("/my/calendar-token")
+async def rotate_my_calendar_token(
+    user_id: int = Query(...),
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Issue a new calendar feed token for the master linked to the user.
+
+    The previous feed URL stops working.
+    """
+    master = db.query(Master).filter(
+        Master.user_id == user_id,
+        Master.tenant_id == tenant_id
+    ).first()
+
+    if not master:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.MASTER_NOT_FOUND
+        )
+
+    master.calendar_token = secrets.token_urlsafe(32)
+    db.commit()
+
+    logger.info(f"Calendar feed token rotated for master {master.id}")
+
+    return {
+        "master_id": master.id,
+        "feed_url": settings.CALENDAR_FEED_URL.format(token=master.calendar_token)
+    }
+
+
+@app.get("/public/calendar/{token}.ics")
+async def get_master_calendar(token: str, db: Session = Depends(get_read_db)):
+    """
+    Get calendar feed (RFC 5545) of a master by the feed token.
+
+    Calendar apps can't send auth headers, so the token in the URL
+    authorizes access. Contains confirmed bookings from the last
+    CALENDAR_FEED_PAST_DAYS days on (completed ones too, so past events
+    stay), and cancelled ones so subscribed calendars drop them.
+    """
+    master = db.query(Master).filter(
+        Master.calendar_token == token,
+        Master.is_active == True
+    ).first()
+
+    if not master:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.MASTER_NOT_FOUND
+        )
+
+    since = tenant_local_now(master.tenant) - timedelta(days=settings.CALENDAR_FEED_PAST_DAYS)
+
+    bookings = db.query(Booking).filter(
+        Booking.master_id == master.id,
+        Booking.status.in_([BookingStatus.CONFIRMED, BookingStatus.COMPLETED, BookingStatus.CANCELLED]),
+        Booking.booking_date >= since
+    ).order_by(Booking.booking_date).all()
+
+    return Response(
+        content=build_calendar(bookings, name=master.full_name, for_master=True),
+        media_type=ICS_CONTENT_TYPE,
+        headers={"Cache-Control": "no-cache"}
+    )
+
+
+@app.get("/booking/{booking_id}")
+async def get_booking(
+    booking_id: int,
+    user_id: Optional[int] = Query(None),
+    role: Optional[str] = Query(None),
+    tenant_id: Optional[int] = Query(None),
+    language: Optional[str] = Query(None),
+    session_token: Optional[str] = Header(None, alias="X-Client-Session"),
+    db: Session = Depends(get_db)
+):
+    """
+    Get booking details with price breakdown.
+
+    Staff pass user_id, role and tenant_id, see get_scoped_booking.
+    Clients pass their session instead and get their own booking in the
+    client representation.
+    """
+    if session_token:
+        session = get_client_session(db, session_token)
+        return client_booking_response(get_client_booking(db, booking_id, session.client_id), language)
+
+    if user_id is None or not role or role == CLIENT_ROLE:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    booking = get_scoped_booking(db, booking_id, user_id, role, tenant_id)
+
+    return {
+        "id": booking.id,
+        "booking_date": booking.booking_date.isoformat(),
+        "duration_minutes": booking.duration_minutes,
+        "status": booking.status.value,
+        "client_name": booking.client.full_name if booking.client else None,
+        "client_phone": booking.client.phone if booking.client else None,
+        "client_no_show_count": booking.client.no_show_count if booking.client else 0,
+        "master_name": booking.master.full_name if booking.master else None,
+        "service_name": booking.service_names or None,
+        "services": booking_services(booking),
+        "price": to_major(booking.price),
+        "price_minor": booking.price,
+        "currency": booking.currency,
+        "price_breakdown": price_breakdown(booking),
+        "client_notes": booking.client_notes,
+        "notes": booking.admin_notes
+    }
+
+
+@app.put("/booking/{booking_id}")
+async def update_booking(
+    booking_id: int,
+    data: UpdateBookingRequest,
+    db: Session = Depends(get_db)
+):
+    """
+    Update booking date, status or staff notes.
+
+    Only pending and confirmed bookings can be moved, and only from PENDING
+    to CONFIRMED; completion, no-shows and cancellation go through their
+    own endpoints.
+    """
+    booking = get_scoped_booking(db, booking_id, data.user_id, data.role, data.tenant_id)
+    previous_date = booking.booking_date
+
+    if (data.status is not None or data.booking_date is not None) and booking.status not in [
+        BookingStatus.PENDING, BookingStatus.CONFIRMED
+    ]:
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.INVALID_BOOKING_STATUS,
+            details={"status": booking.status.value}
+        )
+
+    if data.status is not None:
+        try:
+            new_status = BookingStatus(data.status)
+        except ValueError:
+            raise APIError(
+                status_code=status.HTTP_400_BAD_REQUEST,
+                code=ErrorCode.INVALID_BOOKING_STATUS
+            )
+
+        if new_status in DEDICATED_STATUSES:
+            raise APIError(
+                status_code=status.HTTP_400_BAD_REQUEST,
+                code=ErrorCode.INVALID_BOOKING_STATUS,
+                details={"status": new_status.value}
+            )
+
+        # A pending booking may be confirmed, nothing goes back to pending
+        if new_status != booking.status and not (
+            new_status == BookingStatus.CONFIRMED
+            and BookingService(db).transition_status(booking, new_status, [BookingStatus.PENDING])
+        ):
+            raise APIError(
+                status_code=status.HTTP_409_CONFLICT,
+                code=ErrorCode.INVALID_BOOKING_STATUS,
+                details={"status": booking.status.value}
+            )
+
+    if data.booking_date is not None and data.booking_date != booking.booking_date:
+        booking_service = BookingService(db)
+        booking_service.lock_master(booking.master_id)
+        if not booking_service.is_slot_available(
+            booking.master_id,
+            data.booking_date,
+            booking.duration_minutes,
+            exclude_booking_id=booking.id,
+            service=booking.service if len(booking.items) <= 1 else None
+        ):
+            raise APIError(
+                status_code=status.HTTP_409_CONFLICT,
+                code=ErrorCode.SLOT_UNAVAILABLE
+            )
+        booking.booking_date = data.booking_date
+
+    if data.notes is not None:
+        booking.admin_notes = data.notes
+
+    db.commit()
+    invalidate_booking_availability(booking, previous_date, booking.booking_date)
+    publish_booking_event(booking, BookingEvent.UPDATED)
+
+    logger.info(f"Booking updated: ID={booking.id}")
+
+    return {
+        "message": "Booking updated successfully",
+        "booking_id": booking.id,
+        "booking_date": booking.booking_date.isoformat(),
+        "status": booking.status.value
+    }
+
+
+@app.put("/booking/{booking_id}/complete")
+async def complete_booking(
+    booking_id: int,
+    data: CompleteBookingRequest,
+    db: Session = Depends(get_db)
+):
+    """
+    Mark booking as completed.
+
+    Only allowed once the appointment has started (in tenant timezone),
+    unless the owner forces it. Completing a completed booking again (a
+    retried request) succeeds without notifying anyone.
+    """
+    if data.force and data.role != UserRole.OWNER.value:
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FORBIDDEN
+        )
+
+    booking = get_scoped_booking(db, booking_id, data.user_id, data.role, data.tenant_id)
+
+    if booking.status == BookingStatus.COMPLETED:
+        return {
+            "message": "Booking already completed",
+            "booking_id": booking.id,
+            "status": booking.status.value
+        }
+
+    if booking.status != BookingStatus.CONFIRMED:
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.INVALID_BOOKING_STATUS,
+            details={"status": booking.status.value}
+        )
+
+    if not data.force and booking.booking_date > tenant_local_now(booking.tenant):
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.BOOKING_NOT_STARTED,
+            details={"booking_date": booking.booking_date.isoformat()}
+        )
+
+    completed = BookingService(db).transition_status(
+        booking, BookingStatus.COMPLETED, [BookingStatus.CONFIRMED]
+    )
+    db.commit()
+
+    if completed:
+        publish_booking_event(booking, BookingEvent.COMPLETED)
+        logger.info(f"Booking completed: ID={booking.id}, forced={data.force}")
+
+    return {
+        "message": "Booking completed successfully",
+        "booking_id": booking.id,
+        "status": booking.status.value
+    }
+
+
+@app.put("/booking/{booking_id}/no-show")
+async def mark_no_show(
+    booking_id: int,
+    data: NoShowBookingRequest,
+    db: Session = Depends(get_db)
+):
+    """
+    Mark that the client did not show up.
+
+    Only allowed once the appointment has started (in tenant timezone).
+    Marking a no-show again (a retried request) succeeds without counting
+    it twice.
+    """
+    booking = get_scoped_booking(db, booking_id, data.user_id, data.role, data.tenant_id)
+
+    if booking.status == BookingStatus.NO_SHOW:
+        return {
+            "message": "Booking already marked as no-show",
+            "booking_id": booking.id,
+            "status": booking.status.value
+        }
+
+    if booking.status != BookingStatus.CONFIRMED:
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.INVALID_BOOKING_STATUS,
+            details={"status": booking.status.value}
+        )
+
+    if booking.booking_date > tenant_local_now(booking.tenant):
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.BOOKING_NOT_STARTED,
+            details={"booking_date": booking.booking_date.isoformat()}
+        )
+
+    # Counted only by the request that changed the status, so concurrent
+    # or retried requests do not count the same no-show twice
+    marked = BookingService(db).transition_status(
+        booking, BookingStatus.NO_SHOW, [BookingStatus.CONFIRMED]
+    )
+    if marked:
+        db.query(Client).filter(Client.id == booking.client_id).update(
+            {Client.no_show_count: Client.no_show_count + 1},
+            synchronize_session=False
+        )
+    db.commit()
+
+    if marked:
+        publish_booking_event(booking, BookingEvent.NO_SHOW)
+        logger.info(f"Booking marked as no-show: ID={booking.id}")
+
+    return {
+        "message": "Booking marked as no-show",
+        "booking_id": booking.id,
+        "status": booking.status.value
+    }
+
+
+@app.get("/statistics")
+async def get_tenant_statistics(
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Get booking statistics of a tenant.
+    """
+    counts = dict(
+        db.query(Booking.status, func.count(Booking.id)).filter(
+            Booking.tenant_id == tenant_id
+        ).group_by(Booking.status).all()
+    )
+
+    total = sum(counts.values())
+    no_show = counts.get(BookingStatus.NO_SHOW, 0)
+    completed = counts.get(BookingStatus.COMPLETED, 0)
+
+    # Repeat clients: more than one booking with this tenant
+    client_counts = db.query(func.count(Booking.id)).filter(
+        Booking.tenant_id == tenant_id
+    ).group_by(Booking.client_id).all()
+    total_clients = len(client_counts)
+    repeat_clients = sum(1 for (count,) in client_counts if count > 1)
+
+    # Revenue of completed bookings, summed exactly in minor units
+    revenue = db.query(
+        Booking.currency,
+        func.sum(Booking.price),
+        func.sum(Booking.tax_amount)
+    ).filter(
+        Booking.tenant_id == tenant_id,
+        Booking.status == BookingStatus.COMPLETED
+    ).group_by(Booking.currency).all()
+
+    return {
+        "bookings": {
+            "total": total,
+            **{s.value.lower(): counts.get(s, 0) for s in BookingStatus}
+        },
+        "no_show_rate": round(no_show / (completed + no_show), 4) if completed + no_show else 0.0,
+        "clients": {
+            "total": total_clients,
+            "repeat": repeat_clients
+        },
+        "customer_retention_rate": round(repeat_clients / total_clients, 4) if total_clients else 0.0,
+        "revenue": [
+            {
+                "currency": currency,
+                "amount": to_major(int(amount_minor)),
+                "amount_minor": int(amount_minor),
+                "tax": to_major(int(tax_minor or 0)),
+                "tax_minor": int(tax_minor or 0)
+            }
+            for currency, amount_minor, tax_minor in revenue
+        ]
+    }
+
+
+@app.get("/onboarding")
+async def get_onboarding_status(
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Get setup checklist of a tenant for the dashboard.
+
+    A step is complete once the tenant has at least one row of it; working
+    hours count only for active masters.
+    """
+    counts = {
+        "location": db.query(func.count(Location.id)).filter(
+            Location.tenant_id == tenant_id
+        ).scalar(),
+        "master": db.query(func.count(Master.id)).filter(
+            Master.tenant_id == tenant_id,
+            Master.is_active == True
+        ).scalar(),
+        "service": db.query(func.count(Service.id)).filter(
+            Service.tenant_id == tenant_id,
+            Service.is_active == True
+        ).scalar(),
+        "working_hours": db.query(func.count(MasterSchedule.id)).join(Master).filter(
+            Master.tenant_id == tenant_id,
+            Master.is_active == True,
+            MasterSchedule.is_working == True
+        ).scalar()
+    }
+
+    steps = [
+        {"step": step, "count": count, "completed": count > 0}
+        for step, count in counts.items()
+    ]
+    completed = sum(1 for s in steps if s["completed"])
+
+    return {
+        "steps": steps,
+        "completed": completed,
+        "total": len(steps),
+        "is_complete": completed == len(steps)
+    }
+
+
+@app.get("/clients/history")
+async def get_client_history(
+    tenant_id: int = Query(...),
+    email: Optional[str] = Query(None),
+    phone: Optional[str] = Query(None),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Get booking history of a client within the tenant.
+
+    The client is looked up by email or phone.
+    """
+    if not email and not phone:
+        raise APIError(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            code=ErrorCode.BAD_REQUEST
+        )
+
+    client_service = ClientService(db)
+    history = client_service.get_client_history(tenant_id, email=email, phone=phone)
+
+    if not history:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.CLIENT_NOT_FOUND
+        )
+
+    client = history["client"]
+    bookings = history["bookings"]
+
+    return {
+        "client": {
+            "id": client.id,
+            "full_name": client.full_name,
+            "phone": client.phone,
+            "email": client.email,
+            "no_show_count": client.no_show_count
+        },
+        "total_bookings": len(bookings),
+        "completed_bookings": history["completed"],
+        "is_repeat_client": len(bookings) > 1,
+        "total_spend": [
+            {
+                "currency": currency,
+                "amount": to_major(amount_minor),
+                "amount_minor": amount_minor
+            }
+            for currency, amount_minor in history["total_spend"].items()
+        ],
+        "favorite_master": history["favorite_master"],
+        "favorite_service": history["favorite_service"],
+        "last_visit": history["last_visit"].isoformat() if history["last_visit"] else None,
+        "bookings": [
+            {
+                "id": b.id,
+                "booking_date": b.booking_date.isoformat(),
+                "status": b.status.value,
+                "master_name": b.master.full_name if b.master else None,
+                "service_name": b.service_names or None,
+                "price": to_major(b.price),
+                "price_minor": b.price,
+                "currency": b.currency
+            }
+            for b in bookings
+        ]
+    }
+
+
+@app.get("/clients/export")
+async def export_client_data(
+    tenant_id: int = Query(...),
+    email: str = Query(...),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Export data of a client within the tenant, for data-portability requests.
+
+    Includes the client's bookings, reviews and notifications with the tenant.
+    """
+    export_service = ExportService(db)
+    clients = export_service.find_tenant_clients(tenant_id, email)
+
+    if not clients:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.CLIENT_NOT_FOUND
+        )
+
+    logger.info(f"Client data exported: tenant={tenant_id}, clients={[c.id for c in clients]}")
+    return export_service.export_client_data(clients, tenant_id=tenant_id)
+
+
+@app.delete("/clients/data")
+async def delete_client_data(
+    tenant_id: int = Query(...),
+    user_id: int = Query(...),
+    email: str = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Delete personal data of a client of the tenant on request.
+
+    Only data with the tenant is scrubbed, bookings are kept so the
+    tenant's stats don't change. The client profile and sessions, shared
+    with other businesses, are deleted by the client via /client/data.
+    """
+    client_service = ClientService(db)
+    clients = ExportService(db).find_tenant_clients(tenant_id, email)
+
+    if not clients:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.CLIENT_NOT_FOUND
+        )
+
+    deleted = [client_service.delete_tenant_client_data(c, tenant_id, requested_by=user_id) for c in clients]
+    db.commit()
+
+    return {"message": "Client data deleted", "clients": deleted}
+
+
+@app.get("/export")
+async def export_tenant_data(
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Export all data of a tenant: settings, services, masters, clients,
+    bookings and reviews.
+    """
+    tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+
+    if not tenant:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.TENANT_NOT_FOUND
+        )
+
+    logger.info(f"Tenant data exported: tenant={tenant_id}")
+    return ExportService(db).export_tenant_data(tenant)
+
+
+@app.get("/bookings/export")
+async def export_bookings_csv(
+    tenant_id: int = Query(...),
+    date: Optional[date] = Query(None),
+    booking_status: Optional[str] = Query(None, alias="status"),
+    language: Optional[str] = Query(None),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Export bookings of the tenant as CSV, with the booking list filters.
+
+    Headers are in the language, the tenant's language by default. The
+    file is streamed; the session stays open until it's sent.
+    """
+    tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+
+    if not tenant:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.TENANT_NOT_FOUND
+        )
+
+    query = filter_bookings(db.query(Booking).filter(Booking.tenant_id == tenant_id), date, booking_status)
+    language = language or tenant.language
+    filename = f"bookings-{tenant.subdomain}-{date.isoformat() if date else 'all'}.csv"
+
+    logger.info(f"Bookings exported as CSV: tenant={tenant_id}")
+    return StreamingResponse(
+        ExportService(db).bookings_csv(query, language),
+        media_type="text/csv; charset=utf-8",
+        headers={"Content-Disposition": f'attachment; filename="{filename}"'}
+    )
+
+
+@app.get("/notifications/history")
+async def get_notification_history(
+    tenant_id: int = Query(...),
+    booking_id: Optional[int] = Query(None),
+    client_id: Optional[int] = Query(None),
+    limit: int = Query(100, ge=1, le=500),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Get notifications sent for a booking or a client within the tenant.
+
+    Newest first.
+    """
+    if booking_id is None and client_id is None:
+        raise APIError(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            code=ErrorCode.BAD_REQUEST
+        )
+
+    query = db.query(NotificationLog).filter(NotificationLog.tenant_id == tenant_id)
+
+    if booking_id is not None:
+        query = query.filter(NotificationLog.booking_id == booking_id)
+    if client_id is not None:
+        query = query.filter(NotificationLog.client_id == client_id)
+
+    notifications = query.order_by(NotificationLog.created_at.desc()).limit(limit).all()
+
+    return {
+        "notifications": [
+            {
+                "id": n.id,
+                "booking_id": n.booking_id,
+                "client_id": n.client_id,
+                "channel": n.channel.value,
+                "recipient": n.recipient,
+                "template": n.template,
+                "status": n.status.value,
+                "provider_message_id": n.provider_message_id,
+                "error": n.error,
+                "created_at": n.created_at.isoformat(),
+                "updated_at": n.updated_at.isoformat() if n.updated_at else None
+            }
+            for n in notifications
+        ]
+    }
+
+
+def check_template(name: str, language: str) -> None:
+    """Raise 404 for templates tenants can't customize or unsupported languages."""
+    if name not in CUSTOMIZABLE_TEMPLATES or language not in settings.supported_languages_list:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.TEMPLATE_NOT_FOUND
+        )
+
+
+def build_template_preview(db: Session, tenant_id: int, name: str, data: TemplatePreviewRequest) -> dict:
+    """
+    Render the draft, the tenant's override or the default template with
+    sample data as text and branded HTML.
+    """
+    check_template(name, data.language)
+
+    if data.body is not None:
+        problems = validate_template(name, data.language, data.body)
+        if problems:
+            raise APIError(
+                status_code=status.HTTP_400_BAD_REQUEST,
+                code=ErrorCode.INVALID_TEMPLATE,
+                details={"problems": problems}
+            )
+        body = data.body
+    else:
+        override = get_template_override(db, tenant_id, name, data.language)
+        body = override.body if override else get_default_template(name, data.language)
+
+    text = render_preview(body, data.sample_data, data.language)
+    tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+
+    return {
+        "name": name,
+        "language": data.language,
+        "text": text,
+        "html": text_to_html(text, get_email_branding(tenant))
+    }
+
+
+@app.get("/reviews")
+async def get_reviews(
+    tenant_id: int = Query(...),
+    review_status: Optional[ReviewStatus] = Query(None, alias="status"),
+    master_id: Optional[int] = Query(None),
+    page: int = Query(1, ge=1),
+    page_size: int = Query(REVIEWS_PAGE_SIZE, ge=1, le=REVIEWS_MAX_PAGE_SIZE),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Get reviews of the tenant of any status for moderation, newest first.
+    """
+    query = db.query(Review).filter(Review.tenant_id == tenant_id)
+
+    if review_status:
+        query = query.filter(Review.status == review_status)
+
+    if master_id is not None:
+        query = query.filter(Review.master_id == master_id)
+
+    total = query.count()
+    reviews = query.order_by(
+        Review.created_at.desc(), Review.id.desc()
+    ).offset((page - 1) * page_size).limit(page_size).all()
+
+    tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+
+    return {
+        "moderate_reviews": bool(tenant and tenant.moderate_reviews),
+        "reviews": [
+            {**review_response(r), "status": r.status.value}
+            for r in reviews
+        ],
+        "total": total,
+        "page": page,
+        "page_size": page_size,
+        "pages": (total + page_size - 1) // page_size
+    }
+
+
+@app.put("/reviews/settings")
+async def update_review_settings(
+    data: ReviewSettingsRequest,
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Turn review moderation of the tenant on or off.
+
+    Reviews already published or waiting for approval are kept as they are.
+    """
+    tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+
+    if not tenant:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.TENANT_NOT_FOUND
+        )
+
+    require_feature(tenant, Feature.REVIEWS)
+
+    tenant.moderate_reviews = data.moderate_reviews
+    db.commit()
+
+    return {"moderate_reviews": tenant.moderate_reviews}
+
+
+@app.get("/settings")
+async def get_settings(tenant_id: int = Query(...), db: Session = Depends(get_read_db)):
+    """
+    Get settings of the tenant: timezone, reminder channels, cancellation
+    policy and branding, with defaults for what was never set.
+    """
+    tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+
+    if not tenant:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.TENANT_NOT_FOUND
+        )
+
+    return get_tenant_settings(tenant).dict()
+
+
+@app.put("/settings")
+async def update_settings(
+    data: TenantSettingsUpdate,
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Update settings of the tenant.
+
+    Sections in the request replace the stored ones as a whole, others are
+    kept. Unknown keys and invalid values are rejected with 422.
+    """
+    tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+
+    if not tenant:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.TENANT_NOT_FOUND
+        )
+
+    apply_tenant_settings(tenant, data)
+    db.commit()
+
+    logger.info(f"Tenant settings updated: {tenant.subdomain} {sorted(data.dict(exclude_none=True))}")
+
+    return get_tenant_settings(tenant).dict()
+
+
+@app.put("/reviews/{review_id}")
+async def moderate_review(
+    review_id: int,
+    data: ReviewModerationRequest,
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Approve or reject a review of the tenant and/or respond to it.
+
+    Only approved reviews are shown publicly and count toward ratings.
+    """
+    review = db.query(Review).filter(
+        Review.id == review_id,
+        Review.tenant_id == tenant_id
+    ).first()
+
+    if not review:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.REVIEW_NOT_FOUND
         )
-        db.add(booking)
-        db.commit()
-        db.refresh(booking)
-
-        logger.info(f"Booking created: ID={booking.id}")
-
-        # Send WhatsApp confirmation
-        if settings.WHATSAPP_ENABLED:
-            try:
-                async with httpx.AsyncClient() as client_http:
-                    await client_http.post(
-                        f"{WHATSAPP_SERVICE_URL}/send-message",
-                        json={
-                            "phone": data.client_phone,
-                            "message": f"✅ Бронирование подтверждено!\n\n"
-                                       f"Бизнес: {tenant.business_name}\n"
-                                       f"Услуга: {service.name}\n"
-                                       f"Дата: {data.booking_date.strftime('%d.%m.%Y %H:%M')}\n"
-                                       f"Цена: {float(service.price)} ₸\n\n"
-                                       f"Спасибо за ваш выбор!"
-                        },
-                        timeout=5.0
-                    )
-            except Exception as e:
-                logger.error(f"Failed to send WhatsApp message: {e}")
 
-        return {
-            "message": "Booking created successfully",
-            "booking_id": booking.id,
-            "booking_date": booking.booking_date.isoformat(),
-            "status": booking.status.value
-        }
+    if data.status is not None:
+        review.status = data.status
 
-    except Exception as e:
-        db.rollback()
-        logger.error(f"Booking creation failed: {e}")
-        raise HTTPException(
-            status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-            detail="Booking creation failed"
+    if data.response is not None:
+        review.owner_response = data.response.strip() or None
+        review.responded_at = datetime.utcnow() if review.owner_response else None
+
+    if data.status is not None and review.master_id:
+        db.flush()
+        recompute_master_ratings(db, master_ids=[review.master_id])
+
+    db.commit()
+
+    logger.info(f"Review moderated: ID={review.id}, status={review.status.value}")
+
+    return {**review_response(review), "status": review.status.value}
+
+
+@app.get("/templates")
+async def list_templates(
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Get customizable message templates with defaults and the tenant's
+    overrides per language.
+    """
+    overrides = {
+        (t.name, t.language): t
+        for t in db.query(MessageTemplate).filter(MessageTemplate.tenant_id == tenant_id)
+    }
+
+    return {
+        "templates": [
+            {
+                "name": name,
+                "language": language,
+                "default": get_default_template(name, language),
+                "override": overrides[(name, language)].body if (name, language) in overrides else None,
+                "updated_at": (
+                    overrides[(name, language)].updated_at.isoformat() if (name, language) in overrides else None
+                )
+            }
+            for name in CUSTOMIZABLE_TEMPLATES
+            for language in settings.supported_languages_list
+        ]
+    }
+
+
+@app.put("/templates/{name}")
+async def set_template(
+    name: str,
+    data: TemplateOverrideRequest,
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Set the tenant's override of a template.
+
+    The override must use the same placeholders as the default template.
+    """
+    check_template(name, data.language)
+
+    problems = validate_template(name, data.language, data.body)
+    if problems or not data.body.strip():
+        raise APIError(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            code=ErrorCode.INVALID_TEMPLATE,
+            details={"problems": problems or ["empty template"]}
         )
 
+    override = get_template_override(db, tenant_id, name, data.language)
+    if override:
+        override.body = data.body
+    else:
+        override = MessageTemplate(tenant_id=tenant_id, name=name, language=data.language, body=data.body)
+        db.add(override)
 
-@app.get("/bookings")
-async def get_bookings(
+    db.commit()
+    logger.info(f"Template override set: tenant={tenant_id}, name={name}, language={data.language}")
+
+    return {
+        "name": name,
+        "language": data.language,
+        "override": override.body
+    }
+
+
+@app.post("/templates/{name}/preview")
+async def preview_template(
+    name: str,
+    data: TemplatePreviewRequest,
+    tenant_id: int = Query(...),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Preview template rendered with sample data.
+    """
+    return build_template_preview(db, tenant_id, name, data)
+
+
+@app.post("/templates/{name}/test")
+async def send_test_template(
+    name: str,
+    data: TemplatePreviewRequest,
+    tenant_id: int = Query(...),
     user_id: int = Query(...),
-    role: str = Query(...),
-    tenant_id: Optional[int] = Query(None),
-    date: Optional[date] = Query(None),
-    status: Optional[str] = Query(None),
     db: Session = Depends(get_db)
 ):
     """
-    Get bookings filtered by user role.
+    Email template rendered with sample data to the requesting user.
+
+    The recipient is always the user's own email, never an address from
+    the request.
     """
-    query = db.query(Booking)
+    user = db.query(User).filter(User.id == user_id, User.tenant_id == tenant_id).first()
+    if not user:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.USER_NOT_FOUND
+        )
 
-    # Filter based on role
-    if role == "OWNER" and tenant_id:
-        query = query.filter(Booking.tenant_id == tenant_id)
-    elif role == "MASTER":
-        # Get master record for this user
-        master = db.query(Master).filter(Master.user_id == user_id).first()
-        if master:
-            query = query.filter(Booking.master_id == master.id)
-        else:
-            return {"bookings": []}
+    preview = build_template_preview(db, tenant_id, name, data)
+    subject = translate("template_test_subject", data.language, template=name)
 
-    if date:
-        start_of_day = datetime.combine(date, time.min)
-        end_of_day = datetime.combine(date, time.max)
-        query = query.filter(
-            Booking.booking_date >= start_of_day,
-            Booking.booking_date <= end_of_day
+    try:
+        # SMTP blocks, keep it off the event loop
+        message_id = await run_in_threadpool(
+            email_client.deliver, user.email, subject, preview["text"], html_body=preview["html"]
+        )
+    except EmailError as e:
+        record_notification(
+            NotificationChannel.EMAIL, user.email, name, NotificationStatus.FAILED,
+            tenant_id=tenant_id, error=str(e)
         )
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+    if message_id:
+        record_notification(
+            NotificationChannel.EMAIL, user.email, name, NotificationStatus.SENT,
+            tenant_id=tenant_id, provider_message_id=message_id
+        )
+
+    return {
+        "message": "Test email sent" if message_id else "Email disabled",
+        "sent": message_id is not None,
+        "to": user.email
+    }
 
-    if status:
-        query = query.filter(Booking.status == status)
 
-    bookings = query.order_by(Booking.booking_date.desc()).all()
+@app.delete("/templates/{name}")
+async def reset_template(
+    name: str,
+    tenant_id: int = Query(...),
+    language: str = Query(...),
+    db: Session = Depends(get_db)
+):
+    """
+    Reset template to the default by removing the tenant's override.
+    """
+    check_template(name, language)
+
+    override = get_template_override(db, tenant_id, name, language)
+    if override:
+        db.delete(override)
+        db.commit()
+        logger.info(f"Template override reset: tenant={tenant_id}, name={name}, language={language}")
 
     return {
-        "bookings": [
-            {
-                "id": b.id,
-                "booking_date": b.booking_date.isoformat(),
-                "status": b.status.value,
-                "client_name": b.client.full_name if b.client else None,
-                "client_phone": b.client.phone if b.client else None,
-                "master_name": b.master.full_name if b.master else None,
-                "price": float(b.price)
-            }
-            for b in bookings
-        ]
+        "name": name,
+        "language": language,
+        "default": get_default_template(name, language)
+    }
+
+
+@app.get("/reports/revenue")
+async def get_revenue_report(
+    tenant_id: int = Query(...),
+    start_date: date = Query(...),
+    end_date: date = Query(...),
+    group_by: RevenueGroupBy = Query(RevenueGroupBy.DAY),
+    db: Session = Depends(get_read_db)
+):
+    """
+    Get revenue report of completed bookings for a date range.
+
+    Dates are in the tenant's local time, the end date is inclusive.
+    """
+    if start_date > end_date:
+        raise APIError(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            code=ErrorCode.INVALID_DATE_RANGE
+        )
+
+    report_service = ReportService(db)
+    buckets = report_service.get_revenue_report(tenant_id, start_date, end_date, group_by)
+
+    return {
+        "start_date": start_date.isoformat(),
+        "end_date": end_date.isoformat(),
+        "group_by": group_by.value,
+        "buckets": buckets
+    }
+
+
+def record_cancellation_refund(db: Session, booking: Booking, fee_percent: int) -> dict:
+    """
+    Set refunds of the cancelled booking's paid deposit, keeping the fee.
+
+    The refund is recorded on the payments, send_deposit_refunds has the
+    provider pay it out. Returns the fee and refund.
+    """
+    payments = db.query(Payment).filter(
+        Payment.booking_id == booking.id,
+        Payment.status == PaymentStatus.SUCCEEDED
+    ).order_by(Payment.id).all()
+
+    amounts = compute_cancellation_fee(booking.price, sum(p.amount for p in payments), fee_percent)
+
+    remaining = amounts["refund"]
+    for payment in payments:
+        payment.refund_amount = min(payment.amount, remaining)
+        remaining -= payment.refund_amount
+
+    return {
+        "fee_percent": fee_percent,
+        "fee": to_major(amounts["fee"]),
+        "fee_minor": amounts["fee"],
+        "refund": to_major(amounts["refund"]),
+        "refund_minor": amounts["refund"],
+        "currency": booking.currency
     }
 
 
+async def send_deposit_refunds(booking: Booking) -> bool:
+    """
+    Ask the payment service to pay out refunds of the cancelled booking.
+
+    Called after the refunds are committed. Failures are logged and not
+    raised; refunds not sent are retried by notifications.send_pending_refunds.
+    """
+    try:
+        async with httpx.AsyncClient(headers=request_id_headers()) as client:
+            response = await client.post(
+                f"{PAYMENT_SERVICE_URL}/refunds",
+                params={"booking_id": booking.id},
+                timeout=10.0
+            )
+            response.raise_for_status()
+    except httpx.HTTPError as e:
+        logger.error(f"Failed to send refunds of booking {booking.id}: {e}")
+        return False
+
+    return True
+
+
 @app.delete("/booking/{booking_id}")
 async def cancel_booking(
     booking_id: int,
     user_id: int = Query(...),
     role: str = Query(...),
+    tenant_id: Optional[int] = Query(None),
+    waive_fee: bool = Query(False),
     db: Session = Depends(get_db)
 ):
     """
     Cancel booking.
-    Sends WhatsApp notification.
+    Sends WhatsApp notification, only when the booking wasn't cancelled
+    already, so retries don't notify the client twice. Completed and
+    no-show bookings can't be cancelled.
+
+    The fee of the cancellation policy is kept from a paid deposit as for
+    clients, the last tier's after the cancellation window closed. With
+    waive_fee (e.g. the business cancels) the deposit is refunded in full.
     """
-    booking = db.query(Booking).filter(Booking.id == booking_id).first()
+    booking = get_scoped_booking(db, booking_id, user_id, role, tenant_id)
 
-    if not booking:
-        raise HTTPException(
-            status_code=status.HTTP_404_NOT_FOUND,
-            detail="Booking not found"
-        )
+    fee_percent = 0
+    if not waive_fee:
+        policy = get_cancellation_policy(booking.tenant, booking.service)
+        tier = get_cancellation_tier(policy, booking.booking_date, tenant_local_now(booking.tenant)) or policy[-1]
+        fee_percent = tier["fee_percent"]
 
-    # Update status
-    booking.status = BookingStatus.CANCELLED
+    cancelled = BookingService(db).transition_status(
+        booking, BookingStatus.CANCELLED, [BookingStatus.PENDING, BookingStatus.CONFIRMED]
+    )
+    if not cancelled and booking.status != BookingStatus.CANCELLED:
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.INVALID_BOOKING_STATUS,
+            details={"status": booking.status.value}
+        )
+    if cancelled:
+        cancellation = record_cancellation_refund(db, booking, fee_percent)
     db.commit()
 
+    if not cancelled:
+        return {"message": "Booking already cancelled"}
+
+    invalidate_booking_availability(booking, booking.booking_date)
+    publish_booking_event(booking, BookingEvent.CANCELLED)
+    if cancellation["refund_minor"]:
+        await send_deposit_refunds(booking)
+
     # Send WhatsApp notification
-    if settings.WHATSAPP_ENABLED and booking.client:
-        try:
-            async with httpx.AsyncClient() as client:
-                await client.post(
-                    f"{WHATSAPP_SERVICE_URL}/send-message",
-                    json={
-                        "phone": booking.client.phone,
-                        "message": f"❌ Ваше бронирование отменено\n\n"
-                                   f"Дата: {booking.booking_date.strftime('%d.%m.%Y %H:%M')}\n\n"
-                                   f"Для новой записи свяжитесь с нами."
-                    },
-                    timeout=5.0
-                )
-        except Exception as e:
-            logger.error(f"Failed to send WhatsApp cancellation: {e}")
+    if booking.client:
+        message = render_sms(
+            SmsTemplate.BOOKING_CANCELLED,
+            booking.tenant.language,
+            booking_date=format_datetime(booking.booking_date, booking.tenant.language)
+        )
+        await send_whatsapp_message(
+            booking.client.phone, message, SmsTemplate.BOOKING_CANCELLED.value, booking
+        )
+
+    return {"message": "Booking cancelled successfully", "cancellation": cancellation}
+
+
+@app.post("/public/client/request-code")
+async def request_client_code(data: ClientCodeRequest, db: Session = Depends(get_db)):
+    """
+    Send verification code to the client's WhatsApp.
+    """
+    session = ClientService(db).create_verification(data.phone)
+
+    message = render_sms(
+        SmsTemplate.CLIENT_VERIFICATION_CODE,
+        code=session.verification_code,
+        minutes=settings.CLIENT_VERIFICATION_CODE_EXPIRE_MINUTES
+    )
+    await send_whatsapp_message(data.phone, message, SmsTemplate.CLIENT_VERIFICATION_CODE.value)
+
+    return {
+        "message": "Verification code sent",
+        "expires_in_minutes": settings.CLIENT_VERIFICATION_CODE_EXPIRE_MINUTES
+    }
+
+
+@app.post("/public/client/verify")
+async def verify_client(data: ClientVerifyRequest, db: Session = Depends(get_db)):
+    """
+    Verify code and issue client session token.
+    """
+    session = ClientService(db).verify(data.phone, data.code)
+
+    if not session:
+        raise APIError(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            code=ErrorCode.INVALID_VERIFICATION_CODE
+        )
+
+    return {
+        "session_token": session.session_token,
+        "expires_at": session.session_expires.isoformat()
+    }
+
+
+@app.post("/client/session/refresh")
+async def refresh_client_session(
+    session_token: str = Header(..., alias="X-Client-Session"),
+    db: Session = Depends(get_db)
+):
+    """
+    Extend a valid client session with a new token.
+
+    Expired sessions are rejected with 401, the client verifies the phone
+    again.
+    """
+    service = ClientService(db)
+    session = service.refresh_session(get_client_session(db, session_token))
+
+    return {
+        "session_token": session.session_token,
+        "expires_at": session.session_expires.isoformat()
+    }
+
+
+@app.get("/client/bookings")
+async def get_client_bookings(
+    session_token: str = Header(..., alias="X-Client-Session"),
+    language: Optional[str] = Query(None),
+    db: Session = Depends(get_db)
+):
+    """
+    Get bookings of the authenticated client.
+    """
+    session = get_client_session(db, session_token)
+    bookings = ClientService(db).get_client_bookings(session.client_id)
+
+    return {"bookings": [client_booking_response(b, language) for b in bookings]}
+
+
+@app.get("/client/export")
+async def export_own_client_data(
+    session_token: str = Header(..., alias="X-Client-Session"),
+    db: Session = Depends(get_db)
+):
+    """
+    Export all data of the authenticated client: profile, bookings with
+    all businesses, reviews, notifications and sessions.
+    """
+    session = get_client_session(db, session_token)
+    logger.info(f"Client data exported by the client: client={session.client_id}")
+
+    return ExportService(db).export_client_data([session.client])
+
+
+@app.delete("/client/data")
+async def delete_own_client_data(
+    session_token: str = Header(..., alias="X-Client-Session"),
+    db: Session = Depends(get_db)
+):
+    """
+    Delete personal data of the authenticated client.
+
+    All sessions are deleted, including the current one.
+    """
+    session = get_client_session(db, session_token)
+    deleted = ClientService(db).anonymize_client(session.client)
+    db.commit()
+
+    return {"message": "Client data deleted", **deleted}
+
+
+@app.get("/client/booking/{booking_id}")
+async def get_client_booking_details(
+    booking_id: int,
+    session_token: str = Header(..., alias="X-Client-Session"),
+    language: Optional[str] = Query(None),
+    db: Session = Depends(get_db)
+):
+    """
+    Get booking of the authenticated client.
+    """
+    session = get_client_session(db, session_token)
+    booking = get_client_booking(db, booking_id, session.client_id)
+
+    return client_booking_response(booking, language)
+
+
+@app.post("/client/booking/{booking_id}/resend-confirmation")
+async def resend_client_booking_confirmation(
+    booking_id: int,
+    session_token: str = Header(..., alias="X-Client-Session"),
+    db: Session = Depends(get_db)
+):
+    """
+    Resend confirmation of a booking of the authenticated client.
+    """
+    session = get_client_session(db, session_token)
+    booking = get_client_booking(db, booking_id, session.client_id)
+
+    return await resend_booking_confirmation(booking)
+
+
+@app.delete("/client/booking/{booking_id}")
+async def cancel_client_booking(
+    booking_id: int,
+    session_token: str = Header(..., alias="X-Client-Session"),
+    db: Session = Depends(get_db)
+):
+    """
+    Cancel booking of the authenticated client.
+
+    The cancellation policy of the service (or business) sets the fee by
+    how long before the booking it's cancelled; the fee is kept from the
+    paid deposit and the rest refunded. Not allowed after the last tier.
+    """
+    session = get_client_session(db, session_token)
+    booking = get_client_booking(db, booking_id, session.client_id)
+
+    if booking.status not in [BookingStatus.PENDING, BookingStatus.CONFIRMED]:
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.INVALID_BOOKING_STATUS,
+            details={"status": booking.status.value}
+        )
+
+    policy = get_cancellation_policy(booking.tenant, booking.service)
+    tier = get_cancellation_tier(policy, booking.booking_date, tenant_local_now(booking.tenant))
+    if tier is None:
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.CANCELLATION_WINDOW_CLOSED,
+            details={"cancellation_hours": policy[-1]["hours_before"]}
+        )
+
+    if not BookingService(db).transition_status(
+        booking, BookingStatus.CANCELLED, [BookingStatus.PENDING, BookingStatus.CONFIRMED]
+    ):
+        return {"message": "Booking cancelled successfully"}
+
+    cancellation = record_cancellation_refund(db, booking, tier["fee_percent"])
+    db.commit()
+    invalidate_booking_availability(booking, booking.booking_date)
+    publish_booking_event(booking, BookingEvent.CANCELLED)
+    if cancellation["refund_minor"]:
+        await send_deposit_refunds(booking)
+    logger.info(f"Booking cancelled by client: ID={booking.id}, fee={cancellation['fee_percent']}%")
+
+    return {"message": "Booking cancelled successfully", "cancellation": cancellation}
+
+
+@app.post("/client/booking/{booking_id}/review", status_code=status.HTTP_201_CREATED)
+async def create_client_review(
+    booking_id: int,
+    data: ReviewRequest,
+    session_token: str = Header(..., alias="X-Client-Session"),
+    db: Session = Depends(get_db)
+):
+    """
+    Review a completed booking of the authenticated client, once per booking.
+
+    Published at once, or after owner approval if the business moderates
+    reviews. Not allowed if the business's plan lacks reviews.
+    """
+    session = get_client_session(db, session_token)
+    booking = get_client_booking(db, booking_id, session.client_id)
+    require_feature(booking.tenant, Feature.REVIEWS)
+
+    if booking.status != BookingStatus.COMPLETED:
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.INVALID_BOOKING_STATUS,
+            details={"status": booking.status.value}
+        )
+
+    if db.query(Review).filter(Review.booking_id == booking.id).first():
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.REVIEW_ALREADY_EXISTS
+        )
+
+    review = Review(
+        tenant_id=booking.tenant_id,
+        booking_id=booking.id,
+        client_id=booking.client_id,
+        master_id=booking.master_id,
+        rating=data.rating,
+        comment=(data.comment or "").strip() or None,
+        status=ReviewStatus.PENDING if booking.tenant.moderate_reviews else ReviewStatus.APPROVED
+    )
+    db.add(review)
+
+    if review.status == ReviewStatus.APPROVED and review.master_id:
+        db.flush()
+        recompute_master_ratings(db, master_ids=[review.master_id])
+
+    db.commit()
+
+    logger.info(f"Review created: booking={booking.id}, rating={review.rating}, status={review.status.value}")
 
-    return {"message": "Booking cancelled successfully"}
+    return {
+        "id": review.id,
+        "booking_id": booking.id,
+        "rating": review.rating,
+        "comment": review.comment,
+        "status": review.status.value,
+        "created_at": review.created_at.isoformat()
+    }
 
 
 if __name__ == "__main__":
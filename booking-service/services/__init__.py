@@ -1,3 +1,4 @@
 from .booking_service import BookingService
+from .client_service import ClientService
 
-__all__ = ["BookingService"]
+__all__ = ["BookingService", "ClientService"]
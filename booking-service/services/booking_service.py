@@ -1,12 +1,160 @@
 from sqlalchemy.orm import Session
 from datetime import datetime, date, time, timedelta
-from typing import List, Optional
+from typing import Dict, List, Optional, Tuple
+from sqlalchemy import or_, func
 import logging
 
-from shared.models import Booking, Master, MasterSchedule, BookingStatus
+from shared.config import settings
+from shared.models import (
+    Booking, BookingItem, LocationHours, Master, MasterSchedule, MasterScheduleOverride, MasterService,
+    MasterTimeOff, BookingStatus, Tenant, Service, TimeOffStatus
+)
+from shared.calendar import tenant_zone
+from shared.billing import Feature, compute_tax, get_tax_config, has_feature
 
 logger = logging.getLogger(__name__)
 
+# Step between offered start times unless the service or tenant sets one
+DEFAULT_SLOT_INTERVAL_MINUTES = 30
+
+# Minimum lead time of a booking unless the service or tenant sets one
+DEFAULT_MIN_LEAD_MINUTES = 0
+
+
+def tenant_local_now(tenant: Optional[Tenant]) -> datetime:
+    """
+    Get current time in the tenant timezone.
+
+    Booking dates are stored as naive local time of the business,
+    so the result is naive as well.
+    """
+    return datetime.now(tenant_zone(tenant)).replace(tzinfo=None)
+
+
+def get_deposit_amount(tenant: Tenant, service: Service, price: int) -> Optional[int]:
+    """
+    Get deposit in minor units required to confirm a booking of the service.
+
+    price is the total booking price. Returns None if no deposit is required,
+    always for tenants whose plan lacks deposits.
+    """
+    if not has_feature(tenant, Feature.DEPOSITS):
+        return None
+
+    if not (service.require_deposit or tenant.require_deposit):
+        return None
+
+    if service.deposit_amount is not None:
+        return service.deposit_amount
+
+    # Round half up in minor units
+    return (price * settings.DEPOSIT_PERCENT + 50) // 100
+
+
+def price_booking_items(tenant: Tenant, services: List[Service]) -> dict:
+    """
+    Price the services of a booking, performed one after another.
+
+    Tax and deposit are computed per service, since services may have
+    their own tax rate and deposit settings. Returns the booking items,
+    total duration, price and tax in minor units and the deposit (None
+    if no service requires one).
+    """
+    items = []
+    deposits = []
+
+    for position, service in enumerate(services):
+        tax_config = get_tax_config(tenant, service)
+        pricing = compute_tax(service.price, tax_config["rate_bp"], tax_config["inclusive"])
+
+        items.append(BookingItem(
+            service_id=service.id,
+            position=position,
+            duration_minutes=service.duration_minutes,
+            price=pricing["total"],
+            tax_amount=pricing["tax"],
+            tax_rate=tax_config["rate_bp"]
+        ))
+
+        deposit = get_deposit_amount(tenant, service, pricing["total"])
+        if deposit is not None:
+            deposits.append(deposit)
+
+    return {
+        "items": items,
+        "duration_minutes": sum(item.duration_minutes for item in items),
+        "total": sum(item.price for item in items),
+        "tax": sum(item.tax_amount for item in items),
+        "deposit": sum(deposits) if deposits else None
+    }
+
+
+def get_slot_interval(tenant: Optional[Tenant], service: Optional[Service] = None) -> int:
+    """Step between offered start times: the service's, else the tenant's, else 30 minutes."""
+    for interval in (
+        service.slot_interval_minutes if service else None,
+        tenant.slot_interval_minutes if tenant else None
+    ):
+        if interval and interval > 0:
+            return interval
+    return DEFAULT_SLOT_INTERVAL_MINUTES
+
+
+def get_min_lead_minutes(tenant: Optional[Tenant], service: Optional[Service] = None) -> int:
+    """Minimum minutes from now to a booking start: the service's, else the tenant's, else 0."""
+    for lead in (
+        service.min_lead_minutes if service else None,
+        tenant.min_lead_minutes if tenant else None
+    ):
+        if lead is not None and lead >= 0:
+            return lead
+    return DEFAULT_MIN_LEAD_MINUTES
+
+
+def earliest_booking_start(tenant: Tenant, services: List[Service]) -> datetime:
+    """
+    Earliest start a client may book the services at, in tenant local time.
+
+    A combo needs the longest lead time of its services.
+    """
+    lead = max([get_min_lead_minutes(tenant, s) for s in services] or [get_min_lead_minutes(tenant)])
+    return tenant_local_now(tenant) + timedelta(minutes=lead)
+
+
+def group_capacity(service: Optional[Service]) -> int:
+    """Clients per slot of the service, 1 unless it's a group service."""
+    return max(service.capacity or 1, 1) if service else 1
+
+
+def joins_group(booking: Booking, service: Service, start: datetime, duration_minutes: int) -> bool:
+    """Check if the booking is a spot in the same group session of the service."""
+    return (
+        booking.service_id == service.id
+        and booking.booking_date == start
+        and booking.duration_minutes == duration_minutes
+    )
+
+
+def bookable_master_filter():
+    """Filter of masters clients can book: active, visible and accepting bookings."""
+    return (
+        Master.is_active == True,
+        Master.is_visible == True,
+        Master.is_accepting_bookings == True
+    )
+
+
+def slot_holding_filter():
+    """
+    Filter of bookings holding their time slot.
+
+    Pending bookings whose deposit was not paid in time no longer hold it.
+    """
+    return (
+        Booking.status.in_([BookingStatus.PENDING, BookingStatus.CONFIRMED]),
+        or_(Booking.payment_due_at.is_(None), Booking.payment_due_at > datetime.utcnow())
+    )
+
 
 class BookingService:
     """Booking service for business logic."""
@@ -14,11 +162,86 @@ class BookingService:
     def __init__(self, db: Session):
         self.db = db
 
+    def get_working_hours(self, master_id: int, day: date) -> Optional[Tuple[time, time]]:
+        """
+        Get start and end of the master's working hours on the day, None if off.
+
+        Approved time off blocks the whole day. Otherwise a one-off override
+        of the date takes precedence over the weekly schedule. The hours are
+        limited to the opening hours of the master's location, if it has any.
+        """
+        on_time_off = self.db.query(MasterTimeOff.id).filter(
+            MasterTimeOff.master_id == master_id,
+            MasterTimeOff.status == TimeOffStatus.APPROVED,
+            MasterTimeOff.start_date <= day,
+            MasterTimeOff.end_date >= day
+        ).first()
+
+        if on_time_off:
+            return None
+
+        override = self.db.query(MasterScheduleOverride).filter(
+            MasterScheduleOverride.master_id == master_id,
+            MasterScheduleOverride.date == day
+        ).first()
+
+        if override:
+            hours = (override.start_time, override.end_time) if override.is_working else None
+        else:
+            schedule = self.db.query(MasterSchedule).filter(
+                MasterSchedule.master_id == master_id,
+                MasterSchedule.day_of_week == day.weekday(),
+                MasterSchedule.is_working == True
+            ).first()
+            hours = (schedule.start_time, schedule.end_time) if schedule else None
+
+        if not hours:
+            return None
+
+        return self.limit_to_location_hours(master_id, day, hours)
+
+    def limit_to_location_hours(
+        self,
+        master_id: int,
+        day: date,
+        hours: Tuple[time, time]
+    ) -> Optional[Tuple[time, time]]:
+        """
+        Intersect working hours with opening hours of the master's location.
+
+        Masters without a location, or at a location without opening hours,
+        keep their hours. Days the location has no open hours are off.
+        """
+        location_hours = self.db.query(LocationHours).join(
+            Master, Master.location_id == LocationHours.location_id
+        ).filter(Master.id == master_id).all()
+
+        if not location_hours:
+            return hours
+
+        opening = next((h for h in location_hours if h.day_of_week == day.weekday() and h.is_open), None)
+        if not opening:
+            return None
+
+        start, end = max(hours[0], opening.start_time), min(hours[1], opening.end_time)
+        return (start, end) if start < end else None
+
+    def get_time_off_conflicts(self, master_id: int, start_date: date, end_date: date) -> List[Booking]:
+        """Bookings of the master holding a slot within the dates, inclusive."""
+        return self.db.query(Booking).filter(
+            Booking.master_id == master_id,
+            Booking.booking_date >= datetime.combine(start_date, time.min),
+            Booking.booking_date <= datetime.combine(end_date, time.max),
+            *slot_holding_filter()
+        ).order_by(Booking.booking_date).all()
+
     def get_available_slots(
         self,
         master_id: int,
         check_date: date,
-        slot_duration: int = 30
+        slot_duration: int = 30,
+        duration_minutes: Optional[int] = None,
+        service: Optional[Service] = None
     ) -> List[str]:
         """
         Get available time slots for a master on a specific date.
@@ -26,30 +249,63 @@ class BookingService:
         Args:
             master_id: Master ID
             check_date: Date to check
-            slot_duration: Slot duration in minutes (default 30)
+            slot_duration: Step between slot start times in minutes (default 30)
+            duration_minutes: Time the booking needs from the slot start,
+                e.g. total of a combo (default slot_duration)
+            service: Booked service, group services allow several
+                bookings per slot
 
         Returns:
             List of available time slots in HH:MM format
         """
-        # Get master's schedule for this day of week
-        day_of_week = check_date.weekday()
-        schedule = self.db.query(MasterSchedule).filter(
-            MasterSchedule.master_id == master_id,
-            MasterSchedule.day_of_week == day_of_week,
-            MasterSchedule.is_working == True
-        ).first()
+        return list(self.get_slot_spots(master_id, check_date, slot_duration, duration_minutes, service))
 
-        if not schedule:
-            return []
+    def get_slot_spots(
+        self,
+        master_id: int,
+        check_date: date,
+        slot_duration: int = 30,
+        duration_minutes: Optional[int] = None,
+        service: Optional[Service] = None
+    ) -> Dict[str, int]:
+        """
+        Get available time slots with the number of free spots.
+
+        A slot has one spot, or capacity spots for a group service. A slot
+        of a group service stays available while it's only taken by
+        bookings of the same session (service, start and duration).
 
-        # Generate all possible slots
-        start_time = datetime.combine(check_date, schedule.start_time)
-        end_time = datetime.combine(check_date, schedule.end_time)
+        Slots start on multiples of slot_duration from midnight (09:00,
+        09:15, ... for 15 minutes) within working hours, and end before
+        the working day does.
+
+        Returns:
+            Free spots by slot time in HH:MM format
+        """
+        duration_minutes = duration_minutes or slot_duration
+        capacity = group_capacity(service)
+
+        # Get master's working hours on this date
+        hours = self.get_working_hours(master_id, check_date)
+
+        if not hours:
+            return {}
+
+        # Generate all possible slots, the first one aligned to the step
+        start_time = datetime.combine(check_date, hours[0])
+        end_time = datetime.combine(check_date, hours[1])
+
+        start_minutes = hours[0].hour * 60 + hours[0].minute
+        misalignment = start_minutes % slot_duration
+        if misalignment:
+            start_time = datetime.combine(check_date, time.min) + timedelta(
+                minutes=start_minutes - misalignment + slot_duration
+            )
 
         all_slots = []
         current_time = start_time
 
-        while current_time + timedelta(minutes=slot_duration) <= end_time:
+        while current_time + timedelta(minutes=duration_minutes) <= end_time:
             all_slots.append(current_time)
             current_time += timedelta(minutes=slot_duration)
 
@@ -61,26 +317,30 @@ class BookingService:
             Booking.master_id == master_id,
             Booking.booking_date >= start_of_day,
             Booking.booking_date <= end_of_day,
-            Booking.status.in_([BookingStatus.PENDING, BookingStatus.CONFIRMED])
+            *slot_holding_filter()
         ).all()
 
         # Filter out booked slots
-        available_slots = []
+        available_slots = {}
         for slot in all_slots:
-            is_available = True
+            slot_end = slot + timedelta(minutes=duration_minutes)
 
-            for booking in bookings:
-                booking_end = booking.booking_date + timedelta(minutes=booking.duration_minutes)
+            # Existing bookings overlapping the slot
+            overlapping = [
+                booking for booking in bookings
+                if slot < booking.booking_date + timedelta(minutes=booking.duration_minutes)
+                and slot_end > booking.booking_date
+            ]
 
-                # Check if slot overlaps with existing booking
-                slot_end = slot + timedelta(minutes=slot_duration)
+            if not overlapping:
+                spots = capacity
+            elif capacity > 1 and all(joins_group(b, service, slot, duration_minutes) for b in overlapping):
+                spots = capacity - len(overlapping)
+            else:
+                spots = 0
 
-                if (slot < booking_end and slot_end > booking.booking_date):
-                    is_available = False
-                    break
-
-            if is_available:
-                available_slots.append(slot.strftime("%H:%M"))
+            if spots > 0:
+                available_slots[slot.strftime("%H:%M")] = spots
 
         return available_slots
 
@@ -88,7 +348,9 @@ class BookingService:
         self,
         master_id: int,
         booking_datetime: datetime,
-        duration_minutes: int
+        duration_minutes: int,
+        exclude_booking_id: Optional[int] = None,
+        service: Optional[Service] = None
     ) -> bool:
         """
         Check if a specific time slot is available.
@@ -97,6 +359,9 @@ class BookingService:
             master_id: Master ID
             booking_datetime: Booking start datetime
             duration_minutes: Booking duration
+            exclude_booking_id: Booking to ignore (when rescheduling it)
+            service: Booked service, a group service session accepts up
+                to capacity bookings
 
         Returns:
             True if slot is available, False otherwise
@@ -104,35 +369,112 @@ class BookingService:
         booking_end = booking_datetime + timedelta(minutes=duration_minutes)
 
         # Check for overlapping bookings
-        overlapping = self.db.query(Booking).filter(
+        query = self.db.query(Booking).filter(
             Booking.master_id == master_id,
-            Booking.status.in_([BookingStatus.PENDING, BookingStatus.CONFIRMED]),
+            *slot_holding_filter(),
             Booking.booking_date < booking_end,
-            (Booking.booking_date + timedelta(minutes=Booking.duration_minutes)) > booking_datetime
-        ).first()
+            Booking.booking_date + func.make_interval(0, 0, 0, 0, 0, Booking.duration_minutes) > booking_datetime
+        )
+
+        if exclude_booking_id:
+            query = query.filter(Booking.id != exclude_booking_id)
 
-        if overlapping:
+        overlapping = query.all()
+        capacity = group_capacity(service)
+
+        if overlapping and not (
+            capacity > 1
+            and len(overlapping) < capacity
+            and all(joins_group(b, service, booking_datetime, duration_minutes) for b in overlapping)
+        ):
             return False
 
         # Check if time is within master's working hours
-        day_of_week = booking_datetime.weekday()
-        schedule = self.db.query(MasterSchedule).filter(
-            MasterSchedule.master_id == master_id,
-            MasterSchedule.day_of_week == day_of_week,
-            MasterSchedule.is_working == True
-        ).first()
+        hours = self.get_working_hours(master_id, booking_datetime.date())
 
-        if not schedule:
+        if not hours:
             return False
 
-        booking_time = booking_datetime.time()
-        booking_end_time = (booking_datetime + timedelta(minutes=duration_minutes)).time()
+        start_time = datetime.combine(booking_datetime.date(), hours[0])
+        end_time = datetime.combine(booking_datetime.date(), hours[1])
 
-        if booking_time < schedule.start_time or booking_end_time > schedule.end_time:
+        start = booking_datetime.replace(tzinfo=None)
+        if start < start_time or start + timedelta(minutes=duration_minutes) > end_time:
             return False
 
         return True
 
+    def get_qualified_masters(self, tenant_id: int, service_ids: List[int]) -> List[Master]:
+        """Get bookable masters of the tenant providing all the services."""
+        return self.db.query(Master).join(MasterService).filter(
+            Master.tenant_id == tenant_id,
+            *bookable_master_filter(),
+            MasterService.service_id.in_(service_ids)
+        ).group_by(Master.id).having(
+            func.count(MasterService.service_id) == len(service_ids)
+        ).order_by(Master.id).all()
+
+    def lock_master(self, master_id: int, skip_locked: bool = False) -> Optional[Master]:
+        """
+        Lock master row until the transaction ends.
+
+        Bookings of a master are created under this lock, so two requests
+        can't both see the same slot free. With skip_locked a master being
+        booked by another request is skipped (None) instead of waited for.
+        """
+        return self.db.query(Master).filter(Master.id == master_id).with_for_update(
+            skip_locked=skip_locked
+        ).first()
+
+    def transition_status(
+        self,
+        booking: Booking,
+        target: BookingStatus,
+        from_statuses: Optional[List[BookingStatus]] = None
+    ) -> bool:
+        """
+        Move the booking to the target status in a single conditional UPDATE.
+
+        Returns False without changing anything if the booking is already
+        in the target status (or not in one of from_statuses), e.g. when a
+        request is retried or raced by another one. Notifications are sent
+        only for True, so a transition is announced once.
+        """
+        query = self.db.query(Booking).filter(Booking.id == booking.id, Booking.status != target)
+        if from_statuses:
+            query = query.filter(Booking.status.in_(from_statuses))
+
+        changed = query.update(
+            {Booking.status: target, Booking.updated_at: datetime.utcnow()},
+            synchronize_session=False
+        ) == 1
+
+        self.db.refresh(booking)
+        return changed
+
+    def find_available_master(
+        self,
+        tenant_id: int,
+        service_ids: List[int],
+        booking_datetime: datetime,
+        duration_minutes: int,
+        service: Optional[Service] = None
+    ) -> Optional[Master]:
+        """
+        Find and lock a master free at the given time, for "any master" bookings.
+
+        Masters providing all the services are tried in order; one being
+        booked concurrently is skipped rather than waited for. Returns None
+        if no master is free.
+        """
+        for candidate in self.get_qualified_masters(tenant_id, service_ids):
+            master = self.lock_master(candidate.id, skip_locked=True)
+
+            if master and self.is_slot_available(master.id, booking_datetime, duration_minutes, service=service):
+                return master
+
+        return None
+
     def get_master_bookings(
         self,
         master_id: int,
@@ -0,0 +1,260 @@
+from sqlalchemy.orm import Session
+from sqlalchemy import or_
+from datetime import datetime, timedelta
+from typing import List, Optional
+from collections import Counter
+import secrets
+import logging
+
+from shared.config import settings
+from shared.models import Booking, BookingStatus, Client, ClientSession, NotificationLog, Review, AdminAction
+
+logger = logging.getLogger(__name__)
+
+# Recipient of logged notifications to a deleted client
+DELETED_RECIPIENT = "deleted"
+
+
+class ClientService:
+    """Client verification and session logic."""
+
+    def __init__(self, db: Session):
+        self.db = db
+
+    def get_or_create_client(self, phone: str, full_name: Optional[str] = None) -> Client:
+        """Get client by phone or create a new one."""
+        client = self.db.query(Client).filter(Client.phone == phone).first()
+        if not client:
+            client = Client(phone=phone, full_name=full_name)
+            self.db.add(client)
+            self.db.flush()
+        return client
+
+    def create_verification(self, phone: str) -> ClientSession:
+        """
+        Create pending session with a one-time verification code.
+
+        The code is sent to the client via WhatsApp by the caller.
+        """
+        client = self.get_or_create_client(phone)
+
+        session = ClientSession(
+            client_id=client.id,
+            verification_code=f"{secrets.randbelow(1000000):06d}",
+            verification_expires=datetime.utcnow() + timedelta(
+                minutes=settings.CLIENT_VERIFICATION_CODE_EXPIRE_MINUTES
+            ),
+            is_verified=False
+        )
+        self.db.add(session)
+        self.db.commit()
+        self.db.refresh(session)
+        return session
+
+    def verify(self, phone: str, code: str) -> Optional[ClientSession]:
+        """
+        Verify code and issue session token.
+
+        Returns None if the code is invalid or expired.
+        """
+        now = datetime.utcnow()
+
+        session = self.db.query(ClientSession).join(Client).filter(
+            Client.phone == phone,
+            ClientSession.verification_code == code,
+            ClientSession.is_verified == False,
+            ClientSession.verification_expires > now
+        ).order_by(ClientSession.created_at.desc()).first()
+
+        if not session:
+            return None
+
+        session.is_verified = True
+        session.verification_code = None
+        session.session_token = secrets.token_urlsafe(32)
+        session.session_expires = now + timedelta(days=settings.CLIENT_SESSION_EXPIRE_DAYS)
+        session.last_used = now
+        self.db.commit()
+        self.db.refresh(session)
+
+        logger.info(f"Client verified: client_id={session.client_id}")
+        return session
+
+    def get_session(self, session_token: str) -> Optional[ClientSession]:
+        """Get valid verified session by token and mark it as used."""
+        if not session_token:
+            return None
+
+        now = datetime.utcnow()
+        session = self.db.query(ClientSession).filter(
+            ClientSession.session_token == session_token,
+            ClientSession.is_verified == True,
+            ClientSession.session_expires > now
+        ).first()
+
+        if session:
+            session.last_used = now
+            self.db.commit()
+
+        return session
+
+    def refresh_session(self, session: ClientSession) -> ClientSession:
+        """
+        Replace token of a valid session and extend it by
+        CLIENT_SESSION_EXPIRE_DAYS from now.
+
+        The old token stops working. Expired sessions are not found by
+        get_session and have to be verified again.
+        """
+        now = datetime.utcnow()
+
+        session.session_token = secrets.token_urlsafe(32)
+        session.session_expires = now + timedelta(days=settings.CLIENT_SESSION_EXPIRE_DAYS)
+        session.last_used = now
+        self.db.commit()
+        self.db.refresh(session)
+
+        logger.info(f"Client session refreshed: client_id={session.client_id}")
+        return session
+
+    def scrub_client_data(self, client: Client, tenant_id: Optional[int] = None) -> dict:
+        """
+        Scrub personal data of a client from bookings, reviews and notifications.
+
+        Booking notes and review comments are cleared and notification
+        recipients replaced, only within the tenant if tenant_id is given.
+        Prices, statuses and ratings are kept, so stats and revenue don't
+        change. Returns the number of rows changed per table.
+        """
+        contacts = [c for c in (client.phone, client.email) if c]
+
+        bookings = self.db.query(Booking).filter(Booking.client_id == client.id)
+        reviews = self.db.query(Review).filter(Review.client_id == client.id)
+        notifications = self.db.query(NotificationLog).filter(
+            or_(NotificationLog.client_id == client.id, NotificationLog.recipient.in_(contacts))
+        )
+
+        if tenant_id is not None:
+            bookings = bookings.filter(Booking.tenant_id == tenant_id)
+            reviews = reviews.filter(Review.tenant_id == tenant_id)
+            notifications = notifications.filter(NotificationLog.tenant_id == tenant_id)
+
+        return {
+            "client_id": client.id,
+            "bookings": bookings.update({Booking.client_notes: None}, synchronize_session=False),
+            "reviews": reviews.update({Review.comment: None}, synchronize_session=False),
+            "notifications": notifications.update(
+                {NotificationLog.recipient: DELETED_RECIPIENT}, synchronize_session=False
+            )
+        }
+
+    def anonymize_client(self, client: Client) -> dict:
+        """
+        Delete personal data of a client on the client's own request.
+
+        Data is scrubbed with all businesses, name, email and phone are
+        replaced with placeholders and sessions are deleted. Bookings stay
+        for stats. The deletion is recorded in admin_actions. The caller
+        commits.
+        """
+        summary = self.scrub_client_data(client)
+
+        summary["sessions"] = self.db.query(ClientSession).filter(
+            ClientSession.client_id == client.id
+        ).delete(synchronize_session=False)
+
+        client.full_name = None
+        client.email = None
+        client.phone = f"deleted-{client.id}"
+        client.anonymized_at = datetime.utcnow()
+
+        self.db.add(AdminAction(
+            action_type="CLIENT_DATA_DELETED",
+            target_type="client",
+            target_id=client.id,
+            details=f"Requested by the client: {summary['bookings']} bookings, {summary['reviews']} reviews, "
+                    f"{summary['notifications']} notifications, {summary['sessions']} sessions"
+        ))
+
+        logger.info(f"Client data deleted: client={client.id}")
+        return summary
+
+    def delete_tenant_client_data(self, client: Client, tenant_id: int, requested_by: int) -> dict:
+        """
+        Delete personal data of a client within one tenant, on a request to its owner.
+
+        Only the tenant's bookings, reviews and notifications are scrubbed.
+        The client profile and sessions are shared with other businesses
+        and are left to the client's own deletion. The deletion is recorded
+        in admin_actions without admin_id, requested_by is a tenant user.
+        The caller commits.
+        """
+        summary = self.scrub_client_data(client, tenant_id)
+
+        self.db.add(AdminAction(
+            action_type="TENANT_CLIENT_DATA_DELETED",
+            target_type="client",
+            target_id=client.id,
+            details=f"Requested by user {requested_by} of tenant {tenant_id}: {summary['bookings']} bookings, "
+                    f"{summary['reviews']} reviews, {summary['notifications']} notifications"
+        ))
+
+        logger.info(f"Client data deleted: client={client.id}, tenant={tenant_id}")
+        return summary
+
+    def get_client_bookings(self, client_id: int) -> List[Booking]:
+        """Get all bookings of a client."""
+        return self.db.query(Booking).filter(
+            Booking.client_id == client_id
+        ).order_by(Booking.booking_date.desc()).all()
+
+    def get_client_history(
+        self,
+        tenant_id: int,
+        email: Optional[str] = None,
+        phone: Optional[str] = None
+    ) -> Optional[dict]:
+        """
+        Get bookings of a client within a tenant with spend and favorites.
+
+        Spend counts completed bookings only. Returns None if the client
+        has no bookings with the tenant.
+        """
+        query = self.db.query(Client)
+        if email:
+            query = query.filter(Client.email == email)
+        else:
+            query = query.filter(Client.phone == phone)
+
+        client_ids = [c.id for c in query.all()]
+        if not client_ids:
+            return None
+
+        bookings = self.db.query(Booking).filter(
+            Booking.tenant_id == tenant_id,
+            Booking.client_id.in_(client_ids)
+        ).order_by(Booking.booking_date.desc()).all()
+
+        if not bookings:
+            return None
+
+        completed = [b for b in bookings if b.status == BookingStatus.COMPLETED]
+
+        total_spend = {}
+        for booking in completed:
+            total_spend[booking.currency] = total_spend.get(booking.currency, 0) + booking.price
+
+        masters = Counter(b.master.full_name for b in completed if b.master)
+        # Each service of a combo counts
+        services = Counter(item.service.name for b in completed for item in b.items if item.service)
+        services.update(b.service.name for b in completed if not b.items and b.service)
+
+        return {
+            "client": bookings[0].client,
+            "bookings": bookings,
+            "completed": len(completed),
+            "total_spend": total_spend,
+            "favorite_master": masters.most_common(1)[0][0] if masters else None,
+            "favorite_service": services.most_common(1)[0][0] if services else None,
+            "last_visit": completed[0].booking_date if completed else None
+        }
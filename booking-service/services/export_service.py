@@ -0,0 +1,284 @@
+from sqlalchemy.orm import Session, Query
+from datetime import datetime
+from typing import Iterator, List, Optional
+import csv
+import io
+
+from shared.models import (
+    Booking, Client, ClientSession, Location, Master, NotificationLog, Review, Service, Tenant
+)
+from shared.billing import to_major
+from shared.i18n import translate
+
+# Columns of the bookings CSV, headers are translated as bookings_csv_<column>
+BOOKINGS_CSV_COLUMNS = (
+    "id", "booking_date", "status", "client_name", "client_phone",
+    "master_name", "services", "price", "currency", "created_at"
+)
+
+# Rows written per streamed chunk
+BOOKINGS_CSV_BATCH_SIZE = 500
+
+# Leading characters making spreadsheets evaluate a cell as a formula
+FORMULA_PREFIXES = ("=", "+", "-", "@", "\t", "\r")
+
+
+def iso(value: Optional[datetime]) -> Optional[str]:
+    return value.isoformat() if value else None
+
+
+def export_client(client: Client) -> dict:
+    return {
+        "id": client.id,
+        "full_name": client.full_name,
+        "phone": client.phone,
+        "email": client.email,
+        "no_show_count": client.no_show_count,
+        "created_at": iso(client.created_at)
+    }
+
+
+def csv_text(value: Optional[str]) -> str:
+    """Cell text entered by users, quoted so it's never run as a formula."""
+    if not value:
+        return ""
+    return f"'{value}" if value.startswith(FORMULA_PREFIXES) else value
+
+
+def booking_csv_row(booking: Booking) -> list:
+    """Row of the bookings CSV, dates in tenant local time."""
+    return [
+        booking.id,
+        booking.booking_date.strftime("%Y-%m-%d %H:%M"),
+        booking.status.value,
+        csv_text(booking.client.full_name if booking.client else None),
+        booking.client.phone if booking.client else "",
+        csv_text(booking.master.full_name if booking.master else None),
+        csv_text(booking.service_names),
+        f"{to_major(booking.price):.2f}",
+        booking.currency or "",
+        booking.created_at.strftime("%Y-%m-%d %H:%M") if booking.created_at else ""
+    ]
+
+
+def export_booking(booking: Booking) -> dict:
+    """Booking with its services, amounts in minor units."""
+    return {
+        "id": booking.id,
+        "tenant_id": booking.tenant_id,
+        "business_name": booking.tenant.business_name if booking.tenant else None,
+        "client_id": booking.client_id,
+        "master_id": booking.master_id,
+        "master_name": booking.master.full_name if booking.master else None,
+        "booking_date": booking.booking_date.isoformat(),
+        "duration_minutes": booking.duration_minutes,
+        "status": booking.status.value,
+        "services": [
+            {
+                "service_id": item.service_id,
+                "name": item.service.name if item.service else None,
+                "duration_minutes": item.duration_minutes,
+                "price_minor": item.price,
+                "tax_minor": item.tax_amount
+            }
+            for item in booking.items
+        ],
+        "price_minor": booking.price,
+        "tax_minor": booking.tax_amount,
+        "currency": booking.currency,
+        "client_notes": booking.client_notes,
+        "created_at": iso(booking.created_at),
+        "updated_at": iso(booking.updated_at)
+    }
+
+
+def export_review(review: Review) -> dict:
+    return {
+        "id": review.id,
+        "tenant_id": review.tenant_id,
+        "booking_id": review.booking_id,
+        "master_id": review.master_id,
+        "rating": review.rating,
+        "comment": review.comment,
+        "status": review.status.value,
+        "owner_response": review.owner_response,
+        "created_at": iso(review.created_at)
+    }
+
+
+def export_notification(log: NotificationLog) -> dict:
+    return {
+        "id": log.id,
+        "tenant_id": log.tenant_id,
+        "booking_id": log.booking_id,
+        "channel": log.channel.value,
+        "recipient": log.recipient,
+        "template": log.template,
+        "status": log.status.value,
+        "created_at": iso(log.created_at)
+    }
+
+
+def export_session(session: ClientSession) -> dict:
+    """Client session without its secrets (token and verification code)."""
+    return {
+        "id": session.id,
+        "is_verified": session.is_verified,
+        "created_at": iso(session.created_at),
+        "last_used": iso(session.last_used),
+        "session_expires": iso(session.session_expires)
+    }
+
+
+class ExportService:
+    """Data exports for data-portability requests."""
+
+    def __init__(self, db: Session):
+        self.db = db
+
+    def find_tenant_clients(self, tenant_id: int, email: str) -> List[Client]:
+        """Get clients with the email who have booked with the tenant."""
+        return self.db.query(Client).join(Booking).filter(
+            Client.email == email,
+            Booking.tenant_id == tenant_id
+        ).distinct().order_by(Client.id).all()
+
+    def export_client_data(self, clients: List[Client], tenant_id: Optional[int] = None) -> dict:
+        """
+        Export all data of the clients.
+
+        With tenant_id only data of the tenant is included, and sessions,
+        shared by all businesses, are left out.
+        """
+        client_ids = [c.id for c in clients]
+
+        bookings = self.db.query(Booking).filter(Booking.client_id.in_(client_ids))
+        reviews = self.db.query(Review).filter(Review.client_id.in_(client_ids))
+        notifications = self.db.query(NotificationLog).filter(NotificationLog.client_id.in_(client_ids))
+
+        if tenant_id is not None:
+            bookings = bookings.filter(Booking.tenant_id == tenant_id)
+            reviews = reviews.filter(Review.tenant_id == tenant_id)
+            notifications = notifications.filter(NotificationLog.tenant_id == tenant_id)
+
+        result = {
+            "exported_at": datetime.utcnow().isoformat(),
+            "clients": [export_client(c) for c in clients],
+            "bookings": [export_booking(b) for b in bookings.order_by(Booking.booking_date).all()],
+            "reviews": [export_review(r) for r in reviews.order_by(Review.created_at).all()],
+            "notifications": [
+                export_notification(n) for n in notifications.order_by(NotificationLog.created_at).all()
+            ]
+        }
+
+        if tenant_id is None:
+            sessions = self.db.query(ClientSession).filter(
+                ClientSession.client_id.in_(client_ids)
+            ).order_by(ClientSession.created_at).all()
+            result["sessions"] = [export_session(s) for s in sessions]
+
+        return result
+
+    def bookings_csv(self, query: Query, language: Optional[str] = None) -> Iterator[str]:
+        """
+        Stream bookings of the query as CSV with headers in the language.
+
+        Starts with a byte order mark so spreadsheet apps detect UTF-8.
+        Bookings are loaded in batches, so large exports don't sit in
+        memory.
+        """
+        buffer = io.StringIO()
+        writer = csv.writer(buffer)
+
+        def flush() -> str:
+            chunk = buffer.getvalue()
+            buffer.seek(0)
+            buffer.truncate()
+            return chunk
+
+        writer.writerow([translate(f"bookings_csv_{column}", language) for column in BOOKINGS_CSV_COLUMNS])
+        yield "\ufeff" + flush()
+
+        rows = 0
+        for booking in query.order_by(Booking.booking_date.desc(), Booking.id.desc()).yield_per(
+            BOOKINGS_CSV_BATCH_SIZE
+        ):
+            writer.writerow(booking_csv_row(booking))
+            rows += 1
+            if rows % BOOKINGS_CSV_BATCH_SIZE == 0:
+                yield flush()
+
+        yield flush()
+
+    def export_tenant_data(self, tenant: Tenant) -> dict:
+        """Export all data of the tenant: settings, catalog, staff, clients and bookings."""
+        bookings = self.db.query(Booking).filter(
+            Booking.tenant_id == tenant.id
+        ).order_by(Booking.booking_date).all()
+
+        client_ids = {b.client_id for b in bookings}
+        clients = self.db.query(Client).filter(
+            Client.id.in_(client_ids)
+        ).order_by(Client.id).all() if client_ids else []
+
+        locations = self.db.query(Location).filter(Location.tenant_id == tenant.id).order_by(Location.id).all()
+        services = self.db.query(Service).filter(Service.tenant_id == tenant.id).order_by(Service.id).all()
+        masters = self.db.query(Master).filter(Master.tenant_id == tenant.id).order_by(Master.id).all()
+        reviews = self.db.query(Review).filter(Review.tenant_id == tenant.id).order_by(Review.created_at).all()
+
+        return {
+            "exported_at": datetime.utcnow().isoformat(),
+            "tenant": {
+                "id": tenant.id,
+                "business_name": tenant.business_name,
+                "subdomain": tenant.subdomain,
+                "phone": tenant.phone,
+                "email": tenant.email,
+                "description": tenant.description,
+                "timezone": tenant.timezone,
+                "language": tenant.language,
+                "currency": tenant.currency,
+                "status": tenant.status.value,
+                "created_at": iso(tenant.created_at)
+            },
+            "locations": [
+                {"id": l.id, "name": l.name, "address": l.address, "phone": l.phone, "is_main": bool(l.is_main)}
+                for l in locations
+            ],
+            "services": [
+                {
+                    "id": s.id,
+                    "name": s.name,
+                    "description": s.description,
+                    "duration_minutes": s.duration_minutes,
+                    "price_minor": s.price,
+                    "capacity": s.capacity,
+                    "is_active": s.is_active
+                }
+                for s in services
+            ],
+            "masters": [
+                {
+                    "id": m.id,
+                    "full_name": m.full_name,
+                    "phone": m.phone,
+                    "description": m.description,
+                    "location_id": m.location_id,
+                    "is_active": m.is_active,
+                    "service_ids": [ms.service_id for ms in m.master_services],
+                    "schedule": [
+                        {
+                            "day_of_week": s.day_of_week,
+                            "start_time": s.start_time.strftime("%H:%M"),
+                            "end_time": s.end_time.strftime("%H:%M"),
+                            "is_working": s.is_working
+                        }
+                        for s in sorted(m.schedules, key=lambda s: s.day_of_week)
+                    ]
+                }
+                for m in masters
+            ],
+            "clients": [export_client(c) for c in clients],
+            "bookings": [export_booking(b) for b in bookings],
+            "reviews": [export_review(r) for r in reviews]
+        }
@@ -0,0 +1,115 @@
+from sqlalchemy.orm import Session
+from datetime import datetime, date, time, timedelta
+from enum import Enum
+from typing import Dict, List, Tuple
+
+from shared.models import Booking, BookingStatus
+from shared.billing import to_major
+
+
+class RevenueGroupBy(str, Enum):
+    DAY = "day"
+    WEEK = "week"
+    MONTH = "month"
+    MASTER = "master"
+    SERVICE = "service"
+
+
+TIME_GROUPS = (RevenueGroupBy.DAY, RevenueGroupBy.WEEK, RevenueGroupBy.MONTH)
+
+
+def bucket_start(booking_date: datetime, group_by: RevenueGroupBy) -> date:
+    """
+    Get start of the time bucket containing the booking.
+
+    Booking dates are stored in the tenant's local time, so buckets
+    follow the business calendar. Weeks start on Monday.
+    """
+    day = booking_date.date()
+
+    if group_by == RevenueGroupBy.WEEK:
+        return day - timedelta(days=day.weekday())
+    if group_by == RevenueGroupBy.MONTH:
+        return day.replace(day=1)
+    return day
+
+
+class ReportService:
+    """Revenue reports of a tenant."""
+
+    def __init__(self, db: Session):
+        self.db = db
+
+    def get_revenue_report(
+        self,
+        tenant_id: int,
+        start_date: date,
+        end_date: date,
+        group_by: RevenueGroupBy
+    ) -> List[dict]:
+        """
+        Get revenue of completed bookings grouped by time or staff.
+
+        Args:
+            tenant_id: Tenant ID
+            start_date: First day of the range (tenant local date)
+            end_date: Last day of the range, inclusive
+            group_by: Time bucket, master or service
+
+        Returns:
+            Buckets with booking count and revenue per currency
+        """
+        bookings = self.db.query(Booking).filter(
+            Booking.tenant_id == tenant_id,
+            Booking.status == BookingStatus.COMPLETED,
+            Booking.booking_date >= datetime.combine(start_date, time.min),
+            Booking.booking_date <= datetime.combine(end_date, time.max)
+        ).all()
+
+        buckets: Dict[Tuple, dict] = {}
+
+        for booking in bookings:
+            if group_by == RevenueGroupBy.SERVICE:
+                # Revenue of a combo is split between its services
+                lines = [(item.service_id, item.service, item.price) for item in booking.items] or [
+                    (booking.service_id, booking.service, booking.price)
+                ]
+                for service_id, service, amount in lines:
+                    bucket = buckets.setdefault(service_id, {
+                        "service_id": service_id,
+                        "service_name": service.name if service else None,
+                        "bookings": 0,
+                        "revenue": {}
+                    })
+                    bucket["bookings"] += 1
+                    bucket["revenue"][booking.currency] = bucket["revenue"].get(booking.currency, 0) + amount
+                continue
+
+            if group_by in TIME_GROUPS:
+                key = bucket_start(booking.booking_date, group_by)
+                bucket = {"start": key.isoformat()}
+            else:
+                key = booking.master_id
+                bucket = {
+                    "master_id": booking.master_id,
+                    "master_name": booking.master.full_name if booking.master else None
+                }
+
+            bucket = buckets.setdefault(key, {**bucket, "bookings": 0, "revenue": {}})
+            bucket["bookings"] += 1
+            bucket["revenue"][booking.currency] = bucket["revenue"].get(booking.currency, 0) + booking.price
+
+        result = []
+        for key in sorted(buckets, key=lambda k: (k is None, k)):
+            bucket = buckets[key]
+            bucket["revenue"] = [
+                {
+                    "currency": currency,
+                    "amount": to_major(amount_minor),
+                    "amount_minor": amount_minor
+                }
+                for currency, amount_minor in sorted(bucket["revenue"].items())
+            ]
+            result.append(bucket)
+
+        return result
@@ -0,0 +1,89 @@
+from datetime import time
+
+import pytest
+
+from shared.models import Booking
+
+
+@pytest.fixture
+def salon(factory):
+    tenant, service, first = factory.salon()
+    second = factory.master(tenant, services=[service])
+    factory.master(tenant, services=[factory.service(tenant, name="Manicure")])
+    return tenant, service, first, second
+
+
+def book(client, tenant, service, booking_date, phone):
+    return client.post("/public/booking", json={
+        "subdomain": tenant.subdomain,
+        "client_phone": phone,
+        "client_name": "Aida",
+        "service_id": service.id,
+        "booking_date": booking_date.isoformat()
+    })
+
+
+def test_free_qualified_master_is_assigned(client, db, factory, salon):
+    tenant, service, first, second = salon
+    start = factory.next_day(time(10))
+
+    assigned = [book(client, tenant, service, start, f"+7701111111{n}").json()["master_id"] for n in range(2)]
+
+    assert assigned == [first.id, second.id]
+    assert {b.master_id for b in db.query(Booking).filter(Booking.tenant_id == tenant.id)} == {first.id, second.id}
+
+
+def test_no_free_master_is_conflict(client, factory, salon):
+    tenant, service, _, _ = salon
+    start = factory.next_day(time(10))
+    for n in range(2):
+        assert book(client, tenant, service, start, f"+7701111111{n}").status_code == 201
+
+    response = book(client, tenant, service, start, "+77019999999")
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "NO_MASTER_AVAILABLE"
+
+
+def test_master_being_booked_concurrently_is_skipped(client, service, factory, salon, monkeypatch):
+    tenant, offered, first, second = salon
+    lock_master = service.BookingService.lock_master
+
+    def locked_elsewhere(self, master_id, skip_locked=False):
+        if skip_locked and master_id == first.id:
+            return None
+        return lock_master(self, master_id, skip_locked)
+
+    monkeypatch.setattr(service.BookingService, "lock_master", locked_elsewhere)
+
+    response = book(client, tenant, offered, factory.next_day(time(10)), "+77011111111")
+
+    assert response.status_code == 201
+    assert response.json()["master_id"] == second.id
+
+
+def test_availability_lists_free_masters_per_slot(client, factory, salon):
+    tenant, service, first, second = salon
+    start = factory.next_day(time(10))
+    assert book(client, tenant, service, start, "+77011111111").status_code == 201
+
+    response = client.get(
+        f"/public/business/{tenant.subdomain}/availability",
+        params={"date": start.date().isoformat(), "service_ids": [service.id]}
+    )
+
+    assert response.status_code == 200
+    masters_by_slot = response.json()["masters_by_slot"]
+    assert masters_by_slot["09:00"] == [first.id, second.id]
+    assert masters_by_slot["10:00"] == [second.id]
+
+
+def test_availability_without_master_needs_services(client, factory, salon):
+    tenant = salon[0]
+
+    response = client.get(
+        f"/public/business/{tenant.subdomain}/availability",
+        params={"date": factory.next_day(time(0)).date().isoformat()}
+    )
+
+    assert response.status_code == 400
@@ -0,0 +1,66 @@
+from datetime import date, time
+
+from shared.cache import get_availability_version, invalidate_master_availability
+
+
+def first_slot(client, tenant, master, day):
+    response = client.get(
+        f"/public/business/{tenant.subdomain}/availability",
+        params={"master_id": master.id, "date": day.isoformat()}
+    )
+    assert response.status_code == 200
+    return response.json()["available_slots"][0] if response.json()["available_slots"] else None
+
+
+def available_dates(client, tenant, master, day):
+    response = client.get(
+        f"/public/business/{tenant.subdomain}/available-dates",
+        params={"master_id": master.id, "month": day.strftime("%Y-%m")}
+    )
+    assert response.status_code == 200
+    return response.json()["available_dates"]
+
+
+def test_schedule_change_invalidates_cached_slots(client, factory, salon):
+    tenant, _, master = salon
+    day = factory.next_day(time(0)).date()
+    assert first_slot(client, tenant, master, day) == "09:00"
+
+    response = client.put(
+        f"/masters/{master.id}/schedule",
+        params={"tenant_id": tenant.id},
+        json={"weekly": [
+            {"day_of_week": d, "start_time": "12:00", "end_time": "18:00"} for d in range(7)
+        ]}
+    )
+
+    assert response.status_code == 200
+    assert first_slot(client, tenant, master, day) == "12:00"
+
+
+def test_time_off_invalidates_cached_dates(client, factory, salon):
+    tenant, _, master = salon
+    owner = factory.user(tenant)
+    day = factory.next_day(time(0)).date()
+    assert day.isoformat() in available_dates(client, tenant, master, day)
+
+    response = client.post(
+        f"/masters/{master.id}/time-off",
+        params={"tenant_id": tenant.id, "user_id": owner.id, "role": owner.role.value},
+        json={"start_date": day.isoformat(), "end_date": day.isoformat()}
+    )
+
+    assert response.status_code == 201
+    assert day.isoformat() not in available_dates(client, tenant, master, day)
+    assert first_slot(client, tenant, master, day) is None
+
+
+def test_master_invalidation_bumps_dates_and_months(cache):
+    versions = {p: get_availability_version(1, 2, p) for p in ("2024-05-30", "2024-06-01", "2024-05", "2024-06")}
+
+    invalidate_master_availability(1, 2, date(2024, 5, 30), date(2024, 6, 1))
+
+    for period, version in versions.items():
+        assert get_availability_version(1, 2, period) == version + 1
+    assert get_availability_version(1, 2, "2024-06-02") == 0
+    assert get_availability_version(3, 2, "2024-05-30") == 0
@@ -0,0 +1,73 @@
+from datetime import timedelta
+
+import pytest
+
+from shared.cache import get_availability_version, get_cached_availability
+from shared.models import MasterScheduleOverride
+
+
+@pytest.fixture
+def salon(factory):
+    return factory.salon(service={"duration_minutes": 60})
+
+
+def cached(service, tenant, master, day, duration, interval):
+    version = get_availability_version(tenant.id, master.id, day.isoformat())
+    variant = service.availability_variant(duration, interval)
+    return get_cached_availability(tenant.id, master.id, day.isoformat(), version, variant)
+
+
+def warm(client, tenant, days):
+    response = client.post("/availability/warm", params={"tenant_id": tenant.id, "days": days})
+    assert response.status_code == 200
+    return response.json()
+
+
+def test_warmer_caches_default_and_service_slots(client, service, salon):
+    tenant, haircut, master = salon
+    today = service.tenant_local_now(tenant).date()
+    default_interval = service.get_slot_interval(tenant)
+    haircut_interval = service.get_slot_interval(tenant, haircut)
+
+    result = warm(client, tenant, 2)
+
+    assert result["masters"] == 1
+    for day in (today, today + timedelta(days=1)):
+        assert cached(service, tenant, master, day, default_interval, default_interval) is not None
+        assert cached(service, tenant, master, day, 60, haircut_interval)["duration_minutes"] == 60
+    assert cached(service, tenant, master, today + timedelta(days=2), 60, haircut_interval) is None
+
+
+def test_warmer_skips_days_off(client, service, factory, salon):
+    tenant, haircut, master = salon
+    day_off = service.tenant_local_now(tenant).date() + timedelta(days=1)
+    factory.add(MasterScheduleOverride(master_id=master.id, date=day_off, is_working=False))
+
+    result = warm(client, tenant, 2)
+
+    assert cached(service, tenant, master, day_off, 60, service.get_slot_interval(tenant, haircut)) is None
+    # Default slot and the haircut, on the working day only
+    assert result["entries"] == 2
+
+
+def test_warmer_is_tenant_scoped_and_skips_hidden_masters(client, service, factory, salon):
+    tenant, haircut, master = salon
+    hidden = factory.master(tenant, [haircut], is_visible=False)
+    other_tenant = factory.tenant()
+    other = factory.master(other_tenant, [factory.service(other_tenant)])
+    today = service.tenant_local_now(tenant).date()
+    interval = service.get_slot_interval(tenant)
+
+    result = warm(client, tenant, 1)
+
+    assert result["masters"] == 1
+    assert cached(service, tenant, master, today, interval, interval) is not None
+    assert cached(service, tenant, hidden, today, interval, interval) is None
+    assert cached(service, other_tenant, other, today, interval, interval) is None
+
+
+def test_unknown_tenant_is_not_found(client):
+    response = client.post("/availability/warm", params={"tenant_id": 999999})
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "TENANT_NOT_FOUND"
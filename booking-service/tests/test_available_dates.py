@@ -0,0 +1,77 @@
+import calendar
+from datetime import datetime, time, timedelta
+
+import pytest
+
+from shared.models import MasterScheduleOverride
+
+
+@pytest.fixture
+def month(factory):
+    """First day of next month, all of it in the future."""
+    today = factory.next_day(time(0), 0).date()
+    return (today.replace(day=1) + timedelta(days=32)).replace(day=1)
+
+
+@pytest.fixture
+def salon(factory):
+    return factory.salon(
+        service={"duration_minutes": 60, "slot_interval_minutes": 60}, master={"hours": (time(9), time(11))}
+    )
+
+
+def month_days(month):
+    return [month.replace(day=d).isoformat() for d in range(1, calendar.monthrange(month.year, month.month)[1] + 1)]
+
+
+def available_dates(client, tenant, month, **params):
+    response = client.get(
+        f"/public/business/{tenant.subdomain}/available-dates", params={"month": month.strftime("%Y-%m"), **params}
+    )
+    assert response.status_code == 200
+    return response.json()["available_dates"]
+
+
+def test_fully_booked_day_and_day_off_are_unavailable(client, factory, month, salon):
+    tenant, service, master = salon
+    booked, off = month.replace(day=10), month.replace(day=20)
+    for hour in (9, 10):
+        factory.booking(tenant, master, service, factory.client(), booking_date=datetime.combine(booked, time(hour)))
+    factory.add(MasterScheduleOverride(master_id=master.id, date=off, is_working=False))
+
+    dates = available_dates(client, tenant, month, master_id=master.id, service_ids=[service.id])
+
+    assert dates == [d for d in month_days(month) if d not in (booked.isoformat(), off.isoformat())]
+
+
+def test_partly_booked_day_is_available(client, factory, month, salon):
+    tenant, service, master = salon
+    factory.booking(tenant, master, service, factory.client(), booking_date=datetime.combine(month, time(9)))
+
+    assert month.isoformat() in available_dates(client, tenant, month, master_id=master.id, service_ids=[service.id])
+
+
+def test_any_master_without_master_id(client, factory, month, salon):
+    tenant, service, master = salon
+    other = factory.master(tenant, [service], hours=None)
+    factory.add(MasterScheduleOverride(
+        master_id=other.id, date=month.replace(day=20), is_working=True, start_time=time(9), end_time=time(11)
+    ))
+    factory.add(MasterScheduleOverride(master_id=master.id, date=month.replace(day=20), is_working=False))
+
+    dates = available_dates(client, tenant, month, service_ids=[service.id])
+
+    assert dates == month_days(month)
+
+
+def test_past_month_is_unavailable(client, factory, salon):
+    tenant, service, master = salon
+    last_month = (factory.next_day(time(0), 0).date().replace(day=1) - timedelta(days=1)).replace(day=1)
+
+    assert available_dates(client, tenant, last_month, master_id=master.id, service_ids=[service.id]) == []
+
+
+def test_invalid_month_is_rejected(client, salon):
+    response = client.get(f"/public/business/{salon[0].subdomain}/available-dates", params={"month": "2026-13"})
+
+    assert response.status_code == 422
@@ -0,0 +1,82 @@
+import pytest
+
+from shared.models import UserRole
+
+
+@pytest.fixture
+def staff(factory):
+    """User of the master the booking is with."""
+    return factory.user(factory.tenant(), role=UserRole.MASTER)
+
+
+@pytest.fixture
+def booking(factory, staff):
+    tenant = staff.tenant
+    service = factory.service(tenant)
+    return factory.booking(tenant, factory.master(tenant, [service], user_id=staff.id), service, factory.client())
+
+
+def as_client(client, factory, booking_id, customer):
+    session = factory.session(customer)
+    return client.get(f"/booking/{booking_id}", headers={"X-Client-Session": session.session_token})
+
+
+def as_staff(client, booking_id, user, tenant_id):
+    return client.get(f"/booking/{booking_id}", params={
+        "user_id": user.id, "role": user.role.value, "tenant_id": tenant_id
+    })
+
+
+def test_client_reads_own_booking(client, factory, booking):
+    response = as_client(client, factory, booking.id, booking.client)
+
+    assert response.status_code == 200
+    assert response.json()["id"] == booking.id
+    # The client representation, without staff notes
+    assert "price_formatted" in response.json()
+    assert "client_no_show_count" not in response.json()
+
+
+def test_client_cannot_read_others_booking(client, factory, booking):
+    response = as_client(client, factory, booking.id, factory.client())
+
+    assert response.status_code == 403
+    assert response.json()["error"] == "FORBIDDEN"
+
+
+def test_staff_reads_booking_of_tenant(client, factory, booking):
+    response = as_staff(client, booking.id, factory.user(booking.tenant), booking.tenant_id)
+
+    assert response.status_code == 200
+    assert "client_no_show_count" in response.json()
+
+
+def test_staff_of_other_tenant_gets_not_found(client, factory, booking):
+    other = factory.tenant()
+
+    response = as_staff(client, booking.id, factory.user(other), other.id)
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "BOOKING_NOT_FOUND"
+
+
+def test_master_reads_only_own_bookings(client, factory, booking, staff):
+    own = as_staff(client, booking.id, staff, booking.tenant_id)
+    other = as_staff(client, booking.id, factory.user(booking.tenant, role=UserRole.MASTER), booking.tenant_id)
+
+    assert own.status_code == 200
+    assert other.status_code == 403
+
+
+def test_client_role_without_session_is_rejected(client, booking):
+    response = client.get(f"/booking/{booking.id}", params={
+        "user_id": booking.client_id, "role": "CLIENT", "tenant_id": booking.tenant_id
+    })
+
+    assert response.status_code == 403
+
+
+def test_super_admin_reads_any_booking(client, factory, booking):
+    response = as_staff(client, booking.id, factory.user(role=UserRole.SUPER_ADMIN), None)
+
+    assert response.status_code == 200
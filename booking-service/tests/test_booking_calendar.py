@@ -0,0 +1,34 @@
+from shared.models import BookingStatus
+
+
+def booked(factory, **values):
+    tenant, service, master = factory.salon()
+    return factory.booking(tenant, master, service, factory.client(), **values)
+
+
+def test_calendar_file_by_public_code(client, db, factory):
+    booking = booked(factory)
+    db.flush()
+
+    response = client.get(f"/public/booking/{booking.public_code}/calendar.ics")
+
+    assert response.status_code == 200
+    assert response.headers["content-type"].startswith("text/calendar")
+    assert f'filename="booking-{booking.id}.ics"' in response.headers["content-disposition"]
+    assert f"UID:booking-{booking.id}@" in response.text
+
+
+def test_unknown_code_is_not_found(client):
+    response = client.get("/public/booking/no-such-code/calendar.ics")
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "BOOKING_NOT_FOUND"
+
+
+def test_cancelled_booking_has_no_calendar_file(client, db, factory):
+    booking = booked(factory, status=BookingStatus.CANCELLED)
+    db.flush()
+
+    response = client.get(f"/public/booking/{booking.public_code}/calendar.ics")
+
+    assert response.status_code == 404
@@ -0,0 +1,170 @@
+from datetime import datetime, timedelta
+
+import pytest
+
+from shared.models import BookingStatus, UserRole
+
+
+def test_booking_not_started_cannot_be_completed(client, factory, salon, staff_params):
+    tenant, service, master = salon
+    owner = factory.user(tenant)
+    booking = factory.booking(tenant, master, service, factory.client())
+
+    response = client.put(f"/booking/{booking.id}/complete", json=staff_params(owner))
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "BOOKING_NOT_STARTED"
+
+
+def test_started_booking_is_completed(client, db, factory, salon, staff_params):
+    tenant, service, master = salon
+    manager = factory.user(tenant, role=UserRole.MANAGER)
+    booking = factory.booking(
+        tenant, master, service, factory.client(), booking_date=datetime.utcnow() - timedelta(minutes=5)
+    )
+
+    response = client.put(f"/booking/{booking.id}/complete", json=staff_params(manager))
+
+    assert response.status_code == 200
+    assert response.json()["status"] == "COMPLETED"
+    db.refresh(booking)
+    assert booking.status == BookingStatus.COMPLETED
+
+
+def test_completing_twice_succeeds(client, factory, salon, staff_params):
+    tenant, service, master = salon
+    owner = factory.user(tenant)
+    booking = factory.booking(
+        tenant, master, service, factory.client(), booking_date=datetime.utcnow() - timedelta(hours=1)
+    )
+
+    client.put(f"/booking/{booking.id}/complete", json=staff_params(owner))
+    response = client.put(f"/booking/{booking.id}/complete", json=staff_params(owner))
+
+    assert response.status_code == 200
+    assert response.json()["message"] == "Booking already completed"
+
+
+def test_owner_can_force_early_completion(client, factory, salon, staff_params):
+    tenant, service, master = salon
+    owner = factory.user(tenant)
+    booking = factory.booking(tenant, master, service, factory.client())
+
+    response = client.put(f"/booking/{booking.id}/complete", json=staff_params(owner, force=True))
+
+    assert response.status_code == 200
+    assert response.json()["status"] == "COMPLETED"
+
+
+def test_only_owner_can_force_completion(client, factory, salon, staff_params):
+    tenant, service, master = salon
+    manager = factory.user(tenant, role=UserRole.MANAGER)
+    booking = factory.booking(tenant, master, service, factory.client())
+
+    response = client.put(f"/booking/{booking.id}/complete", json=staff_params(manager, force=True))
+
+    assert response.status_code == 403
+
+
+def test_cancelled_booking_cannot_be_completed(client, factory, salon, staff_params):
+    tenant, service, master = salon
+    owner = factory.user(tenant)
+    booking = factory.booking(
+        tenant, master, service, factory.client(),
+        booking_date=datetime.utcnow() - timedelta(hours=1), status=BookingStatus.CANCELLED
+    )
+
+    response = client.put(f"/booking/{booking.id}/complete", json=staff_params(owner))
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "INVALID_BOOKING_STATUS"
+
+
+def test_completion_uses_tenant_timezone(client, factory, staff_params):
+    # Booking dates are local time of the business: six hours past UTC
+    # is already over in Auckland (UTC+12 or +13)
+    tenant = factory.tenant(timezone="Pacific/Auckland")
+    service = factory.service(tenant)
+    master = factory.master(tenant, services=[service])
+    owner = factory.user(tenant)
+    booking = factory.booking(
+        tenant, master, service, factory.client(),
+        booking_date=datetime.utcnow() + timedelta(hours=6)
+    )
+
+    response = client.put(f"/booking/{booking.id}/complete", json=staff_params(owner))
+
+    assert response.status_code == 200
+
+
+@pytest.mark.parametrize("target", ["COMPLETED", "NO_SHOW", "CANCELLED"])
+def test_update_cannot_set_dedicated_status(client, db, factory, salon, staff_params, target):
+    tenant, service, master = salon
+    owner = factory.user(tenant)
+    booking = factory.booking(
+        tenant, master, service, factory.client(), booking_date=datetime.utcnow() - timedelta(hours=1)
+    )
+
+    response = client.put(f"/booking/{booking.id}", json=staff_params(owner, status=target))
+
+    assert response.status_code == 400
+    assert response.json()["error"] == "INVALID_BOOKING_STATUS"
+    assert response.json()["details"] == {"status": target}
+    db.refresh(booking)
+    assert booking.status == BookingStatus.CONFIRMED
+
+
+def test_update_confirms_pending_booking(client, db, factory, salon, staff_params):
+    tenant, service, master = salon
+    owner = factory.user(tenant)
+    booking = factory.booking(tenant, master, service, factory.client(), status=BookingStatus.PENDING)
+
+    response = client.put(f"/booking/{booking.id}", json=staff_params(owner, status="CONFIRMED", notes="Paid cash"))
+
+    assert response.status_code == 200
+    db.refresh(booking)
+    assert booking.status == BookingStatus.CONFIRMED
+    assert booking.admin_notes == "Paid cash"
+
+
+@pytest.mark.parametrize("values", [
+    {"status": "CONFIRMED"},
+    {"booking_date": (datetime.utcnow() + timedelta(days=2)).replace(microsecond=0).isoformat()}
+])
+def test_cancelled_booking_cannot_be_updated(client, db, factory, salon, staff_params, values):
+    tenant, service, master = salon
+    owner = factory.user(tenant)
+    booking = factory.booking(tenant, master, service, factory.client(), status=BookingStatus.CANCELLED)
+    booking_date = booking.booking_date
+
+    response = client.put(f"/booking/{booking.id}", json=staff_params(owner, **values))
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "INVALID_BOOKING_STATUS"
+    db.refresh(booking)
+    assert booking.status == BookingStatus.CANCELLED
+    assert booking.booking_date == booking_date
+
+
+def test_cancelled_booking_notes_can_be_updated(client, db, factory, salon, staff_params):
+    tenant, service, master = salon
+    owner = factory.user(tenant)
+    booking = factory.booking(tenant, master, service, factory.client(), status=BookingStatus.CANCELLED)
+
+    response = client.put(f"/booking/{booking.id}", json=staff_params(owner, notes="Called to rebook"))
+
+    assert response.status_code == 200
+    db.refresh(booking)
+    assert booking.admin_notes == "Called to rebook"
+
+
+def test_confirmed_booking_cannot_go_back_to_pending(client, db, factory, salon, staff_params):
+    tenant, service, master = salon
+    owner = factory.user(tenant)
+    booking = factory.booking(tenant, master, service, factory.client())
+
+    response = client.put(f"/booking/{booking.id}", json=staff_params(owner, status="PENDING"))
+
+    assert response.status_code == 409
+    db.refresh(booking)
+    assert booking.status == BookingStatus.CONFIRMED
@@ -0,0 +1,30 @@
+from datetime import time
+import json
+
+from shared.events import booking_events_channel
+
+
+def test_booking_creation_publishes_event(client, cache, factory):
+    tenant, service, master = factory.salon()
+    pubsub = cache.client.pubsub()
+    pubsub.subscribe(booking_events_channel(tenant.id))
+    assert pubsub.get_message(timeout=5)["type"] == "subscribe"
+
+    try:
+        response = client.post("/public/booking", json={
+            "subdomain": tenant.subdomain,
+            "client_phone": "+77011111111",
+            "client_name": "Aida",
+            "master_id": master.id,
+            "service_id": service.id,
+            "booking_date": factory.next_day(time(10)).isoformat()
+        })
+        message = pubsub.get_message(timeout=5)
+    finally:
+        pubsub.close()
+
+    assert response.status_code == 201
+    payload = json.loads(message["data"])
+    assert payload["event"] == "created"
+    assert payload["booking_id"] == response.json()["booking_id"]
+    assert payload["master_id"] == master.id
@@ -0,0 +1,86 @@
+import csv
+import io
+from datetime import time
+
+import pytest
+
+from shared.models import BookingStatus
+
+
+@pytest.fixture
+def salon(factory):
+    return factory.salon(
+        tenant={"subdomain": "beauty-bar", "language": "en"},
+        service={"name": "Haircut", "price": 500000},
+        master={"full_name": "Dana"}
+    )
+
+
+def export(client, tenant, **params):
+    response = client.get("/bookings/export", params={"tenant_id": tenant.id, **params})
+    assert response.status_code == 200
+    return response
+
+
+def rows(response):
+    assert response.text.startswith("\ufeff")
+    return list(csv.reader(io.StringIO(response.text[1:])))
+
+
+def test_csv_formatting(client, factory, salon):
+    tenant, service, master = salon
+    booking = factory.booking(
+        tenant, master, service, factory.client(full_name="Aida", phone="+77011111111"),
+        booking_date=factory.next_day(time(10, 30))
+    )
+
+    response = export(client, tenant)
+
+    assert response.headers["content-type"].startswith("text/csv")
+    assert response.headers["content-disposition"] == 'attachment; filename="bookings-beauty-bar-all.csv"'
+    header, row = rows(response)
+    assert header == [
+        "ID", "Date and time", "Status", "Client", "Client phone",
+        "Master", "Services", "Price", "Currency", "Created"
+    ]
+    assert row[:9] == [
+        str(booking.id), booking.booking_date.strftime("%Y-%m-%d %H:%M"), "CONFIRMED", "Aida", "+77011111111",
+        "Dana", "Haircut", "5000.00", "KZT"
+    ]
+
+
+def test_headers_in_requested_language(client, salon):
+    tenant = salon[0]
+
+    header, = rows(export(client, tenant, language="ru"))
+
+    assert header[:3] == ["Номер", "Дата и время", "Статус"]
+
+
+def test_filters_are_applied(client, factory, salon):
+    tenant, service, master = salon
+    tomorrow = factory.booking(tenant, master, service, factory.client())
+    factory.booking(tenant, master, service, factory.client(), booking_date=factory.next_day(time(10), 2))
+    factory.booking(tenant, master, service, factory.client(), status=BookingStatus.CANCELLED)
+    other = factory.tenant()
+    factory.booking(other, factory.master(other), factory.service(other), factory.client())
+
+    response = export(client, tenant, date=tomorrow.booking_date.date().isoformat(), status="CONFIRMED")
+
+    assert [row[0] for row in rows(response)[1:]] == [str(tomorrow.id)]
+    assert f"-{tomorrow.booking_date.date().isoformat()}.csv" in response.headers["content-disposition"]
+
+
+def test_formula_cells_are_quoted(client, factory, salon):
+    tenant, service, master = salon
+    factory.booking(tenant, master, service, factory.client(full_name="=HYPERLINK(\"http://evil\")"))
+
+    header, row = rows(export(client, tenant))
+
+    assert row[3] == "'=HYPERLINK(\"http://evil\")"
+
+
+def test_unknown_tenant_is_not_found(client):
+    response = client.get("/bookings/export", params={"tenant_id": 999999})
+
+    assert response.status_code == 404
@@ -0,0 +1,87 @@
+from datetime import datetime, time, timedelta
+
+import pytest
+
+from shared.api import decode_cursor, encode_cursor
+from shared.models import UserRole
+
+
+@pytest.fixture
+def bookings(factory):
+    """Seven bookings of one tenant, newest created first; two share created_at."""
+    tenant = factory.tenant()
+    service = factory.service(tenant)
+    master = factory.master(tenant, [service], hours=(8, 20))
+    created = datetime(2026, 1, 1, 12)
+    offsets = [0, 1, 2, 3, 3, 4, 5]
+    rows = [
+        factory.booking(
+            tenant, master, service, factory.client(), booking_date=factory.next_day(time(8 + i)),
+            created_at=created + timedelta(minutes=offset)
+        )
+        for i, offset in enumerate(offsets)
+    ]
+    return tenant, sorted(rows, key=lambda b: (b.created_at, b.id), reverse=True)
+
+
+def list_bookings(client, tenant, **params):
+    response = client.get(
+        "/bookings", params={"user_id": 1, "role": UserRole.OWNER.value, "tenant_id": tenant.id, **params}
+    )
+    assert response.status_code == 200
+    return response.json()
+
+
+def test_cursor_pages_do_not_overlap(client, bookings):
+    tenant, expected = bookings
+    pages, cursor = [], None
+
+    while True:
+        params = {"limit": 2, **({"cursor": cursor} if cursor else {})}
+        result = list_bookings(client, tenant, **params)
+        pages.append([b["id"] for b in result["bookings"]])
+        cursor = result["next_cursor"]
+        if cursor is None:
+            break
+
+    assert [len(page) for page in pages] == [2, 2, 2, 1]
+    assert [id for page in pages for id in page] == [b.id for b in expected]
+
+
+def test_offset_pages_keep_total(client, bookings):
+    tenant, expected = bookings
+
+    result = list_bookings(client, tenant, limit=3, page=3)
+
+    assert result["page"] == 3
+    assert result["total"] == 7
+    assert result["limit"] == 3
+    assert [b["id"] for b in result["bookings"]] == [expected[6].id]
+    assert result["next_cursor"] is None
+
+
+def test_without_limit_all_bookings_are_returned(client, bookings):
+    tenant, _ = bookings
+
+    result = list_bookings(client, tenant)
+
+    assert len(result["bookings"]) == 7
+    assert "next_cursor" not in result
+
+
+def test_invalid_cursor_is_rejected(client, bookings):
+    tenant, _ = bookings
+
+    response = client.get(
+        "/bookings",
+        params={"user_id": 1, "role": UserRole.OWNER.value, "tenant_id": tenant.id, "limit": 2, "cursor": "bogus"}
+    )
+
+    assert response.status_code == 400
+    assert response.json()["error"] == "INVALID_CURSOR"
+
+
+def test_cursor_round_trip():
+    created_at = datetime(2026, 1, 1, 12, 30, 15, 123456)
+
+    assert decode_cursor(encode_cursor(created_at, 42)) == (created_at, 42)
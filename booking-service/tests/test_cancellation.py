@@ -0,0 +1,185 @@
+from datetime import datetime, timedelta
+
+import pytest
+
+from shared.models import BookingStatus, Payment, PaymentStatus
+
+POLICY = [
+    {"hours_before": 24, "fee_percent": 0},
+    {"hours_before": 2, "fee_percent": 50},
+    {"hours_before": 0, "fee_percent": 100}
+]
+
+
+@pytest.fixture
+def salon(factory):
+    tenant, service, master = factory.salon(service={"price": 100000, "cancellation_policy": POLICY})
+    return tenant, master, service, factory.user(tenant)
+
+
+@pytest.fixture
+def refunds_sent(service, monkeypatch):
+    """Bookings whose refunds were sent to the payment service."""
+    sent = []
+
+    async def send_deposit_refunds(booking):
+        sent.append(booking.id)
+        return True
+
+    monkeypatch.setattr(service, "send_deposit_refunds", send_deposit_refunds)
+    return sent
+
+
+def paid_booking(factory, tenant, master, service, starts_in):
+    booking = factory.booking(
+        tenant, master, service, factory.client(), booking_date=datetime.utcnow() + starts_in
+    )
+    payment = factory.add(Payment(
+        tenant_id=tenant.id, booking_id=booking.id, amount=30000, currency="KZT",
+        status=PaymentStatus.SUCCEEDED, provider_payment_id=f"prov-{booking.id}", paid_at=datetime.utcnow()
+    ))
+    return booking, payment
+
+
+def staff_cancel(client, booking, owner, **params):
+    return client.delete(
+        f"/booking/{booking.id}",
+        params={"user_id": owner.id, "role": owner.role.value, "tenant_id": booking.tenant_id, **params}
+    )
+
+
+def test_service_policy_is_set_and_cleared(client, db, salon):
+    tenant, _, service, _ = salon
+    url = f"/services/{service.id}/cancellation-policy"
+    policy = [{"hours_before": 0, "fee_percent": 100}, {"hours_before": 48, "fee_percent": 0}]
+
+    response = client.put(url, params={"tenant_id": tenant.id}, json={"cancellation_policy": policy})
+
+    assert response.status_code == 200
+    assert response.json()["cancellation_policy"] == policy[::-1]
+    db.refresh(service)
+    assert service.cancellation_policy == policy[::-1]
+    detail = client.get(f"/services/{service.id}", params={"tenant_id": tenant.id}).json()
+    assert detail["cancellation_policy_override"] == policy[::-1]
+
+    response = client.put(url, params={"tenant_id": tenant.id}, json={"cancellation_policy": None})
+
+    assert response.status_code == 200
+    assert response.json()["cancellation_policy_override"] is None
+    db.refresh(service)
+    assert service.cancellation_policy is None
+
+
+@pytest.mark.parametrize("policy", [
+    [],
+    [{"hours_before": 24, "fee_percent": 0}, {"hours_before": 24, "fee_percent": 50}],
+    [{"hours_before": 24, "fee_percent": 150}],
+    [{"hours_before": -1, "fee_percent": 0}]
+])
+def test_invalid_service_policy_is_rejected(client, salon, policy):
+    tenant, _, service, _ = salon
+
+    response = client.put(
+        f"/services/{service.id}/cancellation-policy",
+        params={"tenant_id": tenant.id},
+        json={"cancellation_policy": policy}
+    )
+
+    assert response.status_code == 422
+
+
+def test_policy_of_other_tenant_service_is_not_found(client, factory, salon):
+    _, _, service, _ = salon
+
+    response = client.put(
+        f"/services/{service.id}/cancellation-policy",
+        params={"tenant_id": factory.tenant().id},
+        json={"cancellation_policy": None}
+    )
+
+    assert response.status_code == 404
+
+
+@pytest.mark.parametrize("starts_in, fee_percent, refund", [
+    (timedelta(days=2), 0, 30000),
+    (timedelta(hours=5), 50, 0),
+    (timedelta(minutes=30), 100, 0),
+    (timedelta(minutes=-30), 100, 0)
+])
+def test_staff_cancellation_keeps_policy_fee(client, db, factory, salon, refunds_sent, starts_in, fee_percent, refund):
+    tenant, master, service, owner = salon
+    booking, payment = paid_booking(factory, tenant, master, service, starts_in)
+
+    response = staff_cancel(client, booking, owner)
+
+    assert response.status_code == 200
+    cancellation = response.json()["cancellation"]
+    assert cancellation["fee_percent"] == fee_percent
+    assert cancellation["refund_minor"] == refund
+    db.expire_all()
+    assert booking.status == BookingStatus.CANCELLED
+    assert payment.refund_amount == refund
+    assert refunds_sent == ([booking.id] if refund else [])
+
+
+def test_staff_cancellation_can_waive_fee(client, db, factory, salon, refunds_sent):
+    tenant, master, service, owner = salon
+    booking, payment = paid_booking(factory, tenant, master, service, timedelta(minutes=30))
+
+    response = staff_cancel(client, booking, owner, waive_fee=True)
+
+    assert response.status_code == 200
+    assert response.json()["cancellation"]["fee_percent"] == 0
+    db.expire_all()
+    assert payment.refund_amount == 30000
+    assert refunds_sent == [booking.id]
+
+
+@pytest.mark.parametrize("status", [BookingStatus.COMPLETED, BookingStatus.NO_SHOW])
+def test_staff_cannot_cancel_finished_booking(client, db, factory, salon, refunds_sent, status):
+    tenant, master, service, owner = salon
+    booking, payment = paid_booking(factory, tenant, master, service, timedelta(days=-1))
+    booking.status = status
+    db.flush()
+
+    response = staff_cancel(client, booking, owner, waive_fee=True)
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "INVALID_BOOKING_STATUS"
+    db.expire_all()
+    assert booking.status == status
+    assert payment.refund_amount is None
+    assert refunds_sent == []
+
+
+def test_client_cancellation_refund_is_sent(client, db, factory, salon, refunds_sent):
+    tenant, master, service, _ = salon
+    booking, payment = paid_booking(factory, tenant, master, service, timedelta(days=2))
+    session = factory.session(booking.client)
+
+    response = client.delete(
+        f"/client/booking/{booking.id}", headers={"X-Client-Session": session.session_token}
+    )
+
+    assert response.status_code == 200
+    assert response.json()["cancellation"]["refund_minor"] == 30000
+    db.expire_all()
+    assert payment.refund_amount == 30000
+    assert refunds_sent == [booking.id]
+
+
+def test_client_cannot_cancel_after_last_tier(client, db, factory, salon, refunds_sent):
+    tenant, master, service, _ = salon
+    booking, payment = paid_booking(factory, tenant, master, service, timedelta(minutes=-1))
+    session = factory.session(booking.client)
+
+    response = client.delete(
+        f"/client/booking/{booking.id}", headers={"X-Client-Session": session.session_token}
+    )
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "CANCELLATION_WINDOW_CLOSED"
+    db.expire_all()
+    assert booking.status == BookingStatus.CONFIRMED
+    assert payment.refund_amount is None
+    assert refunds_sent == []
@@ -0,0 +1,57 @@
+from datetime import datetime, time, timedelta
+
+import pytest
+
+from shared.models import BookingStatus
+
+
+@pytest.fixture
+def booking(factory):
+    tenant, service, master = factory.salon()
+    return factory.booking(tenant, master, service, factory.client(), booking_date=factory.next_day(time(10), days=3))
+
+
+def session_header(factory, person) -> dict:
+    return {"X-Client-Session": factory.session(person).session_token}
+
+
+def test_client_reads_own_booking(client, factory, booking):
+    response = client.get(f"/client/booking/{booking.id}", headers=session_header(factory, booking.client))
+
+    assert response.status_code == 200
+    assert response.json()["id"] == booking.id
+
+
+def test_client_cannot_read_other_client_booking(client, factory, booking):
+    response = client.get(f"/client/booking/{booking.id}", headers=session_header(factory, factory.client()))
+
+    assert response.status_code == 403
+    assert response.json()["error"] == "FORBIDDEN"
+
+
+def test_client_cannot_cancel_other_client_booking(client, db, factory, booking):
+    response = client.delete(f"/client/booking/{booking.id}", headers=session_header(factory, factory.client()))
+
+    assert response.status_code == 403
+    db.expire_all()
+    assert booking.status == BookingStatus.CONFIRMED
+
+
+def test_other_client_bookings_are_not_listed(client, factory, booking):
+    response = client.get("/client/bookings", headers=session_header(factory, factory.client()))
+
+    assert response.status_code == 200
+    assert response.json()["bookings"] == []
+
+
+@pytest.mark.parametrize("session_values", [
+    {"is_verified": False},
+    {"session_expires": datetime.utcnow() - timedelta(minutes=1)}
+])
+def test_unverified_or_expired_session_is_unauthorized(client, factory, booking, session_values):
+    session = factory.session(booking.client, **session_values)
+
+    response = client.get(f"/client/booking/{booking.id}", headers={"X-Client-Session": session.session_token})
+
+    assert response.status_code == 401
+    assert response.json()["error"] == "INVALID_CLIENT_SESSION"
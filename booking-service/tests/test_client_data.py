@@ -0,0 +1,95 @@
+import pytest
+
+from shared.models import AdminAction, ClientSession, NotificationChannel, NotificationLog, NotificationStatus, Review
+
+
+@pytest.fixture
+def two_salons(factory):
+    """A client with a booking, review and notification at each of two tenants."""
+    person = factory.client(email="aida@example.com")
+    rows = []
+    for _ in range(2):
+        tenant, service, master = factory.salon()
+        booking = factory.booking(tenant, master, service, person, client_notes="Allergic to latex")
+        review = factory.add(Review(
+            tenant_id=tenant.id, booking_id=booking.id, client_id=person.id, master_id=master.id,
+            rating=5, comment="Great"
+        ))
+        notification = factory.add(NotificationLog(
+            tenant_id=tenant.id, booking_id=booking.id, client_id=person.id, channel=NotificationChannel.EMAIL,
+            recipient=person.email, template="booking_confirmed", status=NotificationStatus.SENT
+        ))
+        rows.append((tenant, booking, review, notification))
+    return person, rows
+
+
+def test_owner_deletion_scrubs_only_own_tenant(client, db, factory, two_salons):
+    person, [(tenant, booking, review, notification), (_, other_booking, other_review, other_notification)] = two_salons
+    owner = factory.user(tenant)
+    session = factory.session(person)
+
+    response = client.delete("/clients/data", params={
+        "tenant_id": tenant.id, "user_id": owner.id, "email": "aida@example.com"
+    })
+
+    assert response.status_code == 200
+    assert response.json()["clients"] == [
+        {"client_id": person.id, "bookings": 1, "reviews": 1, "notifications": 1}
+    ]
+    db.expire_all()
+    assert booking.client_notes is None
+    assert review.comment is None
+    assert notification.recipient == "deleted"
+    assert other_booking.client_notes == "Allergic to latex"
+    assert other_review.comment == "Great"
+    assert other_notification.recipient == "aida@example.com"
+    assert person.email == "aida@example.com"
+    assert person.anonymized_at is None
+    assert db.query(ClientSession).filter(ClientSession.id == session.id).count() == 1
+
+
+def test_owner_deletion_is_not_logged_as_admin(client, db, factory, two_salons):
+    person, [(tenant, *_), _] = two_salons
+    owner = factory.user(tenant)
+
+    client.delete("/clients/data", params={"tenant_id": tenant.id, "user_id": owner.id, "email": person.email})
+
+    action = db.query(AdminAction).filter(AdminAction.target_type == "client", AdminAction.target_id == person.id).one()
+    assert action.admin_id is None
+    assert action.action_type == "TENANT_CLIENT_DATA_DELETED"
+    assert f"user {owner.id} of tenant {tenant.id}" in action.details
+
+
+def test_owner_deletion_of_unknown_client(client, factory, two_salons):
+    tenant = factory.tenant()
+
+    response = client.delete("/clients/data", params={
+        "tenant_id": tenant.id, "user_id": 1, "email": "aida@example.com"
+    })
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "CLIENT_NOT_FOUND"
+
+
+def test_client_deletion_scrubs_all_tenants(client, db, factory, two_salons):
+    person, rows = two_salons
+    session = factory.session(person)
+
+    response = client.delete("/client/data", headers={"X-Client-Session": session.session_token})
+
+    assert response.status_code == 200
+    assert response.json()["bookings"] == 2
+    assert response.json()["sessions"] == 1
+    db.expire_all()
+    for _, booking, review, notification in rows:
+        assert booking.client_notes is None
+        assert review.comment is None
+        assert notification.recipient == "deleted"
+    assert person.full_name is None
+    assert person.email is None
+    assert person.phone == f"deleted-{person.id}"
+    assert person.anonymized_at is not None
+    assert db.query(ClientSession).filter(ClientSession.client_id == person.id).count() == 0
+    action = db.query(AdminAction).filter(AdminAction.target_type == "client", AdminAction.target_id == person.id).one()
+    assert action.admin_id is None
+    assert action.action_type == "CLIENT_DATA_DELETED"
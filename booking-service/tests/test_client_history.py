@@ -0,0 +1,72 @@
+from datetime import datetime
+
+import pytest
+
+from shared.models import BookingStatus
+
+
+@pytest.fixture
+def salon(factory):
+    tenant, haircut, anna = factory.salon(service={"name": "Haircut"}, master={"full_name": "Anna"})
+    return tenant, anna, haircut
+
+
+def test_repeat_client_history(client, factory, salon):
+    tenant, anna, haircut = salon
+    regular = factory.client(email="regular@example.com")
+    for day, price, booking_status in [
+        (datetime(2026, 3, 1, 10), 100000, BookingStatus.COMPLETED),
+        (datetime(2026, 4, 1, 10), 150000, BookingStatus.COMPLETED),
+        (datetime(2026, 5, 1, 10), 900000, BookingStatus.CANCELLED),
+    ]:
+        factory.booking(tenant, anna, haircut, regular, booking_date=day, price=price, status=booking_status)
+
+    response = client.get("/clients/history", params={"tenant_id": tenant.id, "email": "regular@example.com"})
+
+    assert response.status_code == 200
+    body = response.json()
+    assert body["total_bookings"] == 3
+    assert body["completed_bookings"] == 2
+    assert body["is_repeat_client"] is True
+    assert body["total_spend"] == [{"currency": "KZT", "amount": 2500.0, "amount_minor": 250000}]
+    assert body["favorite_master"] == "Anna"
+    assert body["favorite_service"] == "Haircut"
+    assert body["last_visit"] == "2026-04-01T10:00:00"
+
+
+def test_one_time_client_history(client, factory, salon):
+    tenant, anna, haircut = salon
+    once = factory.client()
+    factory.booking(tenant, anna, haircut, once, status=BookingStatus.CONFIRMED)
+
+    response = client.get("/clients/history", params={"tenant_id": tenant.id, "phone": once.phone})
+
+    body = response.json()
+    assert body["is_repeat_client"] is False
+    assert body["total_spend"] == []
+    assert body["last_visit"] is None
+
+
+def test_client_of_other_tenant_is_not_found(client, factory, salon):
+    tenant, anna, haircut = salon
+    other = factory.client()
+    factory.booking(tenant, anna, haircut, other)
+
+    response = client.get("/clients/history", params={"tenant_id": factory.tenant().id, "phone": other.phone})
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "CLIENT_NOT_FOUND"
+
+
+def test_retention_rate_counts_repeat_clients(client, factory, salon):
+    tenant, anna, haircut = salon
+    regular = factory.client()
+    factory.booking(tenant, anna, haircut, regular, status=BookingStatus.COMPLETED)
+    factory.booking(tenant, anna, haircut, regular)
+    for _ in range(3):
+        factory.booking(tenant, anna, haircut, factory.client())
+
+    response = client.get("/statistics", params={"tenant_id": tenant.id})
+
+    assert response.json()["clients"] == {"total": 4, "repeat": 1}
+    assert response.json()["customer_retention_rate"] == 0.25
@@ -0,0 +1,91 @@
+from datetime import time
+
+import pytest
+
+from shared.models import Booking
+
+
+@pytest.fixture
+def salon(factory):
+    tenant = factory.tenant()
+    haircut = factory.service(tenant, name="Haircut", duration_minutes=60, price=500000)
+    beard = factory.service(tenant, name="Beard trim", duration_minutes=30, price=200000)
+    master = factory.master(tenant, services=[haircut, beard], hours=(time(9), time(11)))
+    return tenant, master, haircut, beard
+
+
+def book(client, tenant, master, service_ids, booking_date, phone="+77011111111"):
+    return client.post("/public/booking", json={
+        "subdomain": tenant.subdomain,
+        "client_phone": phone,
+        "client_name": "Aida",
+        "master_id": master.id,
+        "service_ids": service_ids,
+        "booking_date": booking_date.isoformat()
+    })
+
+
+def slots(client, tenant, master, day, service_ids):
+    response = client.get(
+        f"/public/business/{tenant.subdomain}/availability",
+        params={"master_id": master.id, "date": day.isoformat(), "service_ids": service_ids}
+    )
+    assert response.status_code == 200
+    return response.json()["available_slots"]
+
+
+def test_combo_sums_duration_and_price(client, db, factory, salon):
+    tenant, master, haircut, beard = salon
+
+    response = book(client, tenant, master, [haircut.id, beard.id], factory.next_day(time(9)))
+
+    assert response.status_code == 201
+    booking = db.query(Booking).filter(Booking.id == response.json()["booking_id"]).one()
+    assert booking.duration_minutes == 90
+    assert booking.price == 700000
+    assert [item.service_id for item in booking.items] == [haircut.id, beard.id]
+    assert booking.service_names == "Haircut + Beard trim"
+
+
+def test_availability_fits_combined_duration(client, factory, salon):
+    tenant, master, haircut, beard = salon
+    day = factory.next_day(time(0)).date()
+
+    assert slots(client, tenant, master, day, [haircut.id]) == ["09:00", "09:30", "10:00"]
+    assert slots(client, tenant, master, day, [haircut.id, beard.id]) == ["09:00", "09:30"]
+
+
+def test_combo_past_closing_is_unavailable(client, factory, salon):
+    tenant, master, haircut, beard = salon
+
+    response = book(client, tenant, master, [haircut.id, beard.id], factory.next_day(time(10)))
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "SLOT_UNAVAILABLE"
+
+
+def test_combo_holds_the_whole_duration(client, factory, salon):
+    tenant, master, haircut, beard = salon
+    assert book(client, tenant, master, [haircut.id, beard.id], factory.next_day(time(9))).status_code == 201
+
+    response = book(client, tenant, master, [beard.id], factory.next_day(time(10)), phone="+77012222222")
+
+    assert response.status_code == 409
+
+
+def test_master_must_provide_all_services(client, factory, salon):
+    tenant, master, haircut, _ = salon
+    other = factory.service(tenant, name="Manicure")
+
+    response = book(client, tenant, master, [haircut.id, other.id], factory.next_day(time(9)))
+
+    assert response.status_code == 400
+    assert response.json()["error"] == "MASTER_SERVICE_MISMATCH"
+
+
+def test_repeated_service_is_rejected(client, factory, salon):
+    tenant, master, haircut, _ = salon
+
+    response = book(client, tenant, master, [haircut.id, haircut.id], factory.next_day(time(9)))
+
+    assert response.status_code == 400
@@ -0,0 +1,98 @@
+from types import SimpleNamespace
+
+import pytest
+
+from shared.config import settings
+from shared.events import EventType
+from shared.models import BookingStatus
+
+
+@pytest.fixture
+def sent(service, monkeypatch):
+    """WhatsApp messages and events sent by the booking service."""
+    sent = SimpleNamespace(messages=[], events=[])
+
+    async def send_whatsapp_message(phone, message, template, booking=None):
+        sent.messages.append((phone, template))
+        return True
+
+    monkeypatch.setattr(service, "send_whatsapp_message", send_whatsapp_message)
+    monkeypatch.setattr(service, "publish_event", lambda event_type, data: sent.events.append((event_type, data)))
+    monkeypatch.setattr(settings, "CONFIRMATION_RESEND_LIMIT", 2)
+    return sent
+
+
+@pytest.fixture
+def booking(factory):
+    tenant = factory.tenant()
+    service = factory.service(tenant)
+    customer = factory.client(phone="+77011111111", email="aida@example.com")
+    return factory.booking(tenant, factory.master(tenant, [service]), service, customer, public_code="ABC123")
+
+
+def resend(client, code="ABC123", email="aida@example.com"):
+    return client.post("/public/booking/resend-confirmation", json={"code": code, "email": email})
+
+
+def test_confirmation_is_resent(client, booking, sent):
+    response = resend(client, email="AIDA@example.com")
+
+    assert response.status_code == 200
+    assert response.json() == {"message": "Confirmation resent", "whatsapp_sent": True, "email_queued": True}
+    assert sent.messages == [("+77011111111", "booking_confirmed")]
+    assert sent.events == [(
+        EventType.BOOKING_CONFIRMATION_REQUESTED,
+        {"booking_id": booking.id, "tenant_id": booking.tenant_id, "status": "CONFIRMED"}
+    )]
+
+
+@pytest.mark.parametrize("code, email", [("ABC123", "other@example.com"), ("XYZ999", "aida@example.com")])
+def test_mismatched_code_and_email_is_not_found(client, booking, sent, code, email):
+    response = resend(client, code, email)
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "BOOKING_NOT_FOUND"
+    assert sent.messages == []
+
+
+def test_resends_are_rate_limited(client, booking, sent):
+    assert [resend(client).status_code for _ in range(2)] == [200, 200]
+
+    response = resend(client)
+
+    assert response.status_code == 429
+    assert response.json()["error"] == "RATE_LIMITED"
+    assert 0 < response.json()["details"]["retry_after_seconds"] <= settings.CONFIRMATION_RESEND_WINDOW_MINUTES * 60
+    assert len(sent.messages) == 2
+
+
+def test_cancelled_booking_is_not_resent(client, db, booking, sent):
+    booking.status = BookingStatus.CANCELLED
+    db.flush()
+
+    response = resend(client)
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "INVALID_BOOKING_STATUS"
+
+
+def test_client_resends_own_booking(client, factory, booking, sent):
+    session = factory.session(booking.client)
+
+    response = client.post(
+        f"/client/booking/{booking.id}/resend-confirmation", headers={"X-Client-Session": session.session_token}
+    )
+
+    assert response.status_code == 200
+    assert len(sent.messages) == 1
+
+
+def test_client_cannot_resend_others_booking(client, factory, booking, sent):
+    session = factory.session(factory.client())
+
+    response = client.post(
+        f"/client/booking/{booking.id}/resend-confirmation", headers={"X-Client-Session": session.session_token}
+    )
+
+    assert response.status_code == 403
+    assert sent.messages == []
@@ -0,0 +1,83 @@
+import pytest
+
+from shared.models import NotificationChannel, NotificationLog, NotificationStatus
+
+
+@pytest.fixture
+def customer(factory):
+    """Client with bookings at two businesses, and the businesses."""
+    salon = factory.tenant(business_name="Salon")
+    spa = factory.tenant(business_name="Spa")
+    customer = factory.client(full_name="Aida", email="aida@example.com")
+    bookings = {}
+    for tenant in (salon, spa):
+        service = factory.service(tenant)
+        bookings[tenant.id] = factory.booking(
+            tenant, factory.master(tenant, [service]), service, customer, client_notes="Short, please"
+        )
+    return customer, salon, spa, bookings
+
+
+def export(client, tenant, email):
+    return client.get("/clients/export", params={"tenant_id": tenant.id, "email": email})
+
+
+def test_client_export_of_tenant(client, db, factory, customer):
+    person, salon, spa, bookings = customer
+    factory.add(NotificationLog(
+        tenant_id=salon.id, booking_id=bookings[salon.id].id, client_id=person.id,
+        channel=NotificationChannel.WHATSAPP, recipient=person.phone, template="booking_confirmed",
+        status=NotificationStatus.SENT
+    ))
+    factory.booking(salon, factory.master(salon), factory.service(salon), factory.client())
+
+    response = export(client, salon, "aida@example.com")
+
+    assert response.status_code == 200
+    data = response.json()
+    assert [c["full_name"] for c in data["clients"]] == ["Aida"]
+    booking, = data["bookings"]
+    assert booking["id"] == bookings[salon.id].id
+    assert booking["business_name"] == "Salon"
+    assert booking["client_notes"] == "Short, please"
+    assert booking["price_minor"] == bookings[salon.id].price
+    assert [n["template"] for n in data["notifications"]] == ["booking_confirmed"]
+    # Sessions are shared by all businesses, so not part of a tenant's export
+    assert "sessions" not in data
+
+
+def test_client_without_bookings_at_tenant_is_not_found(client, factory, customer):
+    response = export(client, factory.tenant(), "aida@example.com")
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "CLIENT_NOT_FOUND"
+
+
+def test_client_exports_own_data_across_businesses(client, factory, customer):
+    person, salon, spa, bookings = customer
+    session = factory.session(person)
+
+    response = client.get("/client/export", headers={"X-Client-Session": session.session_token})
+
+    assert response.status_code == 200
+    data = response.json()
+    assert {b["business_name"] for b in data["bookings"]} == {"Salon", "Spa"}
+    exported_session, = data["sessions"]
+    assert exported_session["id"] == session.id
+    # Secrets of the session are never exported
+    assert "session_token" not in exported_session
+    assert session.session_token not in response.text
+
+
+def test_tenant_export(client, factory, customer):
+    person, salon, spa, bookings = customer
+
+    response = client.get("/export", params={"tenant_id": salon.id})
+
+    assert response.status_code == 200
+    data = response.json()
+    assert data["tenant"]["business_name"] == "Salon"
+    assert [c["id"] for c in data["clients"]] == [person.id]
+    assert [b["id"] for b in data["bookings"]] == [bookings[salon.id].id]
+    assert len(data["masters"]) == 1
+    assert data["masters"][0]["schedule"][0]["start_time"] == "09:00"
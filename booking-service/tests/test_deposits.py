@@ -0,0 +1,85 @@
+from datetime import datetime, time, timedelta
+
+import pytest
+
+from shared.models import BookingStatus, Payment, PaymentStatus
+
+
+@pytest.fixture
+def salon(factory):
+    tenant, service, master = factory.salon(service={"require_deposit": True})
+    return tenant, master, service
+
+
+def book(client, tenant, master, service, booking_date, phone="+77011111111"):
+    return client.post("/public/booking", json={
+        "subdomain": tenant.subdomain,
+        "client_phone": phone,
+        "client_name": "Aida",
+        "master_id": master.id,
+        "service_id": service.id,
+        "booking_date": booking_date.isoformat()
+    })
+
+
+def test_deposit_booking_waits_for_payment(client, db, factory, salon):
+    tenant, master, service = salon
+
+    response = book(client, tenant, master, service, factory.next_day(time(10)))
+
+    assert response.status_code == 201
+    body = response.json()
+    assert body["status"] == BookingStatus.PENDING.value
+    # DEPOSIT_PERCENT of the price
+    assert body["deposit"]["amount_minor"] == 150000
+    payment = db.query(Payment).filter(Payment.id == body["deposit"]["payment_id"]).one()
+    assert payment.booking_id == body["booking_id"]
+    assert payment.status == PaymentStatus.PENDING
+
+
+def test_fixed_deposit_amount_of_service(client, db, factory, salon):
+    tenant, master, service = salon
+    service.deposit_amount = 200000
+    db.flush()
+
+    response = book(client, tenant, master, service, factory.next_day(time(10)))
+
+    assert response.json()["deposit"]["amount_minor"] == 200000
+
+
+def test_booking_without_deposit_is_confirmed(client, factory, salon):
+    tenant, master, _ = salon
+    service = factory.service(tenant)
+    master = factory.master(tenant, services=[service])
+
+    response = book(client, tenant, master, service, factory.next_day(time(10)))
+
+    assert response.json()["status"] == BookingStatus.CONFIRMED.value
+    assert "deposit" not in response.json()
+
+
+def test_unpaid_booking_holds_slot_until_due(client, factory, salon):
+    tenant, master, service = salon
+    start = factory.next_day(time(10))
+    factory.booking(
+        tenant, master, service, factory.client(),
+        booking_date=start, status=BookingStatus.PENDING, payment_due_at=datetime.utcnow() + timedelta(minutes=10)
+    )
+
+    response = book(client, tenant, master, service, start)
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "SLOT_UNAVAILABLE"
+
+
+def test_overdue_unpaid_booking_frees_slot(client, factory, salon):
+    tenant, master, service = salon
+    start = factory.next_day(time(10))
+    factory.booking(
+        tenant, master, service, factory.client(),
+        booking_date=start, status=BookingStatus.PENDING, payment_due_at=datetime.utcnow() - timedelta(minutes=1)
+    )
+
+    response = book(client, tenant, master, service, start)
+
+    assert response.status_code == 201
@@ -0,0 +1,94 @@
+from datetime import time
+
+import pytest
+
+from shared.models import BookingStatus, UserRole
+
+
+@pytest.fixture
+def salon(factory):
+    tenant = factory.tenant()
+    service = factory.service(tenant, name="Haircut", description="Wash and cut")
+    user = factory.user(tenant, role=UserRole.MASTER)
+    master = factory.master(tenant, [service], hours=(time(9), time(18)), user_id=user.id)
+    return tenant, service, master, user
+
+
+def test_public_service_detail(client, salon):
+    tenant, service, master, _ = salon
+
+    response = client.get(f"/public/business/{tenant.subdomain}/services/{service.id}")
+
+    assert response.status_code == 200
+    body = response.json()
+    assert body["name"] == "Haircut"
+    assert body["duration_minutes"] == 60
+    assert body["price_minor"] == 500000
+    assert body["masters"] == [{"id": master.id, "full_name": master.full_name}]
+
+
+def test_public_master_detail(client, factory, salon):
+    tenant, service, master, _ = salon
+    factory.booking(tenant, master, service, factory.client(), status=BookingStatus.COMPLETED)
+
+    response = client.get(f"/public/business/{tenant.subdomain}/masters/{master.id}")
+
+    assert response.status_code == 200
+    body = response.json()
+    assert body["services"] == [{"id": service.id, "name": "Haircut"}]
+    assert body["completed_bookings"] == 1
+    assert len(body["schedule"]) == 7
+    assert body["schedule"][0] == {"day_of_week": 0, "start_time": "09:00", "end_time": "18:00"}
+    assert "user" not in body
+
+
+def test_inactive_service_is_not_public(client, factory, salon):
+    tenant = salon[0]
+    inactive = factory.service(tenant, is_active=False)
+
+    response = client.get(f"/public/business/{tenant.subdomain}/services/{inactive.id}")
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "SERVICE_NOT_FOUND"
+
+    assert client.get(f"/services/{inactive.id}", params={"tenant_id": tenant.id}).status_code == 200
+
+
+def test_hidden_master_is_not_public(client, factory, salon):
+    tenant = salon[0]
+    hidden = factory.master(tenant, is_visible=False)
+
+    response = client.get(f"/public/business/{tenant.subdomain}/masters/{hidden.id}")
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "MASTER_NOT_FOUND"
+
+
+def test_staff_master_detail_includes_user(client, salon):
+    tenant, _, master, user = salon
+
+    response = client.get(f"/masters/{master.id}", params={"tenant_id": tenant.id})
+
+    assert response.status_code == 200
+    assert response.json()["user"]["id"] == user.id
+    assert response.json()["user"]["role"] == "MASTER"
+
+
+def test_staff_service_detail_includes_overrides(client, salon):
+    tenant, service, _, _ = salon
+
+    response = client.get(f"/services/{service.id}", params={"tenant_id": tenant.id})
+
+    assert response.status_code == 200
+    assert response.json()["tax_rate_override"] is None
+    assert "cancellation_policy" in response.json()
+
+
+def test_records_of_other_tenant_are_not_found(client, factory, salon):
+    _, service, master, _ = salon
+    other = factory.tenant()
+
+    assert client.get(f"/services/{service.id}", params={"tenant_id": other.id}).status_code == 404
+    assert client.get(f"/masters/{master.id}", params={"tenant_id": other.id}).status_code == 404
+    assert client.get(f"/public/business/{other.subdomain}/services/{service.id}").status_code == 404
+    assert client.get(f"/public/business/{other.subdomain}/masters/{master.id}").status_code == 404
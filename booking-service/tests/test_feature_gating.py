@@ -0,0 +1,90 @@
+import pytest
+
+from shared.config import settings
+from shared.models import BookingStatus
+
+
+@pytest.fixture(autouse=True)
+def defaults(monkeypatch):
+    monkeypatch.setattr(settings, "DEFAULT_TENANT_FEATURES", "reviews,deposits,multi_location")
+
+
+def assert_not_available(response, feature):
+    assert response.status_code == 403
+    assert response.json()["error"] == "FEATURE_NOT_AVAILABLE"
+    assert response.json()["details"] == {"feature": feature}
+
+
+def review(client, factory, tenant):
+    service = factory.service(tenant)
+    booking = factory.booking(
+        tenant, factory.master(tenant, [service]), service, factory.client(), status=BookingStatus.COMPLETED
+    )
+    session = factory.session(booking.client)
+    return client.post(
+        f"/client/booking/{booking.id}/review",
+        json={"rating": 5, "comment": "Great"},
+        headers={"X-Client-Session": session.session_token}
+    )
+
+
+def test_review_without_reviews_feature(client, factory):
+    response = review(client, factory, factory.tenant(features={"reviews": False}))
+
+    assert_not_available(response, "reviews")
+
+
+def test_review_with_reviews_feature(client, factory):
+    response = review(client, factory, factory.tenant())
+
+    assert response.status_code == 201
+
+
+def test_moderation_setting_without_reviews_feature(client, factory):
+    tenant = factory.tenant(features={"reviews": False})
+
+    response = client.put("/reviews/settings", params={"tenant_id": tenant.id}, json={"moderate_reviews": True})
+
+    assert_not_available(response, "reviews")
+    assert not tenant.moderate_reviews
+
+
+def test_only_main_location_without_multi_location(client, factory):
+    tenant = factory.tenant(features={"multi_location": False})
+    factory.location(tenant, name="Branch", is_main=False)
+    factory.location(tenant)
+
+    response = client.get(f"/public/business/{tenant.subdomain}/locations")
+
+    assert response.status_code == 200
+    assert [l["name"] for l in response.json()["locations"]] == ["Main"]
+
+
+def test_all_locations_with_multi_location(client, factory):
+    tenant = factory.tenant()
+    factory.location(tenant, name="Branch", is_main=False)
+    factory.location(tenant)
+
+    response = client.get(f"/public/business/{tenant.subdomain}/locations")
+
+    assert [l["name"] for l in response.json()["locations"]] == ["Main", "Branch"]
+
+
+@pytest.mark.parametrize("features, required", [(None, True), ({"deposits": False}, False)])
+def test_deposit_required_only_with_deposits(client, factory, features, required):
+    tenant = factory.tenant(features=features)
+    service = factory.service(tenant, require_deposit=True, deposit_amount=200000)
+    factory.master(tenant, [service])
+
+    response = client.get(f"/public/business/{tenant.subdomain}/services/{service.id}")
+
+    assert response.status_code == 200
+    assert response.json()["require_deposit"] is required
+
+
+def test_public_info_lists_enabled_features(client, factory):
+    tenant = factory.tenant(features={"deposits": False})
+
+    response = client.get(f"/public/business/{tenant.subdomain}")
+
+    assert response.json()["features"] == ["reviews", "multi_location"]
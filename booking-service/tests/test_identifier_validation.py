@@ -0,0 +1,48 @@
+from datetime import time
+
+import pytest
+
+
+def error_fields(response):
+    assert response.status_code == 422
+    assert response.json()["error"] == "VALIDATION_ERROR"
+    return [detail["field"] for detail in response.json()["details"]]
+
+
+@pytest.mark.parametrize("field", ["master_id", "service_id"])
+def test_malformed_body_identifier_is_reported_by_field(client, factory, field):
+    tenant = factory.tenant()
+
+    response = client.post("/public/booking", json={
+        "subdomain": tenant.subdomain,
+        "client_phone": "+77011111111",
+        "client_name": "Aida",
+        "booking_date": factory.next_day(time(10)).isoformat(),
+        field: "not-an-id"
+    })
+
+    assert error_fields(response) == [f"body.{field}"]
+
+
+def test_malformed_path_identifier_is_reported_by_field(client):
+    response = client.get("/booking/not-an-id", params={"user_id": 1, "role": "OWNER", "tenant_id": 1})
+
+    assert error_fields(response) == ["path.booking_id"]
+
+
+def test_malformed_query_identifier_is_reported_by_field(client):
+    response = client.get("/booking/1", params={"user_id": 1, "role": "OWNER", "tenant_id": "not-an-id"})
+
+    assert error_fields(response) == ["query.tenant_id"]
+
+
+def test_malformed_cursor_is_rejected(client, factory):
+    tenant = factory.tenant()
+    owner = factory.user(tenant)
+
+    response = client.get("/bookings", params={
+        "user_id": owner.id, "role": owner.role.value, "tenant_id": tenant.id, "limit": 10, "cursor": "not-a-cursor"
+    })
+
+    assert response.status_code == 400
+    assert response.json()["error"] == "INVALID_CURSOR"
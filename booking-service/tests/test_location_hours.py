@@ -0,0 +1,98 @@
+from datetime import time
+
+import pytest
+
+from shared.models import LocationHours
+
+
+@pytest.fixture
+def salon(factory):
+    tenant = factory.tenant()
+    location = factory.location(tenant)
+    service = factory.service(tenant, duration_minutes=60, slot_interval_minutes=60)
+    master = factory.master(tenant, [service], hours=(time(9), time(13)), location_id=location.id)
+    return tenant, location, service, master
+
+
+@pytest.fixture
+def tomorrow(factory):
+    return factory.next_day(time(0)).date()
+
+
+def open_hours(factory, location, day, start, end, is_open=True):
+    factory.add(LocationHours(
+        location_id=location.id, day_of_week=day.weekday(), start_time=start, end_time=end, is_open=is_open
+    ))
+
+
+def slots(client, tenant, service, master, day):
+    response = client.get(
+        f"/public/business/{tenant.subdomain}/availability",
+        params={"master_id": master.id, "date": day.isoformat(), "service_ids": [service.id]}
+    )
+    assert response.status_code == 200
+    return response.json()["available_slots"]
+
+
+def book(client, tenant, service, master, start):
+    return client.post("/public/booking", json={
+        "subdomain": tenant.subdomain,
+        "client_phone": "+77011111111",
+        "client_name": "Aida",
+        "master_id": master.id,
+        "service_id": service.id,
+        "booking_date": start.isoformat()
+    })
+
+
+def test_location_without_hours_keeps_master_hours(client, salon, tomorrow):
+    tenant, _, service, master = salon
+
+    assert slots(client, tenant, service, master, tomorrow) == ["09:00", "10:00", "11:00", "12:00"]
+
+
+def test_hours_are_intersected(client, factory, salon, tomorrow):
+    tenant, location, service, master = salon
+    open_hours(factory, location, tomorrow, time(11), time(18))
+
+    assert slots(client, tenant, service, master, tomorrow) == ["11:00", "12:00"]
+
+
+def test_master_free_but_location_closed(client, factory, salon, tomorrow):
+    tenant, location, service, master = salon
+    open_hours(factory, location, tomorrow, time(9), time(18), is_open=False)
+
+    assert slots(client, tenant, service, master, tomorrow) == []
+
+    response = book(client, tenant, service, master, factory.next_day(time(10)))
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "SLOT_UNAVAILABLE"
+
+
+def test_days_without_opening_hours_are_closed(client, factory, salon, tomorrow):
+    tenant, location, service, master = salon
+    open_hours(factory, location, factory.next_day(time(0), 2).date(), time(9), time(18))
+
+    assert slots(client, tenant, service, master, tomorrow) == []
+
+
+def test_set_location_hours(client, factory, salon, tomorrow):
+    tenant, location, service, master = salon
+
+    response = client.put(f"/locations/{location.id}/hours", params={"tenant_id": tenant.id}, json={
+        "weekly": [{"day_of_week": tomorrow.weekday(), "start_time": "12:00", "end_time": "18:00"}]
+    })
+
+    assert response.status_code == 200
+    assert response.json()["hours"] == [
+        {"day_of_week": tomorrow.weekday(), "start_time": "12:00", "end_time": "18:00", "is_open": True}
+    ]
+    assert slots(client, tenant, service, master, tomorrow) == ["12:00"]
+
+
+def test_other_tenants_location_is_not_found(client, factory, salon):
+    response = client.get(f"/locations/{salon[1].id}/hours", params={"tenant_id": factory.tenant().id})
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "LOCATION_NOT_FOUND"
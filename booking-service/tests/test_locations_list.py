@@ -0,0 +1,54 @@
+from sqlalchemy.exc import OperationalError
+
+
+def locations(client, subdomain):
+    return client.get(f"/public/business/{subdomain}/locations")
+
+
+def test_main_location_first(client, factory):
+    tenant = factory.tenant()
+    branch = factory.location(tenant, name="Branch", is_main=False)
+    main = factory.location(tenant, name="Main", is_main=True)
+
+    response = locations(client, tenant.subdomain)
+
+    assert response.status_code == 200
+    assert [l["id"] for l in response.json()["locations"]] == [main.id, branch.id]
+    assert response.json()["locations"][0]["is_main"] is True
+
+
+def test_business_without_locations_gets_empty_list(client, factory):
+    response = locations(client, factory.tenant().subdomain)
+
+    assert response.status_code == 200
+    assert response.json() == {"locations": []}
+
+
+def test_unknown_business_is_not_found(client):
+    response = locations(client, "no-such-business")
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "BUSINESS_NOT_FOUND"
+
+
+def test_database_failure_is_internal_error(client, service, factory, monkeypatch):
+    tenant = factory.tenant()
+
+    def get_public_tenant(db, subdomain):
+        raise OperationalError("SELECT 1", {}, Exception("connection refused"))
+
+    monkeypatch.setattr(service, "get_public_tenant", get_public_tenant)
+
+    response = locations(client, tenant.subdomain)
+
+    assert response.status_code == 500
+    assert response.json()["error"] == "INTERNAL_ERROR"
+
+
+def test_empty_master_list_is_ok(client, factory):
+    tenant = factory.tenant()
+
+    response = client.get(f"/public/business/{tenant.subdomain}/masters")
+
+    assert response.status_code == 200
+    assert response.json() == {"masters": []}
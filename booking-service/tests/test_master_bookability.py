@@ -0,0 +1,69 @@
+from datetime import time
+
+import pytest
+
+from shared.models import Booking
+
+
+@pytest.fixture
+def salon(factory):
+    tenant = factory.tenant()
+    service = factory.service(tenant)
+    return tenant, service
+
+
+def book(client, factory, tenant, service, master=None):
+    return client.post("/public/booking", json={
+        "subdomain": tenant.subdomain,
+        "client_phone": "+77011111111",
+        "client_name": "Aida",
+        "master_id": master.id if master else None,
+        "service_id": service.id,
+        "booking_date": factory.next_day(time(10)).isoformat()
+    })
+
+
+def test_paused_master_is_not_bookable(client, db, factory, salon):
+    tenant, service = salon
+    master = factory.master(tenant, [service], is_accepting_bookings=False)
+
+    response = book(client, factory, tenant, service, master)
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "MASTER_NOT_ACCEPTING_BOOKINGS"
+    assert db.query(Booking).filter(Booking.master_id == master.id).count() == 0
+
+
+def test_hidden_master_is_not_found(client, factory, salon):
+    tenant, service = salon
+    master = factory.master(tenant, [service], is_visible=False)
+
+    response = book(client, factory, tenant, service, master)
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "MASTER_NOT_FOUND"
+
+
+def test_paused_and_hidden_masters_are_not_assigned(client, factory, salon):
+    tenant, service = salon
+    factory.master(tenant, [service], is_accepting_bookings=False)
+    factory.master(tenant, [service], is_visible=False)
+
+    response = book(client, factory, tenant, service)
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "NO_MASTER_AVAILABLE"
+
+
+def test_paused_master_has_no_slots(client, factory, salon):
+    tenant, service = salon
+    master = factory.master(tenant, [service], is_accepting_bookings=False)
+
+    response = client.get(
+        f"/public/business/{tenant.subdomain}/availability",
+        params={"master_id": master.id, "date": factory.next_day(time(0)).date().isoformat()}
+    )
+
+    assert response.status_code == 200
+    assert response.json()["accepting_bookings"] is False
+    assert response.json()["available_slots"] == []
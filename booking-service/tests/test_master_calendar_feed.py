@@ -0,0 +1,108 @@
+from datetime import datetime, time, timedelta
+
+import pytest
+
+from shared.models import BookingStatus, UserRole
+
+
+@pytest.fixture
+def salon(factory):
+    tenant = factory.tenant()
+    service = factory.service(tenant, name="Haircut")
+    user = factory.user(tenant, role=UserRole.MASTER)
+    master = factory.master(tenant, [service], user_id=user.id)
+    return tenant, service, master, user
+
+
+def feed_token(client, user) -> str:
+    response = client.post("/my/calendar-token", params={"user_id": user.id, "tenant_id": user.tenant_id})
+    assert response.status_code == 200
+    return response.json()["feed_url"].rsplit("/", 1)[1][:-len(".ics")]
+
+
+def events(calendar: str) -> list:
+    """VEVENT blocks of the calendar as property dicts, folded lines joined."""
+    lines = calendar.replace("\r\n ", "").split("\r\n")
+    blocks, current = [], None
+    for line in lines:
+        if line == "BEGIN:VEVENT":
+            current = {}
+        elif line == "END:VEVENT":
+            blocks.append(current)
+            current = None
+        elif current is not None:
+            name, value = line.split(":", 1)
+            current[name] = value
+    return blocks
+
+
+def test_feed_lists_master_bookings(client, factory, salon):
+    tenant, service, master, user = salon
+    customer = factory.client(full_name="Aida", phone="+77011111111")
+    booking = factory.booking(tenant, master, service, customer)
+    cancelled = factory.booking(
+        tenant, master, service, factory.client(), booking_date=factory.next_day(time(12)),
+        status=BookingStatus.CANCELLED
+    )
+    factory.booking(tenant, factory.master(tenant, [service]), service, factory.client())
+    factory.booking(tenant, master, service, factory.client(), status=BookingStatus.PENDING)
+    factory.booking(tenant, master, service, factory.client(), booking_date=factory.next_day(time(10), -30))
+
+    response = client.get(f"/public/calendar/{feed_token(client, user)}.ics")
+
+    assert response.status_code == 200
+    assert response.headers["content-type"].startswith("text/calendar")
+    assert f"X-WR-CALNAME:{master.full_name}" in response.text
+    by_uid = {event["UID"].split("@")[0]: event for event in events(response.text)}
+    assert set(by_uid) == {f"booking-{booking.id}", f"booking-{cancelled.id}"}
+    assert by_uid[f"booking-{booking.id}"]["SUMMARY"] == "Haircut — Aida"
+    assert "+77011111111" in by_uid[f"booking-{booking.id}"]["DESCRIPTION"]
+    assert by_uid[f"booking-{cancelled.id}"]["STATUS"] == "CANCELLED"
+
+
+def test_changed_booking_has_higher_sequence(client, db, factory, salon):
+    tenant, service, master, user = salon
+    created = datetime.utcnow() - timedelta(hours=1)
+    booking = factory.booking(tenant, master, service, factory.client(), created_at=created, updated_at=created)
+    token = feed_token(client, user)
+
+    assert events(client.get(f"/public/calendar/{token}.ics").text)[0]["SEQUENCE"] == "0"
+
+    booking.updated_at = created + timedelta(minutes=5)
+    db.flush()
+
+    assert events(client.get(f"/public/calendar/{token}.ics").text)[0]["SEQUENCE"] == "300"
+
+
+def test_rotated_token_replaces_previous(client, salon):
+    user = salon[3]
+    old = feed_token(client, user)
+    new = feed_token(client, user)
+
+    assert new != old
+    assert client.get(f"/public/calendar/{old}.ics").status_code == 404
+    assert client.get(f"/public/calendar/{new}.ics").status_code == 200
+
+
+def test_unknown_token_is_not_found(client):
+    response = client.get("/public/calendar/not-a-token.ics")
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "MASTER_NOT_FOUND"
+
+
+def test_feed_of_deactivated_master_is_not_found(client, db, salon):
+    _, _, master, user = salon
+    token = feed_token(client, user)
+    master.is_active = False
+    db.flush()
+
+    assert client.get(f"/public/calendar/{token}.ics").status_code == 404
+
+
+def test_user_without_master_gets_no_token(client, factory, salon):
+    owner = factory.user(salon[0])
+
+    response = client.post("/my/calendar-token", params={"user_id": owner.id, "tenant_id": owner.tenant_id})
+
+    assert response.status_code == 404
@@ -0,0 +1,119 @@
+from datetime import time
+
+import pytest
+
+
+@pytest.fixture
+def salon(factory):
+    return factory.salon(
+        service={"duration_minutes": 60, "slot_interval_minutes": 60}, master={"hours": (time(9), time(12))}
+    )
+
+
+def day(factory, days=1):
+    return factory.next_day(time(0), days).date()
+
+
+def slots(client, tenant, service, master, on):
+    response = client.get(
+        f"/public/business/{tenant.subdomain}/availability",
+        params={"master_id": master.id, "date": on.isoformat(), "service_ids": [service.id]}
+    )
+    assert response.status_code == 200
+    return response.json()["available_slots"]
+
+
+def set_override(client, master, on, **data):
+    return client.put(
+        f"/masters/{master.id}/schedule/overrides/{on.isoformat()}", params={"tenant_id": master.tenant_id}, json=data
+    )
+
+
+def test_weekly_hours_replace_schedule(client, factory, salon):
+    tenant, service, master = salon
+    tomorrow = day(factory)
+
+    response = client.put(f"/masters/{master.id}/schedule", params={"tenant_id": tenant.id}, json={
+        "weekly": [{"day_of_week": tomorrow.weekday(), "start_time": "10:00", "end_time": "12:00"}]
+    })
+
+    assert response.status_code == 200
+    assert response.json()["weekly"] == [
+        {"day_of_week": tomorrow.weekday(), "start_time": "10:00", "end_time": "12:00", "is_working": True}
+    ]
+    assert slots(client, tenant, service, master, tomorrow) == ["10:00", "11:00"]
+    # Days left out are days off
+    assert slots(client, tenant, service, master, day(factory, 2)) == []
+
+
+def test_day_off_override(client, factory, salon):
+    tenant, service, master = salon
+    tomorrow = day(factory)
+    assert slots(client, tenant, service, master, tomorrow) == ["09:00", "10:00", "11:00"]
+
+    response = set_override(client, master, tomorrow, is_working=False, reason="Vacation")
+
+    assert response.status_code == 200
+    assert response.json()["overrides"] == [
+        {"date": tomorrow.isoformat(), "is_working": False, "start_time": None, "end_time": None, "reason": "Vacation"}
+    ]
+    assert slots(client, tenant, service, master, tomorrow) == []
+    # Other days keep the weekly hours
+    assert slots(client, tenant, service, master, day(factory, 2)) == ["09:00", "10:00", "11:00"]
+
+
+def test_changed_hours_override(client, factory, salon):
+    tenant, service, master = salon
+    tomorrow = day(factory)
+
+    set_override(client, master, tomorrow, is_working=True, start_time="14:00", end_time="16:00")
+
+    assert slots(client, tenant, service, master, tomorrow) == ["14:00", "15:00"]
+
+
+def test_removed_override_restores_weekly_hours(client, factory, salon):
+    tenant, service, master = salon
+    tomorrow = day(factory)
+    set_override(client, master, tomorrow, is_working=False)
+
+    response = client.delete(
+        f"/masters/{master.id}/schedule/overrides/{tomorrow.isoformat()}", params={"tenant_id": tenant.id}
+    )
+
+    assert response.json()["overrides"] == []
+    assert slots(client, tenant, service, master, tomorrow) == ["09:00", "10:00", "11:00"]
+
+
+def test_schedule_lists_upcoming_overrides(client, factory, salon):
+    tenant, _, master = salon
+    set_override(client, master, day(factory, 3), is_working=False)
+    set_override(client, master, day(factory, -1), is_working=False)
+
+    response = client.get(f"/masters/{master.id}/schedule", params={"tenant_id": tenant.id})
+
+    assert [o["date"] for o in response.json()["overrides"]] == [day(factory, 3).isoformat()]
+    assert len(response.json()["weekly"]) == 7
+
+
+@pytest.mark.parametrize("data", [
+    {"is_working": True},
+    {"is_working": True, "start_time": "16:00", "end_time": "14:00"}
+])
+def test_invalid_override_is_rejected(client, factory, salon, data):
+    assert set_override(client, salon[2], day(factory), **data).status_code == 422
+
+
+def test_duplicate_days_are_rejected(client, salon):
+    tenant, _, master = salon
+    hours = {"day_of_week": 0, "start_time": "09:00", "end_time": "12:00"}
+
+    response = client.put(f"/masters/{master.id}/schedule", params={"tenant_id": tenant.id}, json={"weekly": [hours, hours]})
+
+    assert response.status_code == 422
+
+
+def test_other_tenants_master_is_not_found(client, factory, salon):
+    response = client.get(f"/masters/{salon[2].id}/schedule", params={"tenant_id": factory.tenant().id})
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "MASTER_NOT_FOUND"
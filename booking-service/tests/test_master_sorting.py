@@ -0,0 +1,79 @@
+from datetime import time
+
+import pytest
+
+from shared.models import BookingStatus, MasterScheduleOverride, Review
+
+
+@pytest.fixture
+def salon(factory):
+    tenant = factory.tenant()
+    service = factory.service(tenant, duration_minutes=60)
+    return tenant, service
+
+
+def review(factory, tenant, service, master, rating):
+    booking = factory.booking(tenant, master, service, factory.client(), status=BookingStatus.COMPLETED)
+    factory.add(Review(
+        tenant_id=tenant.id, booking_id=booking.id, client_id=booking.client_id, master_id=master.id, rating=rating
+    ))
+
+
+def masters(client, tenant, **params):
+    response = client.get(f"/public/business/{tenant.subdomain}/masters", params=params)
+    assert response.status_code == 200
+    return response.json()["masters"]
+
+
+def test_rating_sort(client, factory, salon):
+    tenant, service = salon
+    unrated, good, best, popular = (factory.master(tenant, [service]) for _ in range(4))
+    review(factory, tenant, service, good, 4)
+    review(factory, tenant, service, best, 5)
+    for rating in (4, 4):
+        review(factory, tenant, service, popular, rating)
+
+    result = masters(client, tenant, sort="rating")
+
+    # More reviews break ties, masters without reviews go last
+    assert [m["id"] for m in result] == [best.id, popular.id, good.id, unrated.id]
+    assert [m["rating"] for m in result] == [5.0, 4.0, 4.0, None]
+
+
+def test_experience_sort(client, factory, salon):
+    tenant, service = salon
+    novice, expert = factory.master(tenant, [service]), factory.master(tenant, [service])
+    for _ in range(2):
+        factory.booking(tenant, expert, service, factory.client(), status=BookingStatus.COMPLETED)
+
+    result = masters(client, tenant, sort="experience")
+
+    assert [(m["id"], m["completed_bookings"]) for m in result] == [(expert.id, 2), (novice.id, 0)]
+
+
+def test_soonest_sort(client, factory, salon):
+    tenant, service = salon
+    unavailable = factory.master(tenant, [service], hours=None)
+    later = factory.master(tenant, [service], hours=None)
+    later_day = factory.next_day(time(0), 3).date()
+    factory.add(MasterScheduleOverride(
+        master_id=later.id, date=later_day, is_working=True, start_time=time(9), end_time=time(18)
+    ))
+    sooner = factory.master(tenant, [service], hours=(time(0), time(23, 59)))
+
+    result = masters(client, tenant, sort="soonest", service_id=service.id)
+
+    assert [m["id"] for m in result] == [sooner.id, later.id, unavailable.id]
+    assert result[1]["next_available_at"] == f"{later_day.isoformat()}T09:00:00"
+    assert result[2]["next_available_at"] is None
+
+
+def test_unsorted_by_id(client, factory, salon):
+    tenant, service = salon
+    first, second = factory.master(tenant, [service]), factory.master(tenant, [service])
+    review(factory, tenant, service, second, 5)
+
+    result = masters(client, tenant)
+
+    assert [m["id"] for m in result] == [first.id, second.id]
+    assert "next_available_at" not in result[0]
@@ -0,0 +1,79 @@
+import pytest
+
+from shared.config import settings
+from shared.i18n import render_template
+
+SUBJECT = "booking_confirmation_subject"
+
+
+def put_template(client, tenant, body, language="en", name=SUBJECT):
+    return client.put(f"/templates/{name}", params={"tenant_id": tenant.id}, json={"language": language, "body": body})
+
+
+@pytest.fixture(autouse=True)
+def languages(monkeypatch):
+    monkeypatch.setattr(settings, "SUPPORTED_LANGUAGES", "ru,en,kk")
+
+
+def test_override_is_used_until_reset(client, db, factory):
+    tenant = factory.tenant()
+
+    response = put_template(client, tenant, "See you at {business_name}!")
+
+    assert response.status_code == 200
+    assert render_template(db, tenant.id, SUBJECT, "en", business_name="Salon") == "See you at Salon!"
+    assert render_template(db, tenant.id, SUBJECT, "ru", business_name="Salon") == "Запись в Salon подтверждена"
+
+    response = client.delete(f"/templates/{SUBJECT}", params={"tenant_id": tenant.id, "language": "en"})
+
+    assert response.status_code == 200
+    db.expire_all()
+    assert render_template(db, tenant.id, SUBJECT, "en", business_name="Salon") == (
+        "Your booking at Salon is confirmed"
+    )
+
+
+def test_override_of_other_tenant_is_not_used(client, db, factory):
+    tenant, other = factory.tenant(), factory.tenant()
+
+    put_template(client, other, "{business_name}")
+
+    assert render_template(db, tenant.id, SUBJECT, "en", business_name="Salon") == (
+        "Your booking at Salon is confirmed"
+    )
+
+
+def test_list_shows_defaults_and_overrides(client, factory):
+    tenant = factory.tenant()
+    put_template(client, tenant, "{business_name}")
+
+    templates = client.get("/templates", params={"tenant_id": tenant.id}).json()["templates"]
+
+    by_key = {(t["name"], t["language"]): t for t in templates}
+    assert by_key[(SUBJECT, "en")]["override"] == "{business_name}"
+    assert by_key[(SUBJECT, "ru")]["override"] is None
+    assert by_key[(SUBJECT, "ru")]["default"] == "Запись в {business_name} подтверждена"
+
+
+@pytest.mark.parametrize("body, problem", [
+    ("Booking confirmed", "missing placeholder: {business_name}"),
+    ("{business_name} {client_phone}", "unknown placeholder: {client_phone}")
+])
+def test_placeholders_must_be_preserved(client, factory, body, problem):
+    tenant = factory.tenant()
+
+    response = put_template(client, tenant, body)
+
+    assert response.status_code == 400
+    assert response.json()["error"] == "INVALID_TEMPLATE"
+    assert problem in response.json()["details"]["problems"]
+
+
+@pytest.mark.parametrize("name, language", [("trial_expiration_body", "en"), (SUBJECT, "de")])
+def test_only_customizable_templates_can_be_set(client, factory, name, language):
+    tenant = factory.tenant()
+
+    response = put_template(client, tenant, "x", language, name)
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "TEMPLATE_NOT_FOUND"
@@ -0,0 +1,68 @@
+import importlib
+from datetime import time
+
+import pytest
+
+
+@pytest.fixture
+def now(service, factory, monkeypatch):
+    """Tenant local time frozen at 08:00 tomorrow."""
+    frozen = factory.next_day(time(8))
+    booking_service = importlib.import_module("services.booking_service")
+    monkeypatch.setattr(booking_service, "tenant_local_now", lambda tenant: frozen)
+    return frozen
+
+
+@pytest.fixture
+def salon(factory):
+    return factory.salon(service={"duration_minutes": 60, "min_lead_minutes": 120})
+
+
+def book(client, tenant, service, master, start):
+    return client.post("/public/booking", json={
+        "subdomain": tenant.subdomain,
+        "client_phone": "+77011111111",
+        "client_name": "Aida",
+        "master_id": master.id,
+        "service_id": service.id,
+        "booking_date": start.isoformat()
+    })
+
+
+def test_slot_just_outside_lead_window_is_bookable(client, now, salon):
+    tenant, service, master = salon
+
+    response = book(client, tenant, service, master, now.replace(hour=10))
+
+    assert response.status_code == 201
+
+
+def test_slot_inside_lead_window_is_rejected(client, now, salon):
+    tenant, service, master = salon
+
+    response = book(client, tenant, service, master, now.replace(hour=9, minute=30))
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "BOOKING_TOO_SOON"
+    assert response.json()["details"] == {"min_lead_minutes": 120}
+
+
+def test_slots_inside_lead_window_are_hidden(client, now, salon):
+    tenant, service, master = salon
+
+    response = client.get(
+        f"/public/business/{tenant.subdomain}/availability",
+        params={"master_id": master.id, "date": now.date().isoformat(), "service_ids": [service.id]}
+    )
+
+    assert response.status_code == 200
+    assert response.json()["available_slots"][0] == "10:00"
+
+
+def test_tenant_lead_time_applies_without_service_setting(client, factory, now):
+    tenant = factory.tenant(min_lead_minutes=90)
+    service = factory.service(tenant, duration_minutes=60)
+    master = factory.master(tenant, [service])
+
+    assert book(client, tenant, service, master, now.replace(hour=9)).status_code == 409
+    assert book(client, tenant, service, master, now.replace(hour=9, minute=30)).status_code == 201
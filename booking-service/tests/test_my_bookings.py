@@ -0,0 +1,73 @@
+from datetime import time
+
+import pytest
+
+from shared.models import BookingStatus, UserRole
+
+
+@pytest.fixture
+def salon(factory):
+    tenant = factory.tenant()
+    service = factory.service(tenant)
+    user = factory.user(tenant, role=UserRole.MASTER)
+    master = factory.master(tenant, [service], user_id=user.id)
+    colleague = factory.master(tenant, [service])
+    return tenant, service, user, master, colleague
+
+
+def my_bookings(client, user, **params):
+    return client.get("/my/bookings", params={"user_id": user.id, "tenant_id": user.tenant_id, **params})
+
+
+def test_master_sees_own_bookings_by_day(client, factory, salon):
+    tenant, service, user, master, colleague = salon
+    today = factory.booking(tenant, master, service, factory.client(), booking_date=factory.next_day(time(18), 0))
+    tomorrow = factory.booking(tenant, master, service, factory.client())
+    factory.booking(tenant, colleague, service, factory.client())
+    factory.booking(tenant, master, service, factory.client(), status=BookingStatus.CANCELLED)
+
+    response = my_bookings(client, user)
+
+    assert response.status_code == 200
+    body = response.json()
+    assert body["master_id"] == master.id
+    assert [day["date"] for day in body["days"]] == [
+        factory.next_day(time(0), n).date().isoformat() for n in range(3)
+    ]
+    assert [b["id"] for b in body["days"][0]["bookings"]] == [today.id]
+    assert [b["id"] for b in body["days"][1]["bookings"]] == [tomorrow.id]
+    assert body["days"][2]["bookings"] == []
+
+
+def test_bookings_outside_window_are_left_out(client, factory, salon):
+    tenant, service, user, master, _ = salon
+    factory.booking(tenant, master, service, factory.client(), booking_date=factory.next_day(time(10), -1))
+    later = factory.booking(tenant, master, service, factory.client(), booking_date=factory.next_day(time(10), 3))
+
+    assert all(day["bookings"] == [] for day in my_bookings(client, user).json()["days"])
+
+    days = my_bookings(client, user, days=4).json()["days"]
+    assert len(days) == 4
+    assert [b["id"] for b in days[3]["bookings"]] == [later.id]
+
+
+def test_user_without_master_is_not_found(client, factory, salon):
+    owner = factory.user(salon[0])
+
+    response = my_bookings(client, owner)
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "MASTER_NOT_FOUND"
+
+
+def test_master_of_other_tenant_is_not_found(client, factory, salon):
+    user = salon[2]
+
+    response = client.get("/my/bookings", params={"user_id": user.id, "tenant_id": factory.tenant().id})
+
+    assert response.status_code == 404
+
+
+@pytest.mark.parametrize("days", [0, 15])
+def test_window_is_limited(client, salon, days):
+    assert my_bookings(client, salon[2], days=days).status_code == 422
@@ -0,0 +1,59 @@
+from datetime import datetime, timedelta
+
+import pytest
+
+from shared.models import BookingStatus, UserRole
+
+
+@pytest.fixture
+def salon(factory):
+    tenant, service, master = factory.salon()
+    return tenant, master, service, factory.user(tenant, role=UserRole.MANAGER)
+
+
+def test_no_show_is_counted_once(client, db, factory, salon, staff_params):
+    tenant, master, service, manager = salon
+    visitor = factory.client()
+    booking = factory.booking(
+        tenant, master, service, visitor, booking_date=datetime.utcnow() - timedelta(minutes=30)
+    )
+
+    first = client.put(f"/booking/{booking.id}/no-show", json=staff_params(manager))
+    second = client.put(f"/booking/{booking.id}/no-show", json=staff_params(manager))
+
+    assert first.status_code == 200
+    assert second.status_code == 200
+    db.expire_all()
+    assert booking.status == BookingStatus.NO_SHOW
+    assert visitor.no_show_count == 1
+
+
+def test_future_booking_is_not_a_no_show(client, db, factory, salon, staff_params):
+    tenant, master, service, manager = salon
+    visitor = factory.client()
+    booking = factory.booking(tenant, master, service, visitor)
+
+    response = client.put(f"/booking/{booking.id}/no-show", json=staff_params(manager))
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "BOOKING_NOT_STARTED"
+    db.expire_all()
+    assert visitor.no_show_count == 0
+
+
+@pytest.mark.parametrize("booking_status", [BookingStatus.PENDING, BookingStatus.COMPLETED, BookingStatus.CANCELLED])
+def test_only_confirmed_booking_is_a_no_show(client, db, factory, salon, staff_params, booking_status):
+    tenant, master, service, manager = salon
+    visitor = factory.client()
+    booking = factory.booking(
+        tenant, master, service, visitor,
+        booking_date=datetime.utcnow() - timedelta(minutes=30), status=booking_status
+    )
+
+    response = client.put(f"/booking/{booking.id}/no-show", json=staff_params(manager))
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "INVALID_BOOKING_STATUS"
+    db.expire_all()
+    assert booking.status == booking_status
+    assert visitor.no_show_count == 0
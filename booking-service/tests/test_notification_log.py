@@ -0,0 +1,96 @@
+import asyncio
+
+import httpx
+import pytest
+
+from shared.config import settings
+from shared.models import NotificationChannel, NotificationLog, NotificationStatus
+from shared.notifications import record_notification
+
+
+@pytest.fixture
+def whatsapp(monkeypatch):
+    """WhatsApp service answering with the given status code."""
+    state = {"status_code": 200}
+    real_client = httpx.AsyncClient
+
+    def handler(request: httpx.Request) -> httpx.Response:
+        if state["status_code"] != 200:
+            return httpx.Response(state["status_code"], text="session closed")
+        return httpx.Response(200, json={"message_id": "wa-1"})
+
+    monkeypatch.setattr(settings, "WHATSAPP_ENABLED", True)
+    monkeypatch.setattr(
+        httpx, "AsyncClient", lambda **kwargs: real_client(transport=httpx.MockTransport(handler), **kwargs)
+    )
+    return state
+
+
+def booked(factory):
+    tenant, service, master = factory.salon()
+    return factory.booking(tenant, master, service, factory.client())
+
+
+def test_sent_message_is_logged(service, db, factory, whatsapp):
+    booking = booked(factory)
+
+    assert asyncio.run(service.send_whatsapp_message("+77010000001", "Hi", "booking_confirmed", booking)) is True
+
+    log = db.query(NotificationLog).one()
+    assert log.channel == NotificationChannel.WHATSAPP
+    assert log.status == NotificationStatus.SENT
+    assert log.template == "booking_confirmed"
+    assert log.recipient == "+77010000001"
+    assert log.provider_message_id == "wa-1"
+    assert (log.tenant_id, log.booking_id, log.client_id) == (booking.tenant_id, booking.id, booking.client_id)
+
+
+def test_failed_message_is_logged(service, db, factory, whatsapp):
+    whatsapp["status_code"] = 500
+    booking = booked(factory)
+
+    assert asyncio.run(service.send_whatsapp_message("+77010000001", "Hi", "booking_confirmed", booking)) is False
+
+    log = db.query(NotificationLog).one()
+    assert log.status == NotificationStatus.FAILED
+    assert log.error == "session closed"
+
+
+def test_booking_fills_tenant_and_client(db, factory):
+    booking = booked(factory)
+
+    record_notification(
+        NotificationChannel.EMAIL, "client@example.com", "booking_confirmation", NotificationStatus.SENT,
+        booking_id=booking.id
+    )
+
+    log = db.query(NotificationLog).one()
+    assert (log.tenant_id, log.client_id) == (booking.tenant_id, booking.client_id)
+
+
+def test_history_of_booking_and_client(client, db, factory):
+    booking = booked(factory)
+    other = booked(factory)
+    for target in (booking, booking, other):
+        record_notification(
+            NotificationChannel.WHATSAPP, "+77010000001", "booking_reminder", NotificationStatus.SENT,
+            booking_id=target.id
+        )
+
+    by_booking = client.get("/notifications/history", params={"tenant_id": booking.tenant_id, "booking_id": booking.id})
+    by_client = client.get(
+        "/notifications/history", params={"tenant_id": booking.tenant_id, "client_id": booking.client_id}
+    )
+    other_tenant = client.get("/notifications/history", params={"tenant_id": other.tenant_id, "booking_id": booking.id})
+
+    assert by_booking.status_code == 200
+    assert len(by_booking.json()["notifications"]) == 2
+    assert by_booking.json()["notifications"][0]["template"] == "booking_reminder"
+    assert len(by_client.json()["notifications"]) == 2
+    assert other_tenant.json()["notifications"] == []
+
+
+def test_history_needs_booking_or_client(client, factory):
+    response = client.get("/notifications/history", params={"tenant_id": factory.tenant().id})
+
+    assert response.status_code == 400
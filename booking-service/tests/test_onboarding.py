@@ -0,0 +1,56 @@
+from datetime import time
+
+from shared.models import MasterSchedule
+
+
+def checklist(client, tenant) -> dict:
+    response = client.get("/onboarding", params={"tenant_id": tenant.id})
+    assert response.status_code == 200
+    return response.json()
+
+
+def completed_steps(result) -> list:
+    return [s["step"] for s in result["steps"] if s["completed"]]
+
+
+def test_new_tenant_has_nothing_done(client, factory):
+    result = checklist(client, factory.tenant())
+
+    assert [s["step"] for s in result["steps"]] == ["location", "master", "service", "working_hours"]
+    assert completed_steps(result) == []
+    assert (result["completed"], result["total"], result["is_complete"]) == (0, 4, False)
+
+
+def test_steps_complete_as_rows_are_added(client, factory):
+    tenant = factory.tenant()
+
+    factory.location(tenant)
+    assert completed_steps(checklist(client, tenant)) == ["location"]
+
+    service = factory.service(tenant)
+    assert completed_steps(checklist(client, tenant)) == ["location", "service"]
+
+    master = factory.master(tenant, [service], hours=None)
+    assert completed_steps(checklist(client, tenant)) == ["location", "master", "service"]
+
+    factory.add(MasterSchedule(master_id=master.id, day_of_week=0, start_time=time(9), end_time=time(18)))
+
+    result = checklist(client, tenant)
+    assert result["is_complete"] is True
+    assert result["completed"] == 4
+
+
+def test_inactive_rows_do_not_count(client, factory):
+    tenant = factory.tenant()
+    factory.service(tenant, is_active=False)
+    factory.master(tenant, is_active=False)
+
+    assert completed_steps(checklist(client, tenant)) == []
+
+
+def test_other_tenants_rows_do_not_count(client, factory):
+    tenant = factory.tenant()
+    other = factory.tenant()
+    factory.master(other, [factory.service(other)])
+
+    assert completed_steps(checklist(client, tenant)) == []
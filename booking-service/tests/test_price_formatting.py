@@ -0,0 +1,31 @@
+import pytest
+
+
+@pytest.fixture
+def session(factory):
+    tenant = factory.tenant(language="ru")
+    service = factory.service(tenant)
+    customer = factory.client()
+    factory.booking(tenant, factory.master(tenant, [service]), service, customer, price=150050, currency="KZT")
+    return factory.session(customer)
+
+
+def bookings(client, session, **params):
+    response = client.get(
+        "/client/bookings", params=params, headers={"X-Client-Session": session.session_token}
+    )
+    assert response.status_code == 200
+    return response.json()["bookings"]
+
+
+def test_price_in_tenant_language(client, session):
+    booking, = bookings(client, session)
+
+    assert booking["price"] == 1500.5
+    assert booking["price_formatted"] == "1\u00a0500,50\u00a0₸"
+
+
+def test_price_in_requested_language(client, session):
+    booking, = bookings(client, session, language="en")
+
+    assert booking["price_formatted"] == "₸1,500.50"
@@ -0,0 +1,83 @@
+from datetime import datetime, timedelta
+
+import pytest
+
+from shared.models import BookingStatus, Review, ReviewStatus
+
+
+@pytest.fixture
+def salon(factory):
+    tenant, service, master = factory.salon()
+    return tenant, service, master, factory.master(tenant, [service])
+
+
+def review(factory, tenant, service, master, rating, age_days=0, **values):
+    """Review of a completed booking, written age_days ago."""
+    customer = factory.client(full_name="Aida Serikova")
+    booking = factory.booking(tenant, master, service, customer, status=BookingStatus.COMPLETED)
+    return factory.add(Review(
+        tenant_id=tenant.id, booking_id=booking.id, client_id=customer.id, master_id=master.id,
+        rating=rating, created_at=datetime.utcnow() - timedelta(days=age_days), **values
+    ))
+
+
+def reviews(client, tenant, **params):
+    response = client.get(f"/public/business/{tenant.subdomain}/reviews", params=params)
+    assert response.status_code == 200
+    return response.json()
+
+
+def test_average_covers_approved_reviews_only(client, factory, salon):
+    tenant, service, master, other_master = salon
+    review(factory, tenant, service, master, 5)
+    review(factory, tenant, service, master, 4)
+    review(factory, tenant, service, other_master, 4)
+    review(factory, tenant, service, master, 1, status=ReviewStatus.PENDING)
+    review(factory, tenant, service, master, 1, status=ReviewStatus.REJECTED)
+
+    body = reviews(client, tenant)
+
+    assert body["total"] == 3
+    assert body["average_rating"] == 4.33
+    assert {r["rating"] for r in body["reviews"]} == {4, 5}
+    assert body["reviews"][0]["client_name"] == "Aida"
+
+
+def test_pages_are_newest_first(client, factory, salon):
+    tenant, service, master, _ = salon
+    written = [review(factory, tenant, service, master, 5, age_days=age) for age in range(5)]
+
+    first = reviews(client, tenant, page=1, page_size=2)
+    last = reviews(client, tenant, page=3, page_size=2)
+
+    assert [r["id"] for r in first["reviews"]] == [written[0].id, written[1].id]
+    assert [r["id"] for r in last["reviews"]] == [written[4].id]
+    assert first["pages"] == last["pages"] == 3
+    assert first["average_rating"] == last["average_rating"] == 5.0
+
+
+def test_reviews_of_one_master(client, factory, salon):
+    tenant, service, master, other_master = salon
+    review(factory, tenant, service, master, 5)
+    review(factory, tenant, service, other_master, 3)
+
+    body = reviews(client, tenant, master_id=other_master.id)
+
+    assert body["total"] == 1
+    assert body["average_rating"] == 3.0
+    assert body["reviews"][0]["master_id"] == other_master.id
+
+
+def test_no_reviews_have_no_average(client, salon):
+    body = reviews(client, salon[0])
+
+    assert body == {"reviews": [], "average_rating": None, "total": 0, "page": 1, "page_size": 20, "pages": 0}
+
+
+def test_master_of_other_tenant_is_not_found(client, factory, salon):
+    other_tenant = factory.tenant()
+
+    response = client.get(f"/public/business/{other_tenant.subdomain}/reviews", params={"master_id": salon[2].id})
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "MASTER_NOT_FOUND"
@@ -0,0 +1,45 @@
+def services_of(client, tenant, **params):
+    response = client.get(f"/public/business/{tenant.subdomain}/services", params=params)
+    assert response.status_code == 200
+    return {s["id"]: s for s in response.json()["services"]}
+
+
+def test_service_lists_offering_masters(client, factory):
+    tenant, service, first = factory.salon()
+    second = factory.master(tenant, [service])
+
+    listed = services_of(client, tenant)[service.id]
+
+    assert listed["masters_count"] == 2
+    assert {m["id"] for m in listed["masters"]} == {first.id, second.id}
+
+
+def test_service_without_masters_is_hidden(client, factory):
+    tenant = factory.tenant()
+    offered = factory.service(tenant)
+    unoffered = factory.service(tenant)
+    factory.master(tenant, [offered])
+
+    assert list(services_of(client, tenant)) == [offered.id]
+
+
+def test_inactive_master_does_not_offer(client, factory):
+    tenant = factory.tenant()
+    service = factory.service(tenant)
+    factory.master(tenant, [service], is_active=False)
+
+    assert services_of(client, tenant) == {}
+
+
+def test_location_filters_offering_masters(client, factory):
+    tenant = factory.tenant()
+    center = factory.location(tenant, name="Center")
+    suburb = factory.location(tenant, name="Suburb", is_main=False)
+    everywhere = factory.service(tenant)
+    center_only = factory.service(tenant)
+    factory.master(tenant, [everywhere, center_only], location_id=center.id)
+    factory.master(tenant, [everywhere], location_id=suburb.id)
+
+    assert set(services_of(client, tenant, location_id=suburb.id)) == {everywhere.id}
+    assert services_of(client, tenant, location_id=center.id)[everywhere.id]["masters_count"] == 1
+    assert set(services_of(client, tenant)) == {everywhere.id, center_only.id}
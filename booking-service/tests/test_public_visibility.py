@@ -0,0 +1,64 @@
+from datetime import datetime, timedelta
+
+import pytest
+
+from shared.billing import is_publicly_visible
+from shared.models import TenantStatus
+
+
+def page(client, tenant):
+    return client.get(f"/public/business/{tenant.subdomain}")
+
+
+def test_active_business_is_visible(client, factory):
+    tenant = factory.tenant(subscription_end_date=datetime.utcnow() + timedelta(days=10))
+
+    assert page(client, tenant).status_code == 200
+
+
+def test_trial_business_is_visible(client, factory):
+    tenant = factory.tenant(status=TenantStatus.TRIAL, trial_end_date=datetime.utcnow() + timedelta(days=3))
+
+    assert page(client, tenant).status_code == 200
+
+
+@pytest.mark.parametrize("status", [
+    TenantStatus.PENDING, TenantStatus.SUSPENDED, TenantStatus.REJECTED, TenantStatus.EXPIRED
+])
+def test_inactive_business_is_not_found(client, factory, status):
+    response = page(client, factory.tenant(status=status))
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "BUSINESS_NOT_FOUND"
+
+
+def test_lapsed_subscription_is_not_found_before_expiry_job(client, factory):
+    tenant = factory.tenant(subscription_end_date=datetime.utcnow() - timedelta(hours=1))
+
+    assert page(client, tenant).status_code == 404
+
+
+def test_ended_trial_is_not_found(client, factory):
+    tenant = factory.tenant(status=TenantStatus.TRIAL, trial_end_date=datetime.utcnow() - timedelta(days=1))
+
+    assert page(client, tenant).status_code == 404
+
+
+def test_suspended_business_takes_no_bookings(client, factory):
+    tenant = factory.tenant(status=TenantStatus.SUSPENDED)
+    service = factory.service(tenant)
+    master = factory.master(tenant, [service])
+
+    response = client.get(
+        f"/public/business/{tenant.subdomain}/availability",
+        params={"master_id": master.id, "date": factory.next_day(datetime.min.time()).date().isoformat()}
+    )
+
+    assert response.status_code == 404
+
+
+def test_visibility_at_a_given_time(factory):
+    tenant = factory.tenant(subscription_end_date=datetime(2026, 6, 1))
+
+    assert is_publicly_visible(tenant, now=datetime(2026, 5, 31)) is True
+    assert is_publicly_visible(tenant, now=datetime(2026, 6, 2)) is False
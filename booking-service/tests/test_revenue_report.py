@@ -0,0 +1,99 @@
+from datetime import datetime
+
+import pytest
+
+from shared.models import BookingStatus
+
+
+@pytest.fixture
+def salon(factory):
+    """Completed bookings on Jan 30, Jan 31 and Feb 2 2026, and a cancelled one."""
+    tenant = factory.tenant()
+    haircut = factory.service(tenant, name="Haircut")
+    coloring = factory.service(tenant, name="Coloring")
+    anna = factory.master(tenant, services=[haircut, coloring], full_name="Anna")
+    dana = factory.master(tenant, services=[haircut], full_name="Dana")
+    for day, master, service, price in [
+        (datetime(2026, 1, 30, 10), anna, haircut, 100000),
+        (datetime(2026, 1, 31, 23, 30), dana, haircut, 200000),
+        (datetime(2026, 2, 2, 9), anna, coloring, 400000),
+    ]:
+        factory.booking(
+            tenant, master, service, factory.client(),
+            booking_date=day, price=price, status=BookingStatus.COMPLETED
+        )
+    factory.booking(
+        tenant, anna, haircut, factory.client(),
+        booking_date=datetime(2026, 2, 1, 12), price=800000, status=BookingStatus.CANCELLED
+    )
+    return tenant, anna, dana, haircut, coloring
+
+
+def report(client, tenant, group_by, start="2026-01-01", end="2026-02-28"):
+    response = client.get("/reports/revenue", params={
+        "tenant_id": tenant.id, "start_date": start, "end_date": end, "group_by": group_by
+    })
+    assert response.status_code == 200
+    return response.json()["buckets"]
+
+
+def revenue(bucket):
+    return {row["currency"]: row["amount_minor"] for row in bucket["revenue"]}
+
+
+def test_daily_buckets(client, salon):
+    tenant = salon[0]
+
+    buckets = report(client, tenant, "day")
+
+    assert [(b["start"], b["bookings"], revenue(b)) for b in buckets] == [
+        ("2026-01-30", 1, {"KZT": 100000}),
+        ("2026-01-31", 1, {"KZT": 200000}),
+        ("2026-02-02", 1, {"KZT": 400000}),
+    ]
+
+
+def test_monthly_buckets_split_at_month_boundary(client, salon):
+    tenant = salon[0]
+
+    buckets = report(client, tenant, "month")
+
+    assert [(b["start"], b["bookings"], revenue(b)) for b in buckets] == [
+        ("2026-01-01", 2, {"KZT": 300000}),
+        ("2026-02-01", 1, {"KZT": 400000}),
+    ]
+
+
+def test_weekly_buckets_start_on_monday(client, salon):
+    tenant = salon[0]
+
+    buckets = report(client, tenant, "week")
+
+    assert [b["start"] for b in buckets] == ["2026-01-26", "2026-02-02"]
+
+
+def test_grouped_by_master_and_service(client, salon):
+    tenant, anna, dana, haircut, coloring = salon
+
+    by_master = {b["master_id"]: revenue(b) for b in report(client, tenant, "master")}
+    by_service = {b["service_id"]: revenue(b) for b in report(client, tenant, "service")}
+
+    assert by_master == {anna.id: {"KZT": 500000}, dana.id: {"KZT": 200000}}
+    assert by_service == {haircut.id: {"KZT": 300000}, coloring.id: {"KZT": 400000}}
+
+
+def test_end_date_is_inclusive(client, salon):
+    tenant = salon[0]
+
+    buckets = report(client, tenant, "day", start="2026-01-31", end="2026-01-31")
+
+    assert [b["start"] for b in buckets] == ["2026-01-31"]
+
+
+def test_inverted_range_is_rejected(client, salon):
+    response = client.get("/reports/revenue", params={
+        "tenant_id": salon[0].id, "start_date": "2026-02-01", "end_date": "2026-01-01"
+    })
+
+    assert response.status_code == 400
+    assert response.json()["error"] == "INVALID_DATE_RANGE"
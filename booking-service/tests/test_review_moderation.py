@@ -0,0 +1,104 @@
+import pytest
+
+from shared.models import BookingStatus, Master, Review, ReviewStatus
+
+
+@pytest.fixture
+def salon(factory):
+    return factory.salon(tenant={"features": {"reviews": True}})
+
+
+def completed(factory, salon):
+    tenant, service, master = salon
+    return factory.booking(tenant, master, service, factory.client(), status=BookingStatus.COMPLETED)
+
+
+def write_review(client, factory, booking, rating):
+    return client.post(
+        f"/client/booking/{booking.id}/review",
+        headers={"X-Client-Session": factory.session(booking.client).session_token},
+        json={"rating": rating}
+    )
+
+
+def moderate(client, review_id, tenant, **body):
+    return client.put(f"/reviews/{review_id}", params={"tenant_id": tenant.id}, json=body)
+
+
+def rating_of(db, master):
+    db.expire_all()
+    master = db.query(Master).filter(Master.id == master.id).one()
+    return master.rating, master.reviews_count
+
+
+def test_review_is_published_without_moderation(client, db, factory, salon):
+    response = write_review(client, factory, completed(factory, salon), 4)
+
+    assert response.status_code == 201
+    assert response.json()["status"] == ReviewStatus.APPROVED.value
+    assert rating_of(db, salon[2]) == (4, 1)
+
+
+def test_moderated_review_waits_for_approval(client, db, factory, salon):
+    tenant, _, master = salon
+    tenant.moderate_reviews = True
+    db.flush()
+
+    response = write_review(client, factory, completed(factory, salon), 2)
+
+    assert response.json()["status"] == ReviewStatus.PENDING.value
+    assert rating_of(db, master) == (None, 0)
+
+    moderate(client, response.json()["id"], tenant, status="APPROVED")
+
+    assert rating_of(db, master) == (2, 1)
+
+
+def test_rejected_review_does_not_affect_rating(client, db, factory, salon):
+    tenant, _, master = salon
+    write_review(client, factory, completed(factory, salon), 5)
+    bad = write_review(client, factory, completed(factory, salon), 1).json()
+    assert rating_of(db, master) == (3, 2)
+
+    response = moderate(client, bad["id"], tenant, status="REJECTED")
+
+    assert response.status_code == 200
+    assert rating_of(db, master) == (5, 1)
+
+
+def test_owner_responds_to_review(client, db, factory, salon):
+    tenant = salon[0]
+    review_id = write_review(client, factory, completed(factory, salon), 5).json()["id"]
+
+    response = moderate(client, review_id, tenant, response="  Thank you!  ")
+
+    assert response.json()["owner_response"] == "Thank you!"
+    assert response.json()["responded_at"] is not None
+
+    moderate(client, review_id, tenant, response="")
+
+    db.expire_all()
+    review = db.query(Review).filter(Review.id == review_id).one()
+    assert review.owner_response is None
+    assert review.responded_at is None
+
+
+def test_pending_reviews_are_listed_for_moderation(client, db, factory, salon):
+    tenant = salon[0]
+    tenant.moderate_reviews = True
+    db.flush()
+    pending = write_review(client, factory, completed(factory, salon), 3).json()
+
+    response = client.get("/reviews", params={"tenant_id": tenant.id, "status": "PENDING"})
+
+    assert response.json()["moderate_reviews"] is True
+    assert [r["id"] for r in response.json()["reviews"]] == [pending["id"]]
+
+
+def test_review_of_other_tenant_cannot_be_moderated(client, factory, salon):
+    review_id = write_review(client, factory, completed(factory, salon), 5).json()["id"]
+
+    response = moderate(client, review_id, factory.tenant(), status="REJECTED")
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "REVIEW_NOT_FOUND"
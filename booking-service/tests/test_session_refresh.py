@@ -0,0 +1,49 @@
+from datetime import datetime, timedelta
+
+from shared.config import settings
+
+
+def refresh(client, token):
+    return client.post("/client/session/refresh", headers={"X-Client-Session": token})
+
+
+def bookings(client, token):
+    return client.get("/client/bookings", headers={"X-Client-Session": token})
+
+
+def test_valid_session_is_extended_with_new_token(client, db, factory):
+    session = factory.session(factory.client())
+    old_token = session.session_token
+    before = datetime.utcnow()
+
+    response = refresh(client, old_token)
+
+    assert response.status_code == 200
+    new_token = response.json()["session_token"]
+    assert new_token != old_token
+    expires_at = datetime.fromisoformat(response.json()["expires_at"])
+    assert expires_at >= before + timedelta(days=settings.CLIENT_SESSION_EXPIRE_DAYS)
+    db.expire_all()
+    assert session.last_used >= before
+    # The old token stops working
+    assert bookings(client, new_token).status_code == 200
+    assert bookings(client, old_token).status_code == 401
+
+
+def test_expired_session_is_rejected(client, factory):
+    session = factory.session(factory.client(), session_expires=datetime.utcnow() - timedelta(minutes=1))
+
+    response = refresh(client, session.session_token)
+
+    assert response.status_code == 401
+    assert response.json()["error"] == "INVALID_CLIENT_SESSION"
+
+
+def test_unverified_session_is_rejected(client, factory):
+    session = factory.session(factory.client(), is_verified=False)
+
+    assert refresh(client, session.session_token).status_code == 401
+
+
+def test_unknown_token_is_rejected(client):
+    assert refresh(client, "not-a-token").status_code == 401
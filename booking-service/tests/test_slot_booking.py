@@ -0,0 +1,96 @@
+from datetime import time
+
+import pytest
+
+from shared.models import Booking, BookingStatus
+
+
+@pytest.fixture
+def salon(factory):
+    tenant = factory.tenant()
+    service = factory.service(tenant)
+    group_service = factory.service(tenant, name="Yoga", capacity=2)
+    master = factory.master(tenant, services=[service, group_service])
+    return tenant, master, service, group_service
+
+
+def book(client, tenant, master, service, booking_date, phone):
+    return client.post("/public/booking", json={
+        "subdomain": tenant.subdomain,
+        "client_phone": phone,
+        "client_name": "Aida",
+        "master_id": master.id,
+        "service_id": service.id,
+        "booking_date": booking_date.isoformat()
+    })
+
+
+def test_book_free_slot(client, db, factory, salon):
+    tenant, master, service, _ = salon
+    start = factory.next_day(time(10))
+
+    response = book(client, tenant, master, service, start, "+77011111111")
+
+    assert response.status_code == 201
+    body = response.json()
+    assert body["status"] == BookingStatus.CONFIRMED.value
+    assert body["master_id"] == master.id
+    booking = db.query(Booking).filter(Booking.id == body["booking_id"]).one()
+    assert booking.booking_date == start
+    assert booking.duration_minutes == service.duration_minutes
+
+
+def test_overlapping_slot_is_unavailable(client, factory, salon):
+    tenant, master, service, _ = salon
+    start = factory.next_day(time(10))
+    assert book(client, tenant, master, service, start, "+77011111111").status_code == 201
+
+    response = book(client, tenant, master, service, start.replace(minute=30), "+77012222222")
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "SLOT_UNAVAILABLE"
+
+
+def test_slot_after_previous_booking_ends_is_free(client, factory, salon):
+    tenant, master, service, _ = salon
+    start = factory.next_day(time(10))
+    assert book(client, tenant, master, service, start, "+77011111111").status_code == 201
+
+    response = book(client, tenant, master, service, start.replace(hour=11), "+77012222222")
+
+    assert response.status_code == 201
+
+
+def test_group_service_takes_bookings_up_to_capacity(client, factory, salon):
+    tenant, master, _, group_service = salon
+    start = factory.next_day(time(12))
+
+    assert book(client, tenant, master, group_service, start, "+77011111111").status_code == 201
+    assert book(client, tenant, master, group_service, start, "+77012222222").status_code == 201
+
+    response = book(client, tenant, master, group_service, start, "+77013333333")
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "SLOT_UNAVAILABLE"
+
+
+def test_reschedule_booking(client, db, factory, salon):
+    tenant, master, service, _ = salon
+    owner = factory.user(tenant)
+    start = factory.next_day(time(10))
+    booking = factory.booking(tenant, master, service, factory.client(), booking_date=start)
+    factory.booking(tenant, master, service, factory.client(), booking_date=start.replace(hour=14))
+
+    taken = client.put(f"/booking/{booking.id}", json={
+        "user_id": owner.id, "role": "OWNER", "tenant_id": tenant.id,
+        "booking_date": start.replace(hour=14, minute=30).isoformat()
+    })
+    moved = client.put(f"/booking/{booking.id}", json={
+        "user_id": owner.id, "role": "OWNER", "tenant_id": tenant.id,
+        "booking_date": start.replace(hour=15).isoformat()
+    })
+
+    assert taken.status_code == 409
+    assert taken.json()["error"] == "SLOT_UNAVAILABLE"
+    assert moved.status_code == 200
+    assert moved.json()["booking_date"] == start.replace(hour=15).isoformat()
@@ -0,0 +1,89 @@
+from datetime import time
+
+import pytest
+
+
+@pytest.fixture
+def tomorrow(factory):
+    return factory.next_day(time(0)).date().isoformat()
+
+
+def slots(client, tenant, master, day, *services):
+    params = {"date": day}
+    if master:
+        params["master_id"] = master.id
+    if services:
+        params["service_ids"] = [s.id for s in services]
+    response = client.get(f"/public/business/{tenant.subdomain}/availability", params=params)
+    assert response.status_code == 200
+    return response.json()
+
+
+def hours(start, end, step):
+    """HH:MM start times from start up to and including end, step minutes apart."""
+    result, minutes = [], start * 60
+    while minutes <= end * 60:
+        result.append(f"{minutes // 60:02d}:{minutes % 60:02d}")
+        minutes += step
+    return result
+
+
+def test_15_minute_service_interval(client, factory, tomorrow):
+    tenant = factory.tenant()
+    service = factory.service(tenant, duration_minutes=60, slot_interval_minutes=15)
+    master = factory.master(tenant, [service], hours=(time(9), time(12)))
+
+    result = slots(client, tenant, master, tomorrow, service)
+
+    assert result["slot_interval_minutes"] == 15
+    # The last start leaves room for the whole service
+    assert result["available_slots"] == hours(9, 11, 15)
+
+
+def test_60_minute_service_interval(client, factory, tomorrow):
+    tenant = factory.tenant()
+    service = factory.service(tenant, duration_minutes=90, slot_interval_minutes=60)
+    master = factory.master(tenant, [service], hours=(time(9), time(13)))
+
+    result = slots(client, tenant, master, tomorrow, service)
+
+    assert result["available_slots"] == ["09:00", "10:00", "11:00"]
+
+
+def test_tenant_default_interval(client, factory, tomorrow):
+    tenant = factory.tenant(slot_interval_minutes=15)
+    service = factory.service(tenant, duration_minutes=30)
+    master = factory.master(tenant, [service], hours=(time(9), time(10)))
+
+    assert slots(client, tenant, master, tomorrow, service)["available_slots"] == ["09:00", "09:15", "09:30"]
+    assert slots(client, tenant, master, tomorrow)["slot_interval_minutes"] == 15
+
+
+def test_service_interval_overrides_tenant(client, factory, tomorrow):
+    tenant = factory.tenant(slot_interval_minutes=15)
+    service = factory.service(tenant, duration_minutes=60, slot_interval_minutes=60)
+    master = factory.master(tenant, [service], hours=(time(9), time(12)))
+
+    assert slots(client, tenant, master, tomorrow, service)["available_slots"] == ["09:00", "10:00", "11:00"]
+
+
+def test_booking_blocks_slots_on_the_grid(client, factory, tomorrow):
+    tenant = factory.tenant()
+    service = factory.service(tenant, duration_minutes=30, slot_interval_minutes=15)
+    master = factory.master(tenant, [service], hours=(time(9), time(11)))
+    factory.booking(tenant, master, service, factory.client(), booking_date=factory.next_day(time(10)))
+
+    result = slots(client, tenant, master, tomorrow, service)
+
+    assert result["available_slots"] == ["09:00", "09:15", "09:30", "10:30"]
+
+
+def test_any_master_uses_service_interval(client, factory, tomorrow):
+    tenant = factory.tenant()
+    service = factory.service(tenant, duration_minutes=60, slot_interval_minutes=60)
+    factory.master(tenant, [service], hours=(time(9), time(11)))
+
+    result = slots(client, tenant, None, tomorrow, service)
+
+    assert result["slot_interval_minutes"] == 60
+    assert result["available_slots"] == ["09:00", "10:00"]
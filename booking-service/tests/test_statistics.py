@@ -0,0 +1,31 @@
+from shared.models import BookingStatus
+
+
+def test_revenue_is_summed_in_minor_units_per_currency(client, factory):
+    tenant, service, master = factory.salon()
+    for price, currency in [(1010, "KZT"), (2020, "KZT"), (3030, "KZT"), (999, "USD")]:
+        factory.booking(
+            tenant, master, service, factory.client(),
+            price=price, currency=currency, status=BookingStatus.COMPLETED
+        )
+    factory.booking(tenant, master, service, factory.client(), price=100000)
+
+    response = client.get("/statistics", params={"tenant_id": tenant.id})
+
+    assert response.status_code == 200
+    revenue = {row["currency"]: row for row in response.json()["revenue"]}
+    assert revenue["KZT"]["amount_minor"] == 6060
+    assert revenue["KZT"]["amount"] == 60.6
+    assert revenue["USD"]["amount_minor"] == 999
+    assert revenue["USD"]["amount"] == 9.99
+
+
+def test_revenue_excludes_other_tenants(client, factory):
+    tenant = factory.tenant()
+    other, service, master = factory.salon()
+    factory.booking(other, master, service, factory.client(), status=BookingStatus.COMPLETED)
+
+    response = client.get("/statistics", params={"tenant_id": tenant.id})
+
+    assert response.json()["revenue"] == []
+    assert response.json()["bookings"]["total"] == 0
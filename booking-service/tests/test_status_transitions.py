@@ -0,0 +1,91 @@
+import importlib
+from datetime import time
+from types import SimpleNamespace
+
+import pytest
+
+from shared.models import Booking, BookingStatus, UserRole
+
+
+@pytest.fixture
+def notified(service, monkeypatch):
+    """Booking events published and WhatsApp templates sent."""
+    notified = SimpleNamespace(events=[], messages=[])
+
+    async def send_whatsapp_message(phone, message, template, booking=None):
+        notified.messages.append(template)
+        return True
+
+    monkeypatch.setattr(service, "publish_booking_event", lambda booking, event: notified.events.append(event))
+    monkeypatch.setattr(service, "send_whatsapp_message", send_whatsapp_message)
+    return notified
+
+
+@pytest.fixture
+def booked(factory):
+    tenant = factory.tenant()
+    service = factory.service(tenant)
+    owner = factory.user(tenant)
+    booking = factory.booking(
+        tenant, factory.master(tenant, [service]), service, factory.client(),
+        booking_date=factory.next_day(time(10), -1)
+    )
+    return owner, booking
+
+
+def complete(client, owner, booking):
+    return client.put(f"/booking/{booking.id}/complete", json={
+        "user_id": owner.id, "role": UserRole.OWNER.value, "tenant_id": owner.tenant_id
+    })
+
+
+def cancel(client, owner, booking):
+    return client.delete(f"/booking/{booking.id}", params={
+        "user_id": owner.id, "role": UserRole.OWNER.value, "tenant_id": owner.tenant_id, "waive_fee": True
+    })
+
+
+def test_second_complete_sends_no_duplicate(client, booked, notified, service):
+    owner, booking = booked
+
+    first = complete(client, owner, booking)
+    second = complete(client, owner, booking)
+
+    assert first.status_code == second.status_code == 200
+    assert second.json()["message"] == "Booking already completed"
+    assert notified.events == [service.BookingEvent.COMPLETED]
+
+
+def test_second_cancel_sends_no_duplicate(client, booked, notified, service):
+    owner, booking = booked
+
+    first = cancel(client, owner, booking)
+    second = cancel(client, owner, booking)
+
+    assert first.status_code == second.status_code == 200
+    assert second.json() == {"message": "Booking already cancelled"}
+    assert notified.events == [service.BookingEvent.CANCELLED]
+    assert len(notified.messages) == 1
+
+
+def test_transition_lost_to_concurrent_request(service, db, booked):
+    _, booking = booked
+    booking_service = importlib.import_module("services.booking_service").BookingService(db)
+
+    # Another request completed the booking after this one loaded it
+    db.query(Booking).filter(Booking.id == booking.id).update(
+        {Booking.status: BookingStatus.COMPLETED}, synchronize_session=False
+    )
+
+    assert booking_service.transition_status(booking, BookingStatus.COMPLETED, [BookingStatus.CONFIRMED]) is False
+    assert booking.status == BookingStatus.COMPLETED
+
+
+def test_transition_only_from_allowed_statuses(service, db, booked):
+    _, booking = booked
+    booking.status = BookingStatus.NO_SHOW
+    db.flush()
+    booking_service = importlib.import_module("services.booking_service").BookingService(db)
+
+    assert booking_service.transition_status(booking, BookingStatus.COMPLETED, [BookingStatus.CONFIRMED]) is False
+    assert booking.status == BookingStatus.NO_SHOW
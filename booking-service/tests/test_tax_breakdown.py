@@ -0,0 +1,31 @@
+from datetime import time
+
+import pytest
+
+
+@pytest.mark.parametrize("inclusive, total, tax", [(True, 500000, 53571), (False, 560000, 60000)])
+def test_booking_stores_tax_breakdown(client, factory, inclusive, total, tax):
+    tenant = factory.tenant(tax_rate=1200, tax_inclusive=inclusive)
+    service = factory.service(tenant)
+    master = factory.master(tenant, services=[service])
+    owner = factory.user(tenant)
+
+    created = client.post("/public/booking", json={
+        "subdomain": tenant.subdomain,
+        "client_phone": "+77011111111",
+        "client_name": "Aida",
+        "master_id": master.id,
+        "service_id": service.id,
+        "booking_date": factory.next_day(time(10)).isoformat()
+    })
+    response = client.get(f"/booking/{created.json()['booking_id']}", params={
+        "user_id": owner.id, "role": owner.role.value, "tenant_id": tenant.id
+    })
+
+    assert response.status_code == 200
+    breakdown = response.json()["price_breakdown"]
+    assert breakdown["total_minor"] == total
+    assert breakdown["tax_minor"] == tax
+    assert breakdown["net_minor"] == total - tax
+    assert breakdown["tax_rate_percent"] == 12
+    assert breakdown["tax_inclusive"] is inclusive
@@ -0,0 +1,109 @@
+import pytest
+
+from shared.config import settings
+from shared.email import EmailTransientError
+from shared.i18n import render_preview
+
+SUBJECT = "booking_confirmation_subject"
+
+
+@pytest.fixture(autouse=True)
+def languages(monkeypatch):
+    monkeypatch.setattr(settings, "SUPPORTED_LANGUAGES", "ru,en,kk")
+
+
+@pytest.fixture
+def sent_emails(service, monkeypatch):
+    emails = []
+
+    def deliver(to, subject, body, **kwargs):
+        emails.append({"to": to, "subject": subject, "body": body, **kwargs})
+        return f"msg-{len(emails)}"
+
+    monkeypatch.setattr(service.email_client, "deliver", deliver)
+    return emails
+
+
+def test_preview_renders_default_with_sample_data(client, factory):
+    tenant = factory.tenant(branding={"primary_color": "#ff0066"})
+
+    response = client.post(f"/templates/{SUBJECT}/preview", params={"tenant_id": tenant.id}, json={"language": "en"})
+
+    assert response.status_code == 200
+    assert response.json()["text"] == "Your booking at Beauty Salon is confirmed"
+    assert "background: #ff0066" in response.json()["html"]
+
+
+def test_preview_of_draft_with_given_sample_data(client, factory):
+    tenant = factory.tenant()
+
+    response = client.post(
+        f"/templates/{SUBJECT}/preview",
+        params={"tenant_id": tenant.id},
+        json={"language": "en", "body": "Welcome to {business_name}", "sample_data": {"business_name": "Nail Bar"}}
+    )
+
+    assert response.json()["text"] == "Welcome to Nail Bar"
+
+
+def test_invalid_draft_is_rejected(client, factory):
+    tenant = factory.tenant()
+
+    response = client.post(
+        f"/templates/{SUBJECT}/preview", params={"tenant_id": tenant.id}, json={"language": "en", "body": "Hi {name}"}
+    )
+
+    assert response.status_code == 400
+    assert response.json()["error"] == "INVALID_TEMPLATE"
+
+
+def test_sample_date_and_price_are_localized():
+    text = render_preview("{booking_date} {price} {unknown}", language="en")
+
+    assert "5,000" in text
+    assert text.endswith("{unknown}")
+
+
+def test_test_email_goes_to_requesting_user(client, db, factory, sent_emails):
+    tenant = factory.tenant()
+    user = factory.user(tenant)
+
+    response = client.post(
+        f"/templates/{SUBJECT}/test",
+        params={"tenant_id": tenant.id, "user_id": user.id},
+        json={"language": "en", "to": "someone@example.com"}
+    )
+
+    assert response.status_code == 200
+    assert response.json()["to"] == user.email
+    assert [email["to"] for email in sent_emails] == [user.email]
+    assert "Beauty Salon" in sent_emails[0]["body"]
+
+
+def test_test_email_to_user_of_other_tenant_is_refused(client, factory, sent_emails):
+    tenant = factory.tenant()
+    stranger = factory.user(factory.tenant())
+
+    response = client.post(
+        f"/templates/{SUBJECT}/test", params={"tenant_id": tenant.id, "user_id": stranger.id}, json={"language": "en"}
+    )
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "USER_NOT_FOUND"
+    assert sent_emails == []
+
+
+def test_failed_test_email_is_unavailable(client, factory, service, monkeypatch):
+    tenant = factory.tenant()
+    user = factory.user(tenant)
+
+    def deliver(*args, **kwargs):
+        raise EmailTransientError("SMTP unavailable")
+
+    monkeypatch.setattr(service.email_client, "deliver", deliver)
+
+    response = client.post(
+        f"/templates/{SUBJECT}/test", params={"tenant_id": tenant.id, "user_id": user.id}, json={"language": "en"}
+    )
+
+    assert response.status_code == 503
@@ -0,0 +1,81 @@
+from datetime import datetime, timedelta
+
+import pytest
+
+from shared.models import BookingStatus, UserRole
+
+
+@pytest.fixture
+def bookings(factory):
+    """Booking of another tenant and a manager of the caller's tenant."""
+    own = factory.tenant()
+    other, service, master = factory.salon()
+    booking = factory.booking(
+        other, master, service, factory.client(), booking_date=datetime.utcnow() - timedelta(hours=1)
+    )
+    return booking, factory.user(own, role=UserRole.MANAGER)
+
+
+def test_update_of_other_tenant_booking_is_not_found(client, db, bookings, staff_params):
+    booking, manager = bookings
+
+    response = client.put(f"/booking/{booking.id}", json=staff_params(manager, notes="moved"))
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "BOOKING_NOT_FOUND"
+    db.expire_all()
+    assert booking.admin_notes is None
+
+
+def test_complete_of_other_tenant_booking_is_not_found(client, db, bookings, staff_params):
+    booking, manager = bookings
+
+    response = client.put(f"/booking/{booking.id}/complete", json=staff_params(manager))
+
+    assert response.status_code == 404
+    db.expire_all()
+    assert booking.status == BookingStatus.CONFIRMED
+
+
+def test_cancel_of_other_tenant_booking_is_not_found(client, db, bookings, staff_params):
+    booking, manager = bookings
+
+    response = client.delete(f"/booking/{booking.id}", params=staff_params(manager))
+
+    assert response.status_code == 404
+    db.expire_all()
+    assert booking.status == BookingStatus.CONFIRMED
+
+
+def test_staff_without_tenant_is_forbidden(client, bookings, staff_params):
+    booking, manager = bookings
+
+    response = client.put(f"/booking/{booking.id}", json=staff_params(manager, tenant_id=None, notes="x"))
+
+    assert response.status_code == 403
+    assert response.json()["error"] == "FORBIDDEN"
+
+
+def test_master_cannot_modify_colleague_booking(client, db, factory, staff_params):
+    tenant = factory.tenant()
+    service = factory.service(tenant)
+    booking = factory.booking(tenant, factory.master(tenant, services=[service]), service, factory.client())
+    colleague = factory.user(tenant, role=UserRole.MASTER)
+    factory.master(tenant, user_id=colleague.id)
+
+    response = client.put(f"/booking/{booking.id}", json=staff_params(colleague, notes="mine"))
+
+    assert response.status_code == 403
+
+
+def test_own_tenant_booking_is_updated(client, db, factory, staff_params):
+    tenant = factory.tenant()
+    service = factory.service(tenant)
+    booking = factory.booking(tenant, factory.master(tenant, services=[service]), service, factory.client())
+    manager = factory.user(tenant, role=UserRole.MANAGER)
+
+    response = client.put(f"/booking/{booking.id}", json=staff_params(manager, notes="VIP"))
+
+    assert response.status_code == 200
+    db.expire_all()
+    assert booking.admin_notes == "VIP"
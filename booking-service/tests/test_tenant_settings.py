@@ -0,0 +1,35 @@
+def test_settings_with_defaults(client, factory):
+    tenant = factory.tenant(branding={"primary_color": "#ff0066"})
+
+    response = client.get("/settings", params={"tenant_id": tenant.id})
+
+    assert response.status_code == 200
+    assert response.json()["branding"] == {"logo_url": None, "primary_color": "#ff0066"}
+    assert response.json()["reminder_channels"]["same_day"]
+    assert response.json()["cancellation_policy"]
+
+
+def test_update_is_stored(client, db, factory):
+    tenant = factory.tenant()
+
+    response = client.put("/settings", params={"tenant_id": tenant.id}, json={
+        "reminder_channels": {"same_day": ["WHATSAPP", "EMAIL"], "advance": ["EMAIL"]}
+    })
+
+    assert response.status_code == 200
+    assert response.json()["reminder_channels"] == {"same_day": ["WHATSAPP", "EMAIL"], "advance": ["EMAIL"]}
+    db.expire_all()
+    assert tenant.reminder_channels == {"same_day": ["WHATSAPP", "EMAIL"], "advance": ["EMAIL"]}
+
+
+def test_unknown_key_is_rejected(client, factory):
+    tenant = factory.tenant()
+
+    response = client.put("/settings", params={"tenant_id": tenant.id}, json={"reminder_chanels": {}})
+
+    assert response.status_code == 422
+    assert response.json()["error"] == "VALIDATION_ERROR"
+
+
+def test_unknown_tenant_is_not_found(client):
+    assert client.get("/settings", params={"tenant_id": 0}).status_code == 404
@@ -0,0 +1,121 @@
+from datetime import time
+
+import pytest
+
+from shared.config import settings
+from shared.models import BookingStatus, UserRole
+
+
+@pytest.fixture
+def salon(factory):
+    tenant = factory.tenant()
+    service = factory.service(tenant, duration_minutes=60, slot_interval_minutes=60)
+    staff = factory.user(tenant, role=UserRole.MASTER)
+    master = factory.master(tenant, [service], hours=(time(9), time(12)), user_id=staff.id)
+    return tenant, service, master, factory.user(tenant), staff
+
+
+def day(factory, days=1):
+    return factory.next_day(time(0), days).date()
+
+
+def slots(client, tenant, service, master, on):
+    response = client.get(
+        f"/public/business/{tenant.subdomain}/availability",
+        params={"master_id": master.id, "date": on.isoformat(), "service_ids": [service.id]}
+    )
+    assert response.status_code == 200
+    return response.json()["available_slots"]
+
+
+def add_time_off(client, master, user, start, end):
+    return client.post(f"/masters/{master.id}/time-off", params={
+        "tenant_id": master.tenant_id, "user_id": user.id, "role": user.role.value
+    }, json={"start_date": start.isoformat(), "end_date": end.isoformat(), "reason": "Vacation"})
+
+
+def test_no_slots_during_approved_time_off(client, factory, salon):
+    tenant, service, master, owner, _ = salon
+
+    response = add_time_off(client, master, owner, day(factory), day(factory, 2))
+
+    assert response.status_code == 201
+    assert response.json()["status"] == "APPROVED"
+    assert slots(client, tenant, service, master, day(factory)) == []
+    assert slots(client, tenant, service, master, day(factory, 2)) == []
+    assert slots(client, tenant, service, master, day(factory, 3)) == ["09:00", "10:00", "11:00"]
+
+
+def test_master_request_waits_for_approval(client, factory, salon, monkeypatch):
+    monkeypatch.setattr(settings, "TIME_OFF_REQUIRES_APPROVAL", True)
+    tenant, service, master, owner, staff = salon
+
+    time_off = add_time_off(client, master, staff, day(factory), day(factory)).json()
+
+    assert time_off["status"] == "PENDING"
+    assert slots(client, tenant, service, master, day(factory)) == ["09:00", "10:00", "11:00"]
+
+    response = client.put(
+        f"/masters/{master.id}/time-off/{time_off['id']}/approve", params={"tenant_id": tenant.id, "user_id": owner.id}
+    )
+
+    assert response.status_code == 200
+    assert response.json()["reviewed_by"] == owner.id
+    assert slots(client, tenant, service, master, day(factory)) == []
+
+
+def test_rejected_time_off_keeps_slots(client, factory, salon, monkeypatch):
+    monkeypatch.setattr(settings, "TIME_OFF_REQUIRES_APPROVAL", True)
+    tenant, service, master, owner, staff = salon
+    time_off = add_time_off(client, master, staff, day(factory), day(factory)).json()
+
+    response = client.put(
+        f"/masters/{master.id}/time-off/{time_off['id']}/reject", params={"tenant_id": tenant.id, "user_id": owner.id}
+    )
+
+    assert response.json()["status"] == "REJECTED"
+    assert slots(client, tenant, service, master, day(factory)) == ["09:00", "10:00", "11:00"]
+
+
+def test_deleted_time_off_frees_dates(client, factory, salon):
+    tenant, service, master, owner, _ = salon
+    time_off = add_time_off(client, master, owner, day(factory), day(factory)).json()
+
+    response = client.delete(f"/masters/{master.id}/time-off/{time_off['id']}", params={
+        "tenant_id": tenant.id, "user_id": owner.id, "role": owner.role.value
+    })
+
+    assert response.status_code == 200
+    assert slots(client, tenant, service, master, day(factory)) == ["09:00", "10:00", "11:00"]
+
+
+def test_overlapping_bookings_are_a_conflict(client, factory, salon):
+    tenant, service, master, owner, _ = salon
+    booking = factory.booking(tenant, master, service, factory.client(), booking_date=factory.next_day(time(10), 2))
+    factory.booking(
+        tenant, master, service, factory.client(), booking_date=factory.next_day(time(11), 2),
+        status=BookingStatus.CANCELLED
+    )
+
+    response = add_time_off(client, master, owner, day(factory), day(factory, 3))
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "TIME_OFF_CONFLICT"
+    assert response.json()["details"] == {"booking_ids": [booking.id]}
+
+
+def test_invalid_date_range(client, factory, salon, monkeypatch):
+    monkeypatch.setattr(settings, "TIME_OFF_MAX_DAYS", 7)
+    master, owner = salon[2], salon[3]
+
+    assert add_time_off(client, master, owner, day(factory, 2), day(factory)).status_code == 400
+    assert add_time_off(client, master, owner, day(factory), day(factory, 7)).status_code == 400
+
+
+def test_master_cannot_manage_others_time_off(client, factory, salon):
+    tenant = salon[0]
+    other = factory.master(tenant, user_id=factory.user(tenant, role=UserRole.MASTER).id)
+
+    response = add_time_off(client, other, salon[4], day(factory), day(factory))
+
+    assert response.status_code == 403
@@ -1,3 +1,6 @@
+-- Superseded: legacy UUID schema, not applied. init_db.py creates the tables from
+-- shared.models, 003 onwards upgrade databases it created (see README).
+
 -- Enable UUID extension
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
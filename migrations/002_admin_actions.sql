@@ -1,3 +1,6 @@
+-- Superseded: legacy UUID schema, not applied. init_db.py creates the tables from
+-- shared.models, 003 onwards upgrade databases it created (see README).
+
 -- Admin Actions Log Table
 CREATE TABLE admin_actions (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
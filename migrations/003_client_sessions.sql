@@ -0,0 +1,15 @@
+-- Sessions of clients verified by a code sent to their phone
+CREATE TABLE IF NOT EXISTS client_sessions (
+    id SERIAL PRIMARY KEY,
+    client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    verification_code VARCHAR(10),
+    verification_expires TIMESTAMP,
+    is_verified BOOLEAN DEFAULT FALSE,
+    session_token VARCHAR(100) UNIQUE,
+    session_expires TIMESTAMP,
+    last_used TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_sessions_client_id ON client_sessions(client_id);
@@ -0,0 +1,2 @@
+-- IANA timezone of the tenant, DEFAULT_TIMEZONE if unset
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS timezone VARCHAR(50);
@@ -0,0 +1,4 @@
+-- Language of tenant emails and when the trial expiration warning was sent
+ALTER TABLE tenants
+    ADD COLUMN IF NOT EXISTS language VARCHAR(5),
+    ADD COLUMN IF NOT EXISTS trial_warning_sent_at TIMESTAMP;
@@ -0,0 +1,16 @@
+-- Tenants whose trial lapsed, and the log of admin and background job actions
+ALTER TYPE tenantstatus ADD VALUE IF NOT EXISTS 'EXPIRED';
+
+CREATE TABLE IF NOT EXISTS admin_actions (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    action_type VARCHAR(100) NOT NULL,
+    target_type VARCHAR(100) NOT NULL,
+    target_id INTEGER NOT NULL,
+    details TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_actions_admin_id ON admin_actions(admin_id);
+CREATE INDEX IF NOT EXISTS idx_admin_actions_action_type ON admin_actions(action_type);
+CREATE INDEX IF NOT EXISTS idx_admin_actions_created_at ON admin_actions(created_at);
@@ -0,0 +1,2 @@
+-- End of the paid subscription period, extended by each payment
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS subscription_end_date TIMESTAMP;
@@ -0,0 +1,20 @@
+-- Subscription payments, processed once from provider webhooks
+DO $$ BEGIN
+    CREATE TYPE paymentstatus AS ENUM ('PENDING', 'SUCCEEDED', 'FAILED');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+CREATE TABLE IF NOT EXISTS payments (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    amount NUMERIC(10, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    period_days INTEGER NOT NULL,
+    status paymentstatus NOT NULL DEFAULT 'PENDING',
+    provider_payment_id VARCHAR(100) UNIQUE,
+    paid_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_tenant_id ON payments(tenant_id);
@@ -0,0 +1,14 @@
+-- Deposits: bookings wait for payment until payment_due_at, payments may belong to a booking
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS require_deposit BOOLEAN DEFAULT FALSE;
+
+ALTER TABLE services
+    ADD COLUMN IF NOT EXISTS require_deposit BOOLEAN DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS deposit_amount NUMERIC(10, 2);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS payment_due_at TIMESTAMP;
+
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS booking_id INTEGER REFERENCES bookings(id) ON DELETE SET NULL,
+    ALTER COLUMN period_days DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_payments_booking_id ON payments(booking_id);
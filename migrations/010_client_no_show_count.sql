@@ -0,0 +1,2 @@
+-- Bookings the client did not show up for
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS no_show_count INTEGER NOT NULL DEFAULT 0;
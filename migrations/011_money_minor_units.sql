@@ -0,0 +1,21 @@
+-- Store money as integer minor units (tiyn, cents) and the currency of each booking
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS currency VARCHAR(3);
+
+-- Convert only once, databases created with init_db.py already store minor units
+DO $$ BEGIN
+    IF (SELECT data_type FROM information_schema.columns
+        WHERE table_name = 'services' AND column_name = 'price') = 'numeric' THEN
+        ALTER TABLE services
+            ALTER COLUMN price TYPE INTEGER USING ROUND(price * 100),
+            ALTER COLUMN deposit_amount TYPE INTEGER USING ROUND(deposit_amount * 100);
+
+        ALTER TABLE payments ALTER COLUMN amount TYPE INTEGER USING ROUND(amount * 100);
+
+        ALTER TABLE bookings ALTER COLUMN price TYPE INTEGER USING ROUND(price * 100);
+    END IF;
+END $$;
+
+-- Existing bookings were priced in DEFAULT_CURRENCY, replace KZT if it's configured otherwise
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'KZT';
+
+ALTER TABLE bookings ALTER COLUMN currency DROP DEFAULT;
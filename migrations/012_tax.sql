@@ -0,0 +1,14 @@
+-- Tax rates in basis points (1200 = 12%), the service overriding the tenant;
+-- bookings keep the tax charged, existing ones without tax
+ALTER TABLE tenants
+    ADD COLUMN IF NOT EXISTS tax_rate INTEGER,
+    ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN;
+
+ALTER TABLE services
+    ADD COLUMN IF NOT EXISTS tax_rate INTEGER,
+    ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN;
+
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS tax_amount INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_rate INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,2 @@
+-- Completed bookings of the service within POPULARITY_WINDOW_DAYS, recomputed periodically
+ALTER TABLE services ADD COLUMN IF NOT EXISTS popularity_score INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+-- Unguessable code of public booking links, generated for existing bookings
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS public_code VARCHAR(32) UNIQUE;
+
+UPDATE bookings SET public_code = md5(random()::text || id::text) WHERE public_code IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_public_code ON bookings(public_code);
@@ -0,0 +1,9 @@
+-- Addresses that permanently failed; emails to them are suppressed
+CREATE TABLE IF NOT EXISTS email_bounces (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    reason TEXT,
+    bounce_count INTEGER NOT NULL DEFAULT 1,
+    last_bounced_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,31 @@
+-- Sent notifications with their delivery status
+DO $$ BEGIN
+    CREATE TYPE notificationchannel AS ENUM ('WHATSAPP', 'EMAIL');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    CREATE TYPE notificationstatus AS ENUM ('SENT', 'DELIVERED', 'FAILED', 'SUPPRESSED');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+CREATE TABLE IF NOT EXISTS notifications_sent (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER REFERENCES tenants(id) ON DELETE CASCADE,
+    booking_id INTEGER REFERENCES bookings(id) ON DELETE SET NULL,
+    client_id INTEGER REFERENCES clients(id) ON DELETE SET NULL,
+    channel notificationchannel NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    template VARCHAR(100) NOT NULL,
+    status notificationstatus NOT NULL,
+    provider_message_id VARCHAR(255),
+    error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_sent_tenant_id ON notifications_sent(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_notifications_sent_booking_id ON notifications_sent(booking_id);
+CREATE INDEX IF NOT EXISTS idx_notifications_sent_client_id ON notifications_sent(client_id);
+CREATE INDEX IF NOT EXISTS idx_notifications_sent_provider_message_id ON notifications_sent(provider_message_id);
+CREATE INDEX IF NOT EXISTS idx_notifications_sent_created_at ON notifications_sent(created_at);
@@ -0,0 +1,2 @@
+-- Branding of tenant emails, {"logo_url": ..., "primary_color": "#2e7d32"}
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS branding JSON;
@@ -0,0 +1,13 @@
+-- Tenant overrides of built-in message templates, per language
+CREATE TABLE IF NOT EXISTS message_templates (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    language VARCHAR(5) NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_id, name, language)
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_templates_tenant_id ON message_templates(tenant_id);
@@ -0,0 +1,14 @@
+-- Services of a booking in the order they are performed, a combo booking has several
+CREATE TABLE IF NOT EXISTS booking_items (
+    id SERIAL PRIMARY KEY,
+    booking_id INTEGER NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    service_id INTEGER NOT NULL REFERENCES services(id),
+    position INTEGER NOT NULL DEFAULT 0,
+    duration_minutes INTEGER NOT NULL,
+    price INTEGER NOT NULL,
+    tax_amount INTEGER NOT NULL DEFAULT 0,
+    tax_rate INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_items_booking_id ON booking_items(booking_id);
@@ -0,0 +1,2 @@
+-- Clients per slot, above 1 for group services (classes)
+ALTER TABLE services ADD COLUMN IF NOT EXISTS capacity INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,4 @@
+-- Paused masters stay visible but can't be booked, hidden ones aren't shown publicly
+ALTER TABLE masters
+    ADD COLUMN IF NOT EXISTS is_accepting_bookings BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS is_visible BOOLEAN NOT NULL DEFAULT TRUE;
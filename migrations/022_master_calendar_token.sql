@@ -0,0 +1,4 @@
+-- Secret of the master's calendar feed URL, rotated on demand
+ALTER TABLE masters ADD COLUMN IF NOT EXISTS calendar_token VARCHAR(64) UNIQUE;
+
+CREATE INDEX IF NOT EXISTS idx_masters_calendar_token ON masters(calendar_token);
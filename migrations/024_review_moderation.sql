@@ -0,0 +1,23 @@
+-- Review moderation: tenants may hold new reviews for approval, owners respond to them
+DO $$ BEGIN
+    CREATE TYPE reviewstatus AS ENUM ('PENDING', 'APPROVED', 'REJECTED');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS moderate_reviews BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS status reviewstatus NOT NULL DEFAULT 'APPROVED',
+    ADD COLUMN IF NOT EXISTS owner_response TEXT,
+    ADD COLUMN IF NOT EXISTS responded_at TIMESTAMP;
+
+-- Reviews hidden before moderation existed stay hidden
+DO $$ BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'reviews' AND column_name = 'is_approved') THEN
+        UPDATE reviews SET status = 'REJECTED' WHERE NOT is_approved;
+        ALTER TABLE reviews DROP COLUMN is_approved;
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_reviews_status ON reviews(status);
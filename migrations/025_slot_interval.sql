@@ -0,0 +1,3 @@
+-- Step between offered start times: tenant default (30 minutes if unset), overridden by the service
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS slot_interval_minutes INTEGER;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS slot_interval_minutes INTEGER;
@@ -0,0 +1,2 @@
+-- Set once personal data of the client was deleted on request, bookings are kept for stats
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMP;
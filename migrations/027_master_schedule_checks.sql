@@ -0,0 +1,7 @@
+-- Reject malformed working hours: unknown days and ranges ending before they start
+DO $$ BEGIN
+    ALTER TABLE master_schedules
+        ADD CONSTRAINT ck_master_schedules_day_of_week CHECK (day_of_week BETWEEN 0 AND 6),
+        ADD CONSTRAINT ck_master_schedules_time_range CHECK (start_time < end_time);
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
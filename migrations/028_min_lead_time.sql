@@ -0,0 +1,3 @@
+-- Minimum time between booking and start: tenant default (none if unset), overridden by the service
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS min_lead_minutes INTEGER;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS min_lead_minutes INTEGER;
@@ -0,0 +1,2 @@
+-- Overrides of DEFAULT_TENANT_FEATURES, {"deposits": false}
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS features JSON;
@@ -0,0 +1,16 @@
+-- One-off working hours of a master on a date, e.g. a day off
+CREATE TABLE IF NOT EXISTS master_schedule_overrides (
+    id SERIAL PRIMARY KEY,
+    master_id INTEGER NOT NULL REFERENCES masters(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    is_working BOOLEAN NOT NULL DEFAULT FALSE,
+    start_time TIME,
+    end_time TIME,
+    reason VARCHAR(200),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (master_id, date),
+    CONSTRAINT ck_master_schedule_overrides_time_range CHECK (NOT is_working OR start_time < end_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_master_schedule_overrides_master_id ON master_schedule_overrides(master_id);
@@ -0,0 +1,21 @@
+-- Vacation or other absence of a master over a date range
+DO $$ BEGIN
+    CREATE TYPE timeoffstatus AS ENUM ('PENDING', 'APPROVED', 'REJECTED');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+CREATE TABLE IF NOT EXISTS master_time_off (
+    id SERIAL PRIMARY KEY,
+    master_id INTEGER NOT NULL REFERENCES masters(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason VARCHAR(200),
+    status timeoffstatus NOT NULL DEFAULT 'PENDING',
+    requested_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT ck_master_time_off_date_range CHECK (start_date <= end_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_master_time_off_master_id ON master_time_off(master_id);
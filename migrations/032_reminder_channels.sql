@@ -0,0 +1,3 @@
+-- Reminder channels: tenant overrides of the defaults and the client's choice per booking
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS reminder_channels JSON;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS reminder_channels JSON;
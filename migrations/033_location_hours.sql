@@ -0,0 +1,16 @@
+-- Weekly opening hours of a location, limiting the hours of its masters
+CREATE TABLE IF NOT EXISTS location_hours (
+    id SERIAL PRIMARY KEY,
+    location_id INTEGER NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    day_of_week INTEGER NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    is_open BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (location_id, day_of_week),
+    CONSTRAINT ck_location_hours_day_of_week CHECK (day_of_week BETWEEN 0 AND 6),
+    CONSTRAINT ck_location_hours_time_range CHECK (start_time < end_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_location_hours_location_id ON location_hours(location_id);
@@ -0,0 +1,7 @@
+-- Cancellation fee tiers of the tenant, overridden by the service, and refunds of paid deposits
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS cancellation_policy JSON;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS cancellation_policy JSON;
+
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS refund_amount INTEGER,
+    ADD COLUMN IF NOT EXISTS refunded_at TIMESTAMP;
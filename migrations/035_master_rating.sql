@@ -0,0 +1,4 @@
+-- Average and count of the master's approved reviews, recomputed periodically
+ALTER TABLE masters
+    ADD COLUMN IF NOT EXISTS rating FLOAT,
+    ADD COLUMN IF NOT EXISTS reviews_count INTEGER NOT NULL DEFAULT 0;
@@ -1,12 +1,43 @@
-from fastapi import FastAPI, HTTPException, status
+from fastapi import FastAPI, status
+from fastapi.responses import PlainTextResponse
 from pydantic import BaseModel
-from typing import Optional
+from typing import Optional, Tuple
+from datetime import datetime, timedelta, timezone
+import math
 import httpx
 import logging
 from celery import Celery
+from celery.exceptions import SoftTimeLimitExceeded
+from celery.schedules import crontab
+from sqlalchemy import and_, or_
+from sqlalchemy.orm import Session
 
 from shared.config import settings
-from shared.database import check_db_connection
+from shared.database import (
+    check_db_connection, get_db_context, engine, get_pool_stats, render_prometheus_pool_metrics, run_in_transaction
+)
+from shared.models import (
+    Tenant, TenantStatus, AdminAction, Booking, BookingStatus,
+    NotificationChannel, NotificationStatus
+)
+from shared.email import (
+    email_client, EmailTransientError, EmailPermanentError, is_suppressed, record_bounce,
+    get_email_branding, text_to_html
+)
+from shared.i18n import (
+    translate, render_template, verify_translation_coverage, format_date, format_datetime, format_money
+)
+from shared.billing import get_subscription_status, is_access_blocked
+from shared.analytics import recompute_service_popularity, recompute_master_ratings
+from shared.notifications import record_notification, reminder_channels
+from shared.events import BookingEvent, EventType, event_bus, publish_booking_event
+from shared.calendar import ICS_CONTENT_TYPE, booking_ics, tenant_zone
+from shared.api import APIError, ErrorCode, register_exception_handlers, request_id_middleware, request_id_headers
+from shared.jobs import (
+    connect_job_metrics, connect_job_history, configure_job_queues, configure_reliable_delivery,
+    configure_job_time_limits,
+    get_job_stats, render_prometheus_metrics, retry_delay, job_guard, reminder_dedup_key, job_scheduler
+)
 
 # Configure logging
 logging.basicConfig(
@@ -22,6 +53,10 @@ app = FastAPI(
     version="2.0.0"
 )
 
+# Request ID and standard error envelope
+app.middleware("http")(request_id_middleware)
+register_exception_handlers(app)
+
 # Celery app for background tasks
 celery_app = Celery(
     "notifications",
@@ -29,39 +64,124 @@ celery_app = Celery(
     backend=settings.CELERY_RESULT_BACKEND
 )
 
+# Priority queues, crash-safe delivery, job time limits, job counters and history for monitoring
+configure_job_queues(celery_app)
+configure_reliable_delivery(celery_app)
+configure_job_time_limits(celery_app)
+connect_job_metrics(celery_app)
+connect_job_history(celery_app)
+
+# Periodic tasks (run by celery beat)
+celery_app.conf.beat_schedule = {
+    "promote-scheduled-jobs": {
+        "task": "notifications.promote_scheduled_jobs",
+        "schedule": settings.JOB_SCHEDULER_INTERVAL_SECONDS
+    },
+    "expire-subscriptions": {
+        "task": "notifications.expire_subscriptions",
+        "schedule": crontab(hour=0, minute=5)
+    },
+    "expire-unpaid-bookings": {
+        "task": "notifications.expire_unpaid_bookings",
+        "schedule": crontab(minute="*/5")
+    },
+    "process-trial-expiration": {
+        "task": "notifications.process_trial_expiration",
+        "schedule": crontab(hour=9, minute=0)
+    },
+    "recompute-analytics": {
+        "task": "notifications.recompute_analytics",
+        "schedule": crontab(minute=15)
+    },
+    # More often than cached availability expires, so it stays warm
+    "warm-availability-cache": {
+        "task": "notifications.warm_availability_cache",
+        "schedule": crontab(minute="*/4")
+    },
+    "send-pending-refunds": {
+        "task": "notifications.send_pending_refunds",
+        "schedule": crontab(minute="*/10")
+    }
+}
+
 # WhatsApp service URL
 WHATSAPP_SERVICE_URL = settings.WHATSAPP_SERVICE_URL
 
+# Booking service URL
+BOOKING_SERVICE_URL = f"http://booking-service:{settings.BOOKING_SERVICE_PORT if hasattr(settings, 'BOOKING_SERVICE_PORT') else 8002}"
+
+# Payment service URL
+PAYMENT_SERVICE_URL = f"http://payment-service:{settings.PAYMENT_SERVICE_PORT}"
+
 
 # Request models
 class SendWhatsAppRequest(BaseModel):
     phone: str
     message: str
+    template: str = "custom"
+    booking_id: Optional[int] = None
 
 
 class SendReminderRequest(BaseModel):
     booking_id: int
     phone: str
     message: str
+    hours_before: int = 24
 
 
 @app.on_event("startup")
 async def startup_event():
     """Initialize on startup."""
     logger.info("Starting Notification Service...")
+    verify_translation_coverage()
     if check_db_connection():
         logger.info("Database connection successful")
+    event_bus.start()
 
 
 @app.get("/health")
 async def health_check():
     """Health check endpoint."""
+    db_healthy = check_db_connection()
     return {
-        "status": "healthy",
-        "service": "notification-service"
+        "status": "healthy" if db_healthy else "unhealthy",
+        "service": "notification-service",
+        "database": "connected" if db_healthy else "disconnected"
     }
 
 
+@app.get("/jobs/stats")
+async def job_stats():
+    """
+    Get background job counters and queue depth.
+    """
+    try:
+        return get_job_stats()
+    except Exception as e:
+        logger.error(f"Failed to read job stats: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
+@app.get("/metrics", response_class=PlainTextResponse)
+async def metrics():
+    """
+    Job and database pool metrics in Prometheus text format.
+    """
+    pool_metrics = render_prometheus_pool_metrics(get_pool_stats(engine), "notification-service")
+
+    try:
+        return render_prometheus_metrics(get_job_stats()) + pool_metrics
+    except Exception as e:
+        logger.error(f"Failed to read job stats: {e}")
+        raise APIError(
+            status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+            code=ErrorCode.SERVICE_UNAVAILABLE
+        )
+
+
 @app.post("/send-whatsapp")
 async def send_whatsapp(data: SendWhatsAppRequest):
     """
@@ -71,7 +191,7 @@ async def send_whatsapp(data: SendWhatsAppRequest):
         return {"message": "WhatsApp disabled", "sent": False}
 
     try:
-        async with httpx.AsyncClient() as client:
+        async with httpx.AsyncClient(headers=request_id_headers()) as client:
             response = await client.post(
                 f"{WHATSAPP_SERVICE_URL}/send-message",
                 json={
@@ -82,18 +202,30 @@ async def send_whatsapp(data: SendWhatsAppRequest):
             )
 
             if response.status_code == 200:
+                record_notification(
+                    NotificationChannel.WHATSAPP, data.phone, data.template, NotificationStatus.SENT,
+                    booking_id=data.booking_id, provider_message_id=response.json().get("message_id")
+                )
                 return {"message": "WhatsApp sent", "sent": True}
             else:
-                raise HTTPException(
+                record_notification(
+                    NotificationChannel.WHATSAPP, data.phone, data.template, NotificationStatus.FAILED,
+                    booking_id=data.booking_id, error=response.text
+                )
+                raise APIError(
                     status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-                    detail="Failed to send WhatsApp message"
+                    code=ErrorCode.SERVICE_ERROR
                 )
 
     except httpx.RequestError as e:
         logger.error(f"WhatsApp service error: {e}")
-        raise HTTPException(
+        record_notification(
+            NotificationChannel.WHATSAPP, data.phone, data.template, NotificationStatus.FAILED,
+            booking_id=data.booking_id, error=str(e)
+        )
+        raise APIError(
             status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
-            detail="WhatsApp service unavailable"
+            code=ErrorCode.SERVICE_UNAVAILABLE
         )
 
 
@@ -101,46 +233,585 @@ async def send_whatsapp(data: SendWhatsAppRequest):
 async def schedule_reminder(data: SendReminderRequest):
     """
     Schedule a reminder to be sent later via Celery.
+
+    Each booking gets at most one reminder per hours_before.
     """
+    dedup_key = reminder_dedup_key(data.booking_id, data.hours_before)
+
     try:
+        if not job_guard.claim(dedup_key, settings.REMINDER_DEDUP_TTL_SECONDS):
+            logger.info(f"Reminder already scheduled: {dedup_key}")
+            return {"message": "Reminder already scheduled", "scheduled": False}
+
         # Schedule task
-        send_reminder_task.apply_async(
+        job_scheduler.schedule(
+            send_reminder_task.name,
             args=[data.phone, data.message],
+            kwargs={"booking_id": data.booking_id, "hours_before": data.hours_before},
             countdown=3600  # Send in 1 hour (example)
         )
 
-        return {"message": "Reminder scheduled"}
+        return {"message": "Reminder scheduled", "scheduled": True}
 
     except Exception as e:
+        job_guard.release(dedup_key)
         logger.error(f"Failed to schedule reminder: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-            detail="Failed to schedule reminder"
+            code=ErrorCode.INTERNAL_ERROR
         )
 
 
 # Celery tasks
-@celery_app.task
-def send_reminder_task(phone: str, message: str):
+@celery_app.task(name="notifications.send_reminder", bind=True, max_retries=settings.JOB_RETRY_ATTEMPTS)
+def send_reminder_task(
+    self,
+    phone: str,
+    message: str,
+    booking_id: Optional[int] = None,
+    hours_before: Optional[int] = None
+):
     """
     Celery task to send reminder via WhatsApp.
+
+    Booking reminders go by the channels of reminder_channels, the email
+    is queued as its own task. Retried with exponential backoff and jitter
+    on failure. Booking reminders are skipped if already sent or being
+    sent by another worker.
     """
     import requests
 
+    sent_key = processing_key = None
+
+    if booking_id is not None:
+        dedup_key = reminder_dedup_key(booking_id, hours_before)
+        sent_key = f"{dedup_key}:sent"
+        processing_key = f"{dedup_key}:processing"
+
+        if job_guard.is_claimed(sent_key):
+            logger.info(f"Reminder already sent: {dedup_key}")
+            return
+
+        if not job_guard.claim(processing_key, settings.JOB_PROCESSING_LOCK_SECONDS):
+            logger.info(f"Reminder is being sent by another worker: {dedup_key}")
+            return
+
     try:
+        if booking_id is not None:
+            with get_db_context() as db:
+                booking = db.query(Booking).filter(Booking.id == booking_id).first()
+                channels = reminder_channels(booking, hours_before) if booking else [NotificationChannel.WHATSAPP]
+
+            # Queued on the first attempt only, the email task retries on its own
+            if NotificationChannel.EMAIL in channels and self.request.retries == 0:
+                send_booking_reminder_email_task.delay(booking_id)
+
+            if NotificationChannel.WHATSAPP not in channels:
+                logger.info(f"Reminder {dedup_key} not sent by WhatsApp, channels: {[c.value for c in channels]}")
+                job_guard.claim(sent_key, settings.REMINDER_DEDUP_TTL_SECONDS)
+                return
+
         response = requests.post(
             f"{WHATSAPP_SERVICE_URL}/send-message",
             json={"phone": phone, "message": message},
             timeout=10
         )
+        response.raise_for_status()
+        logger.info(f"Reminder sent to {phone}")
+        record_notification(
+            NotificationChannel.WHATSAPP, phone, "booking_reminder", NotificationStatus.SENT,
+            booking_id=booking_id, provider_message_id=response.json().get("message_id")
+        )
 
-        if response.status_code == 200:
-            logger.info(f"Reminder sent to {phone}")
-        else:
-            logger.error(f"Failed to send reminder: {response.text}")
+        if sent_key:
+            job_guard.claim(sent_key, settings.REMINDER_DEDUP_TTL_SECONDS)
 
-    except Exception as e:
-        logger.error(f"Reminder task error: {e}")
+    except (requests.RequestException, SoftTimeLimitExceeded) as e:
+        if self.request.retries >= self.max_retries:
+            record_notification(
+                NotificationChannel.WHATSAPP, phone, "booking_reminder", NotificationStatus.FAILED,
+                booking_id=booking_id, error=str(e)
+            )
+            raise
+
+        delay = retry_delay(self.request.retries + 1)
+        logger.error(f"Reminder task error (attempt {self.request.retries + 1}), retrying in {delay:.0f}s: {e}")
+        raise self.retry(exc=e, countdown=delay)
+
+    finally:
+        if processing_key:
+            job_guard.release(processing_key)
+
+
+
+@celery_app.task(name="notifications.promote_scheduled_jobs")
+def promote_scheduled_jobs_task():
+    """
+    Celery task to move due scheduled jobs to their queue.
+
+    Runs on one replica at a time, workers only consume ready jobs.
+    """
+    promoted = job_scheduler.promote_due(celery_app)
+    if promoted:
+        logger.info(f"Scheduled jobs promoted: {promoted}")
+    return promoted
+
+
+@celery_app.task(name="notifications.process_trial_expiration")
+def process_trial_expiration_task():
+    """
+    Celery task to warn tenants whose trial ends within TRIAL_WARNING_DAYS.
+
+    Each tenant is warned once.
+    """
+    now = datetime.utcnow()
+
+    with get_db_context() as db:
+        tenant_ids = [
+            tenant_id for (tenant_id,) in db.query(Tenant.id).filter(
+                Tenant.status == TenantStatus.TRIAL,
+                Tenant.trial_end_date > now,
+                Tenant.trial_end_date <= now + timedelta(days=settings.TRIAL_WARNING_DAYS),
+                Tenant.trial_warning_sent_at.is_(None),
+                Tenant.email.isnot(None)
+            )
+        ]
+
+    for tenant_id in tenant_ids:
+        send_trial_expiration_warning_task.delay(tenant_id, now.isoformat())
+
+    logger.info(f"Trial expiration warnings queued: {len(tenant_ids)}")
+    return len(tenant_ids)
+
+
+def deliver_email(
+    task,
+    to: str,
+    subject: str,
+    body: str,
+    template: str,
+    tenant_id: Optional[int] = None,
+    booking_id: Optional[int] = None,
+    **kwargs
+) -> bool:
+    """
+    Send email from a bound Celery task and record it in the notification log.
+
+    Addresses that bounced before are skipped. Permanent failures are
+    recorded as bounces and not retried; transient failures are retried
+    with backoff, and EmailTransientError is raised once retries run out.
+    """
+    log = {"tenant_id": tenant_id, "booking_id": booking_id}
+
+    with get_db_context() as db:
+        if is_suppressed(db, to):
+            logger.info(f"Email to bounced address {to} suppressed")
+            record_notification(NotificationChannel.EMAIL, to, template, NotificationStatus.SUPPRESSED, **log)
+            return False
+
+    try:
+        message_id = email_client.deliver(to, subject, body, **kwargs)
+
+    except EmailPermanentError as e:
+        with get_db_context() as db:
+            record_bounce(db, to, str(e))
+        record_notification(NotificationChannel.EMAIL, to, template, NotificationStatus.FAILED, error=str(e), **log)
+        return False
+
+    except (EmailTransientError, SoftTimeLimitExceeded) as e:
+        if task.request.retries >= task.max_retries:
+            record_notification(NotificationChannel.EMAIL, to, template, NotificationStatus.FAILED, error=str(e), **log)
+            raise
+
+        delay = retry_delay(task.request.retries + 1)
+        logger.error(f"Email to {to} failed (attempt {task.request.retries + 1}), retrying in {delay:.0f}s: {e}")
+        raise task.retry(exc=e, countdown=delay)
+
+    if message_id is None:
+        return False
+
+    record_notification(
+        NotificationChannel.EMAIL, to, template, NotificationStatus.SENT, provider_message_id=message_id, **log
+    )
+    return True
+
+
+@celery_app.task(name="notifications.send_trial_expiration_warning", bind=True, max_retries=settings.JOB_RETRY_ATTEMPTS)
+def send_trial_expiration_warning_task(self, tenant_id: int, now_iso: str):
+    """
+    Celery task to send trial expiration email to the tenant.
+
+    The tenant is marked before the first attempt so concurrent runs never
+    warn twice; the mark is cleared if all attempts fail so the next run
+    retries.
+    """
+    now = datetime.fromisoformat(now_iso)
+
+    with get_db_context() as db:
+        if self.request.retries == 0:
+            claimed = db.query(Tenant).filter(
+                Tenant.id == tenant_id,
+                Tenant.trial_warning_sent_at.is_(None)
+            ).update({Tenant.trial_warning_sent_at: now}, synchronize_session=False)
+            db.commit()
+
+            if not claimed:
+                return False
+
+        tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+        email = tenant.email
+        language = tenant.language or settings.DEFAULT_LANGUAGE
+        params = {
+            "business_name": tenant.business_name,
+            "days": max(math.ceil((tenant.trial_end_date - now).total_seconds() / 86400), 1),
+            # trial_end_date is naive UTC
+            "trial_end_date": format_date(
+                tenant.trial_end_date.replace(tzinfo=timezone.utc).astimezone(tenant_zone(tenant)),
+                language
+            ),
+            "upgrade_url": settings.TRIAL_UPGRADE_URL
+        }
+        branding = get_email_branding(tenant)
+
+    body = translate("trial_expiration_body", language, **params)
+
+    try:
+        return deliver_email(
+            self,
+            email,
+            translate("trial_expiration_subject", language, **params),
+            body,
+            "trial_expiration",
+            tenant_id=tenant_id,
+            html_body=text_to_html(body, branding)
+        )
+    except (EmailTransientError, SoftTimeLimitExceeded):
+        with get_db_context() as db:
+            db.query(Tenant).filter(Tenant.id == tenant_id).update(
+                {Tenant.trial_warning_sent_at: None}, synchronize_session=False
+            )
+        return False
+
+
+
+# Every notification-service replica receives each event, only one handles it
+EVENT_DEDUP_TTL_SECONDS = 86400
+
+
+def claim_event(event: dict) -> bool:
+    """Claim event for handling by this replica."""
+    return job_guard.claim(f"event:{event['id']}", EVENT_DEDUP_TTL_SECONDS)
+
+
+@event_bus.subscribe(EventType.TENANT_APPROVED)
+@event_bus.subscribe(EventType.TENANT_REJECTED)
+def on_tenant_status_changed(event: dict):
+    """Email the tenant about the decision on its application."""
+    if not claim_event(event):
+        return
+
+    send_tenant_status_email_task.delay(
+        event["data"]["tenant_id"],
+        "approved" if event["type"] == EventType.TENANT_APPROVED.value else "rejected"
+    )
+
+
+@event_bus.subscribe(EventType.TENANT_DECISION_EMAIL_REQUESTED)
+def on_tenant_decision_email_requested(event: dict):
+    """Resend the decision email, e.g. when the owner never got it."""
+    if not claim_event(event):
+        return
+
+    send_tenant_status_email_task.delay(event["data"]["tenant_id"], event["data"]["decision"])
+
+
+@celery_app.task(name="notifications.send_tenant_status_email", bind=True, max_retries=settings.JOB_RETRY_ATTEMPTS)
+def send_tenant_status_email_task(self, tenant_id: int, decision: str):
+    """
+    Celery task to email the tenant that its application was approved
+    or rejected.
+    """
+    with get_db_context() as db:
+        tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+
+        if not tenant or not tenant.email:
+            logger.warning(f"Tenant {tenant_id} has no email, status email skipped")
+            return False
+
+        email = tenant.email
+        language = tenant.language or settings.DEFAULT_LANGUAGE
+        params = {
+            "business_name": tenant.business_name,
+            "business_url": f"https://{tenant.subdomain}.{settings.BASE_DOMAIN}"
+        }
+        branding = get_email_branding(tenant)
+
+    body = translate(f"tenant_{decision}_body", language, **params)
+
+    return deliver_email(
+        self,
+        email,
+        translate(f"tenant_{decision}_subject", language, **params),
+        body,
+        f"tenant_{decision}",
+        tenant_id=tenant_id,
+        html_body=text_to_html(body, branding)
+    )
+
+
+@event_bus.subscribe(EventType.BOOKING_CREATED)
+@event_bus.subscribe(EventType.BOOKING_CONFIRMED)
+@event_bus.subscribe(EventType.BOOKING_CONFIRMATION_REQUESTED)
+def on_booking_confirmed(event: dict):
+    """
+    Email confirmed bookings to the client with a calendar file, again
+    when the client asks for the confirmation to be resent.
+    """
+    if event["data"].get("status") != BookingStatus.CONFIRMED.value or not claim_event(event):
+        return
+
+    send_booking_confirmation_email_task.delay(event["data"]["booking_id"])
+
+
+@celery_app.task(name="notifications.send_booking_confirmation_email", bind=True, max_retries=settings.JOB_RETRY_ATTEMPTS)
+def send_booking_confirmation_email_task(self, booking_id: int):
+    """
+    Celery task to email booking confirmation with an .ics attachment.
+
+    Skipped for clients without email.
+    """
+    with get_db_context() as db:
+        booking = db.query(Booking).filter(Booking.id == booking_id).first()
+
+        if not booking or not booking.client or not booking.client.email:
+            return False
+
+        tenant = booking.tenant
+        email = booking.client.email
+        language = tenant.language or settings.DEFAULT_LANGUAGE
+        params = {
+            "business_name": tenant.business_name,
+            "service_name": booking.service_names,
+            "master_name": booking.master.full_name if booking.master else "",
+            "booking_date": format_datetime(booking.booking_date, language),
+            "price": format_money(booking.price, booking.currency, language)
+        }
+        attachment = (f"booking-{booking.id}.ics", booking_ics(booking), ICS_CONTENT_TYPE)
+        branding = get_email_branding(tenant)
+        subject = render_template(db, tenant.id, "booking_confirmation_subject", language, **params)
+        body = render_template(db, tenant.id, "booking_confirmation_body", language, **params)
+
+    return deliver_email(
+        self,
+        email,
+        subject,
+        body,
+        "booking_confirmation",
+        booking_id=booking_id,
+        html_body=text_to_html(body, branding),
+        attachments=[attachment]
+    )
+
+
+@celery_app.task(name="notifications.send_booking_reminder_email", bind=True, max_retries=settings.JOB_RETRY_ATTEMPTS)
+def send_booking_reminder_email_task(self, booking_id: int):
+    """
+    Celery task to email booking reminder to the client.
+
+    Skipped for clients without email and bookings no longer confirmed.
+    """
+    with get_db_context() as db:
+        booking = db.query(Booking).filter(Booking.id == booking_id).first()
+
+        if not booking or booking.status != BookingStatus.CONFIRMED or not booking.client or not booking.client.email:
+            return False
+
+        tenant = booking.tenant
+        email = booking.client.email
+        language = tenant.language or settings.DEFAULT_LANGUAGE
+        params = {
+            "business_name": tenant.business_name,
+            "service_name": booking.service_names,
+            "master_name": booking.master.full_name if booking.master else "",
+            "booking_date": format_datetime(booking.booking_date, language),
+            "price": format_money(booking.price, booking.currency, language)
+        }
+        branding = get_email_branding(tenant)
+
+    body = translate("booking_reminder_body", language, **params)
+
+    return deliver_email(
+        self,
+        email,
+        translate("booking_reminder_subject", language, **params),
+        body,
+        "booking_reminder",
+        booking_id=booking_id,
+        html_body=text_to_html(body, branding)
+    )
+
+
+@celery_app.task(name="notifications.expire_subscriptions")
+def expire_subscriptions_task():
+    """
+    Celery task to move tenants whose trial or paid subscription has ended
+    to EXPIRED.
+
+    Tenants approved before paying run on their trial too. Staff of
+    expired tenants can no longer log in.
+    """
+    now = datetime.utcnow()
+    expired = 0
+
+    with get_db_context() as db:
+        tenants = db.query(Tenant).filter(
+            Tenant.status.in_([TenantStatus.TRIAL, TenantStatus.ACTIVE]),
+            or_(
+                Tenant.subscription_end_date <= now,
+                and_(
+                    Tenant.subscription_end_date.is_(None),
+                    Tenant.trial_end_date <= now
+                )
+            )
+        ).with_for_update(skip_locked=True).all()
+
+        for tenant in tenants:
+            if not is_access_blocked(tenant, now):
+                continue
+
+            subscription = get_subscription_status(tenant, now)
+            tenant.status = TenantStatus.EXPIRED
+            db.add(AdminAction(
+                action_type="TRIAL_EXPIRED" if subscription["plan"] == "trial" else "SUBSCRIPTION_EXPIRED",
+                target_type="tenant",
+                target_id=tenant.id,
+                details=f"Ended at {subscription['ends_at']}"
+            ))
+            expired += 1
+            logger.info(f"Tenant expired: ID={tenant.id}, plan={subscription['plan']}")
+
+    logger.info(f"Tenants expired: {expired}")
+    return expired
+
+
+
+@celery_app.task(name="notifications.expire_unpaid_bookings")
+def expire_unpaid_bookings_task():
+    """
+    Celery task to cancel pending bookings whose deposit was not paid in time.
+
+    Frees the time slot of the booking. The deposit payment stays pending,
+    the client may still complete it, and is then refunded by the payment
+    service.
+    """
+    now = datetime.utcnow()
+
+    with get_db_context() as db:
+        bookings = db.query(Booking).filter(
+            Booking.status == BookingStatus.PENDING,
+            Booking.payment_due_at <= now
+        ).with_for_update(skip_locked=True).all()
+
+        for booking in bookings:
+            booking.status = BookingStatus.CANCELLED
+            logger.info(f"Unpaid booking cancelled: ID={booking.id}")
+
+        expired = len(bookings)
+        db.commit()
+
+        for booking in bookings:
+            publish_booking_event(booking, BookingEvent.CANCELLED)
+
+    logger.info(f"Unpaid bookings cancelled: {expired}")
+    return expired
+
+
+@celery_app.task(name="notifications.recompute_analytics")
+def recompute_analytics_task():
+    """
+    Celery task to recompute service popularity from completed bookings
+    and master ratings from approved reviews.
+
+    Tenants are processed in batches of ANALYTICS_BATCH_TENANTS, each in
+    its own transaction retried on serialization failures. Values are
+    recomputed from scratch by single UPDATEs, so the task is idempotent,
+    overlapping runs agree and bookings made meanwhile are counted by the
+    next run at the latest.
+    """
+    with get_db_context() as db:
+        tenant_ids = [tenant_id for (tenant_id,) in db.query(Tenant.id).order_by(Tenant.id).all()]
+
+    services = masters = 0
+    batch_size = settings.ANALYTICS_BATCH_TENANTS
+
+    for start in range(0, len(tenant_ids), batch_size):
+        batch = tenant_ids[start:start + batch_size]
+
+        def recompute(db: Session) -> Tuple[int, int]:
+            return recompute_service_popularity(db, batch), recompute_master_ratings(db, tenant_ids=batch)
+
+        with get_db_context() as db:
+            updated = run_in_transaction(db, recompute)
+
+        services += updated[0]
+        masters += updated[1]
+
+    logger.info(f"Analytics recomputed: {services} services, {masters} masters")
+    return {"services": services, "masters": masters}
+
+
+@celery_app.task(name="notifications.warm_availability_cache")
+def warm_availability_cache_task(tenant_id: Optional[int] = None):
+    """
+    Celery task to pre-warm availability cache for the next AVAILABILITY_WARM_DAYS days.
+
+    Warms every active or trial tenant, or only tenant_id. Each tenant is
+    warmed by the booking service separately, so a failing tenant doesn't
+    stop the others.
+    """
+    import requests
+
+    with get_db_context() as db:
+        query = db.query(Tenant.id).filter(Tenant.status.in_([TenantStatus.ACTIVE, TenantStatus.TRIAL]))
+        if tenant_id is not None:
+            query = query.filter(Tenant.id == tenant_id)
+        tenant_ids = [row.id for row in query.all()]
+
+    warmed = 0
+    for tid in tenant_ids:
+        try:
+            response = requests.post(
+                f"{BOOKING_SERVICE_URL}/availability/warm",
+                params={"tenant_id": tid, "days": settings.AVAILABILITY_WARM_DAYS},
+                timeout=60
+            )
+            response.raise_for_status()
+            warmed += 1
+        except requests.RequestException as e:
+            logger.error(f"Availability warm-up failed for tenant {tid}: {e}")
+
+    logger.info(f"Availability cache warmed: {warmed}/{len(tenant_ids)} tenants")
+    return warmed
+
+
+@celery_app.task(name="notifications.send_pending_refunds")
+def send_pending_refunds_task():
+    """
+    Celery task to retry refunds of cancelled bookings the provider has
+    not accepted yet, e.g. when it was down at cancellation.
+    """
+    import requests
+
+    try:
+        response = requests.post(f"{PAYMENT_SERVICE_URL}/refunds", timeout=120)
+        response.raise_for_status()
+    except requests.RequestException as e:
+        logger.error(f"Sending pending refunds failed: {e}")
+        return 0
+
+    result = response.json()
+    logger.info(f"Pending refunds sent: {result['sent']}/{result['pending']}")
+    return result["sent"]
 
 
 if __name__ == "__main__":
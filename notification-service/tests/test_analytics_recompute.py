@@ -0,0 +1,33 @@
+from datetime import datetime, timedelta
+
+from shared.config import settings
+from shared.models import BookingStatus, Review
+
+
+def test_recompute_matches_seeded_data(service, db, factory, monkeypatch):
+    monkeypatch.setattr(settings, "ANALYTICS_BATCH_TENANTS", 1)
+    seeded = []
+    for ratings in [(5, 3), (4,)]:
+        tenant = factory.tenant()
+        offered = factory.service(tenant, popularity_score=99)
+        master = factory.master(tenant, [offered])
+        for rating in ratings:
+            booking = factory.booking(
+                tenant, master, offered, factory.client(), status=BookingStatus.COMPLETED,
+                booking_date=datetime.utcnow() - timedelta(days=1)
+            )
+            factory.add(Review(
+                tenant_id=tenant.id, booking_id=booking.id, client_id=booking.client_id, master_id=master.id,
+                rating=rating
+            ))
+        seeded.append((offered, master, ratings))
+
+    first = service.recompute_analytics_task()
+    second = service.recompute_analytics_task()
+
+    assert first == second
+    db.expire_all()
+    for offered, master, ratings in seeded:
+        assert offered.popularity_score == len(ratings)
+        assert master.rating == sum(ratings) / len(ratings)
+        assert master.reviews_count == len(ratings)
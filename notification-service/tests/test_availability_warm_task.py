@@ -0,0 +1,56 @@
+from types import SimpleNamespace
+
+import pytest
+import requests
+
+from shared.models import TenantStatus
+
+
+@pytest.fixture
+def warmed(monkeypatch):
+    """Tenant IDs the task asks the booking service to warm; warming state["fail"] errors."""
+    calls = []
+    state = {"fail": None}
+
+    def post(url, params, timeout):
+        calls.append(params["tenant_id"])
+        if params["tenant_id"] == state["fail"]:
+            raise requests.ConnectionError("booking service down")
+        return SimpleNamespace(raise_for_status=lambda: None)
+
+    monkeypatch.setattr(requests, "post", post)
+    return calls, state
+
+
+def test_active_and_trial_tenants_are_warmed(service, factory, warmed):
+    calls, _ = warmed
+    active = factory.tenant()
+    trial = factory.tenant(status=TenantStatus.TRIAL)
+    suspended = factory.tenant(status=TenantStatus.SUSPENDED)
+
+    service.warm_availability_cache_task()
+
+    assert active.id in calls
+    assert trial.id in calls
+    assert suspended.id not in calls
+
+
+def test_single_tenant_is_warmed(service, factory, warmed):
+    calls, _ = warmed
+    tenant = factory.tenant()
+    factory.tenant()
+
+    assert service.warm_availability_cache_task(tenant.id) == 1
+    assert calls == [tenant.id]
+
+
+def test_failing_tenant_does_not_stop_others(service, factory, warmed):
+    calls, state = warmed
+    failing = factory.tenant()
+    tenant = factory.tenant()
+    state["fail"] = failing.id
+
+    warmed_count = service.warm_availability_cache_task()
+
+    assert tenant.id in calls
+    assert warmed_count == len(calls) - 1
@@ -0,0 +1,37 @@
+import pytest
+
+from shared.email import DEFAULT_PRIMARY_COLOR
+
+
+@pytest.fixture
+def sent_emails(service, monkeypatch):
+    emails = []
+
+    def deliver(to, subject, body, **kwargs):
+        emails.append({"to": to, "subject": subject, "body": body, **kwargs})
+        return f"msg-{len(emails)}"
+
+    monkeypatch.setattr(service.email_client, "deliver", deliver)
+    return emails
+
+
+def test_booking_confirmation_uses_tenant_color(service, db, factory, sent_emails):
+    tenant = factory.tenant(branding={"primary_color": "#ff0066"})
+    offered = factory.service(tenant)
+    master = factory.master(tenant, [offered])
+    booking = factory.booking(tenant, master, offered, factory.client(email="client@example.com"))
+
+    assert service.send_booking_confirmation_email_task(booking.id) is True
+
+    email, = sent_emails
+    assert "background: #ff0066" in email["html_body"]
+    assert tenant.business_name in email["html_body"]
+
+
+def test_booking_confirmation_without_branding_uses_default_color(service, db, factory, sent_emails):
+    tenant, offered, master = factory.salon()
+    booking = factory.booking(tenant, master, offered, factory.client(email="client@example.com"))
+
+    service.send_booking_confirmation_email_task(booking.id)
+
+    assert f"background: {DEFAULT_PRIMARY_COLOR}" in sent_emails[0]["html_body"]
@@ -0,0 +1,80 @@
+from types import SimpleNamespace
+
+import pytest
+
+from shared.email import EmailPermanentError, EmailTransientError, is_suppressed, record_bounce
+from shared.models import EmailBounce
+
+
+class Retry(Exception):
+    pass
+
+
+def task(retries=0, max_retries=3):
+    """Bound Celery task on its given attempt, retry records the countdown."""
+    def retry(exc, countdown):
+        return Retry(exc, countdown)
+
+    return SimpleNamespace(request=SimpleNamespace(retries=retries), max_retries=max_retries, retry=retry)
+
+
+def send(service, bound_task, to="client@example.com"):
+    return service.deliver_email(bound_task, to, "Subject", "Body", "booking_confirmation")
+
+
+@pytest.fixture
+def attempts(service, monkeypatch):
+    """Outcomes of the delivery attempts in order, exceptions are raised."""
+    outcomes = []
+    calls = []
+
+    def deliver(to, subject, body, **kwargs):
+        calls.append(to)
+        outcome = outcomes.pop(0)
+        if isinstance(outcome, Exception):
+            raise outcome
+        return outcome
+
+    monkeypatch.setattr(service.email_client, "deliver", deliver)
+    return SimpleNamespace(outcomes=outcomes, calls=calls)
+
+
+def test_transient_failure_succeeds_on_retry(service, db, attempts):
+    attempts.outcomes += [EmailTransientError("421 try later"), "msg-1"]
+
+    with pytest.raises(Retry) as retry:
+        send(service, task(retries=0))
+    assert retry.value.args[1] > 0
+
+    assert send(service, task(retries=1)) is True
+    assert attempts.calls == ["client@example.com"] * 2
+    assert not is_suppressed(db, "client@example.com")
+
+
+def test_transient_failure_is_raised_when_retries_run_out(service, attempts):
+    attempts.outcomes.append(EmailTransientError("421 try later"))
+
+    with pytest.raises(EmailTransientError):
+        send(service, task(retries=3, max_retries=3))
+
+
+def test_permanent_failure_is_recorded_and_not_retried(service, db, attempts):
+    attempts.outcomes.append(EmailPermanentError("550 no such user"))
+
+    assert send(service, task(), to="Client@Example.com") is False
+
+    bounce = db.query(EmailBounce).filter(EmailBounce.email == "client@example.com").one()
+    assert "550" in bounce.reason
+    assert send(service, task()) is False
+    assert attempts.calls == ["Client@Example.com"]
+
+
+def test_repeated_bounce_is_counted(db):
+    record_bounce(db, "client@example.com", "550 no such user")
+    db.flush()
+
+    bounce = record_bounce(db, " CLIENT@example.com ", "551 moved")
+
+    assert bounce.bounce_count == 2
+    assert bounce.reason == "551 moved"
+    assert db.query(EmailBounce).count() == 1
@@ -0,0 +1,49 @@
+from types import SimpleNamespace
+
+import pytest
+from celery.exceptions import SoftTimeLimitExceeded
+
+from shared.config import settings
+from shared.models import NotificationLog, NotificationStatus
+
+
+class Retry(Exception):
+    pass
+
+
+def task(retries=0, max_retries=3):
+    """Bound Celery task on its given attempt, retry records the countdown."""
+    def retry(exc, countdown):
+        return Retry(exc, countdown)
+
+    return SimpleNamespace(request=SimpleNamespace(retries=retries), max_retries=max_retries, retry=retry)
+
+
+@pytest.fixture
+def hung_send(service, monkeypatch):
+    """Email delivery interrupted by the soft time limit."""
+    def deliver(to, subject, body, **kwargs):
+        raise SoftTimeLimitExceeded()
+
+    monkeypatch.setattr(service.email_client, "deliver", deliver)
+
+
+def test_worker_has_time_limits(service):
+    assert service.celery_app.conf.task_soft_time_limit == settings.JOB_SOFT_TIME_LIMIT_SECONDS
+    assert service.celery_app.conf.task_time_limit == settings.JOB_TIME_LIMIT_SECONDS
+
+
+def test_interrupted_send_is_retried(service, db, hung_send):
+    with pytest.raises(Retry) as retry:
+        service.deliver_email(task(), "client@example.com", "Subject", "Body", "booking_confirmation")
+
+    assert isinstance(retry.value.args[0], SoftTimeLimitExceeded)
+    assert retry.value.args[1] > 0
+    assert db.query(NotificationLog).count() == 0
+
+
+def test_interrupted_last_attempt_fails(service, db, hung_send):
+    with pytest.raises(SoftTimeLimitExceeded):
+        service.deliver_email(task(retries=3), "client@example.com", "Subject", "Body", "booking_confirmation")
+
+    assert db.query(NotificationLog).one().status == NotificationStatus.FAILED
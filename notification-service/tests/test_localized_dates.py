@@ -0,0 +1,33 @@
+from datetime import datetime
+
+import pytest
+
+
+@pytest.fixture
+def sent_emails(service, monkeypatch):
+    emails = []
+
+    def deliver(to, subject, body, **kwargs):
+        emails.append({"to": to, "subject": subject, "body": body})
+        return f"msg-{len(emails)}"
+
+    monkeypatch.setattr(service.email_client, "deliver", deliver)
+    return emails
+
+
+@pytest.mark.parametrize("language, expected", [
+    ("ru", "15 января 2030, 14:30"),
+    ("en", "January 15, 2030, 14:30"),
+    ("kk", "2030 жылғы 15 қаңтар, 14:30")
+])
+def test_confirmation_date_is_in_tenant_language(service, factory, sent_emails, language, expected):
+    tenant = factory.tenant(language=language, timezone="Asia/Almaty")
+    offered = factory.service(tenant)
+    booking = factory.booking(
+        tenant, factory.master(tenant, [offered]), offered, factory.client(email="client@example.com"),
+        booking_date=datetime(2030, 1, 15, 14, 30)
+    )
+
+    service.send_booking_confirmation_email_task(booking.id)
+
+    assert expected in sent_emails[0]["body"]
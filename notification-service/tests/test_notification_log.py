@@ -0,0 +1,71 @@
+from types import SimpleNamespace
+
+import pytest
+
+from shared.email import EmailPermanentError, record_bounce
+from shared.models import NotificationChannel, NotificationLog, NotificationStatus
+
+
+def task():
+    return SimpleNamespace(request=SimpleNamespace(retries=0), max_retries=3)
+
+
+def send(service, tenant):
+    return service.deliver_email(task(), tenant.email, "Subject", "Body", "trial_expiration", tenant_id=tenant.id)
+
+
+@pytest.fixture
+def outcome(service, monkeypatch):
+    """Result of delivery: Message-ID, or an exception to raise."""
+    state = {"result": "msg-1"}
+
+    def deliver(to, subject, body, **kwargs):
+        if isinstance(state["result"], Exception):
+            raise state["result"]
+        return state["result"]
+
+    monkeypatch.setattr(service.email_client, "deliver", deliver)
+    return state
+
+
+def test_sent_email_is_logged(service, db, factory, outcome):
+    tenant = factory.tenant()
+
+    assert send(service, tenant) is True
+
+    log = db.query(NotificationLog).one()
+    assert log.channel == NotificationChannel.EMAIL
+    assert log.status == NotificationStatus.SENT
+    assert log.template == "trial_expiration"
+    assert log.recipient == tenant.email
+    assert log.tenant_id == tenant.id
+    assert log.provider_message_id == "msg-1"
+
+
+def test_rejected_email_is_logged(service, db, factory, outcome):
+    outcome["result"] = EmailPermanentError("550 no such user")
+    tenant = factory.tenant()
+
+    send(service, tenant)
+
+    log = db.query(NotificationLog).one()
+    assert log.status == NotificationStatus.FAILED
+    assert "550" in log.error
+
+
+def test_suppressed_email_is_logged(service, db, factory, outcome):
+    tenant = factory.tenant()
+    record_bounce(db, tenant.email, "550 no such user")
+    db.flush()
+
+    assert send(service, tenant) is False
+
+    assert db.query(NotificationLog).one().status == NotificationStatus.SUPPRESSED
+
+
+def test_disabled_email_is_not_logged(service, db, factory, outcome):
+    outcome["result"] = None
+
+    assert send(service, factory.tenant()) is False
+
+    assert db.query(NotificationLog).count() == 0
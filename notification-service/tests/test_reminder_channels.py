@@ -0,0 +1,56 @@
+from types import SimpleNamespace
+
+import pytest
+import requests
+
+from shared.config import settings
+from shared.jobs import JobGuard
+
+
+@pytest.fixture
+def sent(service, broker_url, monkeypatch):
+    """Reminder emails queued and WhatsApp messages posted."""
+    sent = SimpleNamespace(emails=[], whatsapp=[])
+
+    def post(url, json, timeout):
+        sent.whatsapp.append(json["phone"])
+        return SimpleNamespace(raise_for_status=lambda: None, json=lambda: {"message_id": "m1"})
+
+    monkeypatch.setattr(service, "job_guard", JobGuard(broker_url))
+    monkeypatch.setattr(service.send_booking_reminder_email_task, "delay", sent.emails.append)
+    monkeypatch.setattr(service, "record_notification", lambda *args, **kwargs: None)
+    monkeypatch.setattr(requests, "post", post)
+    monkeypatch.setattr(settings, "REMINDER_SAME_DAY_CHANNELS", "WHATSAPP")
+    monkeypatch.setattr(settings, "REMINDER_ADVANCE_CHANNELS", "EMAIL")
+    return sent
+
+
+@pytest.fixture
+def booking(factory):
+    tenant = factory.tenant()
+    service = factory.service(tenant)
+    customer = factory.client(phone="+77011111111", email="aida@example.com")
+    return factory.booking(tenant, factory.master(tenant, [service]), service, customer)
+
+
+def test_advance_reminder_by_email(service, booking, sent):
+    service.send_reminder_task("+77011111111", "See you", booking_id=booking.id, hours_before=24)
+
+    assert sent.emails == [booking.id]
+    assert sent.whatsapp == []
+
+
+def test_same_day_reminder_by_whatsapp(service, booking, sent):
+    service.send_reminder_task("+77011111111", "See you", booking_id=booking.id, hours_before=2)
+
+    assert sent.emails == []
+    assert sent.whatsapp == ["+77011111111"]
+
+
+def test_client_without_reminders(service, db, booking, sent):
+    booking.reminder_channels = []
+    db.flush()
+
+    service.send_reminder_task("+77011111111", "See you", booking_id=booking.id, hours_before=2)
+
+    assert sent.emails == sent.whatsapp == []
@@ -0,0 +1,82 @@
+import pytest
+import requests
+
+from shared.jobs import JobGuard, reminder_dedup_key
+
+
+@pytest.fixture
+def guard(service, broker_url, monkeypatch):
+    guard = JobGuard(broker_url)
+    monkeypatch.setattr(service, "job_guard", guard)
+    return guard
+
+
+@pytest.fixture
+def scheduled(service, monkeypatch):
+    """Reminder jobs scheduled by the service."""
+    jobs = []
+    monkeypatch.setattr(service.job_scheduler, "schedule", lambda task_name, **kwargs: jobs.append(kwargs))
+    return jobs
+
+
+def test_guard_claims_key_once(guard):
+    assert guard.claim("reminder:1:24", 60)
+    assert not guard.claim("reminder:1:24", 60)
+    assert guard.is_claimed("reminder:1:24")
+
+    guard.release("reminder:1:24")
+
+    assert not guard.is_claimed("reminder:1:24")
+
+
+def test_reminder_scheduled_once_per_booking(client, guard, scheduled):
+    reminder = {"booking_id": 7, "phone": "+77010000000", "message": "See you tomorrow"}
+
+    first = client.post("/schedule-reminder", json=reminder)
+    second = client.post("/schedule-reminder", json=reminder)
+
+    assert first.json()["scheduled"] is True
+    assert second.json()["scheduled"] is False
+    assert len(scheduled) == 1
+    assert scheduled[0]["kwargs"] == {"booking_id": 7, "hours_before": 24}
+
+
+def test_reminders_of_other_hours_are_separate(client, guard, scheduled):
+    client.post("/schedule-reminder", json={"booking_id": 7, "phone": "+77010000000", "message": "x"})
+    client.post("/schedule-reminder", json={"booking_id": 7, "phone": "+77010000000", "message": "x", "hours_before": 2})
+
+    assert len(scheduled) == 2
+
+
+def test_failed_scheduling_releases_claim(client, guard, service, monkeypatch):
+    def fail(task_name, **kwargs):
+        raise ConnectionError("broker down")
+
+    monkeypatch.setattr(service.job_scheduler, "schedule", fail)
+
+    response = client.post("/schedule-reminder", json={"booking_id": 8, "phone": "+77010000000", "message": "x"})
+
+    assert response.status_code == 500
+    assert not guard.is_claimed(reminder_dedup_key(8, 24))
+
+
+def test_sent_reminder_is_not_sent_again(service, guard, monkeypatch):
+    def post(*args, **kwargs):
+        raise AssertionError("reminder sent twice")
+
+    monkeypatch.setattr(requests, "post", post)
+    guard.claim(f"{reminder_dedup_key(9, 24)}:sent", 60)
+
+    service.send_reminder_task("+77010000000", "See you", booking_id=9, hours_before=24)
+
+
+def test_reminder_being_sent_by_other_worker_is_skipped(service, guard, monkeypatch):
+    def post(*args, **kwargs):
+        raise AssertionError("reminder sent concurrently")
+
+    monkeypatch.setattr(requests, "post", post)
+    guard.claim(f"{reminder_dedup_key(9, 24)}:processing", 60)
+
+    service.send_reminder_task("+77010000000", "See you", booking_id=9, hours_before=24)
+
+    assert guard.is_claimed(f"{reminder_dedup_key(9, 24)}:processing")
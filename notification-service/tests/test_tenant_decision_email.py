@@ -0,0 +1,44 @@
+import pytest
+
+from shared.events import EventType
+from shared.i18n import translate
+from shared.jobs import JobGuard
+
+
+@pytest.fixture
+def sent_emails(service, monkeypatch):
+    emails = []
+
+    def deliver(to, subject, body, **kwargs):
+        emails.append({"to": to, "subject": subject})
+        return f"msg-{len(emails)}"
+
+    monkeypatch.setattr(service.email_client, "deliver", deliver)
+    return emails
+
+
+@pytest.mark.parametrize("decision", ["approved", "rejected"])
+def test_decision_email_template(service, factory, sent_emails, decision):
+    tenant = factory.tenant(email="owner@example.com", language="en")
+
+    service.send_tenant_status_email_task(tenant.id, decision)
+
+    email, = sent_emails
+    assert email["to"] == "owner@example.com"
+    assert email["subject"] == translate(f"tenant_{decision}_subject", "en", business_name=tenant.business_name)
+
+
+def test_resend_event_queues_decision_email(service, broker_url, monkeypatch):
+    queued = []
+    monkeypatch.setattr(service, "job_guard", JobGuard(broker_url))
+    monkeypatch.setattr(service.send_tenant_status_email_task, "delay", lambda *args: queued.append(args))
+    event = {
+        "id": "evt-1",
+        "type": EventType.TENANT_DECISION_EMAIL_REQUESTED.value,
+        "data": {"tenant_id": 5, "decision": "rejected"}
+    }
+
+    service.on_tenant_decision_email_requested(event)
+    service.on_tenant_decision_email_requested(event)
+
+    assert queued == [(5, "rejected")]
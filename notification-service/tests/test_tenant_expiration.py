@@ -0,0 +1,54 @@
+from datetime import datetime, timedelta
+
+from shared.models import AdminAction, TenantStatus
+
+
+def test_lapsed_trial_is_expired(service, db, factory):
+    tenant = factory.tenant(status=TenantStatus.TRIAL, trial_end_date=datetime.utcnow() - timedelta(hours=1))
+
+    assert service.expire_subscriptions_task() == 1
+
+    db.expire_all()
+    assert tenant.status == TenantStatus.EXPIRED
+    action = db.query(AdminAction).filter(AdminAction.target_id == tenant.id).one()
+    assert action.action_type == "TRIAL_EXPIRED"
+
+
+def test_running_trial_is_kept(service, db, factory):
+    tenant = factory.tenant(status=TenantStatus.TRIAL, trial_end_date=datetime.utcnow() + timedelta(days=1))
+
+    service.expire_subscriptions_task()
+
+    db.expire_all()
+    assert tenant.status == TenantStatus.TRIAL
+
+
+def test_lapsed_subscription_is_expired(service, db, factory):
+    tenant = factory.tenant(subscription_end_date=datetime.utcnow() - timedelta(minutes=1))
+
+    service.expire_subscriptions_task()
+
+    db.expire_all()
+    assert tenant.status == TenantStatus.EXPIRED
+    action = db.query(AdminAction).filter(AdminAction.target_id == tenant.id).one()
+    assert action.action_type == "SUBSCRIPTION_EXPIRED"
+
+
+def test_approved_tenant_with_lapsed_trial_is_expired(service, db, factory):
+    tenant = factory.tenant(trial_end_date=datetime.utcnow() - timedelta(days=1))
+
+    assert service.expire_subscriptions_task() == 1
+
+    db.expire_all()
+    assert tenant.status == TenantStatus.EXPIRED
+    action = db.query(AdminAction).filter(AdminAction.target_id == tenant.id).one()
+    assert action.action_type == "TRIAL_EXPIRED"
+
+
+def test_approved_tenant_within_trial_is_kept(service, db, factory):
+    tenant = factory.tenant(trial_end_date=datetime.utcnow() + timedelta(days=1))
+
+    service.expire_subscriptions_task()
+
+    db.expire_all()
+    assert tenant.status == TenantStatus.ACTIVE
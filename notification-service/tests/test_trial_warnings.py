@@ -0,0 +1,73 @@
+from datetime import datetime, timedelta
+
+import pytest
+
+from shared.email import EmailTransientError
+from shared.models import TenantStatus
+
+
+@pytest.fixture
+def queued(service, monkeypatch):
+    """Tenant IDs of queued warning jobs."""
+    tenant_ids = []
+    monkeypatch.setattr(
+        service.send_trial_expiration_warning_task, "delay", lambda tenant_id, now_iso: tenant_ids.append(tenant_id)
+    )
+    return tenant_ids
+
+
+@pytest.fixture
+def sent_emails(service, monkeypatch):
+    emails = []
+
+    def deliver(to, subject, body, **kwargs):
+        emails.append({"to": to, "subject": subject, "body": body})
+        return f"msg-{len(emails)}"
+
+    monkeypatch.setattr(service.email_client, "deliver", deliver)
+    return emails
+
+
+def trial_tenant(factory, days_left: float, **values):
+    return factory.tenant(
+        status=TenantStatus.TRIAL, trial_end_date=datetime.utcnow() + timedelta(days=days_left), **values
+    )
+
+
+def test_only_trials_ending_soon_are_warned(service, db, factory, queued):
+    ending = trial_tenant(factory, 2)
+    later = trial_tenant(factory, 10)
+    warned = trial_tenant(factory, 1, trial_warning_sent_at=datetime.utcnow())
+    expired = trial_tenant(factory, -1)
+    active = factory.tenant(trial_end_date=datetime.utcnow() + timedelta(days=2))
+
+    service.process_trial_expiration_task()
+
+    assert ending.id in queued
+    assert not {later.id, warned.id, expired.id, active.id} & set(queued)
+
+
+def test_warning_is_sent_once(service, db, factory, sent_emails):
+    tenant = trial_tenant(factory, 2, language="en")
+    now = datetime.utcnow().isoformat()
+
+    assert service.send_trial_expiration_warning_task(tenant.id, now) is True
+    assert service.send_trial_expiration_warning_task(tenant.id, now) is False
+
+    assert [email["to"] for email in sent_emails] == [tenant.email]
+    db.expire_all()
+    assert tenant.trial_warning_sent_at is not None
+
+
+def test_failed_warning_is_retried_by_next_run(service, db, factory, monkeypatch):
+    tenant = trial_tenant(factory, 2)
+
+    def deliver(*args, **kwargs):
+        raise EmailTransientError("SMTP unavailable")
+
+    monkeypatch.setattr(service.email_client, "deliver", deliver)
+
+    assert service.send_trial_expiration_warning_task(tenant.id, datetime.utcnow().isoformat()) is False
+
+    db.expire_all()
+    assert tenant.trial_warning_sent_at is None
@@ -0,0 +1,35 @@
+from datetime import datetime, timedelta
+
+from shared.models import BookingStatus, Payment, PaymentStatus
+
+
+def pending_booking(factory, payment_due_at):
+    tenant, service, master = factory.salon()
+    booking = factory.booking(
+        tenant, master, service, factory.client(), status=BookingStatus.PENDING, payment_due_at=payment_due_at
+    )
+    payment = factory.add(Payment(
+        tenant_id=tenant.id, booking_id=booking.id, amount=150000, currency="KZT", status=PaymentStatus.PENDING
+    ))
+    return booking, payment
+
+
+def test_overdue_unpaid_booking_is_cancelled(service, db, cache, factory):
+    booking, payment = pending_booking(factory, datetime.utcnow() - timedelta(minutes=1))
+
+    service.expire_unpaid_bookings_task()
+
+    db.expire_all()
+    assert booking.status == BookingStatus.CANCELLED
+    # A late success webhook is still accepted, and refunded
+    assert payment.status == PaymentStatus.PENDING
+
+
+def test_booking_within_payment_window_is_kept(service, db, cache, factory):
+    booking, payment = pending_booking(factory, datetime.utcnow() + timedelta(minutes=10))
+
+    service.expire_unpaid_bookings_task()
+
+    db.expire_all()
+    assert booking.status == BookingStatus.PENDING
+    assert payment.status == PaymentStatus.PENDING
@@ -1,8 +1,21 @@
-from fastapi import FastAPI
+from fastapi import FastAPI, Request, status, Depends, Header, Query
+from pydantic import BaseModel, Field, ValidationError
+from sqlalchemy.orm import Session
+from datetime import datetime
+from typing import Optional
+import hashlib
+import hmac
+import httpx
+import json
 import logging
 
 from shared.config import settings
-from shared.database import check_db_connection
+from shared.database import get_db, check_db_connection
+from shared.api import APIError, ErrorCode, register_exception_handlers, request_id_middleware, request_id_headers
+from shared.i18n import verify_translation_coverage
+from shared.models import Tenant, Booking, Payment, PaymentStatus, BookingStatus
+from shared.events import BookingEvent, publish_booking_event
+from shared.billing import BILLED_STATUSES, get_subscription_status, record_subscription, to_minor, to_major
 
 # Configure logging
 logging.basicConfig(
@@ -18,19 +31,125 @@ app = FastAPI(
     version="2.0.0"
 )
 
+# Request ID and standard error envelope
+app.middleware("http")(request_id_middleware)
+register_exception_handlers(app)
+
+
+# Request models
+class RecordSubscriptionRequest(BaseModel):
+    tenant_id: int
+    period_days: int = Field(..., gt=0)
+    admin_id: Optional[int] = None
+    reference: Optional[str] = None
+
+
+class CheckoutRequest(BaseModel):
+    tenant_id: int
+
+
+class PaymentWebhook(BaseModel):
+    payment_id: int
+    provider_payment_id: str
+    status: str
+    amount: int  # minor units
+    currency: str
+
+
+def get_tenant(db: Session, tenant_id: int) -> Tenant:
+    """Load tenant or raise 404."""
+    tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+
+    if not tenant:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.TENANT_NOT_FOUND
+        )
+
+    return tenant
+
+
+def settle_deposit_booking(db: Session, payment: Payment) -> Optional[BookingEvent]:
+    """
+    Confirm booking whose deposit was paid. Returns the event to publish.
+
+    A deposit paid after payment_due_at cancels the booking instead, as
+    the expiry job would, since its slot was offered to others meanwhile.
+    The deposit of a booking that isn't confirmed is refunded in full by
+    the next /refunds run. The booking is locked against the expiry job.
+    """
+    booking = db.query(Booking).filter(Booking.id == payment.booking_id).with_for_update().first()
+    event = None
+
+    if booking and booking.status == BookingStatus.PENDING:
+        if booking.payment_due_at is None or booking.payment_due_at > datetime.utcnow():
+            booking.status = BookingStatus.CONFIRMED
+            booking.payment_due_at = None
+            logger.info(f"Booking confirmed by deposit: ID={booking.id}")
+            return BookingEvent.CONFIRMED
+
+        booking.status = BookingStatus.CANCELLED
+        event = BookingEvent.CANCELLED
+        logger.info(f"Deposit paid after it was due, booking cancelled: ID={booking.id}")
+
+    # Paid after the booking expired or was cancelled
+    payment.refund_amount = payment.amount
+    logger.warning(f"Deposit paid for booking that is not pending, refunding: payment={payment.id}")
+    return event
+
+
+def sign(body: bytes) -> str:
+    """HMAC-SHA256 signature of a body exchanged with the payment provider."""
+    return hmac.new(settings.PAYMENT_WEBHOOK_SECRET.encode(), body, hashlib.sha256).hexdigest()
+
+
+async def send_refund(payment: Payment) -> bool:
+    """
+    Post refund of the payment to the provider, True once it's accepted.
+
+    The refund reference is the same on every attempt, so the provider
+    pays out a refund sent again after a lost response only once.
+    """
+    body = json.dumps({
+        "provider_payment_id": payment.provider_payment_id,
+        "amount": payment.refund_amount,
+        "currency": payment.currency,
+        "reference": f"refund:{payment.id}"
+    }).encode()
+
+    try:
+        async with httpx.AsyncClient(headers=request_id_headers()) as client:
+            response = await client.post(
+                settings.PAYMENT_REFUND_URL,
+                content=body,
+                headers={"Content-Type": "application/json", "X-Payment-Signature": sign(body)},
+                timeout=10.0
+            )
+            response.raise_for_status()
+    except httpx.HTTPError as e:
+        logger.error(f"Refund failed: payment={payment.id}, amount={payment.refund_amount}: {e}")
+        return False
+
+    return True
+
 
 @app.on_event("startup")
 async def startup_event():
     """Initialize on startup."""
     logger.info("Starting Payment Service...")
+    verify_translation_coverage()
+    if check_db_connection():
+        logger.info("Database connection successful")
 
 
 @app.get("/health")
 async def health_check():
     """Health check endpoint."""
+    db_healthy = check_db_connection()
     return {
-        "status": "healthy",
-        "service": "payment-service"
+        "status": "healthy" if db_healthy else "unhealthy",
+        "service": "payment-service",
+        "database": "connected" if db_healthy else "disconnected"
     }
 
 
@@ -43,6 +162,195 @@ async def root():
     }
 
 
+@app.post("/subscriptions", status_code=status.HTTP_201_CREATED)
+async def create_subscription(data: RecordSubscriptionRequest, db: Session = Depends(get_db)):
+    """
+    Record paid subscription period for a tenant.
+
+    Extends subscription_end_date and activates the tenant.
+    """
+    tenant = get_tenant(db, data.tenant_id)
+    record_subscription(db, tenant, data.period_days, data.admin_id, data.reference)
+    db.commit()
+    db.refresh(tenant)
+
+    return {
+        "message": "Subscription recorded",
+        **get_subscription_status(tenant)
+    }
+
+
+@app.get("/subscription/{tenant_id}")
+async def get_subscription(tenant_id: int, db: Session = Depends(get_db)):
+    """
+    Get tenant subscription status: trial or paid, days remaining and
+    whether the account is in good standing.
+    """
+    return get_subscription_status(get_tenant(db, tenant_id))
+
+
+
+@app.post("/subscription/checkout", status_code=status.HTTP_201_CREATED)
+async def create_subscription_checkout(data: CheckoutRequest, db: Session = Depends(get_db)):
+    """
+    Create pending subscription payment and return provider checkout URL.
+
+    Only tenants on trial, active or expired can pay; suspended, rejected
+    and not yet approved ones get 409.
+    """
+    tenant = get_tenant(db, data.tenant_id)
+
+    if tenant.status not in BILLED_STATUSES:
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.INVALID_TENANT_STATUS,
+            details={"status": tenant.status.value}
+        )
+
+    payment = Payment(
+        tenant_id=tenant.id,
+        amount=to_minor(settings.SUBSCRIPTION_PRICE),
+        currency=settings.SUBSCRIPTION_CURRENCY,
+        period_days=settings.SUBSCRIPTION_PERIOD_DAYS,
+        status=PaymentStatus.PENDING
+    )
+    db.add(payment)
+    db.commit()
+    db.refresh(payment)
+
+    logger.info(f"Subscription checkout created: payment={payment.id}, tenant={tenant.id}")
+
+    return {
+        "payment_id": payment.id,
+        "amount": to_major(payment.amount),
+        "amount_minor": payment.amount,
+        "currency": payment.currency,
+        "period_days": payment.period_days,
+        "checkout_url": settings.PAYMENT_CHECKOUT_URL.format(payment_id=payment.id)
+    }
+
+
+@app.post("/webhooks/payment")
+async def payment_webhook(
+    request: Request,
+    signature: str = Header("", alias="X-Payment-Signature"),
+    db: Session = Depends(get_db)
+):
+    """
+    Payment provider webhook.
+
+    A successful subscription payment extends the tenant subscription and
+    a successful deposit confirms the booking, in the same transaction; a
+    deposit paid late is refunded.
+    A success whose amount or currency differs from the payment is rejected
+    and leaves the payment pending. Repeated notifications of a processed
+    payment are ignored.
+    """
+    body = await request.body()
+
+    if not hmac.compare_digest(sign(body), signature):
+        raise APIError(
+            status_code=status.HTTP_401_UNAUTHORIZED,
+            code=ErrorCode.INVALID_SIGNATURE
+        )
+
+    try:
+        data = PaymentWebhook.model_validate_json(body)
+    except ValidationError:
+        raise APIError(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            code=ErrorCode.BAD_REQUEST
+        )
+
+    payment = db.query(Payment).filter(Payment.id == data.payment_id).with_for_update().first()
+
+    if not payment:
+        raise APIError(
+            status_code=status.HTTP_404_NOT_FOUND,
+            code=ErrorCode.PAYMENT_NOT_FOUND
+        )
+
+    if payment.status != PaymentStatus.PENDING:
+        logger.info(f"Payment already processed: ID={payment.id}, status={payment.status.value}")
+        return {"message": "Payment already processed", "status": payment.status.value}
+
+    if data.status == "succeeded" and (
+        data.amount != payment.amount or data.currency.upper() != payment.currency
+    ):
+        logger.error(
+            f"Payment amount mismatch: ID={payment.id}, expected {payment.amount} {payment.currency}, "
+            f"got {data.amount} {data.currency}"
+        )
+        raise APIError(
+            status_code=status.HTTP_409_CONFLICT,
+            code=ErrorCode.PAYMENT_AMOUNT_MISMATCH,
+            details={"amount": payment.amount, "currency": payment.currency}
+        )
+
+    payment.provider_payment_id = data.provider_payment_id
+
+    booking_event = None
+
+    if data.status == "succeeded":
+        payment.status = PaymentStatus.SUCCEEDED
+        payment.paid_at = datetime.utcnow()
+
+        if payment.booking_id:
+            booking_event = settle_deposit_booking(db, payment)
+        else:
+            record_subscription(db, payment.tenant, payment.period_days, reference=f"payment:{payment.id}")
+    else:
+        payment.status = PaymentStatus.FAILED
+
+    db.commit()
+
+    if booking_event:
+        publish_booking_event(payment.booking, booking_event)
+
+    logger.info(f"Payment processed: ID={payment.id}, status={payment.status.value}")
+
+    return {"message": "Payment processed", "status": payment.status.value}
+
+
+@app.post("/refunds")
+async def send_pending_refunds(
+    booking_id: Optional[int] = Query(None),
+    db: Session = Depends(get_db)
+):
+    """
+    Send refunds recorded on paid deposits and not yet accepted by the
+    provider, of the booking or of all bookings.
+
+    Each payment is locked while its refund is sent, and skipped if a
+    concurrent request holds it, then committed at once, so a failure
+    later on doesn't send it again.
+    """
+    query = db.query(Payment.id).filter(
+        Payment.status == PaymentStatus.SUCCEEDED,
+        Payment.refund_amount > 0,
+        Payment.refunded_at.is_(None)
+    )
+    if booking_id is not None:
+        query = query.filter(Payment.booking_id == booking_id)
+
+    payment_ids = [row.id for row in query.order_by(Payment.id).all()]
+
+    sent = 0
+    for payment_id in payment_ids:
+        payment = db.query(Payment).filter(
+            Payment.id == payment_id,
+            Payment.refunded_at.is_(None)
+        ).with_for_update(skip_locked=True).first()
+
+        if payment and await send_refund(payment):
+            payment.refunded_at = datetime.utcnow()
+            sent += 1
+            logger.info(f"Refund sent: payment={payment.id}, amount={payment.refund_amount}")
+        db.commit()
+
+    return {"pending": len(payment_ids), "sent": sent}
+
+
 if __name__ == "__main__":
     import uvicorn
 
@@ -0,0 +1,9 @@
+def test_health_reports_database(client):
+    response = client.get("/health")
+
+    assert response.status_code == 200
+    assert response.json() == {
+        "status": "healthy",
+        "service": "payment-service",
+        "database": "connected"
+    }
@@ -0,0 +1,140 @@
+from datetime import datetime, timedelta
+import hashlib
+import hmac
+import json
+
+import pytest
+
+from shared.config import settings
+from shared.models import BookingStatus, Payment, PaymentStatus, TenantStatus
+
+
+def post_webhook(client, payload, signature=None):
+    body = json.dumps(payload).encode()
+    if signature is None:
+        signature = hmac.new(settings.PAYMENT_WEBHOOK_SECRET.encode(), body, hashlib.sha256).hexdigest()
+    return client.post("/webhooks/payment", content=body, headers={"X-Payment-Signature": signature})
+
+
+@pytest.fixture
+def subscription_payment(factory):
+    tenant = factory.tenant(status=TenantStatus.TRIAL, trial_end_date=datetime.utcnow() + timedelta(days=2))
+    return factory.add(Payment(
+        tenant_id=tenant.id, amount=990000, currency="KZT", period_days=30, status=PaymentStatus.PENDING
+    ))
+
+
+def succeeded(payment, **values):
+    return {
+        "payment_id": payment.id,
+        "provider_payment_id": f"prov-{payment.id}",
+        "status": "succeeded",
+        "amount": payment.amount,
+        "currency": payment.currency,
+        **values
+    }
+
+
+def test_invalid_signature_is_rejected(client, subscription_payment):
+    response = post_webhook(client, succeeded(subscription_payment), signature="forged")
+
+    assert response.status_code == 401
+    assert response.json()["error"] == "INVALID_SIGNATURE"
+
+
+def test_successful_payment_extends_subscription(client, db, subscription_payment):
+    response = post_webhook(client, succeeded(subscription_payment))
+
+    assert response.status_code == 200
+    assert response.json()["status"] == "SUCCEEDED"
+    db.expire_all()
+    tenant = subscription_payment.tenant
+    assert subscription_payment.status == PaymentStatus.SUCCEEDED
+    assert subscription_payment.paid_at is not None
+    assert tenant.status == TenantStatus.ACTIVE
+    assert tenant.subscription_end_date > datetime.utcnow() + timedelta(days=29)
+
+
+def test_repeated_webhook_is_ignored(client, db, subscription_payment):
+    post_webhook(client, succeeded(subscription_payment))
+    db.expire_all()
+    ends_at = subscription_payment.tenant.subscription_end_date
+
+    response = post_webhook(client, succeeded(subscription_payment))
+
+    assert response.status_code == 200
+    assert response.json()["message"] == "Payment already processed"
+    db.expire_all()
+    assert subscription_payment.tenant.subscription_end_date == ends_at
+
+
+@pytest.mark.parametrize("mismatch", [{"amount": 1000}, {"currency": "USD"}])
+def test_mismatched_amount_is_rejected(client, db, subscription_payment, mismatch):
+    response = post_webhook(client, succeeded(subscription_payment, **mismatch))
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "PAYMENT_AMOUNT_MISMATCH"
+    assert response.json()["details"] == {"amount": 990000, "currency": "KZT"}
+    db.expire_all()
+    assert subscription_payment.status == PaymentStatus.PENDING
+    assert subscription_payment.tenant.subscription_end_date is None
+
+
+def test_failed_payment_does_not_extend_subscription(client, db, subscription_payment):
+    response = post_webhook(client, succeeded(subscription_payment, status="failed"))
+
+    assert response.json()["status"] == "FAILED"
+    db.expire_all()
+    assert subscription_payment.tenant.subscription_end_date is None
+
+
+def deposit_payment(factory, status, payment_due_at):
+    tenant, service, master = factory.salon()
+    booking = factory.booking(
+        tenant, master, service, factory.client(), status=status, payment_due_at=payment_due_at
+    )
+    return factory.add(Payment(
+        tenant_id=tenant.id, booking_id=booking.id, amount=150000, currency="KZT", status=PaymentStatus.PENDING
+    ))
+
+
+def test_paid_deposit_confirms_booking(client, db, factory):
+    payment = deposit_payment(factory, BookingStatus.PENDING, datetime.utcnow() + timedelta(minutes=30))
+    booking = payment.booking
+
+    response = post_webhook(client, succeeded(payment))
+
+    assert response.status_code == 200
+    db.expire_all()
+    assert booking.status == BookingStatus.CONFIRMED
+    assert booking.payment_due_at is None
+    assert payment.refund_amount is None
+    assert booking.tenant.subscription_end_date is None
+
+
+def test_deposit_paid_after_due_cancels_and_refunds(client, db, factory):
+    # Due, but the expiry job hasn't run yet
+    payment = deposit_payment(factory, BookingStatus.PENDING, datetime.utcnow() - timedelta(minutes=1))
+    booking = payment.booking
+
+    response = post_webhook(client, succeeded(payment))
+
+    assert response.status_code == 200
+    assert response.json()["status"] == "SUCCEEDED"
+    db.expire_all()
+    assert booking.status == BookingStatus.CANCELLED
+    assert payment.refund_amount == payment.amount
+
+
+def test_deposit_paid_after_expiry_is_refunded(client, db, factory):
+    # The expiry job cancelled the booking and left the payment pending
+    payment = deposit_payment(factory, BookingStatus.CANCELLED, datetime.utcnow() - timedelta(hours=1))
+    booking = payment.booking
+
+    response = post_webhook(client, succeeded(payment))
+
+    assert response.status_code == 200
+    db.expire_all()
+    assert payment.status == PaymentStatus.SUCCEEDED
+    assert payment.refund_amount == payment.amount
+    assert booking.status == BookingStatus.CANCELLED
@@ -0,0 +1,97 @@
+from datetime import datetime
+import hashlib
+import hmac
+import json
+
+import httpx
+import pytest
+
+from shared.config import settings
+from shared.models import Payment, PaymentStatus
+
+
+@pytest.fixture
+def provider(monkeypatch):
+    """Record refunds posted to the provider, failing while failing is set."""
+    state = {"requests": [], "failing": False}
+    real_client = httpx.AsyncClient
+
+    def handler(request: httpx.Request) -> httpx.Response:
+        if state["failing"]:
+            return httpx.Response(503)
+        state["requests"].append(request)
+        return httpx.Response(200, json={"status": "accepted"})
+
+    monkeypatch.setattr(
+        httpx, "AsyncClient", lambda **kwargs: real_client(transport=httpx.MockTransport(handler), **kwargs)
+    )
+    return state
+
+
+@pytest.fixture
+def refundable(factory):
+    tenant, service, master = factory.salon()
+    booking = factory.booking(tenant, master, service, factory.client())
+    return factory.add(Payment(
+        tenant_id=tenant.id, booking_id=booking.id, amount=30000, currency="KZT",
+        status=PaymentStatus.SUCCEEDED, provider_payment_id=f"prov-{booking.id}",
+        paid_at=datetime.utcnow(), refund_amount=20000
+    ))
+
+
+def test_refund_is_sent_signed_to_provider(client, db, provider, refundable):
+    response = client.post("/refunds", params={"booking_id": refundable.booking_id})
+
+    assert response.status_code == 200
+    assert response.json() == {"pending": 1, "sent": 1}
+    request = provider["requests"][0]
+    assert str(request.url) == settings.PAYMENT_REFUND_URL
+    expected = hmac.new(settings.PAYMENT_WEBHOOK_SECRET.encode(), request.content, hashlib.sha256).hexdigest()
+    assert request.headers["X-Payment-Signature"] == expected
+    assert json.loads(request.content) == {
+        "provider_payment_id": refundable.provider_payment_id,
+        "amount": 20000,
+        "currency": "KZT",
+        "reference": f"refund:{refundable.id}"
+    }
+    db.expire_all()
+    assert refundable.refunded_at is not None
+
+
+def test_refund_is_sent_once(client, provider, refundable):
+    client.post("/refunds")
+    response = client.post("/refunds")
+
+    assert response.json()["sent"] == 0
+    assert len([r for r in provider["requests"] if b"refund:%d" % refundable.id in r.content]) == 1
+
+
+def test_failed_refund_is_retried(client, db, provider, refundable):
+    provider["failing"] = True
+
+    response = client.post("/refunds", params={"booking_id": refundable.booking_id})
+
+    assert response.json() == {"pending": 1, "sent": 0}
+    db.expire_all()
+    assert refundable.refunded_at is None
+
+    provider["failing"] = False
+    response = client.post("/refunds", params={"booking_id": refundable.booking_id})
+
+    assert response.json() == {"pending": 1, "sent": 1}
+
+
+@pytest.mark.parametrize("values", [
+    {"refund_amount": None},
+    {"refund_amount": 0},
+    {"status": PaymentStatus.FAILED}
+])
+def test_payment_without_refund_is_skipped(client, db, provider, refundable, values):
+    for name, value in values.items():
+        setattr(refundable, name, value)
+    db.flush()
+
+    response = client.post("/refunds", params={"booking_id": refundable.booking_id})
+
+    assert response.json() == {"pending": 0, "sent": 0}
+    assert provider["requests"] == []
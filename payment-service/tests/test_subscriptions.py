@@ -0,0 +1,110 @@
+from datetime import datetime, timedelta
+
+import pytest
+
+from shared.models import AdminAction, Payment, PaymentStatus, TenantStatus
+
+
+def test_subscription_activates_expired_tenant(client, db, factory):
+    tenant = factory.tenant(status=TenantStatus.EXPIRED, trial_end_date=datetime.utcnow() - timedelta(days=3))
+
+    response = client.post("/subscriptions", json={"tenant_id": tenant.id, "period_days": 30, "reference": "INV-1"})
+
+    assert response.status_code == 201
+    body = response.json()
+    assert body["status"] == TenantStatus.ACTIVE.value
+    assert body["plan"] == "paid"
+    assert body["days_remaining"] == 30
+    assert body["in_good_standing"] is True
+    action = db.query(AdminAction).filter(AdminAction.target_id == tenant.id).one()
+    assert action.action_type == "SUBSCRIPTION_RECORDED"
+    assert "INV-1" in action.details
+
+
+def test_subscription_extends_running_period(client, db, factory):
+    ends = datetime.utcnow() + timedelta(days=10)
+    tenant = factory.tenant(subscription_end_date=ends)
+
+    client.post("/subscriptions", json={"tenant_id": tenant.id, "period_days": 30})
+
+    db.expire_all()
+    assert tenant.subscription_end_date == ends + timedelta(days=30)
+
+
+def test_trial_status(client, factory):
+    tenant = factory.tenant(status=TenantStatus.TRIAL, trial_end_date=datetime.utcnow() + timedelta(days=4, hours=1))
+
+    response = client.get(f"/subscription/{tenant.id}")
+
+    assert response.status_code == 200
+    assert response.json()["plan"] == "trial"
+    assert response.json()["days_remaining"] == 5
+    assert response.json()["in_good_standing"] is True
+
+
+def test_lapsed_trial_is_not_in_good_standing(client, factory):
+    tenant = factory.tenant(status=TenantStatus.TRIAL, trial_end_date=datetime.utcnow() - timedelta(hours=1))
+
+    response = client.get(f"/subscription/{tenant.id}")
+
+    assert response.json()["days_remaining"] == 0
+    assert response.json()["in_good_standing"] is False
+
+
+def test_approved_tenant_before_payment_is_on_trial(client, factory):
+    tenant = factory.tenant(trial_end_date=datetime.utcnow() - timedelta(hours=1))
+
+    response = client.get(f"/subscription/{tenant.id}")
+
+    assert response.json()["plan"] == "trial"
+    assert response.json()["in_good_standing"] is False
+
+
+def test_unknown_tenant_is_not_found(client):
+    response = client.get("/subscription/0")
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "TENANT_NOT_FOUND"
+
+
+def test_period_must_be_positive(client, factory):
+    tenant = factory.tenant()
+
+    response = client.post("/subscriptions", json={"tenant_id": tenant.id, "period_days": 0})
+
+    assert response.status_code == 422
+    assert response.json()["details"][0]["field"] == "body.period_days"
+
+
+def test_subscription_keeps_suspended_tenant_suspended(client, db, factory):
+    tenant = factory.tenant(status=TenantStatus.SUSPENDED)
+
+    response = client.post("/subscriptions", json={"tenant_id": tenant.id, "period_days": 30})
+
+    assert response.status_code == 201
+    assert response.json()["status"] == TenantStatus.SUSPENDED.value
+    db.expire_all()
+    assert tenant.status == TenantStatus.SUSPENDED
+    assert tenant.subscription_end_date is not None
+
+
+@pytest.mark.parametrize("tenant_status", [TenantStatus.SUSPENDED, TenantStatus.REJECTED])
+def test_checkout_is_rejected_for_unbilled_tenant(client, db, factory, tenant_status):
+    tenant = factory.tenant(status=tenant_status)
+
+    response = client.post("/subscription/checkout", json={"tenant_id": tenant.id})
+
+    assert response.status_code == 409
+    assert response.json()["error"] == "INVALID_TENANT_STATUS"
+    assert db.query(Payment).filter(Payment.tenant_id == tenant.id).count() == 0
+
+
+def test_checkout_creates_pending_payment(client, db, factory):
+    tenant = factory.tenant(status=TenantStatus.EXPIRED, trial_end_date=datetime.utcnow() - timedelta(days=1))
+
+    response = client.post("/subscription/checkout", json={"tenant_id": tenant.id})
+
+    assert response.status_code == 201
+    payment = db.query(Payment).filter(Payment.id == response.json()["payment_id"]).one()
+    assert payment.status == PaymentStatus.PENDING
+    assert payment.tenant_id == tenant.id
@@ -0,0 +1,6 @@
+from .recompute import recompute_service_popularity, recompute_master_ratings
+
+__all__ = [
+    "recompute_service_popularity",
+    "recompute_master_ratings"
+]
@@ -0,0 +1,57 @@
+from datetime import datetime, timedelta
+from typing import List, Optional
+
+from sqlalchemy import func, select
+from sqlalchemy.orm import Session
+
+from shared.config import settings
+from shared.models import Booking, BookingStatus, Master, Review, ReviewStatus, Service
+
+
+def recompute_service_popularity(db: Session, tenant_ids: List[int]) -> int:
+    """
+    Set popularity_score of the tenants' services to their completed
+    bookings within POPULARITY_WINDOW_DAYS.
+
+    A single UPDATE counting from scratch, so cancelled and no-show
+    bookings never count and repeated runs give the same result.
+    """
+    cutoff = datetime.utcnow() - timedelta(days=settings.POPULARITY_WINDOW_DAYS)
+
+    completed_count = select(func.count(Booking.id)).where(
+        Booking.service_id == Service.id,
+        Booking.status == BookingStatus.COMPLETED,
+        Booking.booking_date >= cutoff
+    ).scalar_subquery()
+
+    return db.query(Service).filter(Service.tenant_id.in_(tenant_ids)).update(
+        {Service.popularity_score: completed_count},
+        synchronize_session=False
+    )
+
+
+def recompute_master_ratings(
+    db: Session,
+    tenant_ids: Optional[List[int]] = None,
+    master_ids: Optional[List[int]] = None
+) -> int:
+    """
+    Set rating and reviews_count of masters (of the tenants, or the given
+    ones) from their approved reviews.
+
+    A single UPDATE counting from scratch, like recompute_service_popularity.
+    """
+    approved = (Review.master_id == Master.id, Review.status == ReviewStatus.APPROVED)
+    average = select(func.round(func.avg(Review.rating), 2)).where(*approved).scalar_subquery()
+    count = select(func.count(Review.id)).where(*approved).scalar_subquery()
+
+    query = db.query(Master)
+    if tenant_ids is not None:
+        query = query.filter(Master.tenant_id.in_(tenant_ids))
+    if master_ids is not None:
+        query = query.filter(Master.id.in_(master_ids))
+
+    return query.update(
+        {Master.rating: average, Master.reviews_count: count},
+        synchronize_session=False
+    )
@@ -0,0 +1,29 @@
+from .errors import (
+    ErrorCode,
+    APIError,
+    error_envelope,
+    error_response,
+    error_code_from_response,
+    register_exception_handlers
+)
+from .request_id import REQUEST_ID_HEADER, request_id_middleware, get_request_id, request_id_headers
+from .pagination import encode_cursor, decode_cursor
+from .subdomain import RESERVED_SUBDOMAINS, normalize_subdomain, subdomain_error
+
+__all__ = [
+    "ErrorCode",
+    "APIError",
+    "error_envelope",
+    "error_response",
+    "error_code_from_response",
+    "register_exception_handlers",
+    "REQUEST_ID_HEADER",
+    "request_id_middleware",
+    "get_request_id",
+    "request_id_headers",
+    "encode_cursor",
+    "decode_cursor",
+    "RESERVED_SUBDOMAINS",
+    "normalize_subdomain",
+    "subdomain_error"
+]
@@ -0,0 +1,245 @@
+from fastapi import FastAPI, Request, HTTPException, status
+from fastapi.exceptions import RequestValidationError
+from fastapi.responses import JSONResponse
+from starlette.exceptions import HTTPException as StarletteHTTPException
+from sqlalchemy.exc import OperationalError, TimeoutError as SQLAlchemyTimeoutError
+from enum import Enum
+from typing import Any, Dict, Optional
+import logging
+
+from shared.config import settings
+from shared.i18n import translate, get_request_language
+from .request_id import REQUEST_ID_HEADER, get_request_id
+
+logger = logging.getLogger(__name__)
+
+# PostgreSQL error code of a statement cancelled by statement_timeout
+QUERY_CANCELED_PGCODE = "57014"
+
+
+class ErrorCode(str, Enum):
+    """Machine-readable API error codes."""
+    # Generic
+    BAD_REQUEST = "BAD_REQUEST"
+    VALIDATION_ERROR = "VALIDATION_ERROR"
+    UNAUTHORIZED = "UNAUTHORIZED"
+    FORBIDDEN = "FORBIDDEN"
+    NOT_FOUND = "NOT_FOUND"
+    CONFLICT = "CONFLICT"
+    RATE_LIMITED = "RATE_LIMITED"
+    PAYLOAD_TOO_LARGE = "PAYLOAD_TOO_LARGE"
+    INVALID_CURSOR = "INVALID_CURSOR"
+    REQUEST_TIMEOUT = "REQUEST_TIMEOUT"
+    INTERNAL_ERROR = "INTERNAL_ERROR"
+    SERVICE_ERROR = "SERVICE_ERROR"
+    SERVICE_UNAVAILABLE = "SERVICE_UNAVAILABLE"
+    DATABASE_TIMEOUT = "DATABASE_TIMEOUT"
+
+    # Auth
+    INVALID_CREDENTIALS = "INVALID_CREDENTIALS"
+    INVALID_TOKEN = "INVALID_TOKEN"
+    ADMIN_SCOPE_REQUIRED = "ADMIN_SCOPE_REQUIRED"
+    IMPERSONATION_FORBIDDEN = "IMPERSONATION_FORBIDDEN"
+    INVALID_REFRESH_TOKEN = "INVALID_REFRESH_TOKEN"
+    INVALID_OLD_PASSWORD = "INVALID_OLD_PASSWORD"
+    ACCOUNT_INACTIVE = "ACCOUNT_INACTIVE"
+    TENANT_EXPIRED = "TENANT_EXPIRED"
+    FEATURE_NOT_AVAILABLE = "FEATURE_NOT_AVAILABLE"
+    EMAIL_TAKEN = "EMAIL_TAKEN"
+    SUBDOMAIN_TAKEN = "SUBDOMAIN_TAKEN"
+    INVALID_SUBDOMAIN = "INVALID_SUBDOMAIN"
+    REGISTRATION_FAILED = "REGISTRATION_FAILED"
+    CAPTCHA_REQUIRED = "CAPTCHA_REQUIRED"
+    CAPTCHA_FAILED = "CAPTCHA_FAILED"
+
+    # Domain
+    USER_NOT_FOUND = "USER_NOT_FOUND"
+    TENANT_NOT_FOUND = "TENANT_NOT_FOUND"
+    BUSINESS_NOT_FOUND = "BUSINESS_NOT_FOUND"
+    SERVICE_NOT_FOUND = "SERVICE_NOT_FOUND"
+    MASTER_NOT_FOUND = "MASTER_NOT_FOUND"
+    LOCATION_NOT_FOUND = "LOCATION_NOT_FOUND"
+    BOOKING_NOT_FOUND = "BOOKING_NOT_FOUND"
+    CLIENT_NOT_FOUND = "CLIENT_NOT_FOUND"
+    MASTER_SERVICE_MISMATCH = "MASTER_SERVICE_MISMATCH"
+    SLOT_UNAVAILABLE = "SLOT_UNAVAILABLE"
+    NO_MASTER_AVAILABLE = "NO_MASTER_AVAILABLE"
+    BOOKING_TOO_SOON = "BOOKING_TOO_SOON"
+    MASTER_NOT_ACCEPTING_BOOKINGS = "MASTER_NOT_ACCEPTING_BOOKINGS"
+    BOOKING_FAILED = "BOOKING_FAILED"
+    INVALID_BOOKING_STATUS = "INVALID_BOOKING_STATUS"
+    INVALID_TENANT_STATUS = "INVALID_TENANT_STATUS"
+    PAYMENT_NOT_FOUND = "PAYMENT_NOT_FOUND"
+    INVALID_SIGNATURE = "INVALID_SIGNATURE"
+    PAYMENT_AMOUNT_MISMATCH = "PAYMENT_AMOUNT_MISMATCH"
+    BOOKING_NOT_STARTED = "BOOKING_NOT_STARTED"
+    INVALID_DATE_RANGE = "INVALID_DATE_RANGE"
+    TEMPLATE_NOT_FOUND = "TEMPLATE_NOT_FOUND"
+    INVALID_TEMPLATE = "INVALID_TEMPLATE"
+    REVIEW_NOT_FOUND = "REVIEW_NOT_FOUND"
+    TIME_OFF_NOT_FOUND = "TIME_OFF_NOT_FOUND"
+    TIME_OFF_CONFLICT = "TIME_OFF_CONFLICT"
+
+    # Client
+    INVALID_VERIFICATION_CODE = "INVALID_VERIFICATION_CODE"
+    INVALID_CLIENT_SESSION = "INVALID_CLIENT_SESSION"
+    CANCELLATION_WINDOW_CLOSED = "CANCELLATION_WINDOW_CLOSED"
+    REVIEW_ALREADY_EXISTS = "REVIEW_ALREADY_EXISTS"
+
+
+# Default error code for plain HTTPExceptions raised by FastAPI/Starlette
+STATUS_ERROR_CODES = {
+    status.HTTP_400_BAD_REQUEST: ErrorCode.BAD_REQUEST,
+    status.HTTP_401_UNAUTHORIZED: ErrorCode.UNAUTHORIZED,
+    status.HTTP_403_FORBIDDEN: ErrorCode.FORBIDDEN,
+    status.HTTP_404_NOT_FOUND: ErrorCode.NOT_FOUND,
+    status.HTTP_408_REQUEST_TIMEOUT: ErrorCode.REQUEST_TIMEOUT,
+    status.HTTP_409_CONFLICT: ErrorCode.CONFLICT,
+    status.HTTP_413_REQUEST_ENTITY_TOO_LARGE: ErrorCode.PAYLOAD_TOO_LARGE,
+    status.HTTP_422_UNPROCESSABLE_ENTITY: ErrorCode.VALIDATION_ERROR,
+    status.HTTP_429_TOO_MANY_REQUESTS: ErrorCode.RATE_LIMITED,
+    status.HTTP_503_SERVICE_UNAVAILABLE: ErrorCode.SERVICE_UNAVAILABLE,
+}
+
+
+class APIError(HTTPException):
+    """
+    HTTP exception carrying an error code and optional details.
+
+    Rendered as the standard error envelope by register_exception_handlers.
+    """
+
+    def __init__(
+        self,
+        status_code: int,
+        code: ErrorCode,
+        details: Optional[Any] = None,
+        headers: Optional[Dict[str, str]] = None
+    ):
+        super().__init__(status_code=status_code, detail=code.value, headers=headers)
+        self.code = code
+        self.details = details
+
+
+def error_envelope(
+    code: ErrorCode,
+    language: Optional[str] = None,
+    details: Optional[Any] = None,
+    request_id: Optional[str] = None
+) -> Dict[str, Any]:
+    """Build the standard error response body."""
+    body = {
+        "error": code.value,
+        "message": translate(code.value.lower(), language),
+        "request_id": request_id
+    }
+
+    if details is not None:
+        body["details"] = details
+
+    return body
+
+
+def error_response(
+    request: Request,
+    status_code: int,
+    code: ErrorCode,
+    details: Optional[Any] = None,
+    headers: Optional[Dict[str, str]] = None
+) -> JSONResponse:
+    """Build JSON error response for the current request."""
+    request_id = get_request_id(request)
+
+    response = JSONResponse(
+        status_code=status_code,
+        content=error_envelope(code, get_request_language(request), details, request_id),
+        headers=headers
+    )
+
+    if request_id:
+        response.headers[REQUEST_ID_HEADER] = request_id
+
+    return response
+
+
+def error_code_from_response(response: Any, default: ErrorCode) -> ErrorCode:
+    """Extract error code from a downstream service error envelope."""
+    try:
+        return ErrorCode(response.json().get("error"))
+    except (ValueError, AttributeError):
+        return default
+
+
+def is_database_timeout(exc: Exception) -> bool:
+    """Check if a database error is a pool or statement timeout."""
+    if isinstance(exc, SQLAlchemyTimeoutError):
+        return True
+
+    # PostgreSQL query_canceled, raised when statement_timeout is exceeded
+    return getattr(getattr(exc, "orig", None), "pgcode", None) == QUERY_CANCELED_PGCODE
+
+
+def register_exception_handlers(app: FastAPI) -> None:
+    """Register exception handlers rendering the standard error envelope."""
+
+    @app.exception_handler(APIError)
+    async def api_error_handler(request: Request, exc: APIError):
+        return error_response(request, exc.status_code, exc.code, exc.details, exc.headers)
+
+    @app.exception_handler(StarletteHTTPException)
+    async def http_exception_handler(request: Request, exc: StarletteHTTPException):
+        code = STATUS_ERROR_CODES.get(exc.status_code, ErrorCode.INTERNAL_ERROR)
+        return error_response(request, exc.status_code, code, headers=getattr(exc, "headers", None))
+
+    @app.exception_handler(RequestValidationError)
+    async def validation_exception_handler(request: Request, exc: RequestValidationError):
+        return error_response(
+            request,
+            status.HTTP_422_UNPROCESSABLE_ENTITY,
+            ErrorCode.VALIDATION_ERROR,
+            details=jsonable_errors(exc)
+        )
+
+    @app.exception_handler(OperationalError)
+    @app.exception_handler(SQLAlchemyTimeoutError)
+    async def database_exception_handler(request: Request, exc: Exception):
+        if not is_database_timeout(exc):
+            return await general_exception_handler(request, exc)
+
+        logger.warning(f"Database timeout (request_id={get_request_id(request)}): {exc}")
+        return error_response(
+            request,
+            status.HTTP_504_GATEWAY_TIMEOUT,
+            ErrorCode.DATABASE_TIMEOUT,
+            details={"statement_timeout_ms": settings.DB_STATEMENT_TIMEOUT_MS}
+        )
+
+    @app.exception_handler(Exception)
+    async def general_exception_handler(request: Request, exc: Exception):
+        logger.error(
+            f"Unhandled exception (request_id={get_request_id(request)}): {exc}",
+            exc_info=True
+        )
+
+        # Internal error messages are only exposed when explicitly enabled
+        details = None
+        if settings.EXPOSE_ERROR_DETAILS:
+            details = {"debug": f"{type(exc).__name__}: {exc}"}
+
+        return error_response(
+            request,
+            status.HTTP_500_INTERNAL_SERVER_ERROR,
+            ErrorCode.INTERNAL_ERROR,
+            details=details
+        )
+
+
+def jsonable_errors(exc: RequestValidationError) -> list:
+    """Convert validation errors to JSON-serializable field errors."""
+    return [
+        {
+            "field": ".".join(str(loc) for loc in error.get("loc", [])),
+            "message": error.get("msg")
+        }
+        for error in exc.errors()
+    ]
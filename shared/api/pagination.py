@@ -0,0 +1,27 @@
+from datetime import datetime
+from typing import Tuple
+import base64
+import json
+
+from fastapi import status
+
+from .errors import APIError, ErrorCode
+
+
+def encode_cursor(created_at: datetime, item_id: int) -> str:
+    """Encode keyset position (created_at, id) of the last item as an opaque cursor."""
+    raw = json.dumps({"created_at": created_at.isoformat(), "id": item_id}, separators=(",", ":"))
+    return base64.urlsafe_b64encode(raw.encode()).decode().rstrip("=")
+
+
+def decode_cursor(cursor: str) -> Tuple[datetime, int]:
+    """Decode cursor into (created_at, id) or raise 400."""
+    try:
+        padded = cursor + "=" * (-len(cursor) % 4)
+        position = json.loads(base64.urlsafe_b64decode(padded.encode()))
+        return datetime.fromisoformat(position["created_at"]), int(position["id"])
+    except (ValueError, KeyError, TypeError):
+        raise APIError(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            code=ErrorCode.INVALID_CURSOR
+        )
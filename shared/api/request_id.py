@@ -0,0 +1,46 @@
+from fastapi import Request
+from contextvars import ContextVar
+from typing import Dict, Optional
+import uuid
+
+REQUEST_ID_HEADER = "X-Request-ID"
+
+# Request ID of the request being handled, for outgoing calls and logs
+request_id_ctx: ContextVar[Optional[str]] = ContextVar("request_id", default=None)
+
+
+async def request_id_middleware(request: Request, call_next):
+    """
+    Request ID middleware.
+
+    Reuses the incoming X-Request-ID header or generates a new one,
+    stores it in request.state and echoes it in the response.
+    """
+    request_id = request.headers.get(REQUEST_ID_HEADER) or uuid.uuid4().hex
+    request.state.request_id = request_id
+    token = request_id_ctx.set(request_id)
+
+    try:
+        response = await call_next(request)
+    finally:
+        request_id_ctx.reset(token)
+
+    response.headers[REQUEST_ID_HEADER] = request_id
+    return response
+
+
+def get_request_id(request: Request) -> Optional[str]:
+    """Get request ID assigned by request_id_middleware."""
+    return getattr(request.state, "request_id", None)
+
+
+def request_id_headers() -> Dict[str, str]:
+    """
+    Headers propagating the current request ID to downstream services.
+
+    Lets backend logs be correlated with the ID returned to the client.
+    """
+    request_id = request_id_ctx.get()
+    if not request_id:
+        return {}
+    return {REQUEST_ID_HEADER: request_id}
@@ -0,0 +1,35 @@
+import re
+from typing import Optional
+
+SUBDOMAIN_MIN_LENGTH = 3
+SUBDOMAIN_MAX_LENGTH = 50  # tenants.subdomain column, within the 63 of a DNS label
+
+# DNS label: lowercase letters, digits and inner hyphens
+SUBDOMAIN_PATTERN = re.compile(r"^[a-z0-9](?:[a-z0-9-]*[a-z0-9])?$")
+
+# Used by the platform itself or too easy to mistake for it
+RESERVED_SUBDOMAINS = frozenset({
+    "admin", "api", "app", "assets", "auth", "billing", "blog", "cdn", "dashboard",
+    "dev", "docs", "ftp", "help", "login", "mail", "media", "smtp", "static",
+    "staging", "status", "support", "test", "www"
+})
+
+
+def normalize_subdomain(value: str) -> str:
+    """Trim and lowercase a requested subdomain."""
+    return value.strip().lower()
+
+
+def subdomain_error(subdomain: str) -> Optional[str]:
+    """
+    Get why a normalized subdomain can't be used, None if it's valid.
+
+    Double hyphens are rejected, since "xn--" prefixes mark punycode.
+    """
+    if not SUBDOMAIN_MIN_LENGTH <= len(subdomain) <= SUBDOMAIN_MAX_LENGTH:
+        return f"Subdomain must be {SUBDOMAIN_MIN_LENGTH} to {SUBDOMAIN_MAX_LENGTH} characters long"
+    if not SUBDOMAIN_PATTERN.match(subdomain) or "--" in subdomain:
+        return "Subdomain may contain only letters, digits and single hyphens, not at the start or end"
+    if subdomain in RESERVED_SUBDOMAINS:
+        return "Subdomain is reserved"
+    return None
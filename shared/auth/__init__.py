@@ -1,17 +1,23 @@
 from .jwt_handler import (
+    ADMIN_SCOPE,
+    IMPERSONATION_CLAIM,
     verify_password,
     get_password_hash,
     create_access_token,
     create_refresh_token,
     decode_token,
-    create_token_pair
+    create_token_pair,
+    create_impersonation_token
 )
 
 __all__ = [
+    "ADMIN_SCOPE",
+    "IMPERSONATION_CLAIM",
     "verify_password",
     "get_password_hash",
     "create_access_token",
     "create_refresh_token",
     "decode_token",
-    "create_token_pair"
+    "create_token_pair",
+    "create_impersonation_token"
 ]
@@ -8,6 +8,12 @@ from shared.config import settings
 
 logger = logging.getLogger(__name__)
 
+# Token scope for SUPER_ADMIN tokens issued by admin login
+ADMIN_SCOPE = "admin"
+
+# Claim with the SUPER_ADMIN user ID in tokens issued for tenant impersonation
+IMPERSONATION_CLAIM = "impersonated_by"
+
 # Password hashing context
 pwd_context = CryptContext(schemes=["bcrypt"], deprecated="auto")
 
@@ -78,8 +84,19 @@ def decode_token(token: str) -> Optional[Dict]:
         return None
 
 
-def create_token_pair(user_id: int, email: str, role: str, tenant_id: Optional[int] = None) -> Dict[str, str]:
-    """Create access and refresh token pair."""
+def create_token_pair(
+    user_id: int,
+    email: str,
+    role: str,
+    tenant_id: Optional[int] = None,
+    scope: Optional[str] = None,
+    expires_delta: Optional[timedelta] = None
+) -> Dict[str, str]:
+    """
+    Create access and refresh token pair.
+
+    Tokens issued by the admin login carry scope="admin".
+    """
     token_data = {
         "sub": str(user_id),
         "email": email,
@@ -89,7 +106,10 @@ def create_token_pair(user_id: int, email: str, role: str, tenant_id: Optional[i
     if tenant_id:
         token_data["tenant_id"] = tenant_id
 
-    access_token = create_access_token(token_data)
+    if scope:
+        token_data["scope"] = scope
+
+    access_token = create_access_token(token_data, expires_delta)
     refresh_token = create_refresh_token(token_data)
 
     return {
@@ -97,3 +117,28 @@ def create_token_pair(user_id: int, email: str, role: str, tenant_id: Optional[i
         "refresh_token": refresh_token,
         "token_type": "bearer"
     }
+
+
+def create_impersonation_token(
+    admin_id: int,
+    user_id: int,
+    email: str,
+    role: str,
+    tenant_id: int
+) -> str:
+    """
+    Create short-lived access token acting as a tenant user for support.
+
+    The token carries the impersonated_by claim and has no refresh token,
+    so the session ends after IMPERSONATION_TOKEN_EXPIRE_MINUTES.
+    """
+    return create_access_token(
+        {
+            "sub": str(user_id),
+            "email": email,
+            "role": role,
+            "tenant_id": tenant_id,
+            IMPERSONATION_CLAIM: str(admin_id)
+        },
+        timedelta(minutes=settings.IMPERSONATION_TOKEN_EXPIRE_MINUTES)
+    )
@@ -0,0 +1,39 @@
+from .money import (
+    MINOR_UNITS,
+    to_minor,
+    to_major,
+    tenant_currency
+)
+from .tax import BASIS_POINTS, compute_tax, get_tax_config
+from .subscription import (
+    BILLED_STATUSES,
+    get_subscription_status,
+    is_access_blocked,
+    is_publicly_visible,
+    record_subscription
+)
+from .features import Feature, get_tenant_features, enabled_features, has_feature, require_feature
+from .cancellation import compute_cancellation_fee, get_cancellation_policy, get_cancellation_tier
+
+__all__ = [
+    "MINOR_UNITS",
+    "to_minor",
+    "to_major",
+    "tenant_currency",
+    "BASIS_POINTS",
+    "compute_tax",
+    "get_tax_config",
+    "BILLED_STATUSES",
+    "get_subscription_status",
+    "is_access_blocked",
+    "is_publicly_visible",
+    "record_subscription",
+    "Feature",
+    "get_tenant_features",
+    "enabled_features",
+    "has_feature",
+    "require_feature",
+    "compute_cancellation_fee",
+    "get_cancellation_policy",
+    "get_cancellation_tier"
+]
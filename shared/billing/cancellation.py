@@ -0,0 +1,42 @@
+from datetime import datetime
+from typing import Dict, List, Optional
+
+from shared.models import Tenant, Service
+from shared.tenants import get_tenant_settings
+
+PERCENT = 100
+
+
+def get_cancellation_policy(tenant: Optional[Tenant], service: Optional[Service] = None) -> List[Dict[str, int]]:
+    """
+    Get cancellation fee tiers of the service, falling back to the tenant
+    settings (CANCELLATION_POLICY by default).
+
+    Each tier applies from hours_before the booking until the next one,
+    earliest first. Cancelling after the last tier is not allowed.
+    """
+    if service and service.cancellation_policy:
+        return sorted(service.cancellation_policy, key=lambda tier: tier["hours_before"], reverse=True)
+
+    return [tier.dict() for tier in get_tenant_settings(tenant).cancellation_policy]
+
+
+def get_cancellation_tier(
+    policy: List[Dict[str, int]],
+    booking_date: datetime,
+    now: datetime
+) -> Optional[Dict[str, int]]:
+    """Get tier of the policy applying to a cancellation at now, None if it's too late to cancel."""
+    hours_left = (booking_date - now).total_seconds() / 3600
+    return next((tier for tier in policy if hours_left >= tier["hours_before"]), None)
+
+
+def compute_cancellation_fee(price: int, paid: int, fee_percent: int) -> dict:
+    """
+    Compute cancellation fee and refund in minor units.
+
+    The fee is fee_percent of the price, rounded half up, and is kept
+    from what was paid; the rest is refunded.
+    """
+    fee = (price * fee_percent * 2 + PERCENT) // (PERCENT * 2)
+    return {"fee": fee, "refund": max(paid - fee, 0)}
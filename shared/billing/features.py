@@ -0,0 +1,50 @@
+from enum import Enum
+from typing import Dict, List
+
+from fastapi import status
+
+from shared.config import settings
+from shared.models import Tenant
+from shared.api import APIError, ErrorCode
+
+
+class Feature(str, Enum):
+    """Features that can be enabled or disabled per tenant (plan)."""
+    REVIEWS = "reviews"
+    DEPOSITS = "deposits"
+    MULTI_LOCATION = "multi_location"
+
+
+def get_tenant_features(tenant: Tenant) -> Dict[str, bool]:
+    """
+    Get all features of the tenant with whether each is enabled.
+
+    Tenant.features overrides DEFAULT_TENANT_FEATURES per feature, e.g.
+    {"deposits": false}; unknown keys are ignored.
+    """
+    defaults = settings.default_tenant_features_list
+    overrides = tenant.features or {}
+    return {
+        feature.value: bool(overrides.get(feature.value, feature.value in defaults))
+        for feature in Feature
+    }
+
+
+def enabled_features(tenant: Tenant) -> List[str]:
+    """Names of the features enabled for the tenant."""
+    return [name for name, enabled in get_tenant_features(tenant).items() if enabled]
+
+
+def has_feature(tenant: Tenant, feature: Feature) -> bool:
+    """Check if the feature is enabled for the tenant."""
+    return get_tenant_features(tenant)[feature.value]
+
+
+def require_feature(tenant: Tenant, feature: Feature) -> None:
+    """Raise 403 FEATURE_NOT_AVAILABLE unless the feature is enabled for the tenant."""
+    if not has_feature(tenant, feature):
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.FEATURE_NOT_AVAILABLE,
+            details={"feature": feature.value}
+        )
@@ -0,0 +1,23 @@
+from decimal import Decimal, ROUND_HALF_UP
+from typing import Optional, Union
+
+from shared.config import settings
+from shared.models import Tenant
+
+# Monetary amounts are stored as integer minor units (tiyn, cents)
+MINOR_UNITS = 100
+
+
+def to_minor(amount: Union[int, float, str, Decimal]) -> int:
+    """Convert amount in major units to integer minor units."""
+    return int((Decimal(str(amount)) * MINOR_UNITS).quantize(Decimal("1"), rounding=ROUND_HALF_UP))
+
+
+def to_major(minor: Optional[int]) -> float:
+    """Convert integer minor units to amount in major units for responses."""
+    return float(Decimal(minor or 0) / MINOR_UNITS)
+
+
+def tenant_currency(tenant: Optional[Tenant]) -> str:
+    """Get currency of the tenant."""
+    return (tenant.currency if tenant else None) or settings.DEFAULT_CURRENCY
@@ -0,0 +1,98 @@
+from sqlalchemy.orm import Session
+from datetime import datetime, timedelta
+from typing import Optional
+import math
+import logging
+
+from shared.models import Tenant, TenantStatus, AdminAction
+
+logger = logging.getLogger(__name__)
+
+# Tenant statuses that depend on trial/subscription state
+BILLED_STATUSES = (TenantStatus.TRIAL, TenantStatus.ACTIVE, TenantStatus.EXPIRED)
+
+
+def get_subscription_status(tenant: Tenant, now: Optional[datetime] = None) -> dict:
+    """
+    Get tenant subscription state.
+
+    plan is "paid" once a subscription was recorded, "trial" before that,
+    also for tenants approved (ACTIVE) before paying, and "none" for
+    tenants without a trial end date.
+    """
+    now = now or datetime.utcnow()
+
+    if tenant.subscription_end_date:
+        plan, ends_at = "paid", tenant.subscription_end_date
+    elif tenant.trial_end_date and tenant.status in BILLED_STATUSES:
+        plan, ends_at = "trial", tenant.trial_end_date
+    else:
+        plan, ends_at = "none", None
+
+    days_remaining = None
+    if ends_at:
+        days_remaining = max(math.ceil((ends_at - now).total_seconds() / 86400), 0)
+
+    in_good_standing = (
+        tenant.status in (TenantStatus.TRIAL, TenantStatus.ACTIVE)
+        and (ends_at is None or ends_at > now)
+    )
+
+    return {
+        "tenant_id": tenant.id,
+        "status": tenant.status.value,
+        "plan": plan,
+        "ends_at": ends_at.isoformat() if ends_at else None,
+        "days_remaining": days_remaining,
+        "in_good_standing": in_good_standing
+    }
+
+
+def is_publicly_visible(tenant: Tenant, now: Optional[datetime] = None) -> bool:
+    """
+    Check if the business page of the tenant is public and accepts bookings.
+
+    Only TRIAL and ACTIVE tenants whose trial or subscription hasn't ended
+    are visible, even before the expiry job moves them to EXPIRED.
+    """
+    return get_subscription_status(tenant, now)["in_good_standing"]
+
+
+def is_access_blocked(tenant: Tenant, now: Optional[datetime] = None) -> bool:
+    """Check if staff of the tenant are blocked because trial or subscription lapsed."""
+    return tenant.status in BILLED_STATUSES and not get_subscription_status(tenant, now)["in_good_standing"]
+
+
+def record_subscription(
+    db: Session,
+    tenant: Tenant,
+    period_days: int,
+    admin_id: Optional[int] = None,
+    reference: Optional[str] = None
+) -> Tenant:
+    """
+    Record paid subscription period and activate the tenant.
+
+    The period is added to the current subscription if it is still active,
+    otherwise it starts now. Only billed tenants are activated, a suspended
+    or rejected one keeps its status. The caller commits, so the
+    subscription can be recorded in the same transaction as the payment.
+    """
+    now = datetime.utcnow()
+    start = max(tenant.subscription_end_date or now, now)
+
+    tenant.subscription_end_date = start + timedelta(days=period_days)
+    if tenant.status in BILLED_STATUSES:
+        tenant.status = TenantStatus.ACTIVE
+
+    db.add(AdminAction(
+        admin_id=admin_id,
+        action_type="SUBSCRIPTION_RECORDED",
+        target_type="tenant",
+        target_id=tenant.id,
+        details=f"{period_days} days until {tenant.subscription_end_date.isoformat()}"
+                + (f", reference {reference}" if reference else "")
+    ))
+
+    logger.info(f"Subscription recorded: tenant={tenant.id}, until={tenant.subscription_end_date}")
+    return tenant
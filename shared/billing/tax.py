@@ -0,0 +1,35 @@
+from typing import Optional
+
+from shared.models import Tenant, Service
+
+# Tax rates are stored in basis points (1200 = 12%)
+BASIS_POINTS = 10000
+
+
+def compute_tax(price: int, rate_bp: int, inclusive: bool) -> dict:
+    """
+    Compute tax breakdown of a price in minor units.
+
+    Inclusive tax is contained in the price, added tax is charged on top.
+    Tax is rounded half up to the nearest minor unit.
+    """
+    if rate_bp <= 0:
+        return {"net": price, "tax": 0, "total": price}
+
+    if inclusive:
+        net = (price * BASIS_POINTS * 2 + (BASIS_POINTS + rate_bp)) // ((BASIS_POINTS + rate_bp) * 2)
+        return {"net": net, "tax": price - net, "total": price}
+
+    tax = (price * rate_bp * 2 + BASIS_POINTS) // (BASIS_POINTS * 2)
+    return {"net": price, "tax": tax, "total": price + tax}
+
+
+def get_tax_config(tenant: Optional[Tenant], service: Service) -> dict:
+    """Get tax rate and mode of the service, falling back to the tenant."""
+    rate_bp = service.tax_rate if service.tax_rate is not None else (tenant.tax_rate if tenant else None)
+    inclusive = service.tax_inclusive if service.tax_inclusive is not None else (tenant.tax_inclusive if tenant else None)
+
+    return {
+        "rate_bp": rate_bp or 0,
+        "inclusive": True if inclusive is None else inclusive
+    }
@@ -1,9 +1,15 @@
 from .redis_client import (
     redis_client,
     RedisClient,
+    CacheError,
+    get_cache_error_counts,
+    render_prometheus_cache_metrics,
     build_cache_key,
     cache_availability,
+    get_availability_version,
     get_cached_availability,
+    invalidate_availability,
+    invalidate_master_availability,
     cache_business_info,
     get_cached_business_info,
     invalidate_cache_pattern
@@ -12,9 +18,15 @@ from .redis_client import (
 __all__ = [
     "redis_client",
     "RedisClient",
+    "CacheError",
+    "get_cache_error_counts",
+    "render_prometheus_cache_metrics",
     "build_cache_key",
     "cache_availability",
+    "get_availability_version",
     "get_cached_availability",
+    "invalidate_availability",
+    "invalidate_master_availability",
     "cache_business_info",
     "get_cached_business_info",
     "invalidate_cache_pattern"
@@ -1,13 +1,39 @@
 import redis
 import json
 import logging
-from typing import Any, Optional
-from datetime import timedelta
+from collections import Counter
+from typing import Any, Dict, Optional
+from datetime import date, timedelta
 
 from shared.config import settings
 
 logger = logging.getLogger(__name__)
 
+# Redis failures by operation, exported as metrics
+cache_errors: Counter = Counter()
+
+
+class CacheError(Exception):
+    """Redis failure, as opposed to a missing key."""
+
+
+def get_cache_error_counts() -> Dict[str, int]:
+    """Get Redis failure counts by operation."""
+    return dict(cache_errors)
+
+
+def render_prometheus_cache_metrics(service: str) -> str:
+    """Render Redis failure counts in Prometheus text format."""
+    lines = [
+        "# HELP cache_errors_total Redis failures by operation.",
+        "# TYPE cache_errors_total counter"
+    ]
+
+    for operation, value in sorted(cache_errors.items()):
+        lines.append(f'cache_errors_total{{service="{service}",operation="{operation}"}} {value}')
+
+    return "\n".join(lines) + "\n"
+
 
 class RedisClient:
     """Redis client wrapper with JSON serialization support."""
@@ -20,15 +46,34 @@ class RedisClient:
             decode_responses=True
         )
 
-    def get(self, key: str) -> Optional[Any]:
-        """Get value from Redis and deserialize from JSON."""
+    def fetch(self, key: str) -> Optional[Any]:
+        """
+        Get value from Redis and deserialize from JSON.
+
+        Returns None for a missing key and raises CacheError when Redis
+        fails, so callers can tell a miss from an outage.
+        """
         try:
             value = self.client.get(key)
-            if value:
-                return json.loads(value)
-            return None
-        except Exception as e:
+        except redis.RedisError as e:
+            cache_errors["get"] += 1
             logger.error(f"Redis GET error for key {key}: {e}")
+            raise CacheError(str(e)) from e
+
+        if value is None:
+            return None
+
+        try:
+            return json.loads(value)
+        except ValueError:
+            logger.warning(f"Invalid cached value for key {key}, treating as miss")
+            return None
+
+    def get(self, key: str) -> Optional[Any]:
+        """Get value from Redis, None for both a miss and a failure."""
+        try:
+            return self.fetch(key)
+        except CacheError:
             return None
 
     def set(self, key: str, value: Any, expire: Optional[int] = None) -> bool:
@@ -40,6 +85,7 @@ class RedisClient:
             else:
                 return self.client.set(key, serialized)
         except Exception as e:
+            cache_errors["set"] += 1
             logger.error(f"Redis SET error for key {key}: {e}")
             return False
 
@@ -48,6 +94,7 @@ class RedisClient:
         try:
             return self.client.delete(*keys)
         except Exception as e:
+            cache_errors["delete"] += 1
             logger.error(f"Redis DELETE error: {e}")
             return 0
 
@@ -75,14 +122,47 @@ class RedisClient:
             logger.error(f"Redis TTL error for key {key}: {e}")
             return -1
 
-    def incr(self, key: str, amount: int = 1) -> Optional[int]:
-        """Increment value of key."""
+    def increment(self, key: str, amount: int = 1) -> int:
+        """Increment value of key, raising CacheError when Redis fails."""
         try:
             return self.client.incr(key, amount)
-        except Exception as e:
+        except redis.RedisError as e:
+            cache_errors["incr"] += 1
             logger.error(f"Redis INCR error for key {key}: {e}")
+            raise CacheError(str(e)) from e
+
+    def incr(self, key: str, amount: int = 1) -> Optional[int]:
+        """Increment value of key, None on a failure."""
+        try:
+            return self.increment(key, amount)
+        except CacheError:
             return None
 
+    def delete_pattern(self, pattern: str, batch_size: int = 500) -> int:
+        """
+        Delete all keys matching pattern.
+
+        Iterates with SCAN instead of KEYS so Redis is not blocked on
+        large keyspaces, deleting keys in batches.
+        """
+        deleted = 0
+        batch = []
+
+        try:
+            for key in self.client.scan_iter(match=pattern, count=batch_size):
+                batch.append(key)
+                if len(batch) >= batch_size:
+                    deleted += self.client.delete(*batch)
+                    batch = []
+
+            if batch:
+                deleted += self.client.delete(*batch)
+        except Exception as e:
+            cache_errors["delete"] += 1
+            logger.error(f"Redis DELETE error for pattern {pattern}: {e}")
+
+        return deleted
+
     def keys(self, pattern: str = "*"):
         """Get all keys matching pattern."""
         try:
@@ -118,17 +198,85 @@ def build_cache_key(prefix: str, *args) -> str:
     return f"{prefix}:" + ":".join(str(arg) for arg in args)
 
 
+# Availability is cached under a version bumped on every booking change.
+# A request that read slots before a booking committed caches them under
+# the old version, which is no longer read, instead of overwriting the
+# invalidation with stale slots. Dates available in a month are cached the
+# same way, under the month ("2024-05") instead of the date.
+AVAILABILITY_TTL_SECONDS = 300  # 5 minutes
+AVAILABILITY_VERSION_TTL_SECONDS = 86400
+
+
 # Common cache operations
-def cache_availability(tenant_id: int, master_id: int, date: str, data: dict) -> bool:
-    """Cache master availability data."""
-    key = build_cache_key("availability", tenant_id, master_id, date)
-    return redis_client.set(key, data, expire=300)  # 5 minutes
+def get_availability_version(tenant_id: int, master_id: int, date: str) -> int:
+    """
+    Get current version of the master's availability on the date.
 
+    Read before computing availability. Raises CacheError when Redis fails.
+    """
+    version = redis_client.fetch(build_cache_key("availability_version", tenant_id, master_id, date))
+    return version if isinstance(version, int) else 0
 
-def get_cached_availability(tenant_id: int, master_id: int, date: str) -> Optional[dict]:
-    """Get cached master availability."""
-    key = build_cache_key("availability", tenant_id, master_id, date)
-    return redis_client.get(key)
+
+def cache_availability(tenant_id: int, master_id: int, date: str, version: int, variant: str, data: dict) -> bool:
+    """
+    Cache master availability computed at the given version.
+
+    variant tells apart queries with different results for the same
+    master and date, e.g. booking duration.
+    """
+    key = build_cache_key("availability", tenant_id, master_id, date, version, variant)
+    return redis_client.set(key, data, expire=AVAILABILITY_TTL_SECONDS)
+
+
+def get_cached_availability(tenant_id: int, master_id: int, date: str, version: int, variant: str) -> Optional[dict]:
+    """
+    Get cached master availability of the query variant.
+
+    Returns None on a miss, raises CacheError when Redis fails.
+    """
+    key = build_cache_key("availability", tenant_id, master_id, date, version, variant)
+    return redis_client.fetch(key)
+
+
+def bump_availability_version(tenant_id: int, master_id: int, period: str) -> Optional[int]:
+    """Bump the availability version of a date or month, None when Redis fails."""
+    key = build_cache_key("availability_version", tenant_id, master_id, period)
+    version = redis_client.incr(key)
+    if version is not None:
+        redis_client.expire(key, AVAILABILITY_VERSION_TTL_SECONDS)
+    return version
+
+
+def invalidate_availability(tenant_id: int, master_id: int, date: str) -> Optional[int]:
+    """
+    Invalidate cached master availability after a booking change.
+
+    Bumps the version of the date and of its month, so must be called
+    after the change is committed. Entries of older versions are no longer
+    read and expire on their own. Returns the new version of the date,
+    None when Redis fails.
+    """
+    bump_availability_version(tenant_id, master_id, date[:7])
+    return bump_availability_version(tenant_id, master_id, date)
+
+
+def invalidate_master_availability(tenant_id: int, master_id: int, first: date, last: date) -> None:
+    """
+    Invalidate cached availability of the master from first to last date,
+    inclusive, e.g. after a schedule or time off change.
+
+    Bumps versions like invalidate_availability, each month once.
+    """
+    months = set()
+    day = first
+    while day <= last:
+        bump_availability_version(tenant_id, master_id, day.isoformat())
+        months.add(day.strftime("%Y-%m"))
+        day += timedelta(days=1)
+
+    for month in sorted(months):
+        bump_availability_version(tenant_id, master_id, month)
 
 
 def cache_business_info(subdomain: str, data: dict) -> bool:
@@ -145,7 +293,4 @@ def get_cached_business_info(subdomain: str) -> Optional[dict]:
 
 def invalidate_cache_pattern(pattern: str) -> int:
     """Invalidate all cache keys matching pattern."""
-    keys = redis_client.keys(pattern)
-    if keys:
-        return redis_client.delete(*keys)
-    return 0
+    return redis_client.delete_pattern(pattern)
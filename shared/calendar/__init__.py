@@ -0,0 +1,11 @@
+from .ics import ICS_CONTENT_TYPE, tenant_zone, build_calendar, booking_ics
+from .zones import supported_timezones, is_valid_timezone
+
+__all__ = [
+    "ICS_CONTENT_TYPE",
+    "tenant_zone",
+    "build_calendar",
+    "booking_ics",
+    "supported_timezones",
+    "is_valid_timezone"
+]
@@ -0,0 +1,172 @@
+from datetime import datetime, timedelta, timezone
+from typing import Iterable, Optional
+from zoneinfo import ZoneInfo, ZoneInfoNotFoundError
+import logging
+
+from shared.config import settings
+from shared.models import Booking, BookingStatus, Tenant
+
+logger = logging.getLogger(__name__)
+
+ICS_CONTENT_TYPE = "text/calendar"
+PRODUCT_ID = "-//Jazyl//Booking Platform//RU"
+
+# RFC 5545 limits content lines to 75 octets
+MAX_LINE_OCTETS = 75
+
+
+def tenant_zone(tenant: Optional[Tenant]) -> ZoneInfo:
+    """Get timezone of the tenant, DEFAULT_TIMEZONE if unset or unknown."""
+    name = (tenant.timezone if tenant else None) or settings.DEFAULT_TIMEZONE
+
+    try:
+        return ZoneInfo(name)
+    except (ZoneInfoNotFoundError, ValueError):
+        logger.warning(f"Unknown timezone {name}, using {settings.DEFAULT_TIMEZONE}")
+        return ZoneInfo(settings.DEFAULT_TIMEZONE)
+
+
+def escape_text(value: str) -> str:
+    """Escape TEXT value (RFC 5545 section 3.3.11)."""
+    return (
+        value.replace("\\", "\\\\")
+        .replace(";", "\\;")
+        .replace(",", "\\,")
+        .replace("\r\n", "\\n")
+        .replace("\n", "\\n")
+    )
+
+
+def fold_line(line: str) -> str:
+    """Fold content line longer than 75 octets (RFC 5545 section 3.1)."""
+    encoded = line.encode("utf-8")
+    if len(encoded) <= MAX_LINE_OCTETS:
+        return line
+
+    parts = []
+    current = ""
+    limit = MAX_LINE_OCTETS
+
+    for char in line:
+        if len((current + char).encode("utf-8")) > limit:
+            parts.append(current)
+            current = ""
+            # Continuation lines start with a space
+            limit = MAX_LINE_OCTETS - 1
+        current += char
+
+    parts.append(current)
+    return "\r\n ".join(parts)
+
+
+def format_utc(value: datetime) -> str:
+    """Format aware datetime as UTC date-time."""
+    return value.astimezone(timezone.utc).strftime("%Y%m%dT%H%M%SZ")
+
+
+def booking_location(booking: Booking) -> Optional[str]:
+    """Location of the booking: master's location, else the business name."""
+    location = booking.master.location if booking.master else None
+    if location:
+        return ", ".join(p for p in (location.name, location.address) if p)
+    return booking.tenant.business_name if booking.tenant else None
+
+
+def booking_sequence(booking: Booking) -> int:
+    """
+    Revision of the booking event.
+
+    Seconds between creation and the last update: grows on every change,
+    so calendar apps apply reschedules and cancellations.
+    """
+    if not booking.created_at or not booking.updated_at:
+        return 0
+    return max(int((booking.updated_at - booking.created_at).total_seconds()), 0)
+
+
+def master_description(booking: Booking) -> str:
+    """Event description for the master: client contacts and notes."""
+    client = booking.client
+    parts = [client.full_name, client.phone] if client else []
+    if booking.client_notes:
+        parts.append(booking.client_notes)
+    return "\n".join(p for p in parts if p)
+
+
+def booking_vevent(booking: Booking, for_master: bool = False) -> list:
+    """
+    Build VEVENT lines of the booking.
+
+    Events for the client name the business and the master, events for the
+    master's own calendar name the client instead.
+    """
+    zone = tenant_zone(booking.tenant)
+
+    # Booking dates are stored in the tenant's local time
+    start = booking.booking_date.replace(tzinfo=zone)
+    end = start + timedelta(minutes=booking.duration_minutes)
+
+    service = booking.service_names
+    if for_master:
+        other = booking.client.full_name if booking.client else ""
+        description = master_description(booking)
+    else:
+        other = booking.tenant.business_name if booking.tenant else ""
+        description = booking.master.full_name if booking.master else ""
+    summary = " — ".join(p for p in (service, other) if p)
+
+    lines = [
+        "BEGIN:VEVENT",
+        f"UID:booking-{booking.id}@{settings.BASE_DOMAIN}",
+        f"DTSTAMP:{format_utc(datetime.now(timezone.utc))}",
+        f"SEQUENCE:{booking_sequence(booking)}",
+        f"DTSTART:{format_utc(start)}",
+        f"DTEND:{format_utc(end)}",
+        f"SUMMARY:{escape_text(summary)}",
+    ]
+
+    if booking.updated_at:
+        # updated_at is naive UTC
+        lines.append(f"LAST-MODIFIED:{format_utc(booking.updated_at.replace(tzinfo=timezone.utc))}")
+
+    if description:
+        lines.append(f"DESCRIPTION:{escape_text(description)}")
+
+    location = booking_location(booking)
+    if location:
+        lines.append(f"LOCATION:{escape_text(location)}")
+
+    status = "CANCELLED" if booking.status == BookingStatus.CANCELLED else "CONFIRMED"
+    lines += [f"STATUS:{status}", "END:VEVENT"]
+
+    return lines
+
+
+def build_calendar(
+    bookings: Iterable[Booking],
+    name: Optional[str] = None,
+    for_master: bool = False
+) -> str:
+    """Build RFC 5545 calendar with an event per booking."""
+    lines = [
+        "BEGIN:VCALENDAR",
+        "VERSION:2.0",
+        f"PRODID:{PRODUCT_ID}",
+        "CALSCALE:GREGORIAN",
+        "METHOD:PUBLISH",
+    ]
+
+    if name:
+        lines.append(f"X-WR-CALNAME:{escape_text(name)}")
+
+    for booking in bookings:
+        lines += booking_vevent(booking, for_master)
+
+    lines.append("END:VCALENDAR")
+
+    return "\r\n".join(fold_line(line) for line in lines) + "\r\n"
+
+
+def booking_ics(booking: Booking) -> str:
+    """Build calendar file of a single booking."""
+    return build_calendar([booking])
@@ -0,0 +1,14 @@
+from functools import lru_cache
+from typing import List
+from zoneinfo import available_timezones
+
+
+@lru_cache(maxsize=1)
+def supported_timezones() -> List[str]:
+    """IANA timezone names tenants may use, e.g. "Asia/Almaty", sorted."""
+    return sorted(name for name in available_timezones() if "/" in name or name == "UTC")
+
+
+def is_valid_timezone(name: str) -> bool:
+    """Check the name is a supported IANA timezone, e.g. rejecting "Asia/Almati"."""
+    return name in supported_timezones()
@@ -1,11 +1,23 @@
 from pydantic_settings import BaseSettings
-from typing import List
+from typing import Dict, List
+
+
+def parse_limits(value: str) -> Dict[str, int]:
+    """Parse "key=limit,..." setting into a dict."""
+    limits = {}
+    for entry in value.split(","):
+        key, _, limit = entry.partition("=")
+        if key.strip() and limit.strip():
+            limits[key.strip()] = int(limit)
+    return limits
 
 
 class Settings(BaseSettings):
     # Environment
     ENVIRONMENT: str = "development"
     DEBUG: bool = True
+    # Include exception messages in 500 responses, only for local debugging
+    EXPOSE_ERROR_DETAILS: bool = False
     LOG_LEVEL: str = "INFO"
 
     # Domain
@@ -15,11 +27,19 @@ class Settings(BaseSettings):
 
     # Database
     DATABASE_URL: str
+    DATABASE_READ_URL: str = ""
     POSTGRES_USER: str = "booking_user"
     POSTGRES_PASSWORD: str = "booking_password"
     POSTGRES_DB: str = "booking_platform"
     DB_POOL_SIZE: int = 20
     DB_MAX_OVERFLOW: int = 10
+    DB_POOL_TIMEOUT_SECONDS: float = 5.0
+    DB_STATEMENT_TIMEOUT_MS: int = 8000
+    DB_POOL_RECYCLE_SECONDS: int = 3600
+    DB_POOL_MAX_IDLE_SECONDS: int = 600
+    # Runs of a transaction aborted by a serialization failure or deadlock
+    DB_TX_RETRY_ATTEMPTS: int = 3
+    DB_TX_RETRY_DELAY_MS: int = 50
 
     # Redis
     REDIS_URL: str = "redis://redis:6379/0"
@@ -32,11 +52,23 @@ class Settings(BaseSettings):
     JWT_ALGORITHM: str = "HS256"
     ACCESS_TOKEN_EXPIRE_MINUTES: int = 1440
     REFRESH_TOKEN_EXPIRE_DAYS: int = 7
+    ADMIN_ACCESS_TOKEN_EXPIRE_MINUTES: int = 60
+    IMPERSONATION_TOKEN_EXPIRE_MINUTES: int = 30
 
     # WhatsApp
     WHATSAPP_SERVICE_URL: str = "http://whatsapp-service:3000"
     WHATSAPP_ENABLED: bool = True
 
+    # Email
+    EMAIL_ENABLED: bool = False
+    SMTP_HOST: str = "smtp.gmail.com"
+    SMTP_PORT: int = 587
+    SMTP_SECURITY: str = "auto"
+    SMTP_USER: str = ""
+    SMTP_PASSWORD: str = ""
+    SMTP_TIMEOUT_SECONDS: float = 10.0
+    EMAIL_FROM: str = "noreply@jazyl.tech"
+
     # Service Ports
     API_GATEWAY_PORT: int = 8000
     API_GATEWAY_HOST: str = "0.0.0.0"
@@ -46,6 +78,7 @@ class Settings(BaseSettings):
     BOOKING_SERVICE_HOST: str = "0.0.0.0"
     NOTIFICATION_SERVICE_PORT: int = 8003
     NOTIFICATION_SERVICE_HOST: str = "0.0.0.0"
+    NOTIFICATION_SERVICE_URL: str = "http://notification-service:8003"
     PAYMENT_SERVICE_PORT: int = 8004
     PAYMENT_SERVICE_HOST: str = "0.0.0.0"
     ADMIN_SERVICE_PORT: int = 8005
@@ -55,22 +88,112 @@ class Settings(BaseSettings):
     # Security
     BCRYPT_ROUNDS: int = 12
     RATE_LIMIT_PER_MINUTE: int = 100
-    CORS_ORIGINS: str = "*"
+    # Tighter per-IP limits of sensitive routes, "path=limit,..."
+    RATE_LIMIT_ROUTES: str = (
+        "/api/v1/login=10,/api/v1/admin/login=5,"
+        "/api/v1/public/client/request-code=5,/api/v1/public/client/verify=10,"
+        "/api/v1/public/subdomain-available=30,/api/v1/public/booking/resend-confirmation=5"
+    )
+    # Per authenticated user, 0 disables; RATE_LIMIT_ROLES overrides by role, "ROLE=limit,..."
+    RATE_LIMIT_PER_USER_PER_MINUTE: int = 0
+    RATE_LIMIT_ROLES: str = ""
+    # Explicit list: "*" is refused by the gateway while credentials are allowed
+    CORS_ORIGINS: str = "https://jazyl.tech,https://admin.jazyl.tech"
+    CORS_ALLOW_CREDENTIALS: bool = True
+    CORS_MAX_AGE_SECONDS: int = 600
+    MAX_REQUEST_BODY_BYTES: int = 1048576
+    REQUEST_TIMEOUT_SECONDS: float = 30.0
+    UPSTREAM_FAST_TIMEOUT_SECONDS: float = 3.0
+    UPSTREAM_READ_TIMEOUT_SECONDS: float = 10.0
+    UPSTREAM_WRITE_TIMEOUT_SECONDS: float = 15.0
+    UPSTREAM_BULK_TIMEOUT_SECONDS: float = 60.0
+    UPSTREAM_DNS_TTL_SECONDS: float = 10.0
+    UPSTREAM_UNHEALTHY_SECONDS: float = 30.0
+    UPSTREAM_RETRY_ATTEMPTS: int = 2
+    UPSTREAM_RETRY_BACKOFF_SECONDS: float = 0.2
+    SSE_KEEPALIVE_SECONDS: float = 15.0
+    # CAPTCHA on public booking, client verification and registration:
+    # hcaptcha, recaptcha or turnstile; empty disables
+    CAPTCHA_PROVIDER: str = ""
+    CAPTCHA_SECRET_KEY: str = ""
+    CAPTCHA_VERIFY_TIMEOUT_SECONDS: float = 5.0
 
     # Business Logic
     DEFAULT_TRIAL_DAYS: int = 30
     BOOKING_ADVANCE_LIMIT_DAYS: int = 30
     CANCELLATION_HOURS: int = 2
+    # Cancellation fee tiers "hours_before:fee_percent,...", e.g. "24:0,2:50,0:100";
+    # empty: free until CANCELLATION_HOURS before the booking
+    CANCELLATION_POLICY: str = ""
+    CLIENT_SESSION_EXPIRE_DAYS: int = 30
+    CLIENT_VERIFICATION_CODE_EXPIRE_MINUTES: int = 10
+    # Confirmation resends allowed per booking within the window
+    CONFIRMATION_RESEND_LIMIT: int = 3
+    CONFIRMATION_RESEND_WINDOW_MINUTES: int = 60
+    # Features enabled for tenants without an override in tenant.features
+    DEFAULT_TENANT_FEATURES: str = "reviews,deposits,multi_location"
+    # Time off requested by masters waits for owner or manager approval
+    TIME_OFF_REQUIRES_APPROVAL: bool = True
+    TIME_OFF_MAX_DAYS: int = 90
     REMINDER_HOURS: str = "24,2"
+    # Reminder channels (WHATSAPP, EMAIL) of reminders sent at most
+    # REMINDER_SAME_DAY_HOURS before the booking and of earlier ones
+    REMINDER_SAME_DAY_HOURS: int = 12
+    REMINDER_SAME_DAY_CHANNELS: str = "WHATSAPP"
+    REMINDER_ADVANCE_CHANNELS: str = "EMAIL"
+    DEFAULT_TIMEZONE: str = "Asia/Almaty"
+    TRIAL_WARNING_DAYS: int = 3
+    TRIAL_UPGRADE_URL: str = "https://jazyl.tech/billing"
+    POPULARITY_WINDOW_DAYS: int = 90
+    ANALYTICS_BATCH_TENANTS: int = 100
+    AVAILABILITY_WARM_DAYS: int = 7
+    CALENDAR_FEED_URL: str = "https://api.jazyl.tech/api/v1/public/calendar/{token}.ics"
+    CALENDAR_FEED_PAST_DAYS: int = 7
+
+    # Payments
+    SUBSCRIPTION_PRICE: float = 9900.0
+    SUBSCRIPTION_CURRENCY: str = "KZT"
+    SUBSCRIPTION_PERIOD_DAYS: int = 30
+    PAYMENT_CHECKOUT_URL: str = "https://pay.jazyl.tech/checkout/{payment_id}"
+    # Refunds are posted here, signed like webhooks
+    PAYMENT_REFUND_URL: str = "https://pay.jazyl.tech/refunds"
+    # Key of webhook signatures, shared with the payment provider
+    PAYMENT_WEBHOOK_SECRET: str
+    DEFAULT_CURRENCY: str = "KZT"
+    DEPOSIT_PERCENT: int = 30
+    DEPOSIT_PAYMENT_TIMEOUT_MINUTES: int = 30
 
     # i18n
     DEFAULT_LANGUAGE: str = "ru"
     SUPPORTED_LANGUAGES: str = "ru,en,kk"
+    I18N_STRICT: bool = False
+    I18N_LOG_MISSING: bool = True
 
     # Celery
     CELERY_BROKER_URL: str = "redis://redis:6379/1"
     CELERY_RESULT_BACKEND: str = "redis://redis:6379/2"
 
+    # Job queues
+    JOB_HIGH_PRIORITY_QUEUE: str = "notifications"
+    JOB_LOW_PRIORITY_QUEUE: str = "background"
+    JOB_HIGH_PRIORITY_TASKS: str = "notifications.send_reminder,notifications.promote_scheduled_jobs"
+    JOB_HIGH_PRIORITY_CONCURRENCY: int = 4
+    JOB_LOW_PRIORITY_CONCURRENCY: int = 1
+    JOB_RETRY_ATTEMPTS: int = 3
+    JOB_RETRY_DELAY_SECONDS: int = 30
+    JOB_RETRY_MAX_DELAY_SECONDS: int = 600
+    JOB_VISIBILITY_TIMEOUT_SECONDS: int = 90000
+    JOB_PROCESSING_LOCK_SECONDS: int = 300
+    JOB_SOFT_TIME_LIMIT_SECONDS: int = 60
+    JOB_TIME_LIMIT_SECONDS: int = 90
+    JOB_SCHEDULER_INTERVAL_SECONDS: float = 5.0
+    JOB_SCHEDULER_BATCH_SIZE: int = 500
+    JOB_SCHEDULER_LOCK_SECONDS: int = 60
+    REMINDER_DEDUP_TTL_SECONDS: int = 172800
+    JOB_HISTORY_TTL_SECONDS: int = 604800
+    JOB_HISTORY_MAX_JOBS: int = 10000
+    JOB_HISTORY_SKIP_TASKS: str = "notifications.promote_scheduled_jobs"
+
     class Config:
         env_file = ".env"
         case_sensitive = True
@@ -89,5 +212,44 @@ class Settings(BaseSettings):
     def reminder_hours_list(self) -> List[int]:
         return [int(h.strip()) for h in self.REMINDER_HOURS.split(",")]
 
+    @property
+    def reminder_same_day_channels_list(self) -> List[str]:
+        return [c.strip() for c in self.REMINDER_SAME_DAY_CHANNELS.split(",") if c.strip()]
+
+    @property
+    def reminder_advance_channels_list(self) -> List[str]:
+        return [c.strip() for c in self.REMINDER_ADVANCE_CHANNELS.split(",") if c.strip()]
+
+    @property
+    def job_high_priority_tasks_list(self) -> List[str]:
+        return [t.strip() for t in self.JOB_HIGH_PRIORITY_TASKS.split(",") if t.strip()]
+
+    @property
+    def job_history_skip_tasks_list(self) -> List[str]:
+        return [t.strip() for t in self.JOB_HISTORY_SKIP_TASKS.split(",") if t.strip()]
+
+    @property
+    def default_tenant_features_list(self) -> List[str]:
+        return [f.strip() for f in self.DEFAULT_TENANT_FEATURES.split(",") if f.strip()]
+
+    @property
+    def cancellation_policy_list(self) -> List[Dict[str, int]]:
+        if not self.CANCELLATION_POLICY.strip():
+            return [{"hours_before": self.CANCELLATION_HOURS, "fee_percent": 0}]
+        tiers = []
+        for entry in self.CANCELLATION_POLICY.split(","):
+            hours, _, percent = entry.partition(":")
+            if hours.strip() and percent.strip():
+                tiers.append({"hours_before": int(hours), "fee_percent": int(percent)})
+        return tiers
+
+    @property
+    def rate_limit_routes_map(self) -> Dict[str, int]:
+        return parse_limits(self.RATE_LIMIT_ROUTES)
+
+    @property
+    def rate_limit_roles_map(self) -> Dict[str, int]:
+        return parse_limits(self.RATE_LIMIT_ROLES)
+
 
 settings = Settings()
@@ -1,11 +1,31 @@
-from .database import Base, engine, SessionLocal, get_db, get_db_context, init_db, check_db_connection
+from .database import (
+    Base,
+    engine,
+    read_engine,
+    SessionLocal,
+    ReadSessionLocal,
+    get_db,
+    get_read_db,
+    get_db_context,
+    init_db,
+    check_db_connection
+)
+from .pool import get_pool_stats, render_prometheus_pool_metrics
+from .transaction import is_retryable_error, run_in_transaction
 
 __all__ = [
     "Base",
     "engine",
+    "read_engine",
     "SessionLocal",
+    "ReadSessionLocal",
     "get_db",
+    "get_read_db",
     "get_db_context",
     "init_db",
-    "check_db_connection"
+    "check_db_connection",
+    "get_pool_stats",
+    "render_prometheus_pool_metrics",
+    "is_retryable_error",
+    "run_in_transaction"
 ]
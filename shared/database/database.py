@@ -1,4 +1,4 @@
-from sqlalchemy import create_engine
+from sqlalchemy import create_engine, text
 from sqlalchemy.ext.declarative import declarative_base
 from sqlalchemy.orm import sessionmaker, Session
 from contextlib import contextmanager
@@ -6,20 +6,52 @@ from typing import Generator
 import logging
 
 from shared.config import settings
+from .pool import InstrumentedQueuePool, configure_idle_timeout
 
 logger = logging.getLogger(__name__)
 
+
+def connect_args(url: str) -> dict:
+    """Connection arguments applying the statement timeout on PostgreSQL."""
+    if not url.startswith("postgresql"):
+        return {}
+    return {"options": f"-c statement_timeout={settings.DB_STATEMENT_TIMEOUT_MS}"}
+
+
+def create_db_engine(url: str, **kwargs):
+    """Create engine with the configured pool settings."""
+    db_engine = create_engine(
+        url,
+        pool_size=settings.DB_POOL_SIZE,
+        max_overflow=settings.DB_MAX_OVERFLOW,
+        pool_timeout=settings.DB_POOL_TIMEOUT_SECONDS,
+        pool_recycle=settings.DB_POOL_RECYCLE_SECONDS,
+        pool_pre_ping=True,
+        poolclass=InstrumentedQueuePool,
+        connect_args=connect_args(url),
+        echo=settings.DEBUG,
+        **kwargs
+    )
+    configure_idle_timeout(db_engine)
+    return db_engine
+
+
 # Create SQLAlchemy engine
-engine = create_engine(
-    settings.DATABASE_URL,
-    pool_size=settings.DB_POOL_SIZE,
-    max_overflow=settings.DB_MAX_OVERFLOW,
-    pool_pre_ping=True,
-    echo=settings.DEBUG
-)
+engine = create_db_engine(settings.DATABASE_URL)
+
+# Read replica engine, the primary when no replica is configured.
+# Replica connections are read-only so writes can't go there by mistake.
+if settings.DATABASE_READ_URL:
+    read_engine = create_db_engine(
+        settings.DATABASE_READ_URL,
+        execution_options={"postgresql_readonly": True}
+    )
+else:
+    read_engine = engine
 
 # Create SessionLocal class
 SessionLocal = sessionmaker(autocommit=False, autoflush=False, bind=engine)
+ReadSessionLocal = sessionmaker(autocommit=False, autoflush=False, bind=read_engine)
 
 # Create Base class for models
 Base = declarative_base()
@@ -41,6 +73,21 @@ def get_db() -> Generator[Session, None, None]:
         db.close()
 
 
+def get_read_db() -> Generator[Session, None, None]:
+    """
+    Dependency for getting read-only database session.
+
+    Uses the read replica when DATABASE_READ_URL is set. The replica may
+    lag behind the primary, so endpoints that write or must see their own
+    writes use get_db instead.
+    """
+    db = ReadSessionLocal()
+    try:
+        yield db
+    finally:
+        db.close()
+
+
 @contextmanager
 def get_db_context():
     """
@@ -72,7 +119,7 @@ def check_db_connection() -> bool:
     """Check if database connection is working."""
     try:
         with engine.connect() as conn:
-            conn.execute("SELECT 1")
+            conn.execute(text("SELECT 1"))
         return True
     except Exception as e:
         logger.error(f"Database connection failed: {e}")
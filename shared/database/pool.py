@@ -0,0 +1,115 @@
+from sqlalchemy import event
+from sqlalchemy.exc import DisconnectionError
+from sqlalchemy.pool import QueuePool
+import threading
+import time
+import logging
+
+from shared.config import settings
+
+logger = logging.getLogger(__name__)
+
+# Minimum interval between pool saturation warnings
+SATURATION_LOG_INTERVAL_SECONDS = 60
+
+
+class InstrumentedQueuePool(QueuePool):
+    """
+    Queue pool counting checkouts that had to wait for a free connection.
+    """
+
+    def __init__(self, *args, **kwargs):
+        super().__init__(*args, **kwargs)
+        self._stats_lock = threading.Lock()
+        self.wait_count = 0
+        self.wait_duration = 0.0
+        self._last_saturation_log = 0.0
+
+    def is_saturated(self) -> bool:
+        """Check if all connections including overflow are in use."""
+        return self.checkedin() == 0 and self.checkedout() >= self.size() + self._max_overflow
+
+    def _do_get(self):
+        if not self.is_saturated():
+            return super()._do_get()
+
+        self._log_saturation()
+        started = time.monotonic()
+        try:
+            return super()._do_get()
+        finally:
+            with self._stats_lock:
+                self.wait_count += 1
+                self.wait_duration += time.monotonic() - started
+
+    def _log_saturation(self) -> None:
+        now = time.monotonic()
+        with self._stats_lock:
+            if now - self._last_saturation_log < SATURATION_LOG_INTERVAL_SECONDS:
+                return
+            self._last_saturation_log = now
+
+        logger.warning(
+            f"Database pool saturated: {self.checkedout()} connections in use, "
+            f"waiting up to {settings.DB_POOL_TIMEOUT_SECONDS}s"
+        )
+
+
+def configure_idle_timeout(engine) -> None:
+    """
+    Discard pooled connections idle longer than DB_POOL_MAX_IDLE_SECONDS.
+
+    Raising DisconnectionError on checkout makes the pool replace the
+    connection with a fresh one.
+    """
+    max_idle = settings.DB_POOL_MAX_IDLE_SECONDS
+    if max_idle <= 0:
+        return
+
+    @event.listens_for(engine, "checkin")
+    def on_checkin(dbapi_connection, connection_record):
+        connection_record.info["checked_in_at"] = time.monotonic()
+
+    @event.listens_for(engine, "checkout")
+    def on_checkout(dbapi_connection, connection_record, connection_proxy):
+        checked_in_at = connection_record.info.pop("checked_in_at", None)
+        if checked_in_at is not None and time.monotonic() - checked_in_at > max_idle:
+            raise DisconnectionError("Connection idle for too long")
+
+
+def get_pool_stats(engine) -> dict:
+    """Get connection pool usage of the engine."""
+    pool = engine.pool
+
+    return {
+        "size": pool.size(),
+        "max_connections": pool.size() + getattr(pool, "_max_overflow", 0),
+        "in_use": pool.checkedout(),
+        "idle": pool.checkedin(),
+        "overflow": max(pool.overflow(), 0),
+        "wait_count": getattr(pool, "wait_count", 0),
+        "wait_duration_seconds": round(getattr(pool, "wait_duration", 0.0), 3)
+    }
+
+
+def render_prometheus_pool_metrics(stats: dict, service: str) -> str:
+    """Render connection pool stats in Prometheus text format."""
+    metrics = [
+        ("db_pool_size", "gauge", "Configured pool size.", stats["size"]),
+        ("db_pool_max_connections", "gauge", "Pool size including overflow.", stats["max_connections"]),
+        ("db_pool_in_use", "gauge", "Connections checked out.", stats["in_use"]),
+        ("db_pool_idle", "gauge", "Idle connections in the pool.", stats["idle"]),
+        ("db_pool_wait_count", "counter", "Checkouts that waited for a free connection.", stats["wait_count"]),
+        ("db_pool_wait_duration_seconds", "counter", "Total time spent waiting for a connection.",
+         stats["wait_duration_seconds"])
+    ]
+
+    lines = []
+    for name, metric_type, help_text, value in metrics:
+        lines += [
+            f"# HELP {name} {help_text}",
+            f"# TYPE {name} {metric_type}",
+            f'{name}{{service="{service}"}} {value}'
+        ]
+
+    return "\n".join(lines) + "\n"
@@ -0,0 +1,48 @@
+from typing import Callable, Optional, TypeVar
+import logging
+import time
+
+from sqlalchemy.exc import DBAPIError
+from sqlalchemy.orm import Session
+
+from shared.config import settings
+
+logger = logging.getLogger(__name__)
+
+T = TypeVar("T")
+
+# PostgreSQL serialization_failure and deadlock_detected: the transaction
+# was aborted and succeeds when run again
+RETRYABLE_PGCODES = {"40001", "40P01"}
+
+
+def is_retryable_error(exc: Exception) -> bool:
+    """Check if a database error aborted the transaction for a serialization failure or deadlock."""
+    return isinstance(exc, DBAPIError) and getattr(exc.orig, "pgcode", None) in RETRYABLE_PGCODES
+
+
+def run_in_transaction(db: Session, work: Callable[[Session], T], attempts: Optional[int] = None) -> T:
+    """
+    Run work in a transaction of the session and commit it.
+
+    Rolls back and re-raises if work or the commit fails. Transactions
+    aborted by a serialization failure or deadlock run again, up to
+    DB_TX_RETRY_ATTEMPTS times in total with a growing delay, so work must
+    only change the database; messages and events are sent after this
+    returns.
+    """
+    attempts = attempts or settings.DB_TX_RETRY_ATTEMPTS
+
+    for attempt in range(1, attempts + 1):
+        try:
+            result = work(db)
+            db.commit()
+            return result
+        except Exception as e:
+            db.rollback()
+            if attempt == attempts or not is_retryable_error(e):
+                raise
+
+            delay = settings.DB_TX_RETRY_DELAY_MS * attempt / 1000
+            logger.warning(f"Transaction aborted ({e.orig.pgcode}), attempt {attempt}, retrying in {delay:.2f}s")
+            time.sleep(delay)
@@ -0,0 +1,23 @@
+from .email_client import (
+    EmailClient,
+    EmailError,
+    EmailTransientError,
+    EmailPermanentError,
+    email_client,
+    text_to_html
+)
+from .bounces import is_suppressed, record_bounce
+from .branding import DEFAULT_PRIMARY_COLOR, get_email_branding
+
+__all__ = [
+    "EmailClient",
+    "EmailError",
+    "EmailTransientError",
+    "EmailPermanentError",
+    "email_client",
+    "text_to_html",
+    "DEFAULT_PRIMARY_COLOR",
+    "get_email_branding",
+    "is_suppressed",
+    "record_bounce"
+]
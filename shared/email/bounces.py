@@ -0,0 +1,40 @@
+from sqlalchemy.orm import Session
+from datetime import datetime
+import logging
+
+from shared.models import EmailBounce
+
+logger = logging.getLogger(__name__)
+
+
+def normalize_email(email: str) -> str:
+    """Normalize address for bounce lookups."""
+    return email.strip().lower()
+
+
+def is_suppressed(db: Session, email: str) -> bool:
+    """Check if the address bounced permanently before."""
+    return db.query(EmailBounce.id).filter(
+        EmailBounce.email == normalize_email(email)
+    ).first() is not None
+
+
+def record_bounce(db: Session, email: str, reason: str) -> EmailBounce:
+    """
+    Record permanent delivery failure of the address.
+
+    Does not commit; the caller commits.
+    """
+    address = normalize_email(email)
+    bounce = db.query(EmailBounce).filter(EmailBounce.email == address).first()
+
+    if bounce:
+        bounce.bounce_count += 1
+        bounce.reason = reason
+        bounce.last_bounced_at = datetime.utcnow()
+    else:
+        bounce = EmailBounce(email=address, reason=reason)
+        db.add(bounce)
+
+    logger.warning(f"Email address {address} bounced: {reason}")
+    return bounce
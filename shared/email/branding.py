@@ -0,0 +1,20 @@
+from shared.tenants import get_tenant_settings
+
+# Used when the tenant has no branding color
+DEFAULT_PRIMARY_COLOR = "#2e7d32"
+
+
+def get_email_branding(tenant) -> dict:
+    """
+    Get email branding of the tenant: logo URL, primary color and
+    business name.
+
+    Missing or invalid values fall back to defaults, see get_tenant_settings.
+    """
+    branding = get_tenant_settings(tenant).branding
+
+    return {
+        "logo_url": branding.logo_url,
+        "primary_color": branding.primary_color or DEFAULT_PRIMARY_COLOR,
+        "business_name": tenant.business_name if tenant else None
+    }
@@ -0,0 +1,224 @@
+import smtplib
+import ssl
+import logging
+import html
+from email.message import EmailMessage
+from email.utils import formatdate, make_msgid
+from typing import List, Optional, Tuple, Union
+
+from shared.config import settings
+
+logger = logging.getLogger(__name__)
+
+class EmailError(Exception):
+    """Email delivery failure."""
+
+
+class EmailTransientError(EmailError):
+    """Temporary failure (4xx reply, connection problem), worth retrying."""
+
+
+class EmailPermanentError(EmailError):
+    """Permanent rejection of the recipient (5xx reply), must not be retried."""
+
+
+def classify_smtp_error(error: Exception) -> EmailError:
+    """Map smtplib error to a transient or permanent email error."""
+    if isinstance(error, smtplib.SMTPRecipientsRefused):
+        codes = [code for code, _ in error.recipients.values()]
+        if codes and all(code >= 500 for code in codes):
+            return EmailPermanentError(str(error.recipients))
+        return EmailTransientError(str(error.recipients))
+
+    # Authentication failures are configuration problems, not bad recipients
+    if isinstance(error, smtplib.SMTPAuthenticationError):
+        return EmailTransientError(str(error))
+
+    if isinstance(error, smtplib.SMTPResponseException) and error.smtp_code >= 500:
+        return EmailPermanentError(f"{error.smtp_code} {error.smtp_error!r}")
+
+    return EmailTransientError(str(error))
+
+
+# Port of SMTP over implicit TLS, other ports upgrade with STARTTLS
+SMTPS_PORT = 465
+
+# (filename, content, MIME type), text content is sent as UTF-8
+Attachment = Tuple[str, Union[str, bytes], str]
+
+
+def render_header(branding: dict) -> str:
+    """Render branded email header with the logo or business name."""
+    if branding.get("logo_url"):
+        content = (
+            f"<img src=\"{html.escape(branding['logo_url'], quote=True)}\" "
+            f"alt=\"{html.escape(branding.get('business_name') or '', quote=True)}\" "
+            "style=\"max-height: 48px;\">"
+        )
+    else:
+        content = html.escape(branding.get("business_name") or "")
+
+    return (
+        f"<div style=\"background: {branding['primary_color']}; color: #ffffff; "
+        "padding: 16px 24px; font-size: 20px; font-weight: bold;\">"
+        f"{content}</div>"
+    )
+
+
+def text_to_html(body: str, branding: Optional[dict] = None) -> str:
+    """
+    Render plain text body as a minimal HTML document.
+
+    With branding (see get_email_branding) the document gets a header in
+    the tenant's primary color with its logo or business name.
+    """
+    paragraphs = [
+        html.escape(paragraph).replace("\n", "<br>")
+        for paragraph in body.strip().split("\n\n")
+    ]
+    header = render_header(branding) if branding else ""
+
+    return (
+        "<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"></head>"
+        "<body style=\"font-family: Arial, sans-serif; line-height: 1.5;\">"
+        + header
+        + "".join(f"<p>{p}</p>" for p in paragraphs)
+        + "</body></html>"
+    )
+
+
+class EmailClient:
+    """SMTP email client."""
+
+    def __init__(
+        self,
+        host: Optional[str] = None,
+        port: Optional[int] = None,
+        user: Optional[str] = None,
+        password: Optional[str] = None,
+        sender: Optional[str] = None,
+        security: Optional[str] = None
+    ):
+        self.host = host or settings.SMTP_HOST
+        self.port = port or settings.SMTP_PORT
+        self.user = user if user is not None else settings.SMTP_USER
+        self.password = password if password is not None else settings.SMTP_PASSWORD
+        self.sender = sender or settings.EMAIL_FROM
+        self.security = (security or settings.SMTP_SECURITY).lower()
+
+    def connection_mode(self) -> str:
+        """
+        Get connection security: ssl (implicit TLS), starttls or none.
+
+        In auto mode port 465 uses implicit TLS and other ports STARTTLS.
+        """
+        if self.security in ("ssl", "starttls", "none"):
+            return self.security
+        return "ssl" if self.port == SMTPS_PORT else "starttls"
+
+    def connect(self) -> smtplib.SMTP:
+        """Open authenticated SMTP connection."""
+        mode = self.connection_mode()
+        context = ssl.create_default_context()
+
+        if mode == "ssl":
+            smtp = smtplib.SMTP_SSL(self.host, self.port, timeout=settings.SMTP_TIMEOUT_SECONDS, context=context)
+        else:
+            smtp = smtplib.SMTP(self.host, self.port, timeout=settings.SMTP_TIMEOUT_SECONDS)
+
+        try:
+            if mode == "starttls":
+                smtp.ehlo()
+                smtp.starttls(context=context)
+                smtp.ehlo()
+
+            if self.user:
+                smtp.login(self.user, self.password)
+        except Exception:
+            smtp.close()
+            raise
+
+        return smtp
+
+    def build_message(
+        self,
+        to: str,
+        subject: str,
+        body: str,
+        html_body: Optional[str] = None,
+        attachments: Optional[List[Attachment]] = None
+    ) -> EmailMessage:
+        """
+        Build multipart/alternative message with plain text and HTML parts.
+
+        HTML is rendered from the text when not given. With attachments the
+        message becomes multipart/mixed wrapping the alternative part.
+        Non-ASCII headers (ru/kk subjects) are RFC 2047 encoded on output.
+        """
+        message = EmailMessage()
+        message["From"] = self.sender
+        message["To"] = to
+        message["Subject"] = subject
+        message["Date"] = formatdate(localtime=True)
+        message["Message-ID"] = make_msgid(domain=settings.BASE_DOMAIN)
+
+        message.set_content(body)
+        message.add_alternative(html_body or text_to_html(body), subtype="html")
+
+        for filename, content, mime_type in attachments or []:
+            maintype, subtype = mime_type.split("/", 1)
+            if isinstance(content, str):
+                content = content.encode("utf-8")
+            message.add_attachment(content, maintype=maintype, subtype=subtype, filename=filename)
+
+        return message
+
+    def deliver(
+        self,
+        to: str,
+        subject: str,
+        body: str,
+        html_body: Optional[str] = None,
+        attachments: Optional[List[Attachment]] = None
+    ) -> Optional[str]:
+        """
+        Send email with plain text and HTML parts and optional attachments.
+
+        Returns the Message-ID of the sent email, None if email is disabled.
+        Raises EmailTransientError or EmailPermanentError when sending fails.
+        """
+        if not settings.EMAIL_ENABLED:
+            logger.info(f"Email disabled, not sending '{subject}' to {to}")
+            return None
+
+        message = self.build_message(to, subject, body, html_body, attachments)
+
+        try:
+            with self.connect() as smtp:
+                smtp.send_message(message)
+        except (smtplib.SMTPException, OSError) as e:
+            logger.error(f"Failed to send email to {to}: {e}")
+            raise classify_smtp_error(e) from e
+
+        logger.info(f"Email '{subject}' sent to {to}")
+        return message["Message-ID"]
+
+    def send(
+        self,
+        to: str,
+        subject: str,
+        body: str,
+        html_body: Optional[str] = None,
+        attachments: Optional[List[Attachment]] = None
+    ) -> bool:
+        """
+        Send email, returning False if email is disabled or sending failed.
+        """
+        try:
+            return self.deliver(to, subject, body, html_body, attachments) is not None
+        except EmailError:
+            return False
+
+
+# Global email client instance
+email_client = EmailClient()
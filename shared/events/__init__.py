@@ -0,0 +1,13 @@
+from .bus import DOMAIN_EVENTS_CHANNEL, EventType, EventBus, event_bus, publish_event
+from .booking_events import BookingEvent, booking_events_channel, publish_booking_event
+
+__all__ = [
+    "DOMAIN_EVENTS_CHANNEL",
+    "EventType",
+    "EventBus",
+    "event_bus",
+    "publish_event",
+    "BookingEvent",
+    "booking_events_channel",
+    "publish_booking_event"
+]
@@ -0,0 +1,54 @@
+import json
+import logging
+from datetime import datetime
+from enum import Enum
+
+import redis
+
+from shared.cache import redis_client
+from shared.models import Booking
+from .bus import EventType, publish_event
+
+logger = logging.getLogger(__name__)
+
+
+class BookingEvent(str, Enum):
+    """Booking lifecycle events streamed to owner dashboards."""
+    CREATED = "created"
+    UPDATED = "updated"
+    CONFIRMED = "confirmed"
+    CANCELLED = "cancelled"
+    COMPLETED = "completed"
+    NO_SHOW = "no_show"
+
+
+def booking_events_channel(tenant_id: int) -> str:
+    """Redis pub/sub channel with booking events of the tenant."""
+    return f"booking_events:{tenant_id}"
+
+
+def publish_booking_event(booking: Booking, event: BookingEvent) -> bool:
+    """
+    Publish booking event to the tenant's channel and the domain event bus.
+
+    Publishing is best effort: a Redis failure is logged and never fails
+    the booking change that triggered it.
+    """
+    payload = {
+        "event": event.value,
+        "booking_id": booking.id,
+        "tenant_id": booking.tenant_id,
+        "master_id": booking.master_id,
+        "status": booking.status.value,
+        "booking_date": booking.booking_date.isoformat(),
+        "occurred_at": datetime.utcnow().isoformat()
+    }
+
+    publish_event(EventType(f"booking.{event.value}"), payload)
+
+    try:
+        redis_client.client.publish(booking_events_channel(booking.tenant_id), json.dumps(payload))
+        return True
+    except redis.RedisError as e:
+        logger.error(f"Failed to publish booking event {event.value} for booking {booking.id}: {e}")
+        return False
@@ -0,0 +1,140 @@
+import json
+import logging
+import threading
+import time
+import uuid
+from collections import defaultdict
+from datetime import datetime
+from enum import Enum
+from typing import Callable, Dict, List
+
+import redis
+
+from shared.cache import redis_client
+
+logger = logging.getLogger(__name__)
+
+# Single channel carrying all domain events
+DOMAIN_EVENTS_CHANNEL = "domain_events"
+
+# Delay before resubscribing after a Redis failure
+LISTENER_RETRY_SECONDS = 5
+
+
+class EventType(str, Enum):
+    """Domain events published by services."""
+    BOOKING_CREATED = "booking.created"
+    BOOKING_UPDATED = "booking.updated"
+    BOOKING_CONFIRMED = "booking.confirmed"
+    BOOKING_CANCELLED = "booking.cancelled"
+    BOOKING_COMPLETED = "booking.completed"
+    BOOKING_NO_SHOW = "booking.no_show"
+    BOOKING_CONFIRMATION_REQUESTED = "booking.confirmation_requested"
+    TENANT_APPROVED = "tenant.approved"
+    TENANT_REJECTED = "tenant.rejected"
+    TENANT_DECISION_EMAIL_REQUESTED = "tenant.decision_email_requested"
+
+
+EventHandler = Callable[[dict], None]
+
+
+def publish_event(event_type: EventType, data: dict) -> bool:
+    """
+    Publish domain event.
+
+    Pub/sub delivery is best effort: events published while no subscriber
+    is connected are lost, and a Redis failure is logged, never raised.
+    """
+    event = {
+        "id": uuid.uuid4().hex,
+        "type": event_type.value,
+        "occurred_at": datetime.utcnow().isoformat(),
+        "data": data
+    }
+
+    try:
+        redis_client.client.publish(DOMAIN_EVENTS_CHANNEL, json.dumps(event))
+        return True
+    except redis.RedisError as e:
+        logger.error(f"Failed to publish event {event_type.value}: {e}")
+        return False
+
+
+class EventBus:
+    """Dispatches domain events from Redis pub/sub to registered handlers."""
+
+    def __init__(self):
+        self.handlers: Dict[str, List[EventHandler]] = defaultdict(list)
+        self._thread = None
+
+    def subscribe(self, event_type: EventType):
+        """
+        Decorator registering a handler of the event type.
+
+        Usage:
+            @event_bus.subscribe(EventType.TENANT_APPROVED)
+            def on_tenant_approved(event: dict):
+                ...
+        """
+        def decorator(handler: EventHandler) -> EventHandler:
+            self.handlers[event_type.value].append(handler)
+            return handler
+        return decorator
+
+    def dispatch(self, event: dict) -> int:
+        """
+        Call handlers of the event. Handler errors are logged so one
+        failing handler doesn't stop the others.
+
+        Returns number of handlers called successfully.
+        """
+        handled = 0
+
+        for handler in self.handlers.get(event.get("type"), []):
+            try:
+                handler(event)
+                handled += 1
+            except Exception as e:
+                logger.error(f"Event handler {handler.__name__} failed for {event.get('type')}: {e}")
+
+        return handled
+
+    def start(self) -> None:
+        """Start listening for events in a background thread."""
+        if self._thread and self._thread.is_alive():
+            return
+
+        self._thread = threading.Thread(target=self._listen, name="event-bus", daemon=True)
+        self._thread.start()
+
+    def _listen(self) -> None:
+        while True:
+            pubsub = redis_client.client.pubsub(ignore_subscribe_messages=True)
+            try:
+                pubsub.subscribe(DOMAIN_EVENTS_CHANNEL)
+                logger.info(f"Listening for domain events on {DOMAIN_EVENTS_CHANNEL}")
+
+                for message in pubsub.listen():
+                    if message.get("type") != "message":
+                        continue
+
+                    try:
+                        event = json.loads(message["data"])
+                    except ValueError:
+                        logger.warning(f"Invalid domain event: {message['data']}")
+                        continue
+
+                    self.dispatch(event)
+
+            except redis.RedisError as e:
+                logger.error(f"Domain event listener failed, retrying in {LISTENER_RETRY_SECONDS}s: {e}")
+                time.sleep(LISTENER_RETRY_SECONDS)
+            finally:
+                try:
+                    pubsub.close()
+                except redis.RedisError:
+                    pass
+
+
+# Global event bus instance
+event_bus = EventBus()
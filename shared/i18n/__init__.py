@@ -0,0 +1,42 @@
+from .messages import (
+    LANGUAGE_HEADER,
+    MESSAGES,
+    translate,
+    get_supported_languages,
+    get_request_language,
+    get_missing_translation_counts,
+    check_translation_coverage,
+    verify_translation_coverage
+)
+from .formatting import format_date, format_time, format_datetime, format_money
+from .templates import (
+    CUSTOMIZABLE_TEMPLATES,
+    get_default_template,
+    get_template_override,
+    render_template,
+    render_preview,
+    template_placeholders,
+    validate_template
+)
+
+__all__ = [
+    "LANGUAGE_HEADER",
+    "MESSAGES",
+    "translate",
+    "get_supported_languages",
+    "get_request_language",
+    "get_missing_translation_counts",
+    "check_translation_coverage",
+    "verify_translation_coverage",
+    "format_date",
+    "format_time",
+    "format_datetime",
+    "format_money",
+    "CUSTOMIZABLE_TEMPLATES",
+    "get_default_template",
+    "get_template_override",
+    "render_template",
+    "render_preview",
+    "template_placeholders",
+    "validate_template"
+]
@@ -0,0 +1,110 @@
+from datetime import date, datetime
+from decimal import Decimal
+from typing import Optional
+from zoneinfo import ZoneInfo
+
+from shared.config import settings
+
+# Month names as used in a date ("15 января"), by language
+MONTH_NAMES = {
+    "ru": [
+        "января", "февраля", "марта", "апреля", "мая", "июня",
+        "июля", "августа", "сентября", "октября", "ноября", "декабря"
+    ],
+    "en": [
+        "January", "February", "March", "April", "May", "June",
+        "July", "August", "September", "October", "November", "December"
+    ],
+    "kk": [
+        "қаңтар", "ақпан", "наурыз", "сәуір", "мамыр", "маусым",
+        "шілде", "тамыз", "қыркүйек", "қазан", "қараша", "желтоқсан"
+    ]
+}
+
+DATE_FORMATS = {
+    "ru": "{day} {month} {year}",
+    "en": "{month} {day}, {year}",
+    "kk": "{year} жылғы {day} {month}"
+}
+
+
+CURRENCY_SYMBOLS = {
+    "KZT": "₸",
+    "RUB": "₽",
+    "USD": "$",
+    "EUR": "€",
+}
+
+# Thousands separator, decimal mark and whether the symbol goes first, by
+# language: "1 500,50 ₸" in ru and kk, "₸1,500.50" in en
+MONEY_FORMATS = {
+    "ru": ("\u00a0", ",", False),
+    "en": (",", ".", True),
+    "kk": ("\u00a0", ",", False),
+}
+
+
+def resolve_format_language(language: Optional[str]) -> str:
+    """Language with known date formats, DEFAULT_LANGUAGE or ru otherwise."""
+    for candidate in (language, settings.DEFAULT_LANGUAGE):
+        if candidate in DATE_FORMATS:
+            return candidate
+    return "ru"
+
+
+def format_date(value: date, language: Optional[str] = None) -> str:
+    """Format date in the language, e.g. "15 января 2025"."""
+    language = resolve_format_language(language)
+    return DATE_FORMATS[language].format(
+        day=value.day,
+        month=MONTH_NAMES[language][value.month - 1],
+        year=value.year
+    )
+
+
+def format_time(value: datetime) -> str:
+    """Format time of day, 24-hour in all supported languages."""
+    return value.strftime("%H:%M")
+
+
+def format_datetime(
+    value: datetime,
+    language: Optional[str] = None,
+    zone: Optional[ZoneInfo] = None
+) -> str:
+    """
+    Format date and time in the language, e.g. "15 января 2025, 14:30".
+
+    Aware values are converted to zone (the tenant's timezone). Naive
+    values, like booking dates, are already local time and kept as is.
+    """
+    if value.tzinfo is not None and zone is not None:
+        value = value.astimezone(zone)
+
+    return f"{format_date(value, language)}, {format_time(value)}"
+
+
+def format_money(minor: int, currency: str, language: Optional[str] = None) -> str:
+    """
+    Format amount in minor units with the currency symbol in the language,
+    e.g. "1 500 ₸" or "$15.50".
+
+    Kopecks and cents are shown only when present. Currencies without a
+    known symbol are shown by code.
+    """
+    language = resolve_format_language(language)
+    group, decimal_mark, symbol_first = MONEY_FORMATS[language]
+    amount = Decimal(minor or 0) / 100
+    whole, fraction = f"{abs(amount):,.2f}".split(".")
+
+    number = whole.replace(",", group)
+    if fraction != "00":
+        number += decimal_mark + fraction
+
+    symbol = CURRENCY_SYMBOLS.get(currency)
+    if symbol is None:
+        formatted = f"{number} {currency}"
+    else:
+        formatted = f"{symbol}{number}" if symbol_first else f"{number}\u00a0{symbol}"
+
+    return f"-{formatted}" if amount < 0 else formatted
@@ -0,0 +1,517 @@
+from fastapi import Request
+from collections import Counter
+from typing import Dict, List, Optional
+import logging
+
+from shared.config import settings
+
+logger = logging.getLogger(__name__)
+
+
+# Header overriding Accept-Language
+LANGUAGE_HEADER = "X-Language"
+
+# Language names in the language itself
+LANGUAGE_NAMES = {
+    "ru": "Русский",
+    "en": "English",
+    "kk": "Қазақша",
+}
+
+# Message catalog: language -> key -> message
+MESSAGES = {
+    "ru": {
+        # Generic errors
+        "bad_request": "Некорректный запрос",
+        "validation_error": "Ошибка валидации данных",
+        "unauthorized": "Требуется авторизация",
+        "forbidden": "Недостаточно прав",
+        "not_found": "Ресурс не найден",
+        "conflict": "Конфликт данных",
+        "rate_limited": "Слишком много запросов",
+        "payload_too_large": "Слишком большой размер запроса",
+        "invalid_cursor": "Неверный курсор страницы",
+        "request_timeout": "Превышено время обработки запроса",
+        "internal_error": "Внутренняя ошибка сервера",
+        "service_error": "Ошибка сервиса",
+        "service_unavailable": "Сервис временно недоступен",
+        "database_timeout": "Превышено время ожидания базы данных",
+
+        # Auth errors
+        "invalid_credentials": "Неверный email или пароль",
+        "invalid_token": "Недействительный токен авторизации",
+        "admin_scope_required": "Требуется вход через панель администратора",
+        "impersonation_forbidden": "Действие недоступно при входе от имени бизнеса",
+        "invalid_refresh_token": "Недействительный refresh-токен",
+        "invalid_old_password": "Неверный текущий пароль",
+        "account_inactive": "Аккаунт деактивирован",
+        "tenant_expired": "Пробный период закончился, оформите подписку",
+        "feature_not_available": "Функция недоступна на вашем тарифе",
+        "email_taken": "Email уже зарегистрирован",
+        "subdomain_taken": "Поддомен уже занят",
+        "invalid_subdomain": "Недопустимый поддомен: только латинские буквы, цифры и дефис, от 3 до 50 символов, не зарезервированное имя",
+        "registration_failed": "Не удалось завершить регистрацию",
+        "captcha_required": "Подтвердите, что вы не робот",
+        "captcha_failed": "Проверка CAPTCHA не пройдена",
+
+        # Domain errors
+        "user_not_found": "Пользователь не найден",
+        "tenant_not_found": "Бизнес не найден",
+        "business_not_found": "Бизнес не найден",
+        "service_not_found": "Услуга не найдена",
+        "master_not_found": "Мастер не найден",
+        "location_not_found": "Филиал не найден",
+        "booking_not_found": "Бронирование не найдено",
+        "client_not_found": "Клиент не найден",
+        "master_service_mismatch": "Мастер не оказывает эту услугу",
+        "slot_unavailable": "Выбранное время недоступно",
+        "no_master_available": "На выбранное время нет свободных мастеров",
+        "booking_too_soon": "Это время уже недоступно для онлайн-записи, выберите более позднее",
+        "master_not_accepting_bookings": "Мастер временно не принимает записи",
+        "booking_failed": "Не удалось создать бронирование",
+        "invalid_booking_status": "Недопустимый статус бронирования",
+        "invalid_tenant_status": "Недопустимый статус бизнеса",
+        "payment_not_found": "Платёж не найден",
+        "invalid_signature": "Неверная подпись запроса",
+        "payment_amount_mismatch": "Сумма или валюта оплаты не совпадает с платежом",
+        "booking_not_started": "Бронирование ещё не началось",
+        "invalid_date_range": "Неверный период: дата начала позже даты окончания",
+        "template_not_found": "Шаблон не найден",
+        "invalid_template": "Шаблон должен содержать те же поля подстановки, что и шаблон по умолчанию",
+        "review_not_found": "Отзыв не найден",
+        "time_off_not_found": "Отпуск не найден",
+        "time_off_conflict": "На эти даты у мастера есть записи, перенесите или отмените их",
+
+        # Client errors
+        "invalid_verification_code": "Неверный или просроченный код подтверждения",
+        "invalid_client_session": "Сессия клиента недействительна или истекла",
+        "cancellation_window_closed": "Отменить бронирование уже нельзя",
+        "review_already_exists": "Отзыв на это бронирование уже оставлен",
+
+        # Email templates
+        "trial_expiration_subject": "Пробный период {business_name} заканчивается через {days} дн.",
+        "trial_expiration_body": (
+            "Здравствуйте!\n\n"
+            "Пробный период для {business_name} заканчивается через {days} дн. ({trial_end_date}).\n"
+            "Чтобы продолжить принимать онлайн-записи, оформите подписку:\n"
+            "{upgrade_url}\n\n"
+            "Команда Jazyl"
+        ),
+        "booking_confirmation_subject": "Запись в {business_name} подтверждена",
+        "booking_confirmation_body": (
+            "Здравствуйте!\n\n"
+            "Ваша запись подтверждена.\n"
+            "Бизнес: {business_name}\n"
+            "Услуга: {service_name}\n"
+            "Мастер: {master_name}\n"
+            "Дата: {booking_date}\n"
+            "Цена: {price}\n\n"
+            "Добавьте запись в календарь из вложения.\n\n"
+            "Команда Jazyl"
+        ),
+        "booking_reminder_subject": "Напоминание о записи в {business_name}",
+        "booking_reminder_body": (
+            "Здравствуйте!\n\n"
+            "Напоминаем о вашей записи.\n"
+            "Бизнес: {business_name}\n"
+            "Услуга: {service_name}\n"
+            "Мастер: {master_name}\n"
+            "Дата: {booking_date}\n"
+            "Цена: {price}\n\n"
+            "Команда Jazyl"
+        ),
+        "tenant_approved_subject": "Заявка {business_name} одобрена",
+        "tenant_approved_body": (
+            "Здравствуйте!\n\n"
+            "Заявка {business_name} одобрена. Онлайн-запись доступна по адресу:\n"
+            "{business_url}\n\n"
+            "Команда Jazyl"
+        ),
+        "template_test_subject": "[Тест] Шаблон {template}",
+        # Bookings CSV export headers
+        "bookings_csv_id": "Номер",
+        "bookings_csv_booking_date": "Дата и время",
+        "bookings_csv_status": "Статус",
+        "bookings_csv_client_name": "Клиент",
+        "bookings_csv_client_phone": "Телефон клиента",
+        "bookings_csv_master_name": "Мастер",
+        "bookings_csv_services": "Услуги",
+        "bookings_csv_price": "Цена",
+        "bookings_csv_currency": "Валюта",
+        "bookings_csv_created_at": "Создано",
+        "tenant_rejected_subject": "Заявка {business_name} отклонена",
+        "tenant_rejected_body": (
+            "Здравствуйте!\n\n"
+            "К сожалению, заявка {business_name} отклонена. "
+            "Если вы считаете это ошибкой, свяжитесь с поддержкой.\n\n"
+            "Команда Jazyl"
+        ),
+    },
+    "en": {
+        # Generic errors
+        "bad_request": "Bad request",
+        "validation_error": "Validation error",
+        "unauthorized": "Authentication required",
+        "forbidden": "Insufficient permissions",
+        "not_found": "Resource not found",
+        "conflict": "Conflict",
+        "rate_limited": "Too many requests",
+        "payload_too_large": "Request body is too large",
+        "invalid_cursor": "Invalid page cursor",
+        "request_timeout": "Request timed out",
+        "internal_error": "Internal server error",
+        "service_error": "Service error",
+        "service_unavailable": "Service temporarily unavailable",
+        "database_timeout": "Database request timed out",
+
+        # Auth errors
+        "invalid_credentials": "Invalid email or password",
+        "invalid_token": "Invalid authentication credentials",
+        "admin_scope_required": "Admin login required",
+        "impersonation_forbidden": "Action is not allowed while impersonating a business",
+        "invalid_refresh_token": "Invalid refresh token",
+        "invalid_old_password": "Invalid old password",
+        "account_inactive": "Account is inactive",
+        "tenant_expired": "Trial period has ended, please upgrade your subscription",
+        "feature_not_available": "This feature is not available on your plan",
+        "email_taken": "Email already registered",
+        "subdomain_taken": "Subdomain already taken",
+        "invalid_subdomain": "Invalid subdomain: use 3 to 50 latin letters, digits or hyphens, not a reserved name",
+        "registration_failed": "Registration failed",
+        "captcha_required": "Please confirm you are not a robot",
+        "captcha_failed": "CAPTCHA verification failed",
+
+        # Domain errors
+        "user_not_found": "User not found",
+        "tenant_not_found": "Tenant not found",
+        "business_not_found": "Business not found",
+        "service_not_found": "Service not found",
+        "master_not_found": "Master not found",
+        "location_not_found": "Location not found",
+        "booking_not_found": "Booking not found",
+        "client_not_found": "Client not found",
+        "master_service_mismatch": "Master does not provide this service",
+        "slot_unavailable": "Time slot not available",
+        "no_master_available": "No master is available at this time",
+        "booking_too_soon": "This time can no longer be booked online, please choose a later one",
+        "master_not_accepting_bookings": "Master is not accepting bookings at the moment",
+        "booking_failed": "Booking creation failed",
+        "invalid_booking_status": "Invalid booking status",
+        "invalid_tenant_status": "Invalid business status",
+        "payment_not_found": "Payment not found",
+        "invalid_signature": "Invalid request signature",
+        "payment_amount_mismatch": "Paid amount or currency doesn't match the payment",
+        "booking_not_started": "Booking has not started yet",
+        "invalid_date_range": "Invalid date range: start date is after end date",
+        "template_not_found": "Template not found",
+        "invalid_template": "Template must keep the placeholders of the default template",
+        "review_not_found": "Review not found",
+        "time_off_not_found": "Time off not found",
+        "time_off_conflict": "The master has bookings on these dates, reschedule or cancel them first",
+
+        # Client errors
+        "invalid_verification_code": "Invalid or expired verification code",
+        "invalid_client_session": "Client session is invalid or expired",
+        "cancellation_window_closed": "Booking can no longer be cancelled",
+        "review_already_exists": "This booking has already been reviewed",
+
+        # Email templates
+        "trial_expiration_subject": "{business_name} trial ends in {days} day(s)",
+        "trial_expiration_body": (
+            "Hello!\n\n"
+            "The trial period for {business_name} ends in {days} day(s) ({trial_end_date}).\n"
+            "To keep accepting online bookings, upgrade your subscription:\n"
+            "{upgrade_url}\n\n"
+            "The Jazyl team"
+        ),
+        "booking_confirmation_subject": "Your booking at {business_name} is confirmed",
+        "booking_confirmation_body": (
+            "Hello!\n\n"
+            "Your booking is confirmed.\n"
+            "Business: {business_name}\n"
+            "Service: {service_name}\n"
+            "Master: {master_name}\n"
+            "Date: {booking_date}\n"
+            "Price: {price}\n\n"
+            "Add it to your calendar with the attached file.\n\n"
+            "The Jazyl team"
+        ),
+        "booking_reminder_subject": "Reminder of your booking at {business_name}",
+        "booking_reminder_body": (
+            "Hello!\n\n"
+            "This is a reminder of your booking.\n"
+            "Business: {business_name}\n"
+            "Service: {service_name}\n"
+            "Master: {master_name}\n"
+            "Date: {booking_date}\n"
+            "Price: {price}\n\n"
+            "The Jazyl team"
+        ),
+        "tenant_approved_subject": "{business_name} application approved",
+        "tenant_approved_body": (
+            "Hello!\n\n"
+            "The application for {business_name} has been approved. Online booking is available at:\n"
+            "{business_url}\n\n"
+            "The Jazyl team"
+        ),
+        "template_test_subject": "[Test] Template {template}",
+        # Bookings CSV export headers
+        "bookings_csv_id": "ID",
+        "bookings_csv_booking_date": "Date and time",
+        "bookings_csv_status": "Status",
+        "bookings_csv_client_name": "Client",
+        "bookings_csv_client_phone": "Client phone",
+        "bookings_csv_master_name": "Master",
+        "bookings_csv_services": "Services",
+        "bookings_csv_price": "Price",
+        "bookings_csv_currency": "Currency",
+        "bookings_csv_created_at": "Created",
+        "tenant_rejected_subject": "{business_name} application rejected",
+        "tenant_rejected_body": (
+            "Hello!\n\n"
+            "Unfortunately, the application for {business_name} has been rejected. "
+            "If you think this is a mistake, please contact support.\n\n"
+            "The Jazyl team"
+        ),
+    },
+    "kk": {
+        # Generic errors
+        "bad_request": "Қате сұраныс",
+        "validation_error": "Деректерді тексеру қатесі",
+        "unauthorized": "Авторизация қажет",
+        "forbidden": "Құқықтар жеткіліксіз",
+        "not_found": "Ресурс табылмады",
+        "conflict": "Деректер қайшылығы",
+        "rate_limited": "Сұраныстар тым көп",
+        "payload_too_large": "Сұраныс көлемі тым үлкен",
+        "invalid_cursor": "Бет курсоры жарамсыз",
+        "request_timeout": "Сұранысты өңдеу уақыты асып кетті",
+        "internal_error": "Сервердің ішкі қатесі",
+        "service_error": "Сервис қатесі",
+        "service_unavailable": "Сервис уақытша қолжетімсіз",
+        "database_timeout": "Дерекқордан жауап күту уақыты асып кетті",
+
+        # Auth errors
+        "invalid_credentials": "Email немесе құпия сөз қате",
+        "invalid_token": "Авторизация токені жарамсыз",
+        "admin_scope_required": "Әкімші панелі арқылы кіру қажет",
+        "impersonation_forbidden": "Бизнес атынан кірген кезде бұл әрекет қолжетімсіз",
+        "invalid_refresh_token": "Refresh-токен жарамсыз",
+        "invalid_old_password": "Ағымдағы құпия сөз қате",
+        "account_inactive": "Аккаунт өшірілген",
+        "tenant_expired": "Сынақ мерзімі аяқталды, жазылымды рәсімдеңіз",
+        "feature_not_available": "Бұл функция сіздің тарифіңізде қолжетімсіз",
+        "email_taken": "Бұл email тіркелген",
+        "subdomain_taken": "Бұл субдомен бос емес",
+        "invalid_subdomain": "Субдомен жарамсыз: 3-тен 50-ге дейін латын әрпі, сан немесе дефис, резервтелмеген атау",
+        "registration_failed": "Тіркеуді аяқтау мүмкін болмады",
+        "captcha_required": "Робот емес екеніңізді растаңыз",
+        "captcha_failed": "CAPTCHA тексерісі өтпеді",
+
+        # Domain errors
+        "user_not_found": "Пайдаланушы табылмады",
+        "tenant_not_found": "Бизнес табылмады",
+        "business_not_found": "Бизнес табылмады",
+        "service_not_found": "Қызмет табылмады",
+        "master_not_found": "Шебер табылмады",
+        "location_not_found": "Филиал табылмады",
+        "booking_not_found": "Брондау табылмады",
+        "client_not_found": "Клиент табылмады",
+        "master_service_mismatch": "Шебер бұл қызметті көрсетпейді",
+        "slot_unavailable": "Таңдалған уақыт бос емес",
+        "no_master_available": "Таңдалған уақытта бос шебер жоқ",
+        "booking_too_soon": "Бұл уақытқа онлайн жазылу енді мүмкін емес, кейінірек уақытты таңдаңыз",
+        "master_not_accepting_bookings": "Шебер уақытша жазылу қабылдамайды",
+        "booking_failed": "Брондау жасау мүмкін болмады",
+        "invalid_booking_status": "Брондау мәртебесі жарамсыз",
+        "invalid_tenant_status": "Бизнес мәртебесі жарамсыз",
+        "payment_not_found": "Төлем табылмады",
+        "invalid_signature": "Сұраныс қолтаңбасы жарамсыз",
+        "payment_amount_mismatch": "Төленген сома немесе валюта төлемге сәйкес келмейді",
+        "booking_not_started": "Брондау әлі басталған жоқ",
+        "invalid_date_range": "Кезең дұрыс емес: басталу күні аяқталу күнінен кейін",
+        "template_not_found": "Үлгі табылмады",
+        "invalid_template": "Үлгіде әдепкі үлгідегідей орын толтырғыштар болуы керек",
+        "review_not_found": "Пікір табылмады",
+        "time_off_not_found": "Демалыс табылмады",
+        "time_off_conflict": "Бұл күндері шеберде жазылулар бар, алдымен оларды ауыстырыңыз немесе болдырмаңыз",
+
+        # Client errors
+        "invalid_verification_code": "Растау коды қате немесе мерзімі өткен",
+        "invalid_client_session": "Клиент сессиясы жарамсыз немесе мерзімі өткен",
+        "cancellation_window_closed": "Брондауды енді болдырмау мүмкін емес",
+        "review_already_exists": "Бұл брондауға пікір қалдырылған",
+
+        # Email templates
+        "trial_expiration_subject": "{business_name} сынақ мерзімі {days} күннен кейін аяқталады",
+        "trial_expiration_body": (
+            "Сәлеметсіз бе!\n\n"
+            "{business_name} сынақ мерзімі {days} күннен кейін аяқталады ({trial_end_date}).\n"
+            "Онлайн жазылуларды қабылдауды жалғастыру үшін жазылымды рәсімдеңіз:\n"
+            "{upgrade_url}\n\n"
+            "Jazyl командасы"
+        ),
+        "booking_confirmation_subject": "{business_name} жазылуыңыз расталды",
+        "booking_confirmation_body": (
+            "Сәлеметсіз бе!\n\n"
+            "Жазылуыңыз расталды.\n"
+            "Бизнес: {business_name}\n"
+            "Қызмет: {service_name}\n"
+            "Шебер: {master_name}\n"
+            "Күні: {booking_date}\n"
+            "Бағасы: {price}\n\n"
+            "Тіркемедегі файл арқылы жазылуды күнтізбеге қосыңыз.\n\n"
+            "Jazyl командасы"
+        ),
+        "booking_reminder_subject": "{business_name} жазылуыңыз туралы еске салу",
+        "booking_reminder_body": (
+            "Сәлеметсіз бе!\n\n"
+            "Жазылуыңыз туралы еске саламыз.\n"
+            "Бизнес: {business_name}\n"
+            "Қызмет: {service_name}\n"
+            "Шебер: {master_name}\n"
+            "Күні: {booking_date}\n"
+            "Бағасы: {price}\n\n"
+            "Jazyl командасы"
+        ),
+        "tenant_approved_subject": "{business_name} өтінімі мақұлданды",
+        "tenant_approved_body": (
+            "Сәлеметсіз бе!\n\n"
+            "{business_name} өтінімі мақұлданды. Онлайн жазылу мына мекенжайда қолжетімді:\n"
+            "{business_url}\n\n"
+            "Jazyl командасы"
+        ),
+        "template_test_subject": "[Тест] {template} үлгісі",
+        # Bookings CSV export headers
+        "bookings_csv_id": "Нөмірі",
+        "bookings_csv_booking_date": "Күні мен уақыты",
+        "bookings_csv_status": "Мәртебесі",
+        "bookings_csv_client_name": "Клиент",
+        "bookings_csv_client_phone": "Клиент телефоны",
+        "bookings_csv_master_name": "Шебер",
+        "bookings_csv_services": "Қызметтер",
+        "bookings_csv_price": "Бағасы",
+        "bookings_csv_currency": "Валюта",
+        "bookings_csv_created_at": "Құрылған",
+        "tenant_rejected_subject": "{business_name} өтінімі қабылданбады",
+        "tenant_rejected_body": (
+            "Сәлеметсіз бе!\n\n"
+            "Өкінішке қарай, {business_name} өтінімі қабылданбады. "
+            "Қате деп ойласаңыз, қолдау қызметіне хабарласыңыз.\n\n"
+            "Jazyl командасы"
+        ),
+    },
+}
+
+
+# Missing translation lookups: (language, key) -> count
+missing_translations: Counter = Counter()
+
+
+def translate(key: str, language: Optional[str] = None, **kwargs) -> str:
+    """
+    Translate message key to the given language.
+
+    Falls back to the default language, then to the key itself.
+    Missing translations are counted and logged if I18N_LOG_MISSING is set.
+    """
+    language = language or settings.DEFAULT_LANGUAGE
+
+    message = MESSAGES.get(language, {}).get(key)
+    if message is None:
+        record_missing_translation(language, key)
+        message = MESSAGES.get(settings.DEFAULT_LANGUAGE, {}).get(key)
+
+        if message is None:
+            if language != settings.DEFAULT_LANGUAGE:
+                record_missing_translation(settings.DEFAULT_LANGUAGE, key)
+            message = key
+
+    if kwargs:
+        try:
+            return message.format(**kwargs)
+        except (KeyError, IndexError) as e:
+            logger.warning(f"Failed to format message {key}: {e}")
+
+    return message
+
+
+def record_missing_translation(language: str, key: str) -> None:
+    """Count missing translation lookup."""
+    missing_translations[(language, key)] += 1
+
+    if settings.I18N_LOG_MISSING:
+        logger.warning(f"Missing translation: {language}.{key}")
+
+
+def get_missing_translation_counts() -> Dict[str, int]:
+    """Get missing translation lookup counts keyed by "language.key"."""
+    return {f"{language}.{key}": count for (language, key), count in missing_translations.items()}
+
+
+def check_translation_coverage(messages: Optional[Dict[str, Dict[str, str]]] = None) -> Dict[str, List[str]]:
+    """
+    Find keys of the default language missing in other supported languages.
+
+    Returns missing keys per language; languages without gaps are omitted.
+    """
+    messages = messages if messages is not None else MESSAGES
+    reference = messages.get(settings.DEFAULT_LANGUAGE, {})
+
+    gaps = {}
+    for language in settings.supported_languages_list:
+        missing = sorted(key for key in reference if key not in messages.get(language, {}))
+        if missing:
+            gaps[language] = missing
+
+    return gaps
+
+
+def verify_translation_coverage() -> None:
+    """
+    Log translation gaps on startup.
+
+    Raises RuntimeError instead if I18N_STRICT is set.
+    """
+    gaps = check_translation_coverage()
+
+    for language, keys in gaps.items():
+        logger.warning(f"Missing {len(keys)} translation(s) in {language}: {', '.join(keys)}")
+
+    if gaps and settings.I18N_STRICT:
+        raise RuntimeError(f"Missing translations: {gaps}")
+
+
+def get_supported_languages() -> List[dict]:
+    """Get supported languages with their names."""
+    return [
+        {
+            "code": language,
+            "name": LANGUAGE_NAMES.get(language, language),
+            "is_default": language == settings.DEFAULT_LANGUAGE
+        }
+        for language in settings.supported_languages_list
+    ]
+
+
+def get_request_language(request: Request) -> str:
+    """
+    Detect preferred language of the request.
+
+    Precedence: ?lang query parameter, X-Language header, Accept-Language
+    header, default language. Unsupported values are skipped.
+    """
+    supported = settings.supported_languages_list
+
+    for override in (request.query_params.get("lang"), request.headers.get(LANGUAGE_HEADER)):
+        if override and override.strip().lower() in supported:
+            return override.strip().lower()
+
+    accept_language = request.headers.get("accept-language", "")
+
+    for part in accept_language.split(","):
+        language = part.split(";")[0].strip().lower()[:2]
+        if language in supported:
+            return language
+
+    return settings.DEFAULT_LANGUAGE
@@ -0,0 +1,108 @@
+from datetime import datetime
+from string import Formatter
+from typing import Dict, List, Optional, Set
+import logging
+
+from sqlalchemy.orm import Session
+
+from shared.config import settings
+from shared.models import MessageTemplate
+from .formatting import format_datetime, format_money
+from .messages import MESSAGES, translate
+
+logger = logging.getLogger(__name__)
+
+# Built-in messages tenants may override
+CUSTOMIZABLE_TEMPLATES = (
+    "booking_confirmation_subject",
+    "booking_confirmation_body",
+)
+
+# Placeholder values used to preview templates, booking_date and price
+# are formatted in the template language
+SAMPLE_TEMPLATE_DATA = {
+    "business_name": "Beauty Salon",
+    "service_name": "Haircut",
+    "master_name": "Aigerim",
+}
+SAMPLE_BOOKING_DATE = datetime(2025, 9, 1, 14, 0)
+SAMPLE_PRICE = (500000, "KZT")
+
+
+def template_placeholders(text: str) -> Set[str]:
+    """Get names of the {placeholders} used in the template."""
+    return {name for _, name, _, _ in Formatter().parse(text) if name}
+
+
+def get_default_template(name: str, language: str) -> Optional[str]:
+    """Get built-in template text, falling back to the default language."""
+    return MESSAGES.get(language, {}).get(name) or MESSAGES.get(settings.DEFAULT_LANGUAGE, {}).get(name)
+
+
+def validate_template(name: str, language: str, body: str) -> List[str]:
+    """
+    Check override against the built-in template.
+
+    Returns problems found: placeholders of the default that are missing
+    and unknown placeholders. Empty list if the override is valid.
+    """
+    try:
+        used = template_placeholders(body)
+    except ValueError as e:
+        return [f"invalid format: {e}"]
+
+    expected = template_placeholders(get_default_template(name, language) or "")
+
+    return (
+        [f"missing placeholder: {{{p}}}" for p in sorted(expected - used)]
+        + [f"unknown placeholder: {{{p}}}" for p in sorted(used - expected)]
+    )
+
+
+def get_template_override(db: Session, tenant_id: int, name: str, language: str) -> Optional[MessageTemplate]:
+    """Get tenant override of the template in the given language."""
+    return db.query(MessageTemplate).filter(
+        MessageTemplate.tenant_id == tenant_id,
+        MessageTemplate.name == name,
+        MessageTemplate.language == language
+    ).first()
+
+
+def render_template(db: Session, tenant_id: int, name: str, language: Optional[str] = None, **kwargs) -> str:
+    """
+    Render message template for the tenant.
+
+    Uses the tenant's override when present, the built-in translation
+    otherwise (and if the override fails to render).
+    """
+    language = language or settings.DEFAULT_LANGUAGE
+    override = get_template_override(db, tenant_id, name, language)
+
+    if override:
+        try:
+            return override.body.format(**kwargs)
+        except (KeyError, IndexError, ValueError) as e:
+            logger.warning(f"Failed to render template override {name} of tenant {tenant_id}: {e}")
+
+    return translate(name, language, **kwargs)
+
+
+def render_preview(
+    body: str,
+    sample_data: Optional[Dict[str, str]] = None,
+    language: Optional[str] = None
+) -> str:
+    """
+    Render template text with sample data.
+
+    Given sample values override SAMPLE_TEMPLATE_DATA, placeholders
+    without a value are left as is.
+    """
+    params = {
+        **SAMPLE_TEMPLATE_DATA,
+        "booking_date": format_datetime(SAMPLE_BOOKING_DATE, language),
+        "price": format_money(*SAMPLE_PRICE, language),
+        **(sample_data or {})
+    }
+    params.update({p: f"{{{p}}}" for p in template_placeholders(body) if p not in params})
+    return body.format(**params)
@@ -0,0 +1,36 @@
+from .metrics import (
+    JobMetrics,
+    job_metrics,
+    get_job_stats,
+    connect_job_metrics,
+    render_prometheus_metrics
+)
+from .queues import job_queue_names, configure_job_queues
+from .retry import retry_delay
+from .delivery import configure_reliable_delivery, configure_job_time_limits
+from .dedup import JobGuard, job_guard, reminder_dedup_key
+from .scheduler import JobScheduler, job_scheduler
+from .history import JOB_STATUSES, JobHistory, job_history, list_jobs, connect_job_history
+
+__all__ = [
+    "JobMetrics",
+    "job_metrics",
+    "get_job_stats",
+    "connect_job_metrics",
+    "render_prometheus_metrics",
+    "job_queue_names",
+    "configure_job_queues",
+    "retry_delay",
+    "configure_reliable_delivery",
+    "configure_job_time_limits",
+    "JobGuard",
+    "job_guard",
+    "reminder_dedup_key",
+    "JobScheduler",
+    "job_scheduler",
+    "JOB_STATUSES",
+    "JobHistory",
+    "job_history",
+    "list_jobs",
+    "connect_job_history"
+]
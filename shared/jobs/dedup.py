@@ -0,0 +1,49 @@
+import redis
+import logging
+from typing import Optional
+
+from shared.config import settings
+
+logger = logging.getLogger(__name__)
+
+
+def reminder_dedup_key(booking_id: int, hours_before: int) -> str:
+    """Build dedup key of a booking reminder."""
+    return f"reminder:{booking_id}:{hours_before}"
+
+
+class JobGuard:
+    """
+    Redis SET NX guards preventing duplicate jobs.
+
+    Stored in the Celery broker Redis next to the jobs themselves.
+    """
+
+    def __init__(self, broker_url: Optional[str] = None):
+        self.client = redis.Redis.from_url(
+            broker_url or settings.CELERY_BROKER_URL,
+            decode_responses=True
+        )
+
+    def claim(self, key: str, ttl_seconds: int) -> bool:
+        """
+        Claim key for ttl_seconds.
+
+        Returns False if the key is already claimed.
+        """
+        return bool(self.client.set(key, "1", nx=True, ex=max(int(ttl_seconds), 1)))
+
+    def is_claimed(self, key: str) -> bool:
+        """Check if key is claimed."""
+        return self.client.exists(key) > 0
+
+    def release(self, key: str) -> None:
+        """Release claimed key. Errors are logged, never raised."""
+        try:
+            self.client.delete(key)
+        except Exception as e:
+            logger.error(f"Failed to release job guard {key}: {e}")
+
+
+# Global job guard instance
+job_guard = JobGuard()
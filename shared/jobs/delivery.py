@@ -0,0 +1,34 @@
+from celery import Celery
+
+from shared.config import settings
+
+
+def configure_reliable_delivery(celery_app: Celery) -> None:
+    """
+    Keep jobs in the broker until they are processed.
+
+    Jobs are acknowledged only after the task finishes, so a job whose
+    worker crashes stays unacknowledged and the broker requeues it once
+    JOB_VISIBILITY_TIMEOUT_SECONDS pass. The timeout must be longer than
+    the longest job countdown, otherwise scheduled jobs are redelivered.
+    """
+    celery_app.conf.task_acks_late = True
+    celery_app.conf.task_reject_on_worker_lost = True
+    celery_app.conf.worker_prefetch_multiplier = 1
+    celery_app.conf.broker_transport_options = {
+        **(celery_app.conf.broker_transport_options or {}),
+        "visibility_timeout": settings.JOB_VISIBILITY_TIMEOUT_SECONDS
+    }
+
+
+def configure_job_time_limits(celery_app: Celery) -> None:
+    """
+    Bound the time a single job may run.
+
+    After JOB_SOFT_TIME_LIMIT_SECONDS SoftTimeLimitExceeded is raised in
+    the task, so a hung send is retried instead of blocking the worker;
+    after JOB_TIME_LIMIT_SECONDS the pool process running it is killed.
+    This also bounds how long a warm shutdown waits for running jobs.
+    """
+    celery_app.conf.task_soft_time_limit = settings.JOB_SOFT_TIME_LIMIT_SECONDS
+    celery_app.conf.task_time_limit = settings.JOB_TIME_LIMIT_SECONDS
@@ -0,0 +1,173 @@
+import time
+import redis
+import logging
+from datetime import datetime
+from typing import List, Optional
+
+from celery import Celery
+from celery.signals import before_task_publish, task_prerun, task_success, task_failure, task_retry
+
+from shared.config import settings
+
+logger = logging.getLogger(__name__)
+
+# Job statuses in the order a job goes through them
+JOB_STATUSES = ("queued", "started", "retrying", "succeeded", "failed")
+
+# Sorted set of job IDs scored by queued time
+JOB_INDEX_KEY = "job_history"
+
+JOB_KEY_PREFIX = "job_history:job"
+
+
+class JobHistory:
+    """
+    Recent background jobs and their statuses, stored in the Celery broker Redis.
+
+    Each job is a hash indexed by queued time. Jobs are kept for
+    JOB_HISTORY_TTL_SECONDS, the index for the newest JOB_HISTORY_MAX_JOBS.
+    """
+
+    def __init__(self, broker_url: Optional[str] = None):
+        self.client = redis.Redis.from_url(
+            broker_url or settings.CELERY_BROKER_URL,
+            decode_responses=True
+        )
+
+    def record_queued(self, job_id: str, task_name: str, queued_at: Optional[float] = None) -> None:
+        """Add job to the history. Errors are logged, never raised."""
+        queued_at = queued_at if queued_at is not None else time.time()
+        key = f"{JOB_KEY_PREFIX}:{job_id}"
+
+        try:
+            pipe = self.client.pipeline()
+            pipe.hset(key, mapping={
+                "id": job_id,
+                "type": task_name,
+                "status": "queued",
+                "queued_at": queued_at,
+                "updated_at": queued_at,
+                "retries": 0
+            })
+            pipe.expire(key, settings.JOB_HISTORY_TTL_SECONDS)
+            pipe.zadd(JOB_INDEX_KEY, {job_id: queued_at})
+            pipe.zremrangebyscore(JOB_INDEX_KEY, "-inf", queued_at - settings.JOB_HISTORY_TTL_SECONDS)
+            pipe.zremrangebyrank(JOB_INDEX_KEY, 0, -settings.JOB_HISTORY_MAX_JOBS - 1)
+            pipe.execute()
+        except Exception as e:
+            logger.error(f"Failed to record job {job_id}: {e}")
+
+    def record_status(self, job_id: str, status: str, error: Optional[str] = None) -> None:
+        """Update status of a recorded job. Errors are logged, never raised."""
+        key = f"{JOB_KEY_PREFIX}:{job_id}"
+
+        try:
+            # Jobs queued before history was enabled or already expired are skipped
+            if not self.client.exists(key):
+                return
+
+            fields = {"status": status, "updated_at": time.time()}
+            if error is not None:
+                fields["error"] = error[:500]
+
+            pipe = self.client.pipeline()
+            pipe.hset(key, mapping=fields)
+            if status == "retrying":
+                pipe.hincrby(key, "retries", 1)
+            pipe.execute()
+        except Exception as e:
+            logger.error(f"Failed to update job {job_id}: {e}")
+
+    def list_jobs(
+        self,
+        task_name: Optional[str] = None,
+        status: Optional[str] = None,
+        since: Optional[float] = None,
+        until: Optional[float] = None,
+        limit: int = 100
+    ) -> List[dict]:
+        """
+        Get recorded jobs, newest first.
+
+        Filters by type, status and queued time (epoch seconds).
+        """
+        job_ids = self.client.zrevrangebyscore(
+            JOB_INDEX_KEY,
+            until if until is not None else "+inf",
+            since if since is not None else "-inf"
+        )
+
+        jobs = []
+        for start in range(0, len(job_ids), 100):
+            pipe = self.client.pipeline()
+            for job_id in job_ids[start:start + 100]:
+                pipe.hgetall(f"{JOB_KEY_PREFIX}:{job_id}")
+
+            for job in pipe.execute():
+                if not job:
+                    continue
+                if task_name and job.get("type") != task_name:
+                    continue
+                if status and job.get("status") != status:
+                    continue
+
+                jobs.append({
+                    "id": job["id"],
+                    "type": job.get("type"),
+                    "status": job.get("status"),
+                    "queued_at": datetime.utcfromtimestamp(float(job["queued_at"])).isoformat(),
+                    "updated_at": datetime.utcfromtimestamp(float(job["updated_at"])).isoformat(),
+                    "retries": int(job.get("retries", 0)),
+                    "error": job.get("error")
+                })
+                if len(jobs) >= limit:
+                    return jobs
+
+        return jobs
+
+
+# Global job history instance
+job_history = JobHistory()
+
+
+def list_jobs(**filters) -> List[dict]:
+    """Get recorded jobs, newest first."""
+    return job_history.list_jobs(**filters)
+
+
+def connect_job_history(celery_app: Celery) -> None:
+    """Record jobs of the Celery app and their status changes."""
+
+    def is_tracked(task_name: Optional[str]) -> bool:
+        return (
+            bool(task_name)
+            and task_name in celery_app.tasks
+            and task_name not in settings.job_history_skip_tasks_list
+        )
+
+    @before_task_publish.connect(weak=False)
+    def on_publish(sender=None, headers=None, **kwargs):
+        job_id = (headers or {}).get("id")
+        # Retries are republished with the same ID
+        if job_id and is_tracked(sender) and not (headers or {}).get("retries"):
+            job_history.record_queued(job_id, sender)
+
+    @task_prerun.connect(weak=False)
+    def on_start(sender=None, task_id=None, **kwargs):
+        if sender is not None and is_tracked(sender.name):
+            job_history.record_status(task_id, "started")
+
+    @task_success.connect(weak=False)
+    def on_success(sender=None, **kwargs):
+        if sender is not None and is_tracked(sender.name):
+            job_history.record_status(sender.request.id, "succeeded")
+
+    @task_failure.connect(weak=False)
+    def on_failure(sender=None, task_id=None, exception=None, **kwargs):
+        if sender is not None and is_tracked(sender.name):
+            job_history.record_status(task_id, "failed", error=f"{type(exception).__name__}: {exception}")
+
+    @task_retry.connect(weak=False)
+    def on_retry(sender=None, request=None, reason=None, **kwargs):
+        if sender is not None and request is not None and is_tracked(sender.name):
+            job_history.record_status(request.id, "retrying", error=str(reason))
@@ -0,0 +1,129 @@
+import redis
+import logging
+from typing import Dict, Optional
+
+from celery import Celery
+from celery.signals import before_task_publish, task_success, task_failure, task_retry
+
+from shared.config import settings
+from .queues import job_queue_names
+from .scheduler import SCHEDULED_JOBS_KEY
+
+logger = logging.getLogger(__name__)
+
+# Job events counted per task type
+JOB_EVENTS = ("queued", "processed", "failed", "retried")
+
+# Celery broker index of scheduled (ETA/countdown) jobs held by workers
+SCHEDULED_INDEX = "unacked_index"
+
+METRICS_KEY_PREFIX = "job_metrics"
+
+
+class JobMetrics:
+    """
+    Background job counters stored in the Celery broker Redis.
+
+    Counters are shared by all publishers and workers, so stats can be
+    read from any service.
+    """
+
+    def __init__(self, broker_url: Optional[str] = None):
+        self.client = redis.Redis.from_url(
+            broker_url or settings.CELERY_BROKER_URL,
+            decode_responses=True
+        )
+
+    def increment(self, task_name: str, event: str) -> None:
+        """Increment job event counter. Errors are logged, never raised."""
+        try:
+            self.client.hincrby(f"{METRICS_KEY_PREFIX}:{task_name}", event, 1)
+        except Exception as e:
+            logger.error(f"Failed to record job metric {task_name}.{event}: {e}")
+
+    def get_counters(self) -> Dict[str, Dict[str, int]]:
+        """Get event counters per task type."""
+        counters = {}
+
+        for key in self.client.scan_iter(f"{METRICS_KEY_PREFIX}:*"):
+            task_name = key.split(":", 1)[1]
+            values = self.client.hgetall(key)
+            counters[task_name] = {event: int(values.get(event, 0)) for event in JOB_EVENTS}
+
+        return counters
+
+    def get_queue_depth(self) -> Dict[str, int]:
+        """
+        Get number of waiting jobs per queue and scheduled jobs.
+
+        Scheduled jobs are those waiting to be promoted plus ETA/countdown
+        jobs (retries) held by workers.
+        """
+        depth = {name: self.client.llen(name) for name in job_queue_names()}
+        depth["scheduled"] = self.client.zcard(SCHEDULED_JOBS_KEY) + self.client.zcard(SCHEDULED_INDEX)
+        return depth
+
+    def get_job_stats(self) -> dict:
+        """Get job counters and current queue depth."""
+        return {
+            "jobs": self.get_counters(),
+            "queue_depth": self.get_queue_depth()
+        }
+
+
+# Global job metrics instance
+job_metrics = JobMetrics()
+
+
+def get_job_stats() -> dict:
+    """Get job counters and current queue depth."""
+    return job_metrics.get_job_stats()
+
+
+def connect_job_metrics(celery_app: Celery) -> None:
+    """Count queued, processed, failed and retried jobs of the Celery app."""
+
+    def is_own_task(task_name: Optional[str]) -> bool:
+        return bool(task_name) and task_name in celery_app.tasks
+
+    @before_task_publish.connect(weak=False)
+    def on_publish(sender=None, **kwargs):
+        if is_own_task(sender):
+            job_metrics.increment(sender, "queued")
+
+    @task_success.connect(weak=False)
+    def on_success(sender=None, **kwargs):
+        if sender is not None and is_own_task(sender.name):
+            job_metrics.increment(sender.name, "processed")
+
+    @task_failure.connect(weak=False)
+    def on_failure(sender=None, **kwargs):
+        if sender is not None and is_own_task(sender.name):
+            job_metrics.increment(sender.name, "failed")
+
+    @task_retry.connect(weak=False)
+    def on_retry(sender=None, **kwargs):
+        if sender is not None and is_own_task(sender.name):
+            job_metrics.increment(sender.name, "retried")
+
+
+def render_prometheus_metrics(stats: dict) -> str:
+    """Render job stats in Prometheus text format."""
+    lines = [
+        "# HELP job_events_total Background job events by type.",
+        "# TYPE job_events_total counter"
+    ]
+
+    for task_name, counters in sorted(stats["jobs"].items()):
+        for event, value in counters.items():
+            lines.append(f'job_events_total{{type="{task_name}",event="{event}"}} {value}')
+
+    lines += [
+        "# HELP job_queue_depth Jobs waiting in the queue.",
+        "# TYPE job_queue_depth gauge"
+    ]
+
+    for queue, value in stats["queue_depth"].items():
+        lines.append(f'job_queue_depth{{queue="{queue}"}} {value}')
+
+    return "\n".join(lines) + "\n"
@@ -0,0 +1,27 @@
+from celery import Celery
+from kombu import Queue
+from typing import List
+
+from shared.config import settings
+
+
+def job_queue_names() -> List[str]:
+    """Get job queue names, highest priority first."""
+    return [settings.JOB_HIGH_PRIORITY_QUEUE, settings.JOB_LOW_PRIORITY_QUEUE]
+
+
+def configure_job_queues(celery_app: Celery) -> None:
+    """
+    Route jobs to priority queues.
+
+    Time-sensitive tasks listed in JOB_HIGH_PRIORITY_TASKS go to the high
+    priority queue, everything else to the low priority queue. Each queue
+    is consumed by its own worker pool, so a backlog of background jobs
+    never delays notifications.
+    """
+    celery_app.conf.task_queues = [Queue(name) for name in job_queue_names()]
+    celery_app.conf.task_default_queue = settings.JOB_LOW_PRIORITY_QUEUE
+    celery_app.conf.task_routes = {
+        task_name: {"queue": settings.JOB_HIGH_PRIORITY_QUEUE}
+        for task_name in settings.job_high_priority_tasks_list
+    }
@@ -0,0 +1,15 @@
+import random
+
+from shared.config import settings
+
+
+def retry_delay(attempt: int) -> float:
+    """
+    Get delay in seconds before the given retry attempt (starting at 1).
+
+    Exponential backoff (JOB_RETRY_DELAY_SECONDS * 2^(attempt-1)) capped at
+    JOB_RETRY_MAX_DELAY_SECONDS, with full jitter so jobs failing together
+    do not retry in lockstep.
+    """
+    backoff = settings.JOB_RETRY_DELAY_SECONDS * 2 ** (max(attempt, 1) - 1)
+    return random.uniform(0, min(backoff, settings.JOB_RETRY_MAX_DELAY_SECONDS))
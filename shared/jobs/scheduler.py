@@ -0,0 +1,96 @@
+import json
+import time
+import uuid
+import redis
+import logging
+from typing import Optional
+
+from celery import Celery
+
+from shared.config import settings
+
+logger = logging.getLogger(__name__)
+
+# Sorted set of scheduled jobs scored by due time (epoch seconds)
+SCHEDULED_JOBS_KEY = "scheduled_jobs"
+
+# Held while due jobs are promoted, so one scheduler runs across replicas
+PROMOTION_LOCK_KEY = "scheduled_jobs:promoting"
+
+
+class JobScheduler:
+    """
+    Scheduled jobs stored in the Celery broker Redis.
+
+    Jobs wait in a sorted set instead of being held by workers as ETA
+    tasks. A single periodic promotion moves due jobs to their queue, so
+    workers only consume ready jobs.
+    """
+
+    def __init__(self, broker_url: Optional[str] = None):
+        self.client = redis.Redis.from_url(
+            broker_url or settings.CELERY_BROKER_URL,
+            decode_responses=True
+        )
+
+    def schedule(
+        self,
+        task_name: str,
+        args: Optional[list] = None,
+        kwargs: Optional[dict] = None,
+        countdown: float = 0
+    ) -> str:
+        """Schedule task to run in countdown seconds. Returns job ID."""
+        job_id = uuid.uuid4().hex
+        payload = json.dumps({"id": job_id, "task": task_name, "args": args or [], "kwargs": kwargs or {}})
+        self.client.zadd(SCHEDULED_JOBS_KEY, {payload: time.time() + countdown})
+        return job_id
+
+    def count(self) -> int:
+        """Get number of jobs waiting for their due time."""
+        return self.client.zcard(SCHEDULED_JOBS_KEY)
+
+    def promote_due(self, celery_app: Celery, now: Optional[float] = None) -> int:
+        """
+        Move due jobs to their queue. Returns number of promoted jobs.
+
+        Skipped if another replica is promoting. A job is only sent by the
+        caller that removed it from the set, so it's promoted exactly once
+        even if the lock expires mid-run; a job that fails to send is put
+        back with its due time.
+        """
+        if not self.client.set(PROMOTION_LOCK_KEY, "1", nx=True, ex=settings.JOB_SCHEDULER_LOCK_SECONDS):
+            logger.info("Scheduled jobs are being promoted by another replica")
+            return 0
+
+        now = now if now is not None else time.time()
+        promoted = 0
+
+        try:
+            due = self.client.zrangebyscore(
+                SCHEDULED_JOBS_KEY, "-inf", now, withscores=True,
+                start=0, num=settings.JOB_SCHEDULER_BATCH_SIZE
+            )
+
+            for payload, due_at in due:
+                if not self.client.zrem(SCHEDULED_JOBS_KEY, payload):
+                    continue
+
+                job = json.loads(payload)
+                try:
+                    celery_app.send_task(job["task"], args=job["args"], kwargs=job["kwargs"])
+                except Exception as e:
+                    self.client.zadd(SCHEDULED_JOBS_KEY, {payload: due_at})
+                    logger.error(f"Failed to promote scheduled job {job['id']} ({job['task']}): {e}")
+                    continue
+
+                promoted += 1
+
+        finally:
+            self.client.delete(PROMOTION_LOCK_KEY)
+
+        return promoted
+
+
+# Global job scheduler instance
+job_scheduler = JobScheduler()
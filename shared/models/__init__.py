@@ -3,15 +3,31 @@ from .models import (
     UserRole,
     TenantStatus,
     BookingStatus,
+    PaymentStatus,
+    NotificationChannel,
+    NotificationStatus,
+    ReviewStatus,
+    TimeOffStatus,
     Tenant,
     Location,
+    LocationHours,
     User,
     Service,
     Master,
     MasterService,
     MasterSchedule,
+    MasterScheduleOverride,
+    MasterTimeOff,
     Client,
-    Booking
+    ClientSession,
+    Booking,
+    BookingItem,
+    AdminAction,
+    Payment,
+    EmailBounce,
+    NotificationLog,
+    MessageTemplate,
+    Review
 )
 
 __all__ = [
@@ -19,13 +35,29 @@ __all__ = [
     "UserRole",
     "TenantStatus",
     "BookingStatus",
+    "PaymentStatus",
+    "NotificationChannel",
+    "NotificationStatus",
+    "ReviewStatus",
+    "TimeOffStatus",
     "Tenant",
     "Location",
+    "LocationHours",
     "User",
     "Service",
     "Master",
     "MasterService",
     "MasterSchedule",
+    "MasterScheduleOverride",
+    "MasterTimeOff",
     "Client",
-    "Booking"
+    "ClientSession",
+    "Booking",
+    "BookingItem",
+    "AdminAction",
+    "Payment",
+    "EmailBounce",
+    "NotificationLog",
+    "MessageTemplate",
+    "Review"
 ]
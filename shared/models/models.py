@@ -1,7 +1,11 @@
-from sqlalchemy import Column, Integer, String, DateTime, Boolean, ForeignKey, Text, Numeric, Enum as SQLEnum, Time
+from sqlalchemy import (
+    Column, Integer, String, Date, DateTime, Boolean, ForeignKey, Text, JSON, Enum as SQLEnum, Time, UniqueConstraint,
+    CheckConstraint, Float
+)
 from sqlalchemy.orm import relationship
 from datetime import datetime
 from enum import Enum
+import secrets
 
 from shared.database import Base
 
@@ -22,6 +26,7 @@ class TenantStatus(str, Enum):
     SUSPENDED = "SUSPENDED"
     TRIAL = "TRIAL"
     REJECTED = "REJECTED"
+    EXPIRED = "EXPIRED"
 
 
 class BookingStatus(str, Enum):
@@ -33,6 +38,41 @@ class BookingStatus(str, Enum):
     NO_SHOW = "NO_SHOW"
 
 
+class PaymentStatus(str, Enum):
+    """Payment status enum."""
+    PENDING = "PENDING"
+    SUCCEEDED = "SUCCEEDED"
+    FAILED = "FAILED"
+
+
+class NotificationChannel(str, Enum):
+    """Notification channel enum."""
+    WHATSAPP = "WHATSAPP"
+    EMAIL = "EMAIL"
+
+
+class NotificationStatus(str, Enum):
+    """Notification delivery status enum."""
+    SENT = "SENT"
+    DELIVERED = "DELIVERED"
+    FAILED = "FAILED"
+    SUPPRESSED = "SUPPRESSED"
+
+
+class ReviewStatus(str, Enum):
+    """Review moderation status enum."""
+    PENDING = "PENDING"
+    APPROVED = "APPROVED"
+    REJECTED = "REJECTED"
+
+
+class TimeOffStatus(str, Enum):
+    """Master time-off approval status enum."""
+    PENDING = "PENDING"
+    APPROVED = "APPROVED"
+    REJECTED = "REJECTED"
+
+
 class Tenant(Base):
     """Business tenant model."""
     __tablename__ = "tenants"
@@ -43,8 +83,26 @@ class Tenant(Base):
     phone = Column(String(20), nullable=False)
     email = Column(String(100), nullable=True)
     description = Column(Text, nullable=True)
+    timezone = Column(String(50), nullable=True)
+    language = Column(String(5), nullable=True)
+    currency = Column(String(3), nullable=True)
+    tax_rate = Column(Integer, nullable=True)  # basis points, 1200 = 12%
+    tax_inclusive = Column(Boolean, nullable=True)
     status = Column(SQLEnum(TenantStatus), default=TenantStatus.PENDING, nullable=False)
     trial_end_date = Column(DateTime, nullable=True)
+    trial_warning_sent_at = Column(DateTime, nullable=True)
+    subscription_end_date = Column(DateTime, nullable=True)
+    require_deposit = Column(Boolean, default=False)
+    slot_interval_minutes = Column(Integer, nullable=True)  # default step between start times, 30 if unset
+    min_lead_minutes = Column(Integer, nullable=True)  # bookings start at least this long from now, 0 if unset
+    # New reviews wait for owner approval instead of being published at once
+    moderate_reviews = Column(Boolean, default=False, nullable=False)
+    branding = Column(JSON, nullable=True)  # {"logo_url": ..., "primary_color": "#2e7d32"}
+    features = Column(JSON, nullable=True)  # overrides of DEFAULT_TENANT_FEATURES, {"deposits": false}
+    # Overrides of the default reminder channels, {"same_day": ["WHATSAPP"], "advance": ["EMAIL"]}
+    reminder_channels = Column(JSON, nullable=True)
+    # Cancellation fee tiers, [{"hours_before": 24, "fee_percent": 0}, ...], CANCELLATION_POLICY if unset
+    cancellation_policy = Column(JSON, nullable=True)
     created_at = Column(DateTime, default=datetime.utcnow, nullable=False)
     updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
 
@@ -71,6 +129,29 @@ class Location(Base):
     # Relationships
     tenant = relationship("Tenant", back_populates="locations")
     masters = relationship("Master", back_populates="location")
+    hours = relationship("LocationHours", back_populates="location", cascade="all, delete-orphan")
+
+
+class LocationHours(Base):
+    """Weekly opening hours of a location; a location without any is open whenever its masters work."""
+    __tablename__ = "location_hours"
+    __table_args__ = (
+        UniqueConstraint("location_id", "day_of_week"),
+        CheckConstraint("day_of_week BETWEEN 0 AND 6", name="ck_location_hours_day_of_week"),
+        CheckConstraint("start_time < end_time", name="ck_location_hours_time_range"),
+    )
+
+    id = Column(Integer, primary_key=True, index=True)
+    location_id = Column(Integer, ForeignKey("locations.id", ondelete="CASCADE"), nullable=False, index=True)
+    day_of_week = Column(Integer, nullable=False)  # 0=Monday, 6=Sunday
+    start_time = Column(Time, nullable=False)
+    end_time = Column(Time, nullable=False)
+    is_open = Column(Boolean, default=True, nullable=False)
+    created_at = Column(DateTime, default=datetime.utcnow)
+    updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
+
+    # Relationships
+    location = relationship("Location", back_populates="hours")
 
 
 class User(Base):
@@ -101,7 +182,21 @@ class Service(Base):
     name = Column(String(200), nullable=False)
     description = Column(Text, nullable=True)
     duration_minutes = Column(Integer, nullable=False)
-    price = Column(Numeric(10, 2), nullable=False)
+    price = Column(Integer, nullable=False)  # minor units
+    require_deposit = Column(Boolean, default=False)
+    deposit_amount = Column(Integer, nullable=True)  # minor units
+    tax_rate = Column(Integer, nullable=True)  # basis points, overrides tenant
+    tax_inclusive = Column(Boolean, nullable=True)
+    # Completed bookings within POPULARITY_WINDOW_DAYS, recomputed periodically
+    popularity_score = Column(Integer, default=0, nullable=False)
+    # Clients per slot, above 1 for group services (classes)
+    capacity = Column(Integer, default=1, nullable=False)
+    # Step between offered start times, tenant default if unset
+    slot_interval_minutes = Column(Integer, nullable=True)
+    # Minimum time between booking and start, tenant default if unset
+    min_lead_minutes = Column(Integer, nullable=True)
+    # Cancellation fee tiers, tenant policy if unset
+    cancellation_policy = Column(JSON, nullable=True)
     is_active = Column(Boolean, default=True)
     created_at = Column(DateTime, default=datetime.utcnow)
     updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
@@ -123,6 +218,14 @@ class Master(Base):
     phone = Column(String(20), nullable=False)
     description = Column(Text, nullable=True)
     is_active = Column(Boolean, default=True)
+    # Paused masters stay visible but can't be booked, hidden ones aren't shown publicly
+    is_accepting_bookings = Column(Boolean, default=True, nullable=False)
+    is_visible = Column(Boolean, default=True, nullable=False)
+    # Average and count of approved reviews, refreshed on moderation and recomputed periodically
+    rating = Column(Float, nullable=True)
+    reviews_count = Column(Integer, default=0, nullable=False)
+    # Secret of the master's calendar feed URL, rotated on demand
+    calendar_token = Column(String(64), unique=True, nullable=True, index=True)
     created_at = Column(DateTime, default=datetime.utcnow)
     updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
 
@@ -131,6 +234,10 @@ class Master(Base):
     location = relationship("Location", back_populates="masters")
     master_services = relationship("MasterService", back_populates="master", cascade="all, delete-orphan")
     schedules = relationship("MasterSchedule", back_populates="master", cascade="all, delete-orphan")
+    schedule_overrides = relationship(
+        "MasterScheduleOverride", back_populates="master", cascade="all, delete-orphan"
+    )
+    time_off = relationship("MasterTimeOff", back_populates="master", cascade="all, delete-orphan")
     bookings = relationship("Booking", back_populates="master")
 
 
@@ -151,6 +258,10 @@ class MasterService(Base):
 class MasterSchedule(Base):
     """Master working schedule."""
     __tablename__ = "master_schedules"
+    __table_args__ = (
+        CheckConstraint("day_of_week BETWEEN 0 AND 6", name="ck_master_schedules_day_of_week"),
+        CheckConstraint("start_time < end_time", name="ck_master_schedules_time_range"),
+    )
 
     id = Column(Integer, primary_key=True, index=True)
     master_id = Column(Integer, ForeignKey("masters.id", ondelete="CASCADE"), nullable=False)
@@ -165,6 +276,53 @@ class MasterSchedule(Base):
     master = relationship("Master", back_populates="schedules")
 
 
+class MasterScheduleOverride(Base):
+    """One-off working hours of a master on a date, replacing the weekly schedule, e.g. a day off."""
+    __tablename__ = "master_schedule_overrides"
+    __table_args__ = (
+        UniqueConstraint("master_id", "date"),
+        CheckConstraint(
+            "NOT is_working OR start_time < end_time", name="ck_master_schedule_overrides_time_range"
+        ),
+    )
+
+    id = Column(Integer, primary_key=True, index=True)
+    master_id = Column(Integer, ForeignKey("masters.id", ondelete="CASCADE"), nullable=False, index=True)
+    date = Column(Date, nullable=False)
+    is_working = Column(Boolean, default=False, nullable=False)
+    start_time = Column(Time, nullable=True)  # set when working
+    end_time = Column(Time, nullable=True)
+    reason = Column(String(200), nullable=True)
+    created_at = Column(DateTime, default=datetime.utcnow)
+    updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
+
+    # Relationships
+    master = relationship("Master", back_populates="schedule_overrides")
+
+
+class MasterTimeOff(Base):
+    """Vacation or other absence of a master over a date range, inclusive."""
+    __tablename__ = "master_time_off"
+    __table_args__ = (
+        CheckConstraint("start_date <= end_date", name="ck_master_time_off_date_range"),
+    )
+
+    id = Column(Integer, primary_key=True, index=True)
+    master_id = Column(Integer, ForeignKey("masters.id", ondelete="CASCADE"), nullable=False, index=True)
+    start_date = Column(Date, nullable=False)
+    end_date = Column(Date, nullable=False)
+    reason = Column(String(200), nullable=True)
+    # Only approved time off blocks availability
+    status = Column(SQLEnum(TimeOffStatus), default=TimeOffStatus.PENDING, nullable=False)
+    requested_by = Column(Integer, ForeignKey("users.id", ondelete="SET NULL"), nullable=True)
+    reviewed_by = Column(Integer, ForeignKey("users.id", ondelete="SET NULL"), nullable=True)
+    created_at = Column(DateTime, default=datetime.utcnow)
+    updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
+
+    # Relationships
+    master = relationship("Master", back_populates="time_off")
+
+
 class Client(Base):
     """Client model."""
     __tablename__ = "clients"
@@ -173,11 +331,34 @@ class Client(Base):
     phone = Column(String(20), unique=True, nullable=False, index=True)
     full_name = Column(String(200), nullable=True)
     email = Column(String(100), nullable=True)
+    no_show_count = Column(Integer, default=0, nullable=False)
+    # Set once personal data was deleted on request, bookings are kept for stats
+    anonymized_at = Column(DateTime, nullable=True)
     created_at = Column(DateTime, default=datetime.utcnow)
     updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
 
     # Relationships
     bookings = relationship("Booking", back_populates="client")
+    sessions = relationship("ClientSession", back_populates="client", cascade="all, delete-orphan")
+
+
+class ClientSession(Base):
+    """Client session created after phone verification."""
+    __tablename__ = "client_sessions"
+
+    id = Column(Integer, primary_key=True, index=True)
+    client_id = Column(Integer, ForeignKey("clients.id", ondelete="CASCADE"), nullable=False)
+    verification_code = Column(String(10), nullable=True)
+    verification_expires = Column(DateTime, nullable=True)
+    is_verified = Column(Boolean, default=False)
+    session_token = Column(String(100), unique=True, nullable=True, index=True)
+    session_expires = Column(DateTime, nullable=True)
+    last_used = Column(DateTime, nullable=True)
+    created_at = Column(DateTime, default=datetime.utcnow)
+    updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
+
+    # Relationships
+    client = relationship("Client", back_populates="sessions")
 
 
 class Booking(Base):
@@ -188,17 +369,161 @@ class Booking(Base):
     tenant_id = Column(Integer, ForeignKey("tenants.id"), nullable=False)
     client_id = Column(Integer, ForeignKey("clients.id"), nullable=False)
     master_id = Column(Integer, ForeignKey("masters.id"), nullable=False)
-    service_id = Column(Integer, ForeignKey("services.id"), nullable=False)
+    service_id = Column(Integer, ForeignKey("services.id"), nullable=False)  # first service of a combo
     booking_date = Column(DateTime, nullable=False, index=True)
-    duration_minutes = Column(Integer, nullable=False)
-    price = Column(Numeric(10, 2), nullable=False)
+    duration_minutes = Column(Integer, nullable=False)  # all services together
+    price = Column(Integer, nullable=False)  # minor units, total charged
+    currency = Column(String(3), nullable=False)
+    tax_amount = Column(Integer, default=0, nullable=False)  # minor units, included in price
+    tax_rate = Column(Integer, default=0, nullable=False)  # basis points
+    tax_inclusive = Column(Boolean, default=True, nullable=False)
     status = Column(SQLEnum(BookingStatus), default=BookingStatus.PENDING, nullable=False)
     client_notes = Column(Text, nullable=True)
     admin_notes = Column(Text, nullable=True)
     whatsapp_reminder_sent = Column(Boolean, default=False)
+    reminder_channels = Column(JSON, nullable=True)  # chosen by the client, ["EMAIL"]; [] for no reminders
+    payment_due_at = Column(DateTime, nullable=True)
+    # Unguessable code for public links (calendar file)
+    public_code = Column(String(32), unique=True, nullable=True, index=True,
+                         default=lambda: secrets.token_urlsafe(16))
     created_at = Column(DateTime, default=datetime.utcnow)
     updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
 
     # Relationships
+    tenant = relationship("Tenant")
     client = relationship("Client", back_populates="bookings")
     master = relationship("Master", back_populates="bookings")
+    service = relationship("Service")
+    items = relationship(
+        "BookingItem", back_populates="booking", order_by="BookingItem.position", cascade="all, delete-orphan"
+    )
+
+    @property
+    def service_names(self) -> str:
+        """Names of the booked services, joined for combos."""
+        if self.items:
+            return " + ".join(item.service.name for item in self.items if item.service)
+        return self.service.name if self.service else ""
+
+
+class BookingItem(Base):
+    """Service of a booking, a combo booking has several."""
+    __tablename__ = "booking_items"
+
+    id = Column(Integer, primary_key=True, index=True)
+    booking_id = Column(Integer, ForeignKey("bookings.id", ondelete="CASCADE"), nullable=False, index=True)
+    service_id = Column(Integer, ForeignKey("services.id"), nullable=False)
+    position = Column(Integer, default=0, nullable=False)  # order the services are performed in
+    duration_minutes = Column(Integer, nullable=False)
+    price = Column(Integer, nullable=False)  # minor units, total charged for the service
+    tax_amount = Column(Integer, default=0, nullable=False)  # minor units, included in price
+    tax_rate = Column(Integer, default=0, nullable=False)  # basis points
+    created_at = Column(DateTime, default=datetime.utcnow)
+
+    # Relationships
+    booking = relationship("Booking", back_populates="items")
+    service = relationship("Service")
+
+
+class Review(Base):
+    """Client review of a completed booking."""
+    __tablename__ = "reviews"
+
+    id = Column(Integer, primary_key=True, index=True)
+    tenant_id = Column(Integer, ForeignKey("tenants.id", ondelete="CASCADE"), nullable=False, index=True)
+    booking_id = Column(Integer, ForeignKey("bookings.id", ondelete="CASCADE"), nullable=False, unique=True)
+    client_id = Column(Integer, ForeignKey("clients.id", ondelete="CASCADE"), nullable=False)
+    master_id = Column(Integer, ForeignKey("masters.id", ondelete="CASCADE"), nullable=False, index=True)
+    rating = Column(Integer, nullable=False)  # 1-5
+    comment = Column(Text, nullable=True)
+    # Only approved reviews are shown publicly and count toward ratings
+    status = Column(SQLEnum(ReviewStatus), default=ReviewStatus.APPROVED, nullable=False, index=True)
+    owner_response = Column(Text, nullable=True)
+    responded_at = Column(DateTime, nullable=True)
+    created_at = Column(DateTime, default=datetime.utcnow, index=True)
+    updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
+
+    # Relationships
+    booking = relationship("Booking")
+    client = relationship("Client")
+    master = relationship("Master")
+
+
+class AdminAction(Base):
+    """Admin action log. admin_id is empty for actions of background jobs."""
+    __tablename__ = "admin_actions"
+
+    id = Column(Integer, primary_key=True, index=True)
+    admin_id = Column(Integer, ForeignKey("users.id", ondelete="SET NULL"), nullable=True, index=True)
+    action_type = Column(String(100), nullable=False, index=True)
+    target_type = Column(String(100), nullable=False)
+    target_id = Column(Integer, nullable=False)
+    details = Column(Text, nullable=True)
+    created_at = Column(DateTime, default=datetime.utcnow, index=True)
+
+
+class Payment(Base):
+    """Payment model: subscription payment, or booking deposit if booking_id is set."""
+    __tablename__ = "payments"
+
+    id = Column(Integer, primary_key=True, index=True)
+    tenant_id = Column(Integer, ForeignKey("tenants.id", ondelete="CASCADE"), nullable=False, index=True)
+    booking_id = Column(Integer, ForeignKey("bookings.id", ondelete="SET NULL"), nullable=True, index=True)
+    amount = Column(Integer, nullable=False)  # minor units
+    currency = Column(String(3), nullable=False)
+    period_days = Column(Integer, nullable=True)
+    status = Column(SQLEnum(PaymentStatus), default=PaymentStatus.PENDING, nullable=False)
+    provider_payment_id = Column(String(100), unique=True, nullable=True)
+    paid_at = Column(DateTime, nullable=True)
+    refund_amount = Column(Integer, nullable=True)  # minor units owed back after a booking cancellation
+    refunded_at = Column(DateTime, nullable=True)  # refund accepted by the provider
+    created_at = Column(DateTime, default=datetime.utcnow)
+    updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
+
+    # Relationships
+    tenant = relationship("Tenant")
+    booking = relationship("Booking")
+
+
+class EmailBounce(Base):
+    """Recipient address that permanently failed; emails to it are suppressed."""
+    __tablename__ = "email_bounces"
+
+    id = Column(Integer, primary_key=True, index=True)
+    email = Column(String(255), unique=True, nullable=False, index=True)
+    reason = Column(Text, nullable=True)
+    bounce_count = Column(Integer, default=1, nullable=False)
+    last_bounced_at = Column(DateTime, default=datetime.utcnow, nullable=False)
+    created_at = Column(DateTime, default=datetime.utcnow)
+
+
+class NotificationLog(Base):
+    """Sent notification with its delivery status."""
+    __tablename__ = "notifications_sent"
+
+    id = Column(Integer, primary_key=True, index=True)
+    tenant_id = Column(Integer, ForeignKey("tenants.id", ondelete="CASCADE"), nullable=True, index=True)
+    booking_id = Column(Integer, ForeignKey("bookings.id", ondelete="SET NULL"), nullable=True, index=True)
+    client_id = Column(Integer, ForeignKey("clients.id", ondelete="SET NULL"), nullable=True, index=True)
+    channel = Column(SQLEnum(NotificationChannel), nullable=False)
+    recipient = Column(String(255), nullable=False)
+    template = Column(String(100), nullable=False)
+    status = Column(SQLEnum(NotificationStatus), nullable=False)
+    provider_message_id = Column(String(255), nullable=True, index=True)
+    error = Column(Text, nullable=True)
+    created_at = Column(DateTime, default=datetime.utcnow, index=True)
+    updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
+
+
+class MessageTemplate(Base):
+    """Tenant override of a built-in message template."""
+    __tablename__ = "message_templates"
+    __table_args__ = (UniqueConstraint("tenant_id", "name", "language"),)
+
+    id = Column(Integer, primary_key=True, index=True)
+    tenant_id = Column(Integer, ForeignKey("tenants.id", ondelete="CASCADE"), nullable=False, index=True)
+    name = Column(String(100), nullable=False)
+    language = Column(String(5), nullable=False)
+    body = Column(Text, nullable=False)
+    created_at = Column(DateTime, default=datetime.utcnow)
+    updated_at = Column(DateTime, default=datetime.utcnow, onupdate=datetime.utcnow)
@@ -0,0 +1,7 @@
+from .log import record_notification
+from .reminders import reminder_channels
+
+__all__ = [
+    "record_notification",
+    "reminder_channels"
+]
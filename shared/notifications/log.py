@@ -0,0 +1,48 @@
+from typing import Optional
+import logging
+
+from shared.database import get_db_context
+from shared.models import Booking, NotificationLog, NotificationChannel, NotificationStatus
+
+logger = logging.getLogger(__name__)
+
+
+def record_notification(
+    channel: NotificationChannel,
+    recipient: str,
+    template: str,
+    status: NotificationStatus,
+    tenant_id: Optional[int] = None,
+    booking_id: Optional[int] = None,
+    client_id: Optional[int] = None,
+    provider_message_id: Optional[str] = None,
+    error: Optional[str] = None
+) -> None:
+    """
+    Write notification to the delivery log.
+
+    Tenant and client are taken from the booking when not given. Uses its
+    own session so the log row is kept even if the caller's transaction is
+    rolled back. Failures are logged, never raised.
+    """
+    try:
+        with get_db_context() as db:
+            if booking_id is not None and (tenant_id is None or client_id is None):
+                booking = db.query(Booking).filter(Booking.id == booking_id).first()
+                if booking:
+                    tenant_id = tenant_id or booking.tenant_id
+                    client_id = client_id or booking.client_id
+
+            db.add(NotificationLog(
+                tenant_id=tenant_id,
+                booking_id=booking_id,
+                client_id=client_id,
+                channel=channel,
+                recipient=recipient,
+                template=template,
+                status=status,
+                provider_message_id=provider_message_id,
+                error=error[:1000] if error else None
+            ))
+    except Exception as e:
+        logger.error(f"Failed to record {channel.value} notification {template} to {recipient}: {e}")
@@ -0,0 +1,37 @@
+from typing import List, Optional
+
+from shared.config import settings
+from shared.models import Booking, NotificationChannel
+from shared.tenants import get_tenant_settings
+
+
+def parse_channels(names: Optional[List[str]]) -> List[NotificationChannel]:
+    """Known channels of the list, in order."""
+    return [NotificationChannel(n) for n in names or [] if n in NotificationChannel.__members__]
+
+
+def reminder_channels(booking: Booking, hours_before: Optional[int]) -> List[NotificationChannel]:
+    """
+    Channels to send the booking reminder by.
+
+    Same-day reminders (at most REMINDER_SAME_DAY_HOURS before the booking)
+    and earlier ones have their own channels, from the tenant settings.
+    Channels chosen by the client narrow them down, or replace them if
+    none match; an empty choice means no reminders. Email is dropped for
+    clients without an address, falling back to WhatsApp.
+    """
+    if booking.reminder_channels == []:
+        return []
+
+    same_day = hours_before is not None and hours_before <= settings.REMINDER_SAME_DAY_HOURS
+    window = "same_day" if same_day else "advance"
+    channels = getattr(get_tenant_settings(booking.tenant).reminder_channels, window)
+
+    if booking.reminder_channels is not None:
+        chosen = parse_channels(booking.reminder_channels)
+        channels = [c for c in channels if c in chosen] or chosen
+
+    if not (booking.client and booking.client.email):
+        channels = [c for c in channels if c != NotificationChannel.EMAIL]
+
+    return channels or [NotificationChannel.WHATSAPP]
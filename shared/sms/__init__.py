@@ -0,0 +1,13 @@
+from .segments import sms_encoding, sms_length, sms_segments, truncate_sms, describe_sms
+from .templates import SMS_TEMPLATES, SmsTemplate, render_sms
+
+__all__ = [
+    "sms_encoding",
+    "sms_length",
+    "sms_segments",
+    "truncate_sms",
+    "describe_sms",
+    "SMS_TEMPLATES",
+    "SmsTemplate",
+    "render_sms"
+]
@@ -0,0 +1,76 @@
+from typing import Optional
+
+# GSM 03.38 basic character set
+GSM7_BASIC = set(
+    "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?"
+    "¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+)
+
+# Extension table characters take two septets (escape + char)
+GSM7_EXTENDED = set("^{}\\[~]|€\f")
+
+# Characters per segment: single message / part of a concatenated message
+GSM7_SINGLE, GSM7_MULTI = 160, 153
+UCS2_SINGLE, UCS2_MULTI = 70, 67
+
+ELLIPSIS = "..."
+
+
+def sms_encoding(text: str) -> str:
+    """Get SMS encoding needed for the text: GSM-7 or UCS-2 (Cyrillic, emoji)."""
+    allowed = GSM7_BASIC | GSM7_EXTENDED
+    return "GSM-7" if all(char in allowed for char in text) else "UCS-2"
+
+
+def sms_length(text: str, encoding: Optional[str] = None) -> int:
+    """
+    Get message length in encoding units.
+
+    GSM-7 extension characters count twice; in UCS-2 characters outside
+    the BMP (emoji) take two UTF-16 code units.
+    """
+    encoding = encoding or sms_encoding(text)
+
+    if encoding == "GSM-7":
+        return sum(2 if char in GSM7_EXTENDED else 1 for char in text)
+
+    return len(text.encode("utf-16-le")) // 2
+
+
+def sms_segments(text: str) -> int:
+    """Get number of SMS segments the text is sent as (billed per segment)."""
+    encoding = sms_encoding(text)
+    length = sms_length(text, encoding)
+    single, multi = (GSM7_SINGLE, GSM7_MULTI) if encoding == "GSM-7" else (UCS2_SINGLE, UCS2_MULTI)
+
+    if length <= single:
+        return 1
+    return -(-length // multi)
+
+
+def truncate_sms(text: str, max_segments: int = 1) -> str:
+    """Truncate text with an ellipsis so it fits into max_segments."""
+    if sms_segments(text) <= max_segments:
+        return text
+
+    encoding = sms_encoding(text)
+    single, multi = (GSM7_SINGLE, GSM7_MULTI) if encoding == "GSM-7" else (UCS2_SINGLE, UCS2_MULTI)
+    limit = (single if max_segments == 1 else multi * max_segments) - sms_length(ELLIPSIS, encoding)
+
+    result = ""
+    for char in text:
+        if sms_length(result + char, encoding) > limit:
+            break
+        result += char
+
+    return result.rstrip() + ELLIPSIS
+
+
+def describe_sms(text: str) -> dict:
+    """Get encoding, length and segment count of the text for cost tracking."""
+    encoding = sms_encoding(text)
+    return {
+        "encoding": encoding,
+        "length": sms_length(text, encoding),
+        "segments": sms_segments(text)
+    }
@@ -0,0 +1,121 @@
+from enum import Enum
+from typing import Dict, Optional
+import logging
+
+from shared.config import settings
+
+logger = logging.getLogger(__name__)
+
+
+class SmsTemplate(str, Enum):
+    """Text messages sent to clients (WhatsApp), named as in the notification log."""
+    BOOKING_CONFIRMED = "booking_confirmed"
+    BOOKING_DEPOSIT_PENDING = "booking_deposit_pending"
+    BOOKING_CANCELLED = "booking_cancelled"
+    CLIENT_VERIFICATION_CODE = "client_verification_code"
+
+
+# Message texts by language and template
+SMS_TEMPLATES: Dict[str, Dict[str, str]] = {
+    "ru": {
+        "booking_confirmed": (
+            "✅ Бронирование подтверждено!\n\n"
+            "Бизнес: {business_name}\n"
+            "Услуга: {service_name}\n"
+            "Дата: {booking_date}\n"
+            "Цена: {price}\n\n"
+            "Спасибо за ваш выбор!"
+        ),
+        "booking_deposit_pending": (
+            "⏳ Бронирование ожидает предоплаты\n\n"
+            "Бизнес: {business_name}\n"
+            "Услуга: {service_name}\n"
+            "Дата: {booking_date}\n"
+            "Предоплата: {deposit}\n\n"
+            "Оплатите в течение {minutes} минут:\n"
+            "{checkout_url}"
+        ),
+        "booking_cancelled": (
+            "❌ Ваше бронирование отменено\n\n"
+            "Дата: {booking_date}\n\n"
+            "Для новой записи свяжитесь с нами."
+        ),
+        "client_verification_code": (
+            "Ваш код подтверждения: {code}\n\n"
+            "Код действителен {minutes} минут."
+        ),
+    },
+    "en": {
+        "booking_confirmed": (
+            "✅ Booking confirmed!\n\n"
+            "Business: {business_name}\n"
+            "Service: {service_name}\n"
+            "Date: {booking_date}\n"
+            "Price: {price}\n\n"
+            "Thank you for choosing us!"
+        ),
+        "booking_deposit_pending": (
+            "⏳ Booking awaits deposit payment\n\n"
+            "Business: {business_name}\n"
+            "Service: {service_name}\n"
+            "Date: {booking_date}\n"
+            "Deposit: {deposit}\n\n"
+            "Please pay within {minutes} minutes:\n"
+            "{checkout_url}"
+        ),
+        "booking_cancelled": (
+            "❌ Your booking has been cancelled\n\n"
+            "Date: {booking_date}\n\n"
+            "Contact us to book again."
+        ),
+        "client_verification_code": (
+            "Your verification code: {code}\n\n"
+            "The code is valid for {minutes} minutes."
+        ),
+    },
+    "kk": {
+        "booking_confirmed": (
+            "✅ Жазылу расталды!\n\n"
+            "Бизнес: {business_name}\n"
+            "Қызмет: {service_name}\n"
+            "Күні: {booking_date}\n"
+            "Бағасы: {price}\n\n"
+            "Бізді таңдағаныңызға рахмет!"
+        ),
+        "booking_deposit_pending": (
+            "⏳ Жазылу алдын ала төлемді күтуде\n\n"
+            "Бизнес: {business_name}\n"
+            "Қызмет: {service_name}\n"
+            "Күні: {booking_date}\n"
+            "Алдын ала төлем: {deposit}\n\n"
+            "{minutes} минут ішінде төлеңіз:\n"
+            "{checkout_url}"
+        ),
+        "booking_cancelled": (
+            "❌ Жазылуыңыз бас тартылды\n\n"
+            "Күні: {booking_date}\n\n"
+            "Қайта жазылу үшін бізге хабарласыңыз."
+        ),
+        "client_verification_code": (
+            "Растау кодыңыз: {code}\n\n"
+            "Код {minutes} минут жарамды."
+        ),
+    },
+}
+
+
+def render_sms(template: SmsTemplate, language: Optional[str] = None, **kwargs) -> str:
+    """
+    Render text message in the language.
+
+    Falls back to the default language for unsupported languages and
+    missing translations.
+    """
+    name = template.value
+    text = SMS_TEMPLATES.get(language or settings.DEFAULT_LANGUAGE, {}).get(name)
+
+    if text is None:
+        logger.warning(f"Missing SMS template: {language}.{name}")
+        text = SMS_TEMPLATES[settings.DEFAULT_LANGUAGE][name]
+
+    return text.format(**kwargs)
@@ -0,0 +1,23 @@
+from .schema import (
+    CancellationTier,
+    ReminderChannelSettings,
+    BrandingSettings,
+    TenantSettings,
+    TenantSettingsUpdate,
+    ServiceCancellationPolicyUpdate,
+    default_tenant_settings,
+    get_tenant_settings,
+    apply_tenant_settings
+)
+
+__all__ = [
+    "CancellationTier",
+    "ReminderChannelSettings",
+    "BrandingSettings",
+    "TenantSettings",
+    "TenantSettingsUpdate",
+    "ServiceCancellationPolicyUpdate",
+    "default_tenant_settings",
+    "get_tenant_settings",
+    "apply_tenant_settings"
+]
@@ -0,0 +1,158 @@
+from typing import List, Optional
+import logging
+import re
+
+from pydantic import BaseModel, Field, ValidationError, validator
+
+from shared.calendar import is_valid_timezone
+from shared.config import settings
+from shared.models import NotificationChannel, Tenant
+
+logger = logging.getLogger(__name__)
+
+COLOR_PATTERN = re.compile(r"^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$")
+
+
+class CancellationTier(BaseModel):
+    """Fee charged for cancelling at least hours_before the booking."""
+    hours_before: int = Field(..., ge=0)
+    fee_percent: int = Field(..., ge=0, le=100)
+
+    class Config:
+        extra = "forbid"
+
+
+class ReminderChannelSettings(BaseModel):
+    """Reminder channels of same-day (REMINDER_SAME_DAY_HOURS) and earlier reminders."""
+    same_day: List[NotificationChannel]
+    advance: List[NotificationChannel]
+
+    class Config:
+        extra = "forbid"
+
+    @validator('same_day', 'advance')
+    def validate_channels(cls, v):
+        if not v:
+            raise ValueError('At least one channel is required')
+        return list(dict.fromkeys(v))
+
+
+class BrandingSettings(BaseModel):
+    """Branding of emails; defaults are used for values left out."""
+    logo_url: Optional[str] = None
+    primary_color: Optional[str] = None
+
+    class Config:
+        extra = "forbid"
+
+    @validator('logo_url')
+    def validate_logo_url(cls, v):
+        if v is not None and not v.startswith("https://"):
+            raise ValueError('logo_url must be an https URL')
+        return v
+
+    @validator('primary_color')
+    def validate_primary_color(cls, v):
+        if v is not None and not COLOR_PATTERN.match(v):
+            raise ValueError('primary_color must be a hex color, e.g. #2e7d32')
+        return v
+
+
+def validate_timezone(v):
+    """Check the timezone is a supported IANA name."""
+    if v is not None and not is_valid_timezone(v):
+        raise ValueError('Unknown timezone, see /api/v1/public/timezones')
+    return v
+
+
+def validate_cancellation_policy(v):
+    """Check tiers are given, once per hours_before, and sort them earliest first."""
+    if v is None:
+        return v
+    if not v:
+        raise ValueError('At least one tier is required')
+    hours = [tier.hours_before for tier in v]
+    if len(hours) != len(set(hours)):
+        raise ValueError('Each hours_before may be given once')
+    return sorted(v, key=lambda tier: tier.hours_before, reverse=True)
+
+
+class TenantSettings(BaseModel):
+    """Settings of a tenant, kept in the Tenant columns of the same names."""
+    timezone: str
+    reminder_channels: ReminderChannelSettings
+    cancellation_policy: List[CancellationTier]
+    branding: BrandingSettings
+
+    _validate_timezone = validator('timezone', allow_reuse=True)(validate_timezone)
+    _validate_policy = validator('cancellation_policy', allow_reuse=True)(validate_cancellation_policy)
+
+
+class TenantSettingsUpdate(BaseModel):
+    """Sections of tenant settings to replace, unknown keys are rejected."""
+    timezone: Optional[str] = None
+    reminder_channels: Optional[ReminderChannelSettings] = None
+    cancellation_policy: Optional[List[CancellationTier]] = None
+    branding: Optional[BrandingSettings] = None
+
+    class Config:
+        extra = "forbid"
+
+    _validate_timezone = validator('timezone', allow_reuse=True)(validate_timezone)
+    _validate_policy = validator('cancellation_policy', allow_reuse=True)(validate_cancellation_policy)
+
+
+class ServiceCancellationPolicyUpdate(BaseModel):
+    """Cancellation policy of a service; null falls back to the tenant policy."""
+    cancellation_policy: Optional[List[CancellationTier]]
+
+    class Config:
+        extra = "forbid"
+
+    _validate_policy = validator('cancellation_policy', allow_reuse=True)(validate_cancellation_policy)
+
+
+def default_tenant_settings() -> dict:
+    """Settings of tenants without stored values, from the configuration."""
+    return {
+        "timezone": settings.DEFAULT_TIMEZONE,
+        "reminder_channels": {
+            "same_day": settings.reminder_same_day_channels_list,
+            "advance": settings.reminder_advance_channels_list
+        },
+        "cancellation_policy": settings.cancellation_policy_list,
+        "branding": {}
+    }
+
+
+def get_tenant_settings(tenant: Optional[Tenant]) -> TenantSettings:
+    """
+    Get settings of the tenant with defaults filled in for missing keys.
+
+    A stored section failing validation, e.g. written by hand with a
+    typo, is logged and replaced by its default.
+    """
+    defaults = default_tenant_settings()
+    values = {}
+
+    for name, default in defaults.items():
+        stored = getattr(tenant, name, None) if tenant else None
+        if isinstance(default, dict) and isinstance(stored, dict):
+            value = {**default, **stored}
+        else:
+            value = stored or default
+
+        try:
+            TenantSettings(**{**defaults, name: value})
+            values[name] = value
+        except ValidationError as e:
+            logger.warning(f"Invalid {name} settings of tenant {getattr(tenant, 'id', None)}, using defaults: {e}")
+            values[name] = default
+
+    return TenantSettings(**values)
+
+
+def apply_tenant_settings(tenant: Tenant, update: TenantSettingsUpdate) -> None:
+    """Store the sections given in the update on the tenant."""
+    for name, value in update.dict(exclude_none=True).items():
+        setattr(tenant, name, value)
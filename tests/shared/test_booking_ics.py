@@ -0,0 +1,81 @@
+from datetime import datetime
+
+from shared.calendar import ics
+from shared.calendar.ics import booking_ics, escape_text, fold_line
+from shared.config import settings
+from shared.models import BookingStatus
+
+
+def unfold(calendar: str) -> dict:
+    """Properties of the calendar by name, folded lines joined back."""
+    lines = calendar.replace("\r\n ", "").split("\r\n")
+    return dict(line.split(":", 1) for line in lines if ":" in line)
+
+
+def booked(factory, tenant_values=None, master_values=None, **values):
+    tenant = factory.tenant(**(tenant_values or {}))
+    service = factory.service(tenant, name="Haircut")
+    master = factory.master(tenant, [service], **(master_values or {}))
+    client = factory.client()
+    return factory.booking(tenant, master, service, client, **values)
+
+
+def test_times_are_converted_to_utc(db, factory):
+    booking = booked(factory, {"timezone": "Asia/Almaty"}, booking_date=datetime(2026, 3, 10, 10, 0))
+
+    event = unfold(booking_ics(booking))
+
+    assert event["DTSTART"] == "20260310T050000Z"
+    assert event["DTEND"] == "20260310T060000Z"
+    assert event["UID"] == f"booking-{booking.id}@{settings.BASE_DOMAIN}"
+    assert event["STATUS"] == "CONFIRMED"
+
+
+def test_client_event_names_business_and_master(db, factory):
+    booking = booked(factory)
+
+    event = unfold(booking_ics(booking))
+
+    assert event["SUMMARY"] == f"Haircut — {booking.tenant.business_name}"
+    assert event["DESCRIPTION"] == booking.master.full_name
+    assert event["LOCATION"] == booking.tenant.business_name
+
+
+def test_location_of_master_is_used(db, factory):
+    tenant = factory.tenant()
+    location = factory.location(tenant, name="Center", address="Abay 10")
+    service = factory.service(tenant)
+    master = factory.master(tenant, [service], location_id=location.id)
+    booking = factory.booking(tenant, master, service, factory.client())
+
+    assert unfold(booking_ics(booking))["LOCATION"] == "Center\\, Abay 10"
+
+
+def test_cancelled_booking_is_cancelled_event(db, factory):
+    booking = booked(factory, status=BookingStatus.CANCELLED)
+
+    assert unfold(booking_ics(booking))["STATUS"] == "CANCELLED"
+
+
+def test_text_is_escaped():
+    assert escape_text("Cut, wash; dry\\\nnext") == "Cut\\, wash\\; dry\\\\\\nnext"
+
+
+def test_long_lines_are_folded_at_octet_limit():
+    line = "SUMMARY:" + "Стрижка " * 20
+
+    folded = fold_line(line)
+
+    parts = folded.split("\r\n ")
+    assert len(parts) > 1
+    assert len(parts[0].encode("utf-8")) <= ics.MAX_LINE_OCTETS
+    assert all(len(part.encode("utf-8")) <= ics.MAX_LINE_OCTETS - 1 for part in parts[1:])
+    assert "".join(parts) == line
+
+
+def test_calendar_lines_end_with_crlf(db, factory):
+    calendar = booking_ics(booked(factory))
+
+    assert calendar.startswith("BEGIN:VCALENDAR\r\n")
+    assert calendar.endswith("END:VCALENDAR\r\n")
+    assert "\n" not in calendar.replace("\r\n", "")
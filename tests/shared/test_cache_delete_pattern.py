@@ -0,0 +1,19 @@
+def test_matching_keys_are_deleted_in_batches(cache):
+    for day in range(1, 8):
+        cache.set(f"availability:1:7:2026-03-0{day}:0:60", {"slots": []})
+    cache.set("availability:1:8:2026-03-01:0:60", {"slots": []})
+    cache.set("availability_version:1:7:2026-03-01", 3)
+
+    deleted = cache.delete_pattern("availability:1:7:*", batch_size=3)
+
+    assert deleted == 7
+    assert cache.keys("availability:1:7:*") == []
+    assert cache.exists("availability:1:8:2026-03-01:0:60")
+    assert cache.exists("availability_version:1:7:2026-03-01")
+
+
+def test_no_matching_keys(cache):
+    cache.set("availability:1:8:2026-03-01:0:60", {"slots": []})
+
+    assert cache.delete_pattern("availability:1:7:*") == 0
+    assert cache.exists("availability:1:8:2026-03-01:0:60")
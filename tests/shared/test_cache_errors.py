@@ -0,0 +1,54 @@
+import pytest
+import redis
+
+from shared.cache import CacheError
+from shared.cache.redis_client import cache_errors, render_prometheus_cache_metrics
+
+
+@pytest.fixture
+def redis_down(cache, monkeypatch):
+    def fail(*args, **kwargs):
+        raise redis.ConnectionError("Connection refused")
+
+    monkeypatch.setattr(cache.client, "get", fail)
+    monkeypatch.setattr(cache.client, "setex", fail)
+    monkeypatch.setattr(cache.client, "incr", fail)
+    cache_errors.clear()
+    yield
+    cache_errors.clear()
+
+
+def test_missing_key_is_a_miss(cache):
+    assert cache.fetch("availability:1:2026-03-01") is None
+
+
+def test_cached_value_is_returned(cache):
+    cache.set("availability:1:2026-03-01", {"slots": ["10:00"]}, expire=60)
+
+    assert cache.fetch("availability:1:2026-03-01") == {"slots": ["10:00"]}
+
+
+def test_invalid_cached_value_is_a_miss(cache):
+    cache.client.set("availability:1:2026-03-01", "not json")
+
+    assert cache.fetch("availability:1:2026-03-01") is None
+
+
+def test_redis_failure_is_raised_and_counted(cache, redis_down):
+    with pytest.raises(CacheError):
+        cache.fetch("availability:1:2026-03-01")
+
+    assert cache.get("availability:1:2026-03-01") is None
+    assert cache.set("availability:1:2026-03-01", {}, expire=60) is False
+    assert cache_errors == {"get": 2, "set": 1}
+    assert 'cache_errors_total{service="booking-service",operation="get"} 2' in render_prometheus_cache_metrics(
+        "booking-service"
+    )
+
+
+def test_redis_failure_of_increment_is_raised_and_counted(cache, redis_down):
+    with pytest.raises(CacheError):
+        cache.increment("rate_limit:127.0.0.1:1")
+
+    assert cache.incr("rate_limit:127.0.0.1:1") is None
+    assert cache_errors == {"incr": 2}
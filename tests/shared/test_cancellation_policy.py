@@ -0,0 +1,56 @@
+from datetime import datetime, timedelta
+from types import SimpleNamespace
+
+import pytest
+
+from shared.billing import compute_cancellation_fee, get_cancellation_policy, get_cancellation_tier
+
+POLICY = [
+    {"hours_before": 24, "fee_percent": 0},
+    {"hours_before": 2, "fee_percent": 50},
+    {"hours_before": 0, "fee_percent": 100}
+]
+BOOKING_DATE = datetime(2024, 5, 20, 12, 0)
+
+
+@pytest.mark.parametrize("before, fee_percent", [
+    (timedelta(days=3), 0),
+    (timedelta(hours=24), 0),
+    (timedelta(hours=24) - timedelta(seconds=1), 50),
+    (timedelta(hours=2), 50),
+    (timedelta(hours=2) - timedelta(seconds=1), 100),
+    (timedelta(0), 100)
+])
+def test_tier_applies_from_its_hours_before(before, fee_percent):
+    tier = get_cancellation_tier(POLICY, BOOKING_DATE, BOOKING_DATE - before)
+
+    assert tier["fee_percent"] == fee_percent
+
+
+def test_no_tier_after_the_last_one():
+    assert get_cancellation_tier(POLICY, BOOKING_DATE, BOOKING_DATE + timedelta(seconds=1)) is None
+    assert get_cancellation_tier(POLICY[:2], BOOKING_DATE, BOOKING_DATE - timedelta(hours=1)) is None
+
+
+def test_fee_is_rounded_half_up_and_kept_from_deposit():
+    assert compute_cancellation_fee(333, 1000, 50) == {"fee": 167, "refund": 833}
+    assert compute_cancellation_fee(500000, 150000, 0) == {"fee": 0, "refund": 150000}
+    assert compute_cancellation_fee(500000, 150000, 50) == {"fee": 250000, "refund": 0}
+    assert compute_cancellation_fee(500000, 0, 100) == {"fee": 500000, "refund": 0}
+
+
+def test_service_policy_overrides_tenant_policy():
+    service = SimpleNamespace(cancellation_policy=[
+        {"hours_before": 0, "fee_percent": 100},
+        {"hours_before": 48, "fee_percent": 0}
+    ])
+
+    policy = get_cancellation_policy(None, service)
+
+    assert [tier["hours_before"] for tier in policy] == [48, 0]
+
+
+def test_tenant_policy_applies_without_service_policy():
+    tenant = SimpleNamespace(cancellation_policy=POLICY)
+
+    assert get_cancellation_policy(tenant, SimpleNamespace(cancellation_policy=None)) == POLICY
@@ -0,0 +1,5 @@
+from shared.database import check_db_connection
+
+
+def test_check_db_connection(schema):
+    assert check_db_connection() is True
@@ -0,0 +1,39 @@
+from datetime import date, datetime, timezone
+from zoneinfo import ZoneInfo
+
+import pytest
+
+from shared.config import settings
+from shared.i18n import format_date, format_datetime, format_time
+
+INSTANT = datetime(2025, 1, 15, 14, 30)
+
+
+@pytest.mark.parametrize("language, expected", [
+    ("en", "January 15, 2025, 14:30"),
+    ("ru", "15 января 2025, 14:30"),
+    ("kk", "2025 жылғы 15 қаңтар, 14:30")
+])
+def test_same_instant_in_each_language(language, expected):
+    assert format_datetime(INSTANT, language) == expected
+
+
+def test_unknown_language_uses_default(monkeypatch):
+    monkeypatch.setattr(settings, "DEFAULT_LANGUAGE", "en")
+
+    assert format_date(date(2025, 12, 1), "de") == "December 1, 2025"
+
+
+def test_aware_value_is_converted_to_tenant_zone():
+    value = datetime(2025, 1, 15, 9, 30, tzinfo=timezone.utc)
+
+    assert format_datetime(value, "ru", ZoneInfo("Asia/Almaty")) == "15 января 2025, 14:30"
+
+
+def test_naive_value_is_kept_as_local_time():
+    assert format_datetime(INSTANT, "ru", ZoneInfo("Asia/Almaty")) == "15 января 2025, 14:30"
+
+
+def test_time_is_24_hour():
+    assert format_time(datetime(2025, 1, 15, 9, 5)) == "09:05"
+    assert format_time(datetime(2025, 1, 15, 21, 0)) == "21:00"
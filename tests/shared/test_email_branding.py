@@ -0,0 +1,48 @@
+from shared.email import DEFAULT_PRIMARY_COLOR, get_email_branding, text_to_html
+
+
+def test_tenant_branding_is_used(db, factory):
+    tenant = factory.tenant(branding={"logo_url": "https://cdn.example.com/logo.png", "primary_color": "#ff0066"})
+
+    branding = get_email_branding(tenant)
+
+    assert branding == {
+        "logo_url": "https://cdn.example.com/logo.png",
+        "primary_color": "#ff0066",
+        "business_name": tenant.business_name
+    }
+
+
+def test_unset_branding_falls_back_to_defaults(db, factory):
+    tenant = factory.tenant()
+
+    branding = get_email_branding(tenant)
+
+    assert branding["primary_color"] == DEFAULT_PRIMARY_COLOR
+    assert branding["logo_url"] is None
+
+
+def test_invalid_color_falls_back_to_default(db, factory):
+    tenant = factory.tenant(branding={"primary_color": "red; display: none"})
+
+    assert get_email_branding(tenant)["primary_color"] == DEFAULT_PRIMARY_COLOR
+
+
+def test_primary_color_and_logo_appear_in_html():
+    html = text_to_html("Hello", {
+        "logo_url": "https://cdn.example.com/logo.png", "primary_color": "#ff0066", "business_name": "Salon"
+    })
+
+    assert "background: #ff0066" in html
+    assert '<img src="https://cdn.example.com/logo.png" alt="Salon"' in html
+
+
+def test_business_name_is_escaped_without_logo():
+    html = text_to_html("Hello", {"logo_url": None, "primary_color": "#ff0066", "business_name": "Tom & <Jerry>"})
+
+    assert "Tom &amp; &lt;Jerry&gt;" in html
+    assert "<img" not in html
+
+
+def test_html_without_branding_has_no_header():
+    assert "background:" not in text_to_html("Hello")
@@ -0,0 +1,59 @@
+from email import message_from_bytes
+from email.policy import default
+
+from shared.email import EmailClient
+
+
+def build(**kwargs):
+    client = EmailClient(sender="noreply@example.com")
+    message = client.build_message("client@example.com", "Booking confirmed", "Hello,\n\nSee you soon", **kwargs)
+    # Parse the serialized message, as the SMTP server would see it
+    return message_from_bytes(message.as_bytes(), policy=default)
+
+
+def test_message_has_text_and_html_alternatives():
+    message = build()
+
+    assert message.get_content_type() == "multipart/alternative"
+    assert [part.get_content_type() for part in message.iter_parts()] == ["text/plain", "text/html"]
+    assert message.get_body(("plain",)).get_content().strip() == "Hello,\n\nSee you soon"
+    assert "<p>See you soon</p>" in message.get_body(("html",)).get_content()
+
+
+def test_given_html_body_is_used():
+    message = build(html_body="<h1>Custom</h1>")
+
+    assert message.get_body(("html",)).get_content().strip() == "<h1>Custom</h1>"
+
+
+def test_attachments_make_mixed_message():
+    message = build(attachments=[("booking.ics", "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", "text/calendar")])
+
+    assert message.get_content_type() == "multipart/mixed"
+    alternative, attachment = message.iter_parts()
+    assert alternative.get_content_type() == "multipart/alternative"
+    assert attachment.get_content_type() == "text/calendar"
+    assert attachment.get_filename() == "booking.ics"
+    assert attachment.get_payload(decode=True) == b"BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"
+
+
+def test_non_ascii_subject_is_encoded():
+    client = EmailClient(sender="noreply@example.com")
+    subject = "Запись подтверждена — Сұлулық"
+
+    raw = client.build_message("client@example.com", subject, "Текст").as_bytes()
+
+    header = next(line for line in raw.split(b"\n") if line.startswith(b"Subject:"))
+    assert header.decode("ascii").startswith("Subject: =?utf-8?")
+    parsed = message_from_bytes(raw, policy=default)
+    assert parsed["Subject"] == subject
+    assert parsed.get_body(("plain",)).get_content().strip() == "Текст"
+
+
+def test_message_has_id_and_date():
+    message = build()
+
+    assert message["Message-ID"].startswith("<")
+    assert message["Date"]
+    assert message["From"] == "noreply@example.com"
+    assert message["To"] == "client@example.com"
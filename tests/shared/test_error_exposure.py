@@ -0,0 +1,55 @@
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+import pytest
+
+from shared.api import REQUEST_ID_HEADER, register_exception_handlers, request_id_middleware, request_id_headers
+from shared.config import settings
+
+
+app = FastAPI()
+app.middleware("http")(request_id_middleware)
+register_exception_handlers(app)
+
+
+@app.get("/crash")
+async def crash():
+    raise RuntimeError("password authentication failed for user booking_user")
+
+
+@app.get("/outgoing")
+async def outgoing():
+    return request_id_headers()
+
+
+@pytest.fixture
+def client():
+    return TestClient(app, raise_server_exceptions=False)
+
+
+def test_production_hides_exception_message(client, monkeypatch):
+    monkeypatch.setattr(settings, "ENVIRONMENT", "production")
+
+    response = client.get("/crash")
+
+    assert response.status_code == 500
+    assert response.json()["error"] == "INTERNAL_ERROR"
+    assert "details" not in response.json()
+    assert "booking_user" not in response.text
+
+
+def test_development_shows_exception_message(client, monkeypatch):
+    monkeypatch.setattr(settings, "ENVIRONMENT", "development")
+
+    response = client.get("/crash")
+
+    assert response.json()["details"]["debug"].startswith("RuntimeError: password authentication failed")
+
+
+def test_request_id_propagated_to_outgoing_calls(client):
+    response = client.get("/outgoing", headers={REQUEST_ID_HEADER: "req-7"})
+
+    assert response.json() == {REQUEST_ID_HEADER: "req-7"}
+
+
+def test_no_request_id_outside_requests():
+    assert request_id_headers() == {}
@@ -0,0 +1,101 @@
+from fastapi import FastAPI, status
+from fastapi.testclient import TestClient
+from pydantic import BaseModel
+import pytest
+
+from shared.api import APIError, ErrorCode, REQUEST_ID_HEADER, register_exception_handlers, request_id_middleware
+from shared.config import settings
+
+
+class Item(BaseModel):
+    name: str
+
+
+app = FastAPI()
+app.middleware("http")(request_id_middleware)
+register_exception_handlers(app)
+
+
+@app.get("/items/{item_id}")
+async def get_item(item_id: int):
+    if item_id != 1:
+        raise APIError(status_code=status.HTTP_404_NOT_FOUND, code=ErrorCode.NOT_FOUND, details={"id": item_id})
+    return {"id": 1, "name": "Haircut"}
+
+
+@app.post("/items")
+async def create_item(item: Item):
+    return item
+
+
+@app.get("/crash")
+async def crash():
+    raise RuntimeError("boom")
+
+
+@pytest.fixture
+def client():
+    return TestClient(app, raise_server_exceptions=False)
+
+
+def test_success_keeps_body_and_echoes_request_id(client):
+    response = client.get("/items/1", headers={REQUEST_ID_HEADER: "req-1"})
+
+    assert response.status_code == 200
+    assert response.json() == {"id": 1, "name": "Haircut"}
+    assert response.headers[REQUEST_ID_HEADER] == "req-1"
+
+
+def test_api_error_envelope(client):
+    response = client.get("/items/2", headers={REQUEST_ID_HEADER: "req-2", "Accept-Language": "en"})
+
+    assert response.status_code == 404
+    assert response.json() == {
+        "error": "NOT_FOUND",
+        "message": "Resource not found",
+        "request_id": "req-2",
+        "details": {"id": 2}
+    }
+    assert response.headers[REQUEST_ID_HEADER] == "req-2"
+
+
+def test_generated_request_id_in_envelope(client):
+    response = client.get("/items/2")
+
+    assert response.json()["request_id"] == response.headers[REQUEST_ID_HEADER]
+
+
+def test_plain_http_error_mapped_to_code(client):
+    response = client.get("/unknown", params={"lang": "en"})
+
+    assert response.status_code == 404
+    assert response.json()["error"] == "NOT_FOUND"
+    assert "details" not in response.json()
+
+
+def test_validation_error_lists_fields(client):
+    response = client.post("/items", json={}, headers={"X-Language": "en"})
+
+    body = response.json()
+    assert response.status_code == 422
+    assert body["error"] == "VALIDATION_ERROR"
+    assert body["message"] == "Validation error"
+    assert body["details"][0]["field"] == "body.name"
+
+
+def test_unhandled_error_is_internal_error(client, caplog):
+    response = client.get("/crash")
+
+    assert response.status_code == 500
+    assert response.json()["error"] == "INTERNAL_ERROR"
+    assert "details" not in response.json()
+    assert f"request_id={response.json()['request_id']}" in caplog.text
+    assert "boom" in caplog.text
+
+
+def test_error_details_exposed_when_enabled(client, monkeypatch):
+    monkeypatch.setattr(settings, "EXPOSE_ERROR_DETAILS", True)
+
+    response = client.get("/crash")
+
+    assert response.json()["details"] == {"debug": "RuntimeError: boom"}
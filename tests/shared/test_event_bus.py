@@ -0,0 +1,66 @@
+import json
+import threading
+import time
+
+from shared.events import DOMAIN_EVENTS_CHANNEL, EventBus, EventType, publish_event
+
+
+def test_dispatch_calls_handlers_of_the_type():
+    bus = EventBus()
+    received = []
+    bus.subscribe(EventType.TENANT_APPROVED)(received.append)
+
+    handled = bus.dispatch({"type": "tenant.approved", "data": {"tenant_id": 1}})
+    bus.dispatch({"type": "tenant.rejected", "data": {"tenant_id": 2}})
+
+    assert handled == 1
+    assert received == [{"type": "tenant.approved", "data": {"tenant_id": 1}}]
+
+
+def test_failing_handler_does_not_stop_others():
+    bus = EventBus()
+    received = []
+
+    @bus.subscribe(EventType.BOOKING_CREATED)
+    def broken(event):
+        raise RuntimeError("SMTP down")
+
+    bus.subscribe(EventType.BOOKING_CREATED)(received.append)
+
+    assert bus.dispatch({"type": "booking.created", "data": {}}) == 1
+    assert len(received) == 1
+
+
+def test_published_event_is_envelope(cache):
+    pubsub = cache.client.pubsub()
+    pubsub.subscribe(DOMAIN_EVENTS_CHANNEL)
+    assert pubsub.get_message(timeout=5)["type"] == "subscribe"
+
+    assert publish_event(EventType.TENANT_APPROVED, {"tenant_id": 1}) is True
+
+    event = json.loads(pubsub.get_message(timeout=5)["data"])
+    pubsub.close()
+    assert event["type"] == "tenant.approved"
+    assert event["data"] == {"tenant_id": 1}
+    assert event["id"] and event["occurred_at"]
+
+
+def test_published_event_triggers_subscribed_handler(cache):
+    bus = EventBus()
+    handled = threading.Event()
+    received = []
+
+    @bus.subscribe(EventType.TENANT_APPROVED)
+    def on_approved(event):
+        received.append(event["data"])
+        handled.set()
+
+    bus.start()
+    deadline = time.monotonic() + 5
+    while not cache.client.pubsub_numsub(DOMAIN_EVENTS_CHANNEL)[0][1] and time.monotonic() < deadline:
+        time.sleep(0.05)
+
+    publish_event(EventType.TENANT_APPROVED, {"tenant_id": 42})
+
+    assert handled.wait(timeout=5)
+    assert received == [{"tenant_id": 42}]
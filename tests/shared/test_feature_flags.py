@@ -0,0 +1,45 @@
+from types import SimpleNamespace
+
+import pytest
+
+from shared.api import APIError, ErrorCode
+from shared.billing import Feature, enabled_features, get_tenant_features, has_feature, require_feature
+from shared.config import settings
+
+
+@pytest.fixture(autouse=True)
+def defaults(monkeypatch):
+    monkeypatch.setattr(settings, "DEFAULT_TENANT_FEATURES", "reviews, deposits")
+
+
+def tenant(features=None):
+    return SimpleNamespace(features=features)
+
+
+def test_defaults_apply_without_overrides():
+    assert get_tenant_features(tenant()) == {"reviews": True, "deposits": True, "multi_location": False}
+
+
+def test_overrides_take_precedence_over_defaults():
+    features = {"deposits": False, "multi_location": True, "gift_cards": True}
+
+    assert get_tenant_features(tenant(features)) == {"reviews": True, "deposits": False, "multi_location": True}
+    assert enabled_features(tenant(features)) == ["reviews", "multi_location"]
+
+
+def test_has_feature():
+    assert has_feature(tenant(), Feature.REVIEWS)
+    assert not has_feature(tenant({"reviews": False}), Feature.REVIEWS)
+
+
+def test_disabled_feature_is_not_available():
+    with pytest.raises(APIError) as error:
+        require_feature(tenant({"deposits": False}), Feature.DEPOSITS)
+
+    assert error.value.status_code == 403
+    assert error.value.code == ErrorCode.FEATURE_NOT_AVAILABLE
+    assert error.value.details == {"feature": "deposits"}
+
+
+def test_enabled_feature_is_allowed():
+    assert require_feature(tenant(), Feature.REVIEWS) is None
@@ -0,0 +1,41 @@
+from celery import Celery
+
+from shared.config import settings
+from shared.jobs import configure_job_time_limits, configure_reliable_delivery
+
+
+def test_jobs_acknowledged_after_processing():
+    celery_app = Celery("test")
+
+    configure_reliable_delivery(celery_app)
+
+    assert celery_app.conf.task_acks_late is True
+    assert celery_app.conf.task_reject_on_worker_lost is True
+    assert celery_app.conf.worker_prefetch_multiplier == 1
+
+
+def test_visibility_timeout_keeps_other_transport_options():
+    celery_app = Celery("test")
+    celery_app.conf.broker_transport_options = {"max_connections": 10}
+
+    configure_reliable_delivery(celery_app)
+
+    assert celery_app.conf.broker_transport_options == {
+        "max_connections": 10,
+        "visibility_timeout": settings.JOB_VISIBILITY_TIMEOUT_SECONDS
+    }
+
+
+def test_visibility_timeout_outlasts_retry_delays():
+    assert settings.JOB_VISIBILITY_TIMEOUT_SECONDS > settings.JOB_RETRY_MAX_DELAY_SECONDS
+
+
+def test_jobs_have_time_limits():
+    celery_app = Celery("test")
+
+    configure_job_time_limits(celery_app)
+
+    assert celery_app.conf.task_soft_time_limit == settings.JOB_SOFT_TIME_LIMIT_SECONDS
+    assert celery_app.conf.task_time_limit == settings.JOB_TIME_LIMIT_SECONDS
+    # The soft limit leaves the task time to give up before it is killed
+    assert settings.JOB_SOFT_TIME_LIMIT_SECONDS < settings.JOB_TIME_LIMIT_SECONDS
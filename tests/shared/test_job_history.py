@@ -0,0 +1,56 @@
+import pytest
+
+from shared.jobs.history import JobHistory
+
+
+@pytest.fixture
+def history(broker_url):
+    history = JobHistory(broker_url)
+    history.record_queued("job-1", "notifications.send_reminder", queued_at=1000)
+    history.record_queued("job-2", "notifications.send_reminder", queued_at=2000)
+    history.record_queued("job-3", "notifications.cleanup", queued_at=3000)
+    history.record_status("job-1", "succeeded")
+    history.record_status("job-2", "retrying", error="ConnectionError: timed out")
+    history.record_status("job-3", "failed", error="ValueError: bad payload")
+    return history
+
+
+def ids(jobs):
+    return [job["id"] for job in jobs]
+
+
+def test_jobs_newest_first(history):
+    assert ids(history.list_jobs()) == ["job-3", "job-2", "job-1"]
+
+
+@pytest.mark.parametrize("status, expected", [
+    ("succeeded", ["job-1"]),
+    ("retrying", ["job-2"]),
+    ("failed", ["job-3"]),
+    ("queued", []),
+])
+def test_filter_by_status(history, status, expected):
+    assert ids(history.list_jobs(status=status)) == expected
+
+
+def test_filter_by_type_and_time(history):
+    assert ids(history.list_jobs(task_name="notifications.send_reminder")) == ["job-2", "job-1"]
+    assert ids(history.list_jobs(since=1500, until=2500)) == ["job-2"]
+
+
+def test_retry_is_counted_with_error(history):
+    job, = history.list_jobs(status="retrying")
+
+    assert job["retries"] == 1
+    assert job["error"] == "ConnectionError: timed out"
+    assert job["queued_at"] == "1970-01-01T00:33:20"
+
+
+def test_limit(history):
+    assert ids(history.list_jobs(limit=2)) == ["job-3", "job-2"]
+
+
+def test_unknown_job_status_is_ignored(history):
+    history.record_status("job-unknown", "started")
+
+    assert "job-unknown" not in ids(history.list_jobs())
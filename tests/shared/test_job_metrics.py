@@ -0,0 +1,45 @@
+import pytest
+
+from shared.config import settings
+from shared.jobs import JobMetrics, render_prometheus_metrics
+from shared.jobs.scheduler import SCHEDULED_JOBS_KEY
+
+
+@pytest.fixture
+def metrics(broker_url):
+    return JobMetrics(broker_url)
+
+
+def test_counters_per_task_type(metrics):
+    metrics.increment("notifications.send_reminder", "queued")
+    metrics.increment("notifications.send_reminder", "queued")
+    metrics.increment("notifications.send_reminder", "processed")
+    metrics.increment("notifications.cleanup", "failed")
+
+    assert metrics.get_counters() == {
+        "notifications.send_reminder": {"queued": 2, "processed": 1, "failed": 0, "retried": 0},
+        "notifications.cleanup": {"queued": 0, "processed": 0, "failed": 1, "retried": 0}
+    }
+
+
+def test_queue_depth_includes_scheduled_jobs(metrics):
+    metrics.client.rpush(settings.JOB_HIGH_PRIORITY_QUEUE, "job-1", "job-2")
+    metrics.client.zadd(SCHEDULED_JOBS_KEY, {"job-3": 1})
+
+    depth = metrics.get_queue_depth()
+
+    assert depth[settings.JOB_HIGH_PRIORITY_QUEUE] == 2
+    assert depth[settings.JOB_LOW_PRIORITY_QUEUE] == 0
+    assert depth["scheduled"] == 1
+
+
+def test_prometheus_rendering():
+    text = render_prometheus_metrics({
+        "jobs": {"notifications.send_reminder": {"queued": 3, "processed": 2, "failed": 1, "retried": 0}},
+        "queue_depth": {"scheduled": 4}
+    })
+
+    assert 'job_events_total{type="notifications.send_reminder",event="queued"} 3' in text
+    assert 'job_events_total{type="notifications.send_reminder",event="failed"} 1' in text
+    assert 'job_queue_depth{queue="scheduled"} 4' in text
+    assert text.endswith("\n")
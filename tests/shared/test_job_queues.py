@@ -0,0 +1,29 @@
+from celery import Celery
+
+from shared.config import settings
+from shared.jobs import configure_job_queues, job_queue_names
+
+
+def test_queue_names_highest_priority_first():
+    assert job_queue_names() == [settings.JOB_HIGH_PRIORITY_QUEUE, settings.JOB_LOW_PRIORITY_QUEUE]
+
+
+def test_time_sensitive_tasks_routed_to_high_priority_queue(monkeypatch):
+    monkeypatch.setattr(settings, "JOB_HIGH_PRIORITY_TASKS", "notifications.send_reminder")
+    celery_app = Celery("test")
+
+    configure_job_queues(celery_app)
+
+    assert [queue.name for queue in celery_app.conf.task_queues] == job_queue_names()
+    assert celery_app.conf.task_routes == {
+        "notifications.send_reminder": {"queue": settings.JOB_HIGH_PRIORITY_QUEUE}
+    }
+
+
+def test_other_tasks_default_to_low_priority_queue():
+    celery_app = Celery("test")
+
+    configure_job_queues(celery_app)
+
+    assert celery_app.conf.task_default_queue == settings.JOB_LOW_PRIORITY_QUEUE
+    assert "notifications.cleanup_history" not in celery_app.conf.task_routes
@@ -0,0 +1,30 @@
+import random
+
+import pytest
+
+from shared.config import settings
+from shared.jobs import retry_delay
+
+
+@pytest.fixture
+def no_jitter(monkeypatch):
+    """Use the upper bound of the jittered delay."""
+    monkeypatch.setattr(random, "uniform", lambda low, high: high)
+    monkeypatch.setattr(settings, "JOB_RETRY_DELAY_SECONDS", 30)
+    monkeypatch.setattr(settings, "JOB_RETRY_MAX_DELAY_SECONDS", 600)
+
+
+@pytest.mark.parametrize("attempt, expected", [(1, 30), (2, 60), (3, 120), (5, 480), (6, 600), (20, 600)])
+def test_backoff_doubles_up_to_cap(no_jitter, attempt, expected):
+    assert retry_delay(attempt) == expected
+
+
+def test_attempt_below_one_uses_base_delay(no_jitter):
+    assert retry_delay(0) == 30
+
+
+def test_delay_is_jittered_within_bounds():
+    delays = {retry_delay(3) for _ in range(50)}
+
+    assert all(0 <= delay <= settings.JOB_RETRY_DELAY_SECONDS * 4 for delay in delays)
+    assert len(delays) > 1
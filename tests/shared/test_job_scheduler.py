@@ -0,0 +1,80 @@
+import time
+
+import pytest
+
+from shared.jobs.scheduler import PROMOTION_LOCK_KEY, JobScheduler
+
+
+class FakeCelery:
+    """Celery app recording sent tasks; sending task names in failing raises."""
+
+    def __init__(self):
+        self.sent = []
+        self.failing = set()
+
+    def send_task(self, name, args=None, kwargs=None):
+        if name in self.failing:
+            raise ConnectionError("broker down")
+        self.sent.append((name, args, kwargs))
+
+
+@pytest.fixture
+def scheduler(broker_url):
+    return JobScheduler(broker_url)
+
+
+@pytest.fixture
+def celery_app():
+    return FakeCelery()
+
+
+def test_due_job_is_promoted_once(scheduler, celery_app, broker_url):
+    scheduler.schedule("notifications.send_reminder", args=[1], kwargs={"hours_before": 24})
+    replica = JobScheduler(broker_url)
+
+    assert scheduler.promote_due(celery_app) == 1
+    assert replica.promote_due(celery_app) == 0
+    assert scheduler.promote_due(celery_app) == 0
+
+    assert celery_app.sent == [("notifications.send_reminder", [1], {"hours_before": 24})]
+    assert scheduler.count() == 0
+
+
+def test_job_waits_for_due_time(scheduler, celery_app):
+    scheduler.schedule("notifications.send_reminder", countdown=3600)
+
+    assert scheduler.promote_due(celery_app) == 0
+    assert scheduler.count() == 1
+
+    assert scheduler.promote_due(celery_app, now=time.time() + 3601) == 1
+    assert scheduler.count() == 0
+
+
+def test_promotion_skipped_while_another_replica_holds_lock(scheduler, celery_app):
+    scheduler.schedule("notifications.send_reminder")
+    scheduler.client.set(PROMOTION_LOCK_KEY, "1")
+
+    assert scheduler.promote_due(celery_app) == 0
+    assert celery_app.sent == []
+    assert scheduler.count() == 1
+
+
+def test_lock_is_released_after_promotion(scheduler, celery_app):
+    scheduler.promote_due(celery_app)
+
+    assert scheduler.client.exists(PROMOTION_LOCK_KEY) == 0
+
+
+def test_failed_send_is_put_back(scheduler, celery_app):
+    scheduler.schedule("notifications.send_reminder")
+    scheduler.schedule("notifications.cleanup")
+    celery_app.failing.add("notifications.send_reminder")
+
+    assert scheduler.promote_due(celery_app) == 1
+
+    assert [name for name, _, _ in celery_app.sent] == ["notifications.cleanup"]
+    assert scheduler.count() == 1
+
+    celery_app.failing.clear()
+    assert scheduler.promote_due(celery_app) == 1
+    assert scheduler.count() == 0
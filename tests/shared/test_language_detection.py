@@ -0,0 +1,42 @@
+from urllib.parse import urlencode
+
+from starlette.requests import Request
+import pytest
+
+from shared.config import settings
+from shared.i18n import get_request_language, get_supported_languages
+
+
+def request(query=None, **headers) -> Request:
+    return Request({
+        "type": "http",
+        "method": "GET",
+        "path": "/",
+        "query_string": urlencode(query or {}).encode(),
+        "headers": [(name.replace("_", "-").lower().encode(), value.encode()) for name, value in headers.items()]
+    })
+
+
+@pytest.mark.parametrize("query, headers, expected", [
+    ({"lang": "en"}, {"X-Language": "kk", "Accept-Language": "ru"}, "en"),
+    ({}, {"X-Language": "kk", "Accept-Language": "en"}, "kk"),
+    ({}, {"Accept-Language": "en-US,en;q=0.9,ru;q=0.8"}, "en"),
+    ({}, {}, settings.DEFAULT_LANGUAGE),
+])
+def test_override_precedence(query, headers, expected):
+    assert get_request_language(request(query, **headers)) == expected
+
+
+def test_unsupported_override_is_skipped():
+    assert get_request_language(request({"lang": "de"}, X_Language="fr", Accept_Language="kk")) == "kk"
+
+
+def test_override_is_case_insensitive():
+    assert get_request_language(request({"lang": " EN "})) == "en"
+
+
+def test_supported_languages_mark_default():
+    languages = get_supported_languages()
+
+    assert [language["code"] for language in languages] == settings.supported_languages_list
+    assert [language["code"] for language in languages if language["is_default"]] == [settings.DEFAULT_LANGUAGE]
@@ -0,0 +1,50 @@
+from shared.analytics import recompute_master_ratings
+from shared.models import BookingStatus, Review, ReviewStatus
+
+
+def review(factory, master, rating, review_status=ReviewStatus.APPROVED):
+    tenant = master.tenant
+    service = factory.service(tenant)
+    booking = factory.booking(tenant, master, service, factory.client(), status=BookingStatus.COMPLETED)
+    return factory.add(Review(
+        tenant_id=tenant.id, booking_id=booking.id, client_id=booking.client_id, master_id=master.id,
+        rating=rating, status=review_status
+    ))
+
+
+def test_rating_from_approved_reviews(db, factory):
+    master = factory.master(factory.tenant())
+    for rating in (5, 4, 4):
+        review(factory, master, rating)
+    review(factory, master, 1, ReviewStatus.PENDING)
+    review(factory, master, 1, ReviewStatus.REJECTED)
+
+    recompute_master_ratings(db, tenant_ids=[master.tenant_id])
+    recompute_master_ratings(db, tenant_ids=[master.tenant_id])
+
+    db.expire_all()
+    assert master.rating == 4.33
+    assert master.reviews_count == 3
+
+
+def test_master_without_reviews_is_reset(db, factory):
+    master = factory.master(factory.tenant(), rating=4.5, reviews_count=2)
+
+    recompute_master_ratings(db, master_ids=[master.id])
+
+    db.expire_all()
+    assert master.rating is None
+    assert master.reviews_count == 0
+
+
+def test_only_given_masters_are_updated(db, factory):
+    tenant = factory.tenant()
+    master, other = factory.master(tenant), factory.master(tenant, reviews_count=7)
+    review(factory, master, 5)
+
+    updated = recompute_master_ratings(db, master_ids=[master.id])
+
+    db.expire_all()
+    assert updated == 1
+    assert master.reviews_count == 1
+    assert other.reviews_count == 7
@@ -0,0 +1,31 @@
+import pytest
+
+from shared.billing.money import tenant_currency, to_major, to_minor
+from shared.config import settings
+from shared.models import Tenant
+
+
+@pytest.mark.parametrize("amount, expected", [
+    (0.1, 10),
+    (19.99, 1999),
+    ("19.995", 2000),
+    ("0.005", 1),
+    (5000, 500000),
+])
+def test_to_minor_rounds_half_up(amount, expected):
+    assert to_minor(amount) == expected
+
+
+def test_to_major():
+    assert to_major(1999) == 19.99
+    assert to_major(None) == 0.0
+
+
+def test_minor_units_sum_exactly():
+    assert sum(to_minor(0.1) for _ in range(3)) == to_minor(0.3)
+
+
+def test_tenant_currency_defaults():
+    assert tenant_currency(Tenant(currency="USD")) == "USD"
+    assert tenant_currency(Tenant()) == settings.DEFAULT_CURRENCY
+    assert tenant_currency(None) == settings.DEFAULT_CURRENCY
@@ -0,0 +1,47 @@
+import pytest
+
+from shared.config import settings
+from shared.i18n import format_money
+
+
+@pytest.mark.parametrize("currency, language, expected", [
+    ("KZT", "ru", "150\u00a0₸"),
+    ("KZT", "kk", "150\u00a0₸"),
+    ("KZT", "en", "₸150"),
+    ("RUB", "ru", "150\u00a0₽"),
+    ("RUB", "kk", "150\u00a0₽"),
+    ("RUB", "en", "₽150"),
+    ("USD", "ru", "150\u00a0$"),
+    ("USD", "kk", "150\u00a0$"),
+    ("USD", "en", "$150"),
+])
+def test_currency_per_locale(currency, language, expected):
+    assert format_money(15000, currency, language) == expected
+
+
+@pytest.mark.parametrize("language, expected", [
+    ("ru", "1\u00a0500,50\u00a0₸"),
+    ("kk", "1\u00a0500,50\u00a0₸"),
+    ("en", "₸1,500.50")
+])
+def test_thousands_and_fraction(language, expected):
+    assert format_money(150050, "KZT", language) == expected
+
+
+def test_fraction_only_when_present():
+    assert format_money(1234567800, "USD", "en") == "$12,345,678"
+    assert format_money(1505, "USD", "en") == "$15.05"
+
+
+def test_unknown_currency_by_code():
+    assert format_money(15000, "GBP", "en") == "150 GBP"
+
+
+def test_negative_amount():
+    assert format_money(-15000, "USD", "en") == "-$150"
+
+
+def test_unknown_language_uses_default(monkeypatch):
+    monkeypatch.setattr(settings, "DEFAULT_LANGUAGE", "en")
+
+    assert format_money(15000, "KZT", "de") == "₸150"
@@ -0,0 +1,53 @@
+from sqlalchemy import create_engine
+from sqlalchemy.exc import TimeoutError as PoolTimeoutError
+import pytest
+
+from shared.config import settings
+from shared.database import get_pool_stats, render_prometheus_pool_metrics
+from shared.database.pool import InstrumentedQueuePool
+
+
+@pytest.fixture
+def small_engine(schema):
+    """Engine with a single connection and no overflow."""
+    small = create_engine(
+        settings.DATABASE_URL, poolclass=InstrumentedQueuePool, pool_size=1, max_overflow=0, pool_timeout=0.2
+    )
+    yield small
+    small.dispose()
+
+
+def test_stats_of_checked_out_connection(small_engine):
+    with small_engine.connect():
+        stats = get_pool_stats(small_engine)
+
+    assert stats["size"] == 1
+    assert stats["max_connections"] == 1
+    assert stats["in_use"] == 1
+    assert stats["wait_count"] == 0
+
+
+def test_waiting_checkout_is_counted(small_engine):
+    with small_engine.connect():
+        assert small_engine.pool.is_saturated()
+        with pytest.raises(PoolTimeoutError):
+            small_engine.connect()
+
+    stats = get_pool_stats(small_engine)
+    assert stats["wait_count"] == 1
+    assert stats["wait_duration_seconds"] >= 0.2
+    assert stats["in_use"] == 0
+    assert stats["idle"] == 1
+
+
+def test_prometheus_rendering():
+    stats = {
+        "size": 5, "max_connections": 15, "in_use": 3, "idle": 2, "overflow": 0,
+        "wait_count": 4, "wait_duration_seconds": 1.5
+    }
+
+    text = render_prometheus_pool_metrics(stats, "booking-service")
+
+    assert 'db_pool_in_use{service="booking-service"} 3' in text
+    assert 'db_pool_wait_count{service="booking-service"} 4' in text
+    assert "# TYPE db_pool_wait_duration_seconds counter" in text
@@ -0,0 +1,58 @@
+from types import SimpleNamespace
+
+import pytest
+
+from shared.config import settings
+from shared.models import NotificationChannel
+from shared.notifications import reminder_channels
+
+EMAIL, WHATSAPP = NotificationChannel.EMAIL, NotificationChannel.WHATSAPP
+
+
+@pytest.fixture(autouse=True)
+def defaults(monkeypatch):
+    monkeypatch.setattr(settings, "REMINDER_SAME_DAY_HOURS", 12)
+    monkeypatch.setattr(settings, "REMINDER_SAME_DAY_CHANNELS", "WHATSAPP")
+    monkeypatch.setattr(settings, "REMINDER_ADVANCE_CHANNELS", "EMAIL")
+
+
+def booking(tenant_channels=None, chosen=None, email="aida@example.com"):
+    return SimpleNamespace(
+        tenant=SimpleNamespace(id=1, reminder_channels=tenant_channels),
+        client=SimpleNamespace(email=email),
+        reminder_channels=chosen
+    )
+
+
+@pytest.mark.parametrize("hours_before, expected", [(24, [EMAIL]), (13, [EMAIL]), (12, [WHATSAPP]), (2, [WHATSAPP])])
+def test_default_channels_by_window(hours_before, expected):
+    assert reminder_channels(booking(), hours_before) == expected
+
+
+def test_tenant_channels():
+    tenant_channels = {"same_day": ["WHATSAPP", "EMAIL"], "advance": ["WHATSAPP"]}
+
+    assert reminder_channels(booking(tenant_channels), 2) == [WHATSAPP, EMAIL]
+    assert reminder_channels(booking(tenant_channels), 24) == [WHATSAPP]
+
+
+def test_client_choice_narrows_tenant_channels():
+    tenant_channels = {"same_day": ["WHATSAPP", "EMAIL"], "advance": ["EMAIL"]}
+
+    assert reminder_channels(booking(tenant_channels, ["EMAIL"]), 2) == [EMAIL]
+
+
+def test_client_choice_replaces_unmatched_channels():
+    assert reminder_channels(booking(chosen=["WHATSAPP"]), 24) == [WHATSAPP]
+
+
+def test_empty_client_choice_means_no_reminders():
+    assert reminder_channels(booking(chosen=[]), 24) == []
+
+
+def test_email_falls_back_to_whatsapp_without_address():
+    assert reminder_channels(booking(email=None), 24) == [WHATSAPP]
+
+
+def test_unknown_hours_use_advance_channels():
+    assert reminder_channels(booking(), None) == [EMAIL]
@@ -0,0 +1,35 @@
+from datetime import time
+
+import pytest
+from sqlalchemy.exc import IntegrityError
+
+from shared.models import MasterSchedule
+
+
+def add_hours(db, master, day_of_week, start_time, end_time):
+    with db.begin_nested():
+        db.add(MasterSchedule(master_id=master.id, day_of_week=day_of_week, start_time=start_time, end_time=end_time))
+        db.flush()
+
+
+@pytest.fixture
+def master(factory):
+    return factory.master(factory.tenant(), hours=None)
+
+
+def test_valid_hours_are_stored(db, master):
+    add_hours(db, master, 6, time(9), time(18))
+
+    assert db.query(MasterSchedule).filter(MasterSchedule.master_id == master.id).count() == 1
+
+
+@pytest.mark.parametrize("start_time, end_time", [(time(18), time(9)), (time(9), time(9))])
+def test_end_before_start_is_rejected(db, master, start_time, end_time):
+    with pytest.raises(IntegrityError, match="ck_master_schedules_time_range"):
+        add_hours(db, master, 0, start_time, end_time)
+
+
+@pytest.mark.parametrize("day_of_week", [-1, 7])
+def test_invalid_day_is_rejected(db, master, day_of_week):
+    with pytest.raises(IntegrityError, match="ck_master_schedules_day_of_week"):
+        add_hours(db, master, day_of_week, time(9), time(18))
@@ -0,0 +1,51 @@
+from datetime import datetime, timedelta
+
+from shared.analytics import recompute_service_popularity
+from shared.config import settings
+from shared.models import BookingStatus
+
+
+def test_popularity_counts_recent_completed_bookings(db, factory):
+    tenant, service, master = factory.salon()
+    recent = datetime.utcnow() - timedelta(days=1)
+    for booking_status in [
+        BookingStatus.COMPLETED, BookingStatus.COMPLETED, BookingStatus.CANCELLED,
+        BookingStatus.NO_SHOW, BookingStatus.CONFIRMED
+    ]:
+        factory.booking(tenant, master, service, factory.client(), booking_date=recent, status=booking_status)
+    factory.booking(
+        tenant, master, service, factory.client(), status=BookingStatus.COMPLETED,
+        booking_date=datetime.utcnow() - timedelta(days=settings.POPULARITY_WINDOW_DAYS + 1)
+    )
+
+    recompute_service_popularity(db, [tenant.id])
+    recompute_service_popularity(db, [tenant.id])
+
+    db.expire_all()
+    assert service.popularity_score == 2
+
+
+def test_cancelled_booking_stops_counting(db, factory):
+    tenant, service, master = factory.salon()
+    booking = factory.booking(
+        tenant, master, service, factory.client(),
+        booking_date=datetime.utcnow() - timedelta(days=1), status=BookingStatus.COMPLETED
+    )
+    recompute_service_popularity(db, [tenant.id])
+
+    booking.status = BookingStatus.CANCELLED
+    db.flush()
+    recompute_service_popularity(db, [tenant.id])
+
+    db.expire_all()
+    assert service.popularity_score == 0
+
+
+def test_other_tenants_are_untouched(db, factory):
+    tenant = factory.tenant()
+    other_service = factory.service(factory.tenant(), popularity_score=7)
+
+    recompute_service_popularity(db, [tenant.id])
+
+    db.expire_all()
+    assert other_service.popularity_score == 7
@@ -0,0 +1,55 @@
+import pytest
+
+from shared.sms import describe_sms, sms_encoding, sms_length, sms_segments, truncate_sms
+
+
+@pytest.mark.parametrize("length, segments", [(1, 1), (160, 1), (161, 2), (306, 2), (307, 3)])
+def test_ascii_segments(length, segments):
+    text = "a" * length
+
+    assert sms_encoding(text) == "GSM-7"
+    assert sms_segments(text) == segments
+
+
+@pytest.mark.parametrize("length, segments", [(1, 1), (70, 1), (71, 2), (134, 2), (135, 3)])
+def test_cyrillic_segments(length, segments):
+    text = "я" * length
+
+    assert sms_encoding(text) == "UCS-2"
+    assert sms_segments(text) == segments
+
+
+def test_single_cyrillic_letter_switches_to_ucs2():
+    text = "Reminder: " + "a" * 70 + " ә"
+
+    assert sms_encoding(text) == "UCS-2"
+    assert sms_segments(text) == 2
+
+
+def test_extension_characters_count_twice():
+    assert sms_length("[1]€") == 7
+    assert sms_segments("€" * 80) == 1
+    assert sms_segments("€" * 81) == 2
+
+
+def test_emoji_takes_two_units():
+    assert sms_encoding("Hi 😀") == "UCS-2"
+    assert sms_length("Hi 😀") == 5
+
+
+@pytest.mark.parametrize("text, max_segments", [("a" * 400, 1), ("a" * 400, 2), ("Запись " * 40, 1), ("ж" * 300, 2)])
+def test_truncated_text_fits_segments(text, max_segments):
+    result = truncate_sms(text, max_segments)
+
+    assert result.endswith("...")
+    assert sms_segments(result) <= max_segments
+    assert text.startswith(result[:-3])
+
+
+def test_short_text_is_not_truncated():
+    assert truncate_sms("Запись завтра в 10:00") == "Запись завтра в 10:00"
+
+
+def test_describe_for_cost_tracking():
+    assert describe_sms("Запись на 10:00") == {"encoding": "UCS-2", "length": 15, "segments": 1}
+    assert describe_sms("b" * 200) == {"encoding": "GSM-7", "length": 200, "segments": 2}
@@ -0,0 +1,67 @@
+from string import Formatter
+
+import pytest
+
+from shared.config import settings
+from shared.sms import SMS_TEMPLATES, SmsTemplate, render_sms
+
+VALUES = {
+    "business_name": "Beauty Bar",
+    "service_name": "Haircut",
+    "booking_date": "01.06.2026 10:00",
+    "price": "5 000 ₸",
+    "deposit": "1 000 ₸",
+    "minutes": 15,
+    "checkout_url": "https://pay.example.com/abc",
+    "code": "123456"
+}
+
+EXPECTED = {
+    ("en", SmsTemplate.BOOKING_CONFIRMED): "Booking confirmed!",
+    ("ru", SmsTemplate.BOOKING_CONFIRMED): "Бронирование подтверждено!",
+    ("kk", SmsTemplate.BOOKING_CONFIRMED): "Жазылу расталды!",
+    ("en", SmsTemplate.BOOKING_DEPOSIT_PENDING): "Please pay within 15 minutes",
+    ("ru", SmsTemplate.BOOKING_DEPOSIT_PENDING): "Оплатите в течение 15 минут",
+    ("kk", SmsTemplate.BOOKING_DEPOSIT_PENDING): "15 минут ішінде төлеңіз",
+    ("en", SmsTemplate.BOOKING_CANCELLED): "Your booking has been cancelled",
+    ("ru", SmsTemplate.BOOKING_CANCELLED): "Ваше бронирование отменено",
+    ("kk", SmsTemplate.BOOKING_CANCELLED): "Жазылуыңыз бас тартылды",
+    ("en", SmsTemplate.CLIENT_VERIFICATION_CODE): "Your verification code: 123456",
+    ("ru", SmsTemplate.CLIENT_VERIFICATION_CODE): "Ваш код подтверждения: 123456",
+    ("kk", SmsTemplate.CLIENT_VERIFICATION_CODE): "Растау кодыңыз: 123456",
+}
+
+
+def placeholders(text):
+    return {name for _, name, _, _ in Formatter().parse(text) if name}
+
+
+@pytest.mark.parametrize("language, template", list(EXPECTED))
+def test_rendered_in_language(language, template):
+    text = render_sms(template, language, **VALUES)
+
+    assert EXPECTED[language, template] in text
+    assert "{" not in text
+
+
+@pytest.mark.parametrize("template", list(SmsTemplate))
+def test_languages_share_placeholders(template):
+    default = placeholders(SMS_TEMPLATES[settings.DEFAULT_LANGUAGE][template.value])
+
+    for language, templates in SMS_TEMPLATES.items():
+        assert placeholders(templates[template.value]) == default, language
+
+
+def test_unsupported_language_falls_back_to_default():
+    text = render_sms(SmsTemplate.CLIENT_VERIFICATION_CODE, "de", code="123456", minutes=5)
+
+    assert text == render_sms(SmsTemplate.CLIENT_VERIFICATION_CODE, settings.DEFAULT_LANGUAGE, code="123456", minutes=5)
+    assert text == render_sms(SmsTemplate.CLIENT_VERIFICATION_CODE, code="123456", minutes=5)
+
+
+def test_missing_translation_falls_back_to_default(monkeypatch):
+    monkeypatch.setitem(SMS_TEMPLATES, "kk", {})
+
+    text = render_sms(SmsTemplate.BOOKING_CANCELLED, "kk", booking_date="01.06.2026 10:00")
+
+    assert text == render_sms(SmsTemplate.BOOKING_CANCELLED, settings.DEFAULT_LANGUAGE, booking_date="01.06.2026 10:00")
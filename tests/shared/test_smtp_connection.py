@@ -0,0 +1,126 @@
+import smtplib
+
+import pytest
+
+from shared.config import settings
+from shared.email import EmailClient, EmailPermanentError, EmailTransientError
+
+
+class FakeSMTP:
+    """SMTP connection recording the commands sent."""
+
+    instances = []
+    implicit_tls = False
+    reject = None
+
+    def __init__(self, host, port, timeout=None, context=None):
+        self.host, self.port = host, port
+        self.commands = []
+        self.sent = []
+        self.closed = False
+        FakeSMTP.instances.append(self)
+
+    def ehlo(self):
+        self.commands.append("ehlo")
+
+    def starttls(self, context=None):
+        self.commands.append("starttls")
+
+    def login(self, user, password):
+        self.commands.append(f"login {user}")
+
+    def send_message(self, message):
+        if FakeSMTP.reject:
+            raise FakeSMTP.reject
+        self.sent.append(message)
+
+    def close(self):
+        self.closed = True
+
+    def __enter__(self):
+        return self
+
+    def __exit__(self, *exc):
+        self.close()
+
+
+class FakeSMTPSSL(FakeSMTP):
+    implicit_tls = True
+
+
+@pytest.fixture(autouse=True)
+def smtp(monkeypatch):
+    FakeSMTP.instances = []
+    FakeSMTP.reject = None
+    monkeypatch.setattr(smtplib, "SMTP", FakeSMTP)
+    monkeypatch.setattr(smtplib, "SMTP_SSL", FakeSMTPSSL)
+    monkeypatch.setattr(settings, "EMAIL_ENABLED", True)
+    return FakeSMTP.instances
+
+
+@pytest.mark.parametrize("port, security, mode", [
+    (587, "auto", "starttls"),
+    (465, "auto", "ssl"),
+    (2525, "auto", "starttls"),
+    (465, "starttls", "starttls"),
+    (587, "ssl", "ssl"),
+    (25, "none", "none"),
+    (587, "STARTTLS", "starttls")
+])
+def test_connection_mode(port, security, mode):
+    assert EmailClient(host="smtp.test", port=port, security=security).connection_mode() == mode
+
+
+def test_default_config_upgrades_with_starttls(smtp):
+    client = EmailClient(host="smtp.test", port=587, user="mailer", password="secret", security="auto")
+
+    assert client.deliver("client@example.com", "Hello", "Body")
+
+    connection, = smtp
+    assert not connection.implicit_tls
+    assert connection.commands == ["ehlo", "starttls", "ehlo", "login mailer"]
+    assert len(connection.sent) == 1
+    assert connection.closed
+
+
+def test_port_465_uses_implicit_tls(smtp):
+    client = EmailClient(host="smtp.test", port=465, user="mailer", password="secret", security="auto")
+
+    client.deliver("client@example.com", "Hello", "Body")
+
+    connection, = smtp
+    assert connection.implicit_tls
+    assert connection.commands == ["login mailer"]
+
+
+def test_plain_connection_without_credentials(smtp):
+    client = EmailClient(host="localhost", port=1025, user="", password="", security="none")
+
+    client.deliver("client@example.com", "Hello", "Body")
+
+    connection, = smtp
+    assert not connection.implicit_tls
+    assert connection.commands == []
+
+
+def test_failed_login_closes_connection(smtp, monkeypatch):
+    def login(self, user, password):
+        raise smtplib.SMTPAuthenticationError(535, b"Bad credentials")
+
+    monkeypatch.setattr(FakeSMTP, "login", login)
+    client = EmailClient(host="smtp.test", port=587, user="mailer", password="wrong", security="auto")
+
+    with pytest.raises(EmailTransientError):
+        client.deliver("client@example.com", "Hello", "Body")
+
+    assert smtp[0].closed
+
+
+def test_rejected_recipient_is_permanent_error():
+    FakeSMTP.reject = smtplib.SMTPRecipientsRefused({"client@example.com": (550, b"No such user")})
+    client = EmailClient(host="smtp.test", port=587, user="", password="", security="auto")
+
+    with pytest.raises(EmailPermanentError):
+        client.deliver("client@example.com", "Hello", "Body")
+
+    assert client.send("client@example.com", "Hello", "Body") is False
@@ -0,0 +1,49 @@
+import time
+
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+from sqlalchemy import text
+from sqlalchemy.exc import OperationalError
+import pytest
+
+from shared.api import register_exception_handlers
+from shared.api.errors import is_database_timeout
+from shared.config import settings
+from shared.database import SessionLocal
+from shared.database.database import connect_args
+
+
+app = FastAPI()
+register_exception_handlers(app)
+
+
+@app.get("/slow")
+async def slow():
+    with SessionLocal() as session:
+        session.execute(text("SET LOCAL statement_timeout = 100"))
+        session.execute(text("SELECT pg_sleep(5)"))
+
+
+def test_statement_timeout_set_on_postgresql_connections():
+    assert connect_args("postgresql://db/booking") == {
+        "options": f"-c statement_timeout={settings.DB_STATEMENT_TIMEOUT_MS}"
+    }
+    assert connect_args("sqlite://") == {}
+
+
+def test_slow_query_aborts_promptly(db):
+    db.execute(text("SET LOCAL statement_timeout = 100"))
+    started = time.monotonic()
+
+    with pytest.raises(OperationalError) as exc:
+        db.execute(text("SELECT pg_sleep(5)"))
+
+    assert time.monotonic() - started < 2
+    assert is_database_timeout(exc.value)
+
+
+def test_timed_out_request_is_gateway_timeout(db):
+    response = TestClient(app, raise_server_exceptions=False).get("/slow")
+
+    assert response.status_code == 504
+    assert response.json()["error"] == "DATABASE_TIMEOUT"
@@ -0,0 +1,27 @@
+import pytest
+
+from shared.api import normalize_subdomain, subdomain_error
+
+
+@pytest.mark.parametrize("subdomain", ["salon", "beauty-bar", "salon24", "a1b", "x" * 50])
+def test_valid_subdomains(subdomain):
+    assert subdomain_error(subdomain) is None
+
+
+@pytest.mark.parametrize("subdomain", ["ab", "x" * 51])
+def test_length_limits(subdomain):
+    assert "characters long" in subdomain_error(subdomain)
+
+
+@pytest.mark.parametrize("subdomain", ["Salon", "beauty_bar", "-salon", "salon-", "xn--salon", "sa lon", "салон"])
+def test_not_a_dns_label(subdomain):
+    assert "letters, digits" in subdomain_error(subdomain)
+
+
+@pytest.mark.parametrize("subdomain", ["admin", "api", "www"])
+def test_reserved_subdomains(subdomain):
+    assert subdomain_error(subdomain) == "Subdomain is reserved"
+
+
+def test_normalized_to_lowercase():
+    assert normalize_subdomain("  Beauty-Bar ") == "beauty-bar"
@@ -0,0 +1,33 @@
+import pytest
+
+from shared.billing import compute_tax, get_tax_config
+from shared.models import Service, Tenant
+
+
+@pytest.mark.parametrize("price, rate_bp, expected", [
+    (11200, 1200, {"net": 10000, "tax": 1200, "total": 11200}),
+    (100, 1200, {"net": 89, "tax": 11, "total": 100}),
+    (500000, 0, {"net": 500000, "tax": 0, "total": 500000}),
+])
+def test_inclusive_tax_is_contained_in_price(price, rate_bp, expected):
+    assert compute_tax(price, rate_bp, inclusive=True) == expected
+
+
+@pytest.mark.parametrize("price, rate_bp, expected", [
+    (10000, 1200, {"net": 10000, "tax": 1200, "total": 11200}),
+    (999, 1200, {"net": 999, "tax": 120, "total": 1119}),
+    (5, 1000, {"net": 5, "tax": 1, "total": 6}),
+])
+def test_added_tax_is_charged_on_top(price, rate_bp, expected):
+    assert compute_tax(price, rate_bp, inclusive=False) == expected
+
+
+def test_service_tax_overrides_tenant():
+    tenant = Tenant(tax_rate=1200, tax_inclusive=True)
+
+    assert get_tax_config(tenant, Service(tax_rate=0, tax_inclusive=False)) == {"rate_bp": 0, "inclusive": False}
+    assert get_tax_config(tenant, Service()) == {"rate_bp": 1200, "inclusive": True}
+
+
+def test_no_tax_configured_is_inclusive_zero():
+    assert get_tax_config(None, Service()) == {"rate_bp": 0, "inclusive": True}
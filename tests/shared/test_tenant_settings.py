@@ -0,0 +1,78 @@
+from types import SimpleNamespace
+
+import pytest
+from pydantic import ValidationError
+
+from shared.config import settings
+from shared.models import NotificationChannel
+from shared.tenants import TenantSettingsUpdate, apply_tenant_settings, get_tenant_settings
+
+
+@pytest.fixture(autouse=True)
+def defaults(monkeypatch):
+    monkeypatch.setattr(settings, "DEFAULT_TIMEZONE", "Asia/Almaty")
+    monkeypatch.setattr(settings, "REMINDER_SAME_DAY_CHANNELS", "WHATSAPP")
+    monkeypatch.setattr(settings, "REMINDER_ADVANCE_CHANNELS", "EMAIL")
+    monkeypatch.setattr(settings, "CANCELLATION_POLICY", "24:0,2:50")
+
+
+def tenant(**values):
+    return SimpleNamespace(id=1, **{
+        "timezone": None, "reminder_channels": None, "cancellation_policy": None, "branding": None, **values
+    })
+
+
+def test_defaults_fill_missing_settings():
+    result = get_tenant_settings(tenant())
+
+    assert result.timezone == "Asia/Almaty"
+    assert result.reminder_channels.same_day == [NotificationChannel.WHATSAPP]
+    assert result.reminder_channels.advance == [NotificationChannel.EMAIL]
+    assert [(t.hours_before, t.fee_percent) for t in result.cancellation_policy] == [(24, 0), (2, 50)]
+    assert result.branding.dict() == {"logo_url": None, "primary_color": None}
+
+
+def test_defaults_fill_missing_keys_of_stored_section():
+    result = get_tenant_settings(tenant(reminder_channels={"advance": ["WHATSAPP"]}))
+
+    assert result.reminder_channels.same_day == [NotificationChannel.WHATSAPP]
+    assert result.reminder_channels.advance == [NotificationChannel.WHATSAPP]
+
+
+def test_invalid_stored_section_falls_back_to_default():
+    result = get_tenant_settings(tenant(timezone="Mars/Olympus", branding={"primary_colour": "#ff0066"}))
+
+    assert result.timezone == "Asia/Almaty"
+    assert result.branding.primary_color is None
+
+
+def test_without_tenant():
+    assert get_tenant_settings(None).timezone == "Asia/Almaty"
+
+
+@pytest.mark.parametrize("update", [
+    {"reminders": {"same_day": ["EMAIL"]}},
+    {"timezone": "Mars/Olympus"},
+    {"reminder_channels": {"same_day": [], "advance": ["EMAIL"]}},
+    {"reminder_channels": {"same_day": ["SMS"], "advance": ["EMAIL"]}},
+    {"cancellation_policy": [{"hours_before": 24, "fee_percent": 150}]},
+    {"cancellation_policy": [{"hours_before": 24, "fee_percent": 0}, {"hours_before": 24, "fee_percent": 50}]},
+    {"branding": {"logo_url": "http://example.com/logo.png"}},
+    {"branding": {"primary_color": "green"}},
+])
+def test_invalid_update_is_rejected(update):
+    with pytest.raises(ValidationError):
+        TenantSettingsUpdate(**update)
+
+
+def test_update_replaces_given_sections_only():
+    stored = tenant(branding={"primary_color": "#ff0066"})
+    update = TenantSettingsUpdate(cancellation_policy=[
+        {"hours_before": 2, "fee_percent": 100}, {"hours_before": 48, "fee_percent": 0}
+    ])
+
+    apply_tenant_settings(stored, update)
+
+    # Tiers are kept earliest first
+    assert stored.cancellation_policy == [{"hours_before": 48, "fee_percent": 0}, {"hours_before": 2, "fee_percent": 100}]
+    assert stored.branding == {"primary_color": "#ff0066"}
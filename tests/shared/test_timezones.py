@@ -0,0 +1,20 @@
+import pytest
+
+from shared.calendar import is_valid_timezone, supported_timezones
+
+
+@pytest.mark.parametrize("name", ["Asia/Almaty", "Asia/Aqtobe", "Europe/Moscow", "America/New_York", "UTC"])
+def test_iana_zones_are_valid(name):
+    assert is_valid_timezone(name)
+
+
+@pytest.mark.parametrize("name", ["Asia/Almati", "asia/almaty", "EST", "GMT+6", "", "Asia/"])
+def test_typos_and_legacy_names_are_invalid(name):
+    assert not is_valid_timezone(name)
+
+
+def test_supported_timezones_are_sorted():
+    zones = supported_timezones()
+
+    assert zones == sorted(zones)
+    assert "Asia/Almaty" in zones
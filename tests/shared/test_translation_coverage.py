@@ -0,0 +1,50 @@
+import pytest
+
+from shared.config import settings
+from shared.i18n import (
+    MESSAGES, check_translation_coverage, get_missing_translation_counts, translate, verify_translation_coverage
+)
+from shared.i18n.messages import missing_translations
+
+
+@pytest.fixture(autouse=True)
+def languages(monkeypatch):
+    monkeypatch.setattr(settings, "DEFAULT_LANGUAGE", "ru")
+    monkeypatch.setattr(settings, "SUPPORTED_LANGUAGES", "ru,en,kk")
+
+
+def test_incomplete_locale_reports_missing_keys():
+    messages = {
+        "ru": {"booking_cancellation": "Отмена", "booking_confirmed": "Подтверждено"},
+        "en": {"booking_cancellation": "Cancellation", "booking_confirmed": "Confirmed"},
+        "kk": {"booking_confirmed": "Расталды"}
+    }
+
+    assert check_translation_coverage(messages) == {"kk": ["booking_cancellation"]}
+
+
+def test_missing_locale_reports_all_keys():
+    messages = {"ru": {"a": "А", "b": "Б"}, "en": {"a": "A", "b": "B"}}
+
+    assert check_translation_coverage(messages) == {"kk": ["a", "b"]}
+
+
+def test_shipped_messages_are_complete():
+    assert check_translation_coverage() == {}
+
+
+def test_missing_lookup_falls_back_and_is_counted(monkeypatch):
+    monkeypatch.setattr(settings, "I18N_LOG_MISSING", False)
+    missing_translations.clear()
+
+    assert translate("no_such_key", "en") == "no_such_key"
+
+    assert get_missing_translation_counts() == {"en.no_such_key": 1, "ru.no_such_key": 1}
+
+
+def test_strict_mode_fails_startup_on_gaps(monkeypatch):
+    monkeypatch.setattr(settings, "I18N_STRICT", True)
+    monkeypatch.delitem(MESSAGES["kk"], "not_found")
+
+    with pytest.raises(RuntimeError, match="not_found"):
+        verify_translation_coverage()
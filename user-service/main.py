@@ -1,13 +1,25 @@
-from fastapi import FastAPI, HTTPException, status, Depends
-from pydantic import BaseModel, EmailStr
+from fastapi import FastAPI, status, Depends
+from fastapi.responses import PlainTextResponse
+from pydantic import BaseModel, EmailStr, validator
 from sqlalchemy.orm import Session
 from datetime import datetime, timedelta
+from typing import Optional, Tuple
 import logging
 
 from shared.config import settings
-from shared.database import get_db, init_db, check_db_connection
+from shared.database import (
+    get_db, init_db, check_db_connection, engine, get_pool_stats, render_prometheus_pool_metrics,
+    run_in_transaction
+)
 from shared.models import User, Tenant, Location, UserRole, TenantStatus
-from shared.auth import verify_password, get_password_hash, create_token_pair
+from shared.auth import verify_password, get_password_hash, create_token_pair, ADMIN_SCOPE
+from shared.api import (
+    APIError, ErrorCode, register_exception_handlers, request_id_middleware, normalize_subdomain, subdomain_error,
+    RESERVED_SUBDOMAINS
+)
+from shared.i18n import verify_translation_coverage
+from shared.billing import is_access_blocked
+from shared.calendar import is_valid_timezone
 from services.user_service import UserService
 
 # Configure logging
@@ -24,6 +36,10 @@ app = FastAPI(
     version="2.0.0"
 )
 
+# Request ID and standard error envelope
+app.middleware("http")(request_id_middleware)
+register_exception_handlers(app)
+
 
 # Request/Response models
 class RegisterRequest(BaseModel):
@@ -33,6 +49,13 @@ class RegisterRequest(BaseModel):
     phone: str
     business_name: str
     subdomain: str
+    timezone: Optional[str] = None
+
+    @validator('timezone')
+    def timezone_valid(cls, v):
+        if v is not None and not is_valid_timezone(v):
+            raise ValueError('Unknown timezone')
+        return v
 
 
 class LoginRequest(BaseModel):
@@ -54,6 +77,7 @@ class ChangePasswordRequest(BaseModel):
 async def startup_event():
     """Initialize database on startup."""
     logger.info("Starting User Service...")
+    verify_translation_coverage()
     if check_db_connection():
         logger.info("Database connection successful")
     else:
@@ -71,6 +95,14 @@ async def health_check():
     }
 
 
+@app.get("/metrics", response_class=PlainTextResponse)
+async def metrics():
+    """
+    Database pool metrics in Prometheus text format.
+    """
+    return render_prometheus_pool_metrics(get_pool_stats(engine), "user-service")
+
+
 @app.post("/register", status_code=status.HTTP_201_CREATED)
 async def register(data: RegisterRequest, db: Session = Depends(get_db)):
     """
@@ -83,29 +115,41 @@ async def register(data: RegisterRequest, db: Session = Depends(get_db)):
     """
     user_service = UserService(db)
 
+    subdomain = normalize_subdomain(data.subdomain)
+    error = subdomain_error(subdomain)
+    if error:
+        raise APIError(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            code=ErrorCode.INVALID_SUBDOMAIN,
+            details={"subdomain": error}
+        )
+
     # Check if email already exists
     existing_user = db.query(User).filter(User.email == data.email).first()
     if existing_user:
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_400_BAD_REQUEST,
-            detail="Email already registered"
+            code=ErrorCode.EMAIL_TAKEN
         )
 
     # Check if subdomain already exists
-    existing_tenant = db.query(Tenant).filter(Tenant.subdomain == data.subdomain).first()
+    existing_tenant = db.query(Tenant).filter(Tenant.subdomain == subdomain).first()
     if existing_tenant:
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_400_BAD_REQUEST,
-            detail="Subdomain already taken"
+            code=ErrorCode.SUBDOMAIN_TAKEN
         )
 
-    try:
+    hashed_password = get_password_hash(data.password)
+
+    def create_owner(db: Session) -> Tuple[Tenant, User]:
         # Create tenant
         tenant = Tenant(
-            subdomain=data.subdomain,
+            subdomain=subdomain,
             business_name=data.business_name,
             phone=data.phone,
             email=data.email,
+            timezone=data.timezone,
             status=TenantStatus.TRIAL,
             trial_end_date=datetime.utcnow() + timedelta(days=settings.DEFAULT_TRIAL_DAYS)
         )
@@ -122,7 +166,6 @@ async def register(data: RegisterRequest, db: Session = Depends(get_db)):
         db.add(location)
 
         # Create owner user
-        hashed_password = get_password_hash(data.password)
         user = User(
             tenant_id=tenant.id,
             email=data.email,
@@ -133,29 +176,71 @@ async def register(data: RegisterRequest, db: Session = Depends(get_db)):
             is_active=True
         )
         db.add(user)
-        db.commit()
-
-        logger.info(f"New tenant registered: {data.subdomain}")
-
-        # Create tokens
-        tokens = create_token_pair(user.id, user.email, user.role.value, tenant.id)
-
-        return {
-            "message": "Registration successful",
-            "user_id": user.id,
-            "tenant_id": tenant.id,
-            "subdomain": tenant.subdomain,
-            **tokens
-        }
+        return tenant, user
 
+    try:
+        tenant, user = run_in_transaction(db, create_owner)
     except Exception as e:
-        db.rollback()
         logger.error(f"Registration failed: {e}")
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
-            detail="Registration failed"
+            code=ErrorCode.REGISTRATION_FAILED
         )
 
+    logger.info(f"New tenant registered: {subdomain}")
+
+    # Create tokens
+    tokens = create_token_pair(user.id, user.email, user.role.value, tenant.id)
+
+    return {
+        "message": "Registration successful",
+        "user_id": user.id,
+        "tenant_id": tenant.id,
+        "subdomain": tenant.subdomain,
+        **tokens
+    }
+
+
+def check_tenant_not_expired(db: Session, tenant_id: Optional[int]) -> None:
+    """Block staff of tenants whose trial or subscription has lapsed."""
+    if not tenant_id:
+        return
+
+    tenant = db.query(Tenant).filter(Tenant.id == tenant_id).first()
+    if tenant and is_access_blocked(tenant):
+        raise APIError(
+            status_code=status.HTTP_403_FORBIDDEN,
+            code=ErrorCode.TENANT_EXPIRED
+        )
+
+
+@app.get("/subdomain-available")
+async def check_subdomain_available(name: str, db: Session = Depends(get_db)):
+    """
+    Check whether a subdomain can be registered.
+
+    reason is "invalid", "reserved" or "taken" when it can't, with the
+    validation message in error for invalid names.
+    """
+    subdomain = normalize_subdomain(name)
+    error = subdomain_error(subdomain)
+
+    if subdomain in RESERVED_SUBDOMAINS:
+        reason = "reserved"
+    elif error:
+        reason = "invalid"
+    elif db.query(Tenant.id).filter(Tenant.subdomain == subdomain).first():
+        reason = "taken"
+    else:
+        reason = None
+
+    return {
+        "subdomain": subdomain,
+        "available": reason is None,
+        "reason": reason,
+        "error": error if reason == "invalid" else None
+    }
+
 
 @app.post("/login")
 async def login(data: LoginRequest, db: Session = Depends(get_db)):
@@ -166,17 +251,19 @@ async def login(data: LoginRequest, db: Session = Depends(get_db)):
     user = db.query(User).filter(User.email == data.email).first()
 
     if not user or not verify_password(data.password, user.password_hash):
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_401_UNAUTHORIZED,
-            detail="Invalid email or password"
+            code=ErrorCode.INVALID_CREDENTIALS
         )
 
     if not user.is_active:
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_403_FORBIDDEN,
-            detail="Account is inactive"
+            code=ErrorCode.ACCOUNT_INACTIVE
         )
 
+    check_tenant_not_expired(db, user.tenant_id)
+
     # Create tokens
     tokens = create_token_pair(user.id, user.email, user.role.value, user.tenant_id)
 
@@ -196,7 +283,7 @@ async def login(data: LoginRequest, db: Session = Depends(get_db)):
 
 
 @app.post("/refresh-token")
-async def refresh_token(data: RefreshTokenRequest):
+async def refresh_token(data: RefreshTokenRequest, db: Session = Depends(get_db)):
     """
     Refresh access token using refresh token.
     """
@@ -205,11 +292,13 @@ async def refresh_token(data: RefreshTokenRequest):
     payload = decode_token(data.refresh_token)
 
     if not payload or payload.get("type") != "refresh":
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_401_UNAUTHORIZED,
-            detail="Invalid refresh token"
+            code=ErrorCode.INVALID_REFRESH_TOKEN
         )
 
+    check_tenant_not_expired(db, payload.get("tenant_id"))
+
     # Create new access token
     token_data = {
         "sub": payload.get("sub"),
@@ -220,7 +309,13 @@ async def refresh_token(data: RefreshTokenRequest):
     if payload.get("tenant_id"):
         token_data["tenant_id"] = payload.get("tenant_id")
 
-    access_token = create_access_token(token_data)
+    expires_delta = None
+    if payload.get("scope"):
+        token_data["scope"] = payload.get("scope")
+        if payload.get("scope") == ADMIN_SCOPE:
+            expires_delta = timedelta(minutes=settings.ADMIN_ACCESS_TOKEN_EXPIRE_MINUTES)
+
+    access_token = create_access_token(token_data, expires_delta)
 
     return {
         "access_token": access_token,
@@ -236,15 +331,15 @@ async def change_password(data: ChangePasswordRequest, db: Session = Depends(get
     user = db.query(User).filter(User.id == data.user_id).first()
 
     if not user:
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_404_NOT_FOUND,
-            detail="User not found"
+            code=ErrorCode.USER_NOT_FOUND
         )
 
     if not verify_password(data.old_password, user.password_hash):
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_400_BAD_REQUEST,
-            detail="Invalid old password"
+            code=ErrorCode.INVALID_OLD_PASSWORD
         )
 
     # Update password
@@ -264,9 +359,9 @@ async def get_user(user_id: int, db: Session = Depends(get_db)):
     user = db.query(User).filter(User.id == user_id).first()
 
     if not user:
-        raise HTTPException(
+        raise APIError(
             status_code=status.HTTP_404_NOT_FOUND,
-            detail="User not found"
+            code=ErrorCode.USER_NOT_FOUND
         )
 
     return {
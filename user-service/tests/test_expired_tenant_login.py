@@ -0,0 +1,53 @@
+from datetime import datetime, timedelta
+
+import pytest
+
+from shared.auth import create_token_pair, get_password_hash
+from shared.models import TenantStatus
+
+PASSWORD = "correct-horse-battery"
+
+
+def staff(factory, **tenant_values):
+    tenant = factory.tenant(**tenant_values)
+    return factory.user(tenant, password_hash=get_password_hash(PASSWORD))
+
+
+def test_staff_of_expired_tenant_cannot_log_in(client, factory):
+    user = staff(factory, status=TenantStatus.EXPIRED)
+
+    response = client.post("/login", json={"email": user.email, "password": PASSWORD})
+
+    assert response.status_code == 403
+    assert response.json()["error"] == "TENANT_EXPIRED"
+
+
+def test_lapsed_trial_blocks_login_before_expiry_job(client, factory):
+    user = staff(factory, status=TenantStatus.TRIAL, trial_end_date=datetime.utcnow() - timedelta(hours=1))
+
+    response = client.post("/login", json={"email": user.email, "password": PASSWORD})
+
+    assert response.status_code == 403
+    assert response.json()["error"] == "TENANT_EXPIRED"
+
+
+@pytest.mark.parametrize("tenant_values", [
+    {"status": TenantStatus.TRIAL, "trial_end_date": datetime.utcnow() + timedelta(days=5)},
+    {"status": TenantStatus.ACTIVE}
+])
+def test_staff_of_tenant_in_good_standing_logs_in(client, factory, tenant_values):
+    user = staff(factory, **tenant_values)
+
+    response = client.post("/login", json={"email": user.email, "password": PASSWORD})
+
+    assert response.status_code == 200
+
+
+def test_refresh_of_expired_tenant_token_is_rejected(client, factory):
+    user = staff(factory, status=TenantStatus.EXPIRED)
+    tokens = create_token_pair(user.id, user.email, user.role.value, user.tenant_id)
+
+    response = client.post("/refresh-token", json={"refresh_token": tokens["refresh_token"]})
+
+    assert response.status_code == 403
+    assert response.json()["error"] == "TENANT_EXPIRED"
@@ -0,0 +1,35 @@
+def check(client, name) -> dict:
+    response = client.get("/subdomain-available", params={"name": name})
+    assert response.status_code == 200
+    return response.json()
+
+
+def test_available_name(client):
+    assert check(client, "Fresh-Salon") == {
+        "subdomain": "fresh-salon", "available": True, "reason": None, "error": None
+    }
+
+
+def test_taken_name(client, factory):
+    factory.tenant(subdomain="salon")
+
+    result = check(client, "salon")
+
+    assert result["available"] is False
+    assert result["reason"] == "taken"
+    assert result["error"] is None
+
+
+def test_reserved_name(client):
+    result = check(client, "Admin")
+
+    assert result["available"] is False
+    assert result["reason"] == "reserved"
+
+
+def test_invalid_name(client):
+    result = check(client, "beauty_bar")
+
+    assert result["available"] is False
+    assert result["reason"] == "invalid"
+    assert "letters, digits" in result["error"]
@@ -0,0 +1,44 @@
+import uuid
+
+import pytest
+
+from shared.models import Tenant
+
+
+def register(client, subdomain):
+    return client.post("/register", json={
+        "email": f"owner-{uuid.uuid4().hex}@example.com",
+        "password": "correct-horse-battery",
+        "full_name": "Aida",
+        "phone": "+77011111111",
+        "business_name": "Salon",
+        "subdomain": subdomain
+    })
+
+
+def test_uppercase_subdomain_is_stored_lowercase(client, db):
+    response = register(client, "Beauty-Bar")
+
+    assert response.status_code == 201
+    assert response.json()["subdomain"] == "beauty-bar"
+    assert db.query(Tenant).filter(Tenant.subdomain == "beauty-bar").count() == 1
+
+
+@pytest.mark.parametrize("subdomain", ["www", "x" * 51, "beauty_bar", "-salon"])
+def test_invalid_subdomain_is_rejected(client, db, subdomain):
+    response = register(client, subdomain)
+
+    assert response.status_code == 400
+    assert response.json()["error"] == "INVALID_SUBDOMAIN"
+    assert "subdomain" in response.json()["details"]
+    assert db.query(Tenant).filter(Tenant.subdomain == subdomain.lower()).count() == 0
+
+
+def test_taken_subdomain_ignores_case(client, factory):
+    factory.tenant(subdomain="salon")
+
+    response = register(client, "SALON")
+
+    assert response.status_code == 400
+    assert response.json()["error"] == "SUBDOMAIN_TAKEN"
+
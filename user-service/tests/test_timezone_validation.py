@@ -0,0 +1,33 @@
+import uuid
+
+import pytest
+
+from shared.models import Tenant
+
+
+def register(client, subdomain, timezone):
+    return client.post("/register", json={
+        "email": f"owner-{uuid.uuid4().hex}@example.com",
+        "password": "correct-horse-battery",
+        "full_name": "Aida",
+        "phone": "+77011111111",
+        "business_name": "Salon",
+        "subdomain": subdomain,
+        "timezone": timezone
+    })
+
+
+def test_valid_timezone_is_stored(client, db):
+    response = register(client, "salon-almaty", "Asia/Almaty")
+
+    assert response.status_code == 201
+    assert db.query(Tenant).filter(Tenant.subdomain == "salon-almaty").one().timezone == "Asia/Almaty"
+
+
+@pytest.mark.parametrize("timezone", ["Asia/Almati", "asia/almaty", "EST", ""])
+def test_invalid_timezone_is_rejected(client, db, timezone):
+    response = register(client, "salon-typo", timezone)
+
+    assert response.status_code == 422
+    assert response.json()["error"] == "VALIDATION_ERROR"
+    assert db.query(Tenant).filter(Tenant.subdomain == "salon-typo").count() == 0
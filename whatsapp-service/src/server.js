@@ -26,6 +26,7 @@ const app = express();
 app.use(bodyParser.json());
 
 const PORT = process.env.WHATSAPP_SERVICE_PORT || 3000;
+const IS_PRODUCTION = (process.env.ENVIRONMENT || process.env.NODE_ENV) === 'production';
 
 // WhatsApp client
 let whatsappClient = null;
@@ -113,6 +114,11 @@ function formatPhoneNumber(phone) {
     return cleaned + '@c.us';
 }
 
+// Internal error messages are only exposed outside production
+function errorDetails(error) {
+    return IS_PRODUCTION ? undefined : error.message;
+}
+
 // Routes
 
 // Health check
@@ -179,21 +185,22 @@ app.post('/send-message', async (req, res) => {
         }
 
         // Send message
-        await whatsappClient.sendMessage(formattedPhone, message);
+        const sent = await whatsappClient.sendMessage(formattedPhone, message);
 
         logger.info(`Message sent successfully to ${phone}`);
 
         res.json({
             success: true,
             message: 'Message sent successfully',
-            phone: phone
+            phone: phone,
+            message_id: sent.id._serialized
         });
 
     } catch (error) {
-        logger.error('Error sending message:', error);
+        logger.error(`Error sending message (request_id=${req.get('X-Request-ID')}):`, error);
         res.status(500).json({
             error: 'Failed to send message',
-            details: error.message
+            details: errorDetails(error)
         });
     }
 });
@@ -240,7 +247,7 @@ app.post('/send-bulk', async (req, res) => {
             await new Promise(resolve => setTimeout(resolve, 1000));
 
         } catch (error) {
-            results.push({ phone, status: 'error', error: error.message });
+            results.push({ phone, status: 'error', error: errorDetails(error) });
             logger.error(`Bulk message error for ${phone}:`, error);
         }
     }
@@ -280,10 +287,10 @@ app.post('/check-number', async (req, res) => {
         });
 
     } catch (error) {
-        logger.error('Error checking number:', error);
+        logger.error(`Error checking number (request_id=${req.get('X-Request-ID')}):`, error);
         res.status(500).json({
             error: 'Failed to check number',
-            details: error.message
+            details: errorDetails(error)
         });
     }
 });
@@ -306,7 +313,7 @@ app.post('/logout', async (req, res) => {
         logger.error('Error logging out:', error);
         res.status(500).json({
             error: 'Failed to logout',
-            details: error.message
+            details: errorDetails(error)
         });
     }
 });